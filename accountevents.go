@@ -0,0 +1,396 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Account event types, for events that concern the account rather than a
+// message.
+const (
+	EventBalanceLow     = "account.balance_low"
+	EventTemplateStatus = "template.status"
+	EventLineExpiring   = "line.expiring"
+
+	// EventAccountSuspended is raised when the account is suspended or
+	// blocked and every request starts failing.
+	EventAccountSuspended = "account.suspended"
+)
+
+// Template review statuses reported in Template.Status.
+const (
+	TemplatePending  = "pending"
+	TemplateApproved = "approved"
+	TemplateRejected = "rejected"
+)
+
+// BalanceLowEvent reports that the account balance dropped below a
+// threshold.
+type BalanceLowEvent struct {
+	Balance   float64 `json:"balance"`
+	Threshold float64 `json:"threshold"`
+	Currency  string  `json:"currency,omitempty"`
+}
+
+// TemplateStatusEvent reports a change in the review status of a template.
+type TemplateStatusEvent struct {
+	TemplateID string `json:"template_id"`
+	Name       string `json:"name,omitempty"`
+	Status     string `json:"status"`
+
+	// Why the template was rejected, if it was
+	Reason string `json:"reason,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Approved reports whether the template was approved.
+func (e *TemplateStatusEvent) Approved() bool {
+	return e.Status == TemplateApproved
+}
+
+// Rejected reports whether the template was rejected.
+func (e *TemplateStatusEvent) Rejected() bool {
+	return e.Status == TemplateRejected
+}
+
+// LineExpiringEvent reports that a dedicated line will expire unless it is
+// renewed.
+type LineExpiringEvent struct {
+	Line      string    `json:"line"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AccountSuspendedEvent reports that the account was suspended or blocked.
+type AccountSuspendedEvent struct {
+	// Account status, such as AccountSuspended
+	Status string `json:"status,omitempty"`
+
+	// Why the account was suspended, if known
+	Reason string `json:"reason,omitempty"`
+}
+
+// BalanceLow decodes the payload of an EventBalanceLow event.
+func (e *WebhookEvent) BalanceLow() (*BalanceLowEvent, error) {
+	var balance BalanceLowEvent
+	if err := e.decodeData(EventBalanceLow, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// TemplateStatus decodes the payload of an EventTemplateStatus event.
+func (e *WebhookEvent) TemplateStatus() (*TemplateStatusEvent, error) {
+	var status TemplateStatusEvent
+	if err := e.decodeData(EventTemplateStatus, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// LineExpiring decodes the payload of an EventLineExpiring event.
+func (e *WebhookEvent) LineExpiring() (*LineExpiringEvent, error) {
+	var line LineExpiringEvent
+	if err := e.decodeData(EventLineExpiring, &line); err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// AccountSuspended decodes the payload of an EventAccountSuspended event.
+func (e *WebhookEvent) AccountSuspended() (*AccountSuspendedEvent, error) {
+	var suspended AccountSuspendedEvent
+	if err := e.decodeData(EventAccountSuspended, &suspended); err != nil {
+		return nil, err
+	}
+	return &suspended, nil
+}
+
+// OnBalanceLow registers fn for low balance notifications.
+func (h *WebhookHandler) OnBalanceLow(fn func(ctx context.Context, event *BalanceLowEvent) error) {
+	h.On(EventBalanceLow, func(ctx context.Context, event *WebhookEvent) error {
+		balance, err := event.BalanceLow()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, balance)
+	})
+}
+
+// OnTemplateStatus registers fn for template review status changes.
+func (h *WebhookHandler) OnTemplateStatus(fn func(ctx context.Context, event *TemplateStatusEvent) error) {
+	h.On(EventTemplateStatus, func(ctx context.Context, event *WebhookEvent) error {
+		status, err := event.TemplateStatus()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, status)
+	})
+}
+
+// OnLineExpiring registers fn for line expiry notifications.
+func (h *WebhookHandler) OnLineExpiring(fn func(ctx context.Context, event *LineExpiringEvent) error) {
+	h.On(EventLineExpiring, func(ctx context.Context, event *WebhookEvent) error {
+		line, err := event.LineExpiring()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, line)
+	})
+}
+
+// OnAccountSuspended registers fn for account suspension notifications.
+func (h *WebhookHandler) OnAccountSuspended(fn func(ctx context.Context, event *AccountSuspendedEvent) error) {
+	h.On(EventAccountSuspended, func(ctx context.Context, event *WebhookEvent) error {
+		suspended, err := event.AccountSuspended()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, suspended)
+	})
+}
+
+// AccountWatcherOption configures an AccountWatcher.
+type AccountWatcherOption func(*AccountWatcher)
+
+// WithBalanceThreshold makes the watcher raise EventBalanceLow when the
+// balance drops below threshold. It is raised again only after the
+// balance has recovered.
+func WithBalanceThreshold(threshold float64) AccountWatcherOption {
+	return func(w *AccountWatcher) {
+		w.threshold = threshold
+		w.watchBalance = true
+	}
+}
+
+// WithTemplateWatch makes the watcher raise EventTemplateStatus when the
+// review status of a template changes.
+func WithTemplateWatch() AccountWatcherOption {
+	return func(w *AccountWatcher) {
+		w.watchTemplates = true
+	}
+}
+
+// WithLineExpiryWatch makes the watcher raise EventLineExpiring once for
+// every line that will expire within d. A line renewed to a later expiry
+// date is reported again when that date approaches.
+func WithLineExpiryWatch(d time.Duration) AccountWatcherOption {
+	return func(w *AccountWatcher) {
+		w.lineWindow = d
+		w.watchLines = true
+	}
+}
+
+// WithAccountWatchInterval sets how often Run polls. It defaults to five
+// minutes.
+func WithAccountWatchInterval(interval time.Duration) AccountWatcherOption {
+	return func(w *AccountWatcher) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithAccountWatcherErrorHandler sets the function called when a poll or
+// a callback fails during Run. By default such errors are dropped.
+func WithAccountWatcherErrorHandler(fn func(ctx context.Context, err error)) AccountWatcherOption {
+	return func(w *AccountWatcher) {
+		w.onError = fn
+	}
+}
+
+// AccountWatcher is the polling fallback for account event webhooks. It
+// polls the account and dispatches the events it detects to the callbacks
+// registered on a WebhookHandler, so that the same callbacks run whether
+// the provider's webhook or the poller noticed the change first. Callbacks
+// should therefore tolerate seeing an event twice.
+//
+// Whatever it watches, the watcher raises EventAccountSuspended once when a
+// poll finds the account suspended, and again only after a poll has
+// succeeded since.
+type AccountWatcher struct {
+	client   *Client
+	handler  *WebhookHandler
+	interval time.Duration
+	onError  func(ctx context.Context, err error)
+
+	watchBalance   bool
+	threshold      float64
+	watchTemplates bool
+	watchLines     bool
+	lineWindow     time.Duration
+
+	mu            sync.Mutex
+	suspended     bool
+	balanceOK     bool
+	templates     map[string]string    // template ID to last seen status
+	linesReported map[string]time.Time // line number to expiry reported
+}
+
+// NewAccountWatcher creates a watcher that polls client and dispatches to
+// h. It watches nothing until configured with options such as
+// WithBalanceThreshold.
+func NewAccountWatcher(client *Client, h *WebhookHandler, opts ...AccountWatcherOption) *AccountWatcher {
+	w := &AccountWatcher{
+		client:    client,
+		handler:   h,
+		interval:  5 * time.Minute,
+		balanceOK: true,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run polls every interval until ctx is done.
+func (w *AccountWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Check(ctx); err != nil && ctx.Err() == nil && w.onError != nil {
+			w.onError(ctx, err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Check polls once and dispatches the events detected since the previous
+// check. The first check records the template statuses without raising
+// events for them.
+func (w *AccountWatcher) Check(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.check(ctx)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		w.suspended = false
+	case errors.As(err, &apiErr) && apiErr.Code == ErrCodeAccountSuspended:
+		if w.suspended {
+			return err
+		}
+		if dispatchErr := w.dispatch(ctx, EventAccountSuspended, AccountSuspendedEvent{Status: AccountSuspended, Reason: apiErr.Error()}); dispatchErr != nil {
+			return errors.Join(err, dispatchErr)
+		}
+		w.suspended = true
+	}
+	return err
+}
+
+func (w *AccountWatcher) check(ctx context.Context) error {
+	if w.watchBalance {
+		if err := w.checkBalance(ctx); err != nil {
+			return err
+		}
+	}
+	if w.watchTemplates {
+		if err := w.checkTemplates(ctx); err != nil {
+			return err
+		}
+	}
+	if w.watchLines {
+		if err := w.checkLines(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *AccountWatcher) checkBalance(ctx context.Context) error {
+	info, err := w.client.Messages.GetUserInfo(ctx)
+	if err != nil {
+		return err
+	}
+	if info.Suspended() {
+		return &APIError{Code: ErrCodeAccountSuspended, Message: "Account is " + info.Status, StatusCode: http.StatusForbidden}
+	}
+
+	if info.Balance >= w.threshold {
+		w.balanceOK = true
+		return nil
+	}
+	if !w.balanceOK {
+		return nil
+	}
+	if err = w.dispatch(ctx, EventBalanceLow, BalanceLowEvent{Balance: info.Balance, Threshold: w.threshold}); err != nil {
+		return err
+	}
+	w.balanceOK = false
+	return nil
+}
+
+func (w *AccountWatcher) checkTemplates(ctx context.Context) error {
+	templates, err := listAllTemplates(ctx, w.client.Templates)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	first := w.templates == nil
+	if first {
+		w.templates = make(map[string]string, len(templates))
+	}
+	for i := range templates {
+		t := &templates[i]
+		previous, seen := w.templates[t.ID]
+		if !first && (!seen || previous != t.Status) && t.Status != "" {
+			event := TemplateStatusEvent{TemplateID: t.ID, Name: t.Name, Status: t.Status, UpdatedAt: t.UpdatedAt}
+			if err = w.dispatch(ctx, EventTemplateStatus, event); err != nil {
+				return err
+			}
+		}
+		w.templates[t.ID] = t.Status
+	}
+	return nil
+}
+
+func (w *AccountWatcher) checkLines(ctx context.Context) error {
+	expiring, err := w.client.Lines.ExpiringWithin(ctx, w.lineWindow)
+	if err != nil {
+		return err
+	}
+
+	if w.linesReported == nil {
+		w.linesReported = make(map[string]time.Time)
+	}
+	for _, line := range expiring {
+		if reported, ok := w.linesReported[line.Number]; ok && reported.Equal(line.ExpiresAt) {
+			continue
+		}
+		if err = w.dispatch(ctx, EventLineExpiring, LineExpiringEvent{Line: line.Number, ExpiresAt: line.ExpiresAt}); err != nil {
+			return err
+		}
+		w.linesReported[line.Number] = line.ExpiresAt
+	}
+	return nil
+}
+
+// dispatch wraps data in an event envelope and runs the callbacks for it.
+func (w *AccountWatcher) dispatch(ctx context.Context, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	return w.handler.Dispatch(ctx, &WebhookEvent{
+		ID:        "poll_" + id,
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      payload,
+	})
+}
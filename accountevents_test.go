@@ -0,0 +1,183 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler_TemplateStatus(t *testing.T) {
+	h := newTestWebhookHandler()
+
+	var got *TemplateStatusEvent
+	h.OnTemplateStatus(func(ctx context.Context, event *TemplateStatusEvent) error {
+		got = event
+		return nil
+	})
+
+	body := `{
+		"id": "evt-1",
+		"type": "template.status",
+		"data": {"template_id": "welcome", "status": "rejected", "reason": "contains a URL"}
+	}`
+	req := signedWebhookRequest("/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got == nil {
+		t.Fatal("Expected template status callback to run")
+	}
+	if !got.Rejected() || got.Approved() || got.Reason != "contains a URL" {
+		t.Errorf("Unexpected template status event: %+v", got)
+	}
+}
+
+func TestWebhookEvent_AccountEvents(t *testing.T) {
+	balance := &WebhookEvent{ID: "evt-1", Type: EventBalanceLow, Data: []byte(`{"balance": 4.5, "threshold": 10}`)}
+	event, err := balance.BalanceLow()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.Balance != 4.5 || event.Threshold != 10 {
+		t.Errorf("Unexpected balance event: %+v", event)
+	}
+
+	line := &WebhookEvent{ID: "evt-2", Type: EventLineExpiring, Data: []byte(`{"line": "100020", "expires_at": "2026-11-01T00:00:00Z"}`)}
+	expiring, err := line.LineExpiring()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expiring.Line != "100020" || expiring.ExpiresAt.IsZero() {
+		t.Errorf("Unexpected line event: %+v", expiring)
+	}
+
+	if _, err := line.BalanceLow(); err == nil {
+		t.Error("Expected error for mismatched event type")
+	}
+}
+
+func TestAccountWatcher_Balance(t *testing.T) {
+	balances := []float64{50, 8, 6, 20, 5}
+	call := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/info" {
+			t.Errorf("Expected path /user/info, got %s", r.URL.Path)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": "user-1", "balance": %v}`, balances[call])
+		call++
+	}
+	client := setupTestClient(handler)
+
+	h := newTestWebhookHandler()
+	var events []*BalanceLowEvent
+	h.OnBalanceLow(func(ctx context.Context, event *BalanceLowEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	watcher := NewAccountWatcher(client, h, WithBalanceThreshold(10))
+
+	for range balances {
+		if err := watcher.Check(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Raised when dropping to 8 and, after recovering, again at 5.
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Balance != 8 || events[1].Balance != 5 || events[0].Threshold != 10 {
+		t.Errorf("Unexpected events: %+v, %+v", events[0], events[1])
+	}
+}
+
+func TestAccountWatcher_Templates(t *testing.T) {
+	pages := []string{
+		`{"templates": [{"id": "welcome", "body": "Hi", "status": "pending"}, {"id": "otp", "body": "{code}", "status": "approved"}]}`,
+		`{"templates": [{"id": "welcome", "body": "Hi", "status": "rejected"}, {"id": "otp", "body": "{code}", "status": "approved"}, {"id": "promo", "body": "Sale", "status": "pending"}]}`,
+	}
+	call := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[call]))
+		call++
+	}
+	client := setupTestClient(handler)
+
+	h := newTestWebhookHandler()
+	var events []*TemplateStatusEvent
+	h.OnTemplateStatus(func(ctx context.Context, event *TemplateStatusEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	watcher := NewAccountWatcher(client, h, WithTemplateWatch())
+
+	for range pages {
+		if err := watcher.Check(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].TemplateID != "welcome" || !events[0].Rejected() {
+		t.Errorf("Expected welcome to be rejected, got %+v", events[0])
+	}
+	if events[1].TemplateID != "promo" || events[1].Status != TemplatePending {
+		t.Errorf("Expected new promo template, got %+v", events[1])
+	}
+}
+
+func TestAccountWatcher_Suspended(t *testing.T) {
+	responses := []struct {
+		status    int
+		body      string
+		suspended bool
+	}{
+		{http.StatusForbidden, `{"code": "ACCOUNT_BLOCKED", "message": "Account blocked"}`, true},
+		{http.StatusForbidden, `{"code": "ACCOUNT_BLOCKED", "message": "Account blocked"}`, true},
+		{http.StatusOK, `{"id": "user-1", "balance": 50}`, false},
+		{http.StatusOK, `{"id": "user-1", "balance": 50, "status": "suspended"}`, true},
+	}
+	call := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(responses[call].status)
+		w.Write([]byte(responses[call].body))
+		call++
+	}
+	client := setupTestClient(handler)
+
+	h := newTestWebhookHandler()
+	var events []*AccountSuspendedEvent
+	h.OnAccountSuspended(func(ctx context.Context, event *AccountSuspendedEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	watcher := NewAccountWatcher(client, h, WithBalanceThreshold(10))
+
+	for i := range responses {
+		err := watcher.Check(context.Background())
+		if got := IsAccountSuspended(err); got != responses[i].suspended {
+			t.Errorf("Check %d: expected suspended %v, got error %v", i, responses[i].suspended, err)
+		}
+	}
+
+	// Raised on the first suspended poll and again after the account was
+	// active in between.
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[1].Status != AccountSuspended {
+		t.Errorf("Expected status %s, got %+v", AccountSuspended, events[1])
+	}
+}
@@ -0,0 +1,331 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Aggregator defaults.
+const (
+	DefaultAggregatorWindow   = 200 * time.Millisecond
+	DefaultAggregatorMaxBatch = 50
+)
+
+// ErrAggregatorClosed is returned by sends submitted after the Aggregator
+// has been closed.
+var ErrAggregatorClosed = errors.New("aggregator is closed")
+
+// BulkItemError is returned by Aggregator.Send when the bulk request
+// carrying the message succeeded but the message itself was rejected by
+// the pre-filter or frequency cap, or not accepted by the API.
+type BulkItemError struct {
+	Failure BulkFailure
+}
+
+func (e *BulkItemError) Error() string {
+	if e.Failure.Code == "" {
+		return fmt.Sprintf("message to %s was not accepted: %s", RedactPhone(e.Failure.Recipient), e.Failure.Message)
+	}
+	return fmt.Sprintf("message to %s was not accepted: %s: %s", RedactPhone(e.Failure.Recipient), e.Failure.Code, e.Failure.Message)
+}
+
+// AggregatorOption configures an Aggregator.
+type AggregatorOption func(*Aggregator)
+
+// WithAggregatorWindow sets how long the first message of a batch waits for
+// others before the batch is sent. It defaults to DefaultAggregatorWindow.
+func WithAggregatorWindow(window time.Duration) AggregatorOption {
+	return func(a *Aggregator) {
+		if window > 0 {
+			a.window = window
+		}
+	}
+}
+
+// WithAggregatorMaxBatch sets how many messages make a batch be sent
+// before its window ends. It defaults to DefaultAggregatorMaxBatch.
+func WithAggregatorMaxBatch(n int) AggregatorOption {
+	return func(a *Aggregator) {
+		if n > 0 {
+			a.maxBatch = n
+		}
+	}
+}
+
+// withAggregatorMessages makes the Aggregator send through api, unless an
+// earlier option already chose the API. It lets a decorator that is not
+// the client's Messages put itself in front of the service.
+func withAggregatorMessages(api MessagesAPI) AggregatorOption {
+	return func(a *Aggregator) {
+		if a.messages == nil {
+			a.messages = api
+		}
+	}
+}
+
+// batchKey holds the request fields that all messages of one bulk request
+// share.
+type batchKey struct {
+	from      string
+	encoding  Encoding
+	category  Category
+	tag       string
+	clientRef string
+	footer    string
+	hasFooter bool
+}
+
+// batchable reports whether a bulk message item can carry everything req
+// sends besides the fields in batchKey.
+func batchable(req *SendMessageRequest) bool {
+	return req.DocumentLink == "" && req.DocumentCaption == "" && len(req.Params) == 0
+}
+
+type batchItem struct {
+	ctx  context.Context
+	req  *SendMessageRequest
+	done chan struct{}
+	resp *SendMessageResponse
+	err  error
+}
+
+type batch struct {
+	key   batchKey
+	items []*batchItem
+	timer *time.Timer
+}
+
+// Aggregator collects single sends made within a short window and sends
+// them as one bulk request, returning each caller its own response. Only
+// messages that can share a bulk request are batched together: those with
+// the same sender, encoding, category, tag, client reference and footer.
+// Messages with a document link or Params, which a bulk request cannot
+// carry, and messages that the long message policy would split, truncate
+// or reject, are sent on their own.
+//
+// A batch is sent with the context values, such as a calendar override or
+// priority, of its first message; idempotency keys are not carried over,
+// since they identify single sends.
+type Aggregator struct {
+	client   *Client
+	messages MessagesAPI
+	window   time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending map[batchKey]*batch
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// Aggregator creates an Aggregator backed by the client's Messages, so
+// that sends go through any decorators set on it. Close must be called to
+// send the messages still waiting for their window.
+func (s *MessagesService) Aggregator(opts ...AggregatorOption) *Aggregator {
+	a := &Aggregator{
+		client:   s.client,
+		window:   DefaultAggregatorWindow,
+		maxBatch: DefaultAggregatorMaxBatch,
+		pending:  make(map[batchKey]*batch),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.messages == nil {
+		a.messages = s.client.Messages
+	}
+	return a
+}
+
+// Send adds req to a batch and waits for the batch to be sent. If ctx is
+// done before then, the message is taken out of the batch and Send returns
+// the context error; once the batch is on its way, the message may be sent
+// even though Send has returned.
+func (a *Aggregator) Send(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
+	if err := validateSendMessageRequest(req); err != nil {
+		return nil, err
+	}
+	c := a.client
+	if !batchable(req) || c.exceedsSegmentCap(ctx, req.Message, req.Encoding) {
+		return a.messages.SendSingleMessage(ctx, req)
+	}
+
+	_, category := resolveCategory(ctx, req.Category)
+	key := batchKey{
+		from:      c.fromOrDefault(req.From),
+		encoding:  req.Encoding,
+		category:  category,
+		tag:       req.Tag,
+		clientRef: req.ClientRef,
+	}
+	key.footer, key.hasFooter = ctx.Value(footerContextKey{}).(string)
+
+	item := &batchItem{ctx: ctx, req: req, done: make(chan struct{})}
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil, ErrAggregatorClosed
+	}
+	b := a.pending[key]
+	if b == nil {
+		b = &batch{key: key}
+		b.timer = time.AfterFunc(a.window, func() { a.flush(b) })
+		a.pending[key] = b
+	}
+	b.items = append(b.items, item)
+	if len(b.items) >= a.maxBatch {
+		b.timer.Stop()
+		a.detachLocked(b)
+		go a.send(b)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-item.done:
+		return item.resp, item.err
+	case <-ctx.Done():
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending[key] == b {
+		for i, it := range b.items {
+			if it == item {
+				b.items = append(b.items[:i], b.items[i+1:]...)
+				break
+			}
+		}
+		if len(b.items) == 0 {
+			b.timer.Stop()
+			delete(a.pending, key)
+		}
+	}
+	return nil, ctx.Err()
+}
+
+// Close sends the batches still waiting for their window and waits for all
+// batches in flight to complete.
+func (a *Aggregator) Close() error {
+	a.mu.Lock()
+	a.closed = true
+	batches := make([]*batch, 0, len(a.pending))
+	for _, b := range a.pending {
+		b.timer.Stop()
+		batches = append(batches, b)
+	}
+	for _, b := range batches {
+		a.detachLocked(b)
+	}
+	a.mu.Unlock()
+
+	for _, b := range batches {
+		go a.send(b)
+	}
+	a.wg.Wait()
+	return nil
+}
+
+// flush sends b when its window ends, unless it has already been sent.
+func (a *Aggregator) flush(b *batch) {
+	a.mu.Lock()
+	if a.pending[b.key] != b {
+		a.mu.Unlock()
+		return
+	}
+	a.detachLocked(b)
+	a.mu.Unlock()
+	a.send(b)
+}
+
+// detachLocked removes b from the pending batches, so that no more messages
+// join or leave it, and counts it as in flight.
+func (a *Aggregator) detachLocked(b *batch) {
+	delete(a.pending, b.key)
+	a.wg.Add(1)
+}
+
+// send sends b as one bulk request and resolves its messages.
+func (a *Aggregator) send(b *batch) {
+	defer a.wg.Done()
+	if len(b.items) == 0 {
+		return
+	}
+
+	req := &SendBulkMessageRequest{
+		Messages:  make([]BulkMessageItem, len(b.items)),
+		From:      b.key.from,
+		Encoding:  b.key.encoding,
+		Category:  b.key.category,
+		Tag:       b.key.tag,
+		ClientRef: b.key.clientRef,
+	}
+	priority := PriorityLow
+	for i, item := range b.items {
+		req.Messages[i] = BulkMessageItem{To: item.req.To, Message: item.req.Message}
+		priority = max(priority, sendPriority(item.ctx, false))
+	}
+
+	ctx := context.WithoutCancel(b.items[0].ctx)
+	ctx = WithIdempotencyKey(WithPriority(ctx, priority), "")
+	resp, err := a.messages.SendBulkMessages(ctx, req)
+	if err != nil {
+		for _, item := range b.items {
+			item.err = err
+			close(item.done)
+		}
+		return
+	}
+
+	rejected := make(map[int]BulkFailure, len(resp.Rejected))
+	for _, r := range resp.Rejected {
+		rejected[r.Index] = r
+	}
+	// Results and failed items are indexed by position among the messages
+	// that passed the pre-filter.
+	kept := keptIndexes(len(b.items), resp.Rejected)
+	failed := make(map[int]BulkFailure, len(resp.FailedItems))
+	for _, f := range resp.FailedItems {
+		if f.Index >= 0 && f.Index < len(kept) {
+			f.Index = kept[f.Index]
+			failed[f.Index] = f
+		}
+	}
+
+	for i := range b.items {
+		if r, ok := rejected[i]; ok {
+			b.items[i].err = &BulkItemError{Failure: r}
+		}
+	}
+	for j, i := range kept {
+		item := b.items[i]
+		if f, ok := failed[i]; ok {
+			item.err = &BulkItemError{Failure: f}
+			continue
+		}
+		switch {
+		case j < len(resp.Results):
+			result := resp.Results[j]
+			item.resp = &result
+		case j < len(resp.MessageIDs):
+			item.resp = &SendMessageResponse{ID: resp.MessageIDs[j], Status: resp.Status, To: item.req.To}
+		default:
+			item.resp = &SendMessageResponse{Status: resp.Status, To: item.req.To}
+		}
+	}
+	for _, item := range b.items {
+		close(item.done)
+	}
+}
+
+// exceedsSegmentCap reports whether the long message policy applies to
+// text, which would change the number of messages sent for it or fail it.
+func (c *Client) exceedsSegmentCap(ctx context.Context, text string, encoding Encoding) bool {
+	if c.longMessagePolicy == LongMessageAllow || c.maxSegments <= 0 {
+		return false
+	}
+	return CountSegmentsWithEncoding(withFooter(text, c.footerFor(ctx, text)), encoding) > c.maxSegments
+}
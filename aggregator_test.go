@@ -0,0 +1,229 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func bulkEchoHandler(t *testing.T, calls *int32, sizes chan<- int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/bulk" {
+			t.Errorf("Expected path /send-message/bulk, got %s", r.URL.Path)
+			return
+		}
+		atomic.AddInt32(calls, 1)
+		var req SendBulkMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if sizes != nil {
+			sizes <- len(req.Messages)
+		}
+
+		resp := SendBulkMessageResponse{Total: len(req.Messages), Status: "sent"}
+		for i, m := range req.Messages {
+			resp.Results = append(resp.Results, SendMessageResponse{ID: fmt.Sprintf("msg-%s", m.To), Status: "sent", To: m.To})
+			if m.Message == "fail" {
+				resp.Results[i].Status = "failed"
+				resp.Results[i].Message = "blocked"
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestAggregator_BatchesConcurrentSends(t *testing.T) {
+	var calls int32
+	client := setupTestClient(bulkEchoHandler(t, &calls, nil))
+	agg := client.Messages.Aggregator(WithAggregatorWindow(50 * time.Millisecond))
+	defer agg.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	responses := make([]*SendMessageResponse, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = agg.Send(context.Background(), &SendMessageRequest{
+				To:      fmt.Sprintf("+98912345678%d", i),
+				Message: "Hello",
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 bulk request, got %d", got)
+	}
+	for i := range responses {
+		if errs[i] != nil {
+			t.Fatalf("Unexpected error for message %d: %v", i, errs[i])
+		}
+		want := fmt.Sprintf("msg-+98912345678%d", i)
+		if responses[i].ID != want {
+			t.Errorf("Expected ID %s, got %s", want, responses[i].ID)
+		}
+	}
+}
+
+func TestAggregator_MaxBatch(t *testing.T) {
+	var calls int32
+	sizes := make(chan int, 10)
+	client := setupTestClient(bulkEchoHandler(t, &calls, sizes))
+	agg := client.Messages.Aggregator(WithAggregatorWindow(time.Hour), WithAggregatorMaxBatch(3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := agg.Send(context.Background(), &SendMessageRequest{To: fmt.Sprintf("+98912345678%d", i), Message: "Hi"}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if size := <-sizes; size != 3 {
+		t.Errorf("Expected a batch of 3, got %d", size)
+	}
+	if err := agg.Close(); err != nil {
+		t.Fatalf("Unexpected error on close: %v", err)
+	}
+	if _, err := agg.Send(context.Background(), &SendMessageRequest{To: "+989123456789", Message: "Hi"}); !errors.Is(err, ErrAggregatorClosed) {
+		t.Errorf("Expected ErrAggregatorClosed, got %v", err)
+	}
+}
+
+func TestAggregator_SeparatesIncompatibleMessages(t *testing.T) {
+	var calls int32
+	client := setupTestClient(bulkEchoHandler(t, &calls, nil))
+	agg := client.Messages.Aggregator(WithAggregatorWindow(20 * time.Millisecond))
+	defer agg.Close()
+
+	var wg sync.WaitGroup
+	for _, from := range []string{"1000", "2000"} {
+		wg.Add(1)
+		go func(from string) {
+			defer wg.Done()
+			if _, err := agg.Send(context.Background(), &SendMessageRequest{To: "+989123456789", Message: "Hi", From: from}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}(from)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 bulk requests, got %d", got)
+	}
+}
+
+func TestAggregator_PerMessageFailure(t *testing.T) {
+	var calls int32
+	client := setupTestClient(bulkEchoHandler(t, &calls, nil))
+	agg := client.Messages.Aggregator(WithAggregatorWindow(20 * time.Millisecond))
+	defer agg.Close()
+
+	var wg sync.WaitGroup
+	var okErr, failErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, okErr = agg.Send(context.Background(), &SendMessageRequest{To: "+989123456781", Message: "Hi"})
+	}()
+	go func() {
+		defer wg.Done()
+		_, failErr = agg.Send(context.Background(), &SendMessageRequest{To: "+989123456782", Message: "fail"})
+	}()
+	wg.Wait()
+
+	if okErr != nil {
+		t.Errorf("Unexpected error: %v", okErr)
+	}
+	var itemErr *BulkItemError
+	if !errors.As(failErr, &itemErr) {
+		t.Fatalf("Expected BulkItemError, got %v", failErr)
+	}
+	if itemErr.Failure.Recipient != "+989123456782" || itemErr.Failure.Message != "blocked" {
+		t.Errorf("Unexpected failure: %+v", itemErr.Failure)
+	}
+}
+
+func TestAggregator_CanceledSendLeavesBatch(t *testing.T) {
+	var calls int32
+	sizes := make(chan int, 1)
+	client := setupTestClient(bulkEchoHandler(t, &calls, sizes))
+	agg := client.Messages.Aggregator(WithAggregatorWindow(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := agg.Send(ctx, &SendMessageRequest{To: "+989123456781", Message: "Hi"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	agg.Close()
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no bulk request, got %d", got)
+	}
+}
+
+func TestAggregator_SendsDocumentsIndividually(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/single" {
+			t.Errorf("Expected path /send-message/single, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	}
+	client := setupTestClient(handler)
+	agg := client.Messages.Aggregator()
+	defer agg.Close()
+
+	resp, err := agg.Send(context.Background(), &SendMessageRequest{
+		To:           "+989123456789",
+		Message:      "Invoice",
+		DocumentLink: "https://example.com/invoice.pdf",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.ID != "msg-1" {
+		t.Errorf("Expected ID msg-1, got %s", resp.ID)
+	}
+}
+
+func TestAggregator_SendsParamsIndividually(t *testing.T) {
+	var params map[string]interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/single" {
+			t.Errorf("Expected path /send-message/single, got %s", r.URL.Path)
+		}
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		params = req.Params
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	}
+	client := setupTestClient(handler)
+	agg := client.Messages.Aggregator()
+	defer agg.Close()
+
+	_, err := agg.Send(context.Background(), &SendMessageRequest{
+		To:      "+989123456789",
+		Message: "Welcome",
+		Params:  map[string]interface{}{"campaign": "spring"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if params["campaign"] != "spring" {
+		t.Errorf("Expected Params to reach the API, got %v", params)
+	}
+}
@@ -0,0 +1,280 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSenderClosed is returned by futures of sends submitted after the
+// AsyncSender has been closed.
+var ErrSenderClosed = errors.New("async sender is closed")
+
+// AsyncOption configures an AsyncSender.
+type AsyncOption func(*AsyncSender)
+
+// WithQueueSize sets how many sends may be queued before Send blocks.
+// It defaults to twice the number of workers.
+func WithQueueSize(size int) AsyncOption {
+	return func(a *AsyncSender) {
+		if size >= 0 {
+			a.queueSize = size
+		}
+	}
+}
+
+// WithWorkerRateLimit limits each worker to perSecond sends per second.
+// A value of zero or less disables rate limiting.
+func WithWorkerRateLimit(perSecond float64) AsyncOption {
+	return func(a *AsyncSender) {
+		if perSecond > 0 {
+			a.interval = time.Duration(float64(time.Second) / perSecond)
+		} else {
+			a.interval = 0
+		}
+	}
+}
+
+// WithOrderingKey makes sends with the same key, as returned by key, go
+// out one at a time in the order Send returned for them, so that the parts
+// of a multi-part notification sent one after another reach the recipient
+// in order. Sends with
+// different keys still run in parallel, but a single busy key occupies one
+// worker at a time.
+func WithOrderingKey(key func(req *SendMessageRequest) string) AsyncOption {
+	return func(a *AsyncSender) {
+		a.orderingKey = key
+	}
+}
+
+// WithPerRecipientOrdering makes sends to the same recipient go out in
+// order. It is WithOrderingKey keyed by the recipient.
+func WithPerRecipientOrdering() AsyncOption {
+	return WithOrderingKey(func(req *SendMessageRequest) string {
+		return req.To
+	})
+}
+
+// withAsyncMessages makes the AsyncSender send through api, unless an
+// earlier option already chose the API. It lets a decorator that is not
+// the client's Messages put itself in front of the service.
+func withAsyncMessages(api MessagesAPI) AsyncOption {
+	return func(a *AsyncSender) {
+		if a.messages == nil {
+			a.messages = api
+		}
+	}
+}
+
+// Future is a handle to the result of an asynchronous send.
+type Future struct {
+	seq  uint64
+	done chan struct{}
+	resp *SendMessageResponse
+	err  error
+}
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+func (f *Future) resolve(resp *SendMessageResponse, err error) {
+	f.resp = resp
+	f.err = err
+	close(f.done)
+}
+
+// Sequence returns the position of the send among those the sender queued,
+// starting at 1, or 0 if it was never queued. With WithOrderingKey, sends
+// with the same key made one after another go out in ascending sequence.
+func (f *Future) Sequence() uint64 {
+	return f.seq
+}
+
+// Done returns a channel that is closed once the send has completed.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the send completes or ctx is done.
+func (f *Future) Wait(ctx context.Context) (*SendMessageResponse, error) {
+	select {
+	case <-f.done:
+		return f.resp, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Result blocks until the send completes and returns its outcome.
+func (f *Future) Result() (*SendMessageResponse, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+type asyncJob struct {
+	ctx    context.Context
+	req    *SendMessageRequest
+	future *Future
+}
+
+// AsyncSender sends messages on a bounded pool of workers. Each call to Send
+// returns immediately with a Future that is resolved once the message has
+// been handed to the API.
+type AsyncSender struct {
+	client      *Client
+	messages    MessagesAPI
+	workers     int
+	queueSize   int
+	interval    time.Duration
+	orderingKey func(req *SendMessageRequest) string
+
+	// One queue shared by all workers, or with an ordering key one queue
+	// per worker, each job going to the queue its key hashes to
+	queues []chan *asyncJob
+	seq    uint64
+
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Async starts an AsyncSender backed by the client's Messages, so that
+// sends go through any decorators set on it, with the given number of
+// workers. Close must be called to release the workers.
+func (s *MessagesService) Async(workers int, opts ...AsyncOption) *AsyncSender {
+	if workers < 1 {
+		workers = 1
+	}
+
+	a := &AsyncSender{
+		client:    s.client,
+		workers:   workers,
+		queueSize: workers * 2,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.messages == nil {
+		a.messages = s.client.Messages
+	}
+
+	queues, size := 1, a.queueSize
+	if a.orderingKey != nil {
+		queues, size = a.workers, (a.queueSize+a.workers-1)/a.workers
+	}
+	a.queues = make([]chan *asyncJob, queues)
+	for i := range a.queues {
+		a.queues[i] = make(chan *asyncJob, size)
+	}
+
+	a.wg.Add(a.workers)
+	for i := 0; i < a.workers; i++ {
+		go a.worker(a.queues[i%queues])
+	}
+
+	return a
+}
+
+// Send queues req for delivery. It blocks only while the queue is full; if
+// ctx is done before the message is queued, the Future resolves with the
+// context error.
+func (a *AsyncSender) Send(ctx context.Context, req *SendMessageRequest) *Future {
+	future := newFuture()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		future.resolve(nil, ErrSenderClosed)
+		return future
+	}
+	// The ordering key reads req, so a nil request fails here rather than
+	// in the worker.
+	if req == nil {
+		future.resolve(nil, validateSendMessageRequest(req))
+		return future
+	}
+
+	// The worker may resolve the future as soon as it has the job, so the
+	// sequence is set before the job is published.
+	future.seq = atomic.AddUint64(&a.seq, 1)
+	select {
+	case a.queues[a.queueFor(req)] <- &asyncJob{ctx: ctx, req: req, future: future}:
+	case <-ctx.Done():
+		future.seq = 0
+		future.resolve(nil, ctx.Err())
+	}
+
+	return future
+}
+
+// queueFor returns the index of the queue req goes to.
+func (a *AsyncSender) queueFor(req *SendMessageRequest) int {
+	if len(a.queues) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(a.orderingKey(req)))
+	return int(h.Sum32() % uint32(len(a.queues)))
+}
+
+// Close stops accepting new sends and waits for queued and in-flight sends
+// to complete.
+func (a *AsyncSender) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	for _, queue := range a.queues {
+		close(queue)
+	}
+	a.mu.Unlock()
+
+	a.wg.Wait()
+	return nil
+}
+
+func (a *AsyncSender) worker(jobs <-chan *asyncJob) {
+	defer a.wg.Done()
+
+	var next time.Time
+	for job := range jobs {
+		if a.interval > 0 {
+			if err := sleepUntil(job.ctx, next); err != nil {
+				job.future.resolve(nil, err)
+				continue
+			}
+			next = time.Now().Add(a.interval)
+		}
+
+		resp, err := a.messages.SendSingleMessage(job.ctx, job.req)
+		if err != nil {
+			a.client.reportSendFailure(job.ctx, job.req, err)
+		}
+		job.future.resolve(resp, err)
+	}
+}
+
+// sleepUntil blocks until t or until ctx is done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,194 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncSender_Send(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		n := atomic.AddInt32(&calls, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SendMessageResponse{
+			ID:     fmt.Sprintf("msg-%d", n),
+			Status: "sent",
+			To:     req.To,
+		})
+	}
+
+	client := setupTestClient(handler)
+	sender := client.Messages.Async(4)
+	ctx := context.Background()
+
+	futures := make([]*Future, 0, 10)
+	for i := 0; i < 10; i++ {
+		futures = append(futures, sender.Send(ctx, &SendMessageRequest{
+			To:      "+989123456789",
+			Message: "Test message",
+		}))
+	}
+
+	for _, f := range futures {
+		resp, err := f.Wait(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.ID == "" {
+			t.Error("Expected message ID, got empty string")
+		}
+	}
+
+	if err := sender.Close(); err != nil {
+		t.Fatalf("Unexpected error on close: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 10 {
+		t.Errorf("Expected 10 API calls, got %d", got)
+	}
+}
+
+func TestAsyncSender_CloseFlushesQueue(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	sender := client.Messages.Async(1, WithQueueSize(5))
+	ctx := context.Background()
+
+	futures := make([]*Future, 0, 5)
+	for i := 0; i < 5; i++ {
+		futures = append(futures, sender.Send(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"}))
+	}
+	sender.Close()
+
+	if got := atomic.LoadInt32(&calls); got != 5 {
+		t.Errorf("Expected 5 API calls after close, got %d", got)
+	}
+	for _, f := range futures {
+		select {
+		case <-f.Done():
+		default:
+			t.Error("Expected future to be resolved after close")
+		}
+	}
+
+	_, err := sender.Send(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"}).Result()
+	if !errors.Is(err, ErrSenderClosed) {
+		t.Errorf("Expected ErrSenderClosed, got %v", err)
+	}
+}
+
+func TestAsyncSender_WorkerRateLimit(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	sender := client.Messages.Async(1, WithWorkerRateLimit(20))
+	ctx := context.Background()
+
+	start := time.Now()
+	futures := make([]*Future, 0, 3)
+	for i := 0; i < 3; i++ {
+		futures = append(futures, sender.Send(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"}))
+	}
+	for _, f := range futures {
+		if _, err := f.Result(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	sender.Close()
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected rate limiting to take at least 100ms, took %v", elapsed)
+	}
+}
+
+func TestAsyncSender_ValidationError(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no API call for invalid request")
+	})
+	sender := client.Messages.Async(1)
+	defer sender.Close()
+
+	_, err := sender.Send(context.Background(), &SendMessageRequest{Message: "Test"}).Result()
+	if err == nil {
+		t.Error("Expected validation error, got nil")
+	}
+}
+
+func TestAsyncSender_PerRecipientOrdering(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string][]string)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Message == "part 1" {
+			// Give later parts the chance to overtake the first one
+			time.Sleep(20 * time.Millisecond)
+		}
+		mu.Lock()
+		received[req.To] = append(received[req.To], req.Message)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent", To: req.To})
+	}
+
+	client := setupTestClient(handler)
+	sender := client.Messages.Async(4, WithPerRecipientOrdering())
+	ctx := context.Background()
+
+	recipients := []string{"+989121111111", "+989122222222", "+989123333333"}
+	var last uint64
+	for part := 1; part <= 3; part++ {
+		for _, to := range recipients {
+			f := sender.Send(ctx, &SendMessageRequest{To: to, Message: fmt.Sprintf("part %d", part)})
+			if f.Sequence() <= last {
+				t.Errorf("Expected increasing sequence numbers, got %d after %d", f.Sequence(), last)
+			}
+			last = f.Sequence()
+		}
+	}
+	sender.Close()
+
+	want := []string{"part 1", "part 2", "part 3"}
+	for _, to := range recipients {
+		if got := received[to]; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Expected %v for %s, got %v", want, to, got)
+		}
+	}
+}
+
+func TestAsyncSender_NilRequest(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {})
+	sender := client.Messages.Async(2, WithPerRecipientOrdering())
+	defer sender.Close()
+
+	f := sender.Send(context.Background(), nil)
+	if _, err := f.Result(); !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	if f.Sequence() != 0 {
+		t.Errorf("Expected no sequence for a send that was not queued, got %d", f.Sequence())
+	}
+}
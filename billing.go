@@ -0,0 +1,176 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoBalanceStore is returned by BillingService methods that need the
+// store set with WithBalanceStore when none was set.
+var ErrNoBalanceStore = errors.New("no balance store configured")
+
+// BalancePoint is the account balance at one point in time.
+type BalancePoint struct {
+	Time    time.Time `json:"time"`
+	Balance float64   `json:"balance"`
+	Credit  float64   `json:"credit,omitempty"`
+}
+
+// BalanceStore persists balance snapshots. The API keeps no balance
+// history, so the history is built from snapshots taken by the SDK.
+type BalanceStore interface {
+	// Append records a snapshot.
+	Append(ctx context.Context, point BalancePoint) error
+
+	// Range returns the snapshots taken from from up to but excluding to,
+	// oldest first. A zero bound leaves that side of the range open.
+	Range(ctx context.Context, from, to time.Time) ([]BalancePoint, error)
+}
+
+// WithBalanceStore records the snapshots taken by the Billing service in
+// store, from which BalanceHistory is answered.
+func WithBalanceStore(store BalanceStore) ClientOption {
+	return func(c *Client) {
+		c.balanceStore = store
+	}
+}
+
+// BillingService provides the account's balance over time.
+type BillingService struct {
+	client *Client
+}
+
+// Snapshot reads the current balance and records it in the balance store,
+// if one is set.
+func (s *BillingService) Snapshot(ctx context.Context) (*BalancePoint, error) {
+	info, err := s.client.Messages.GetUserInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	point := BalancePoint{Time: time.Now(), Balance: info.Balance, Credit: info.Credit}
+	if s.client.balanceStore != nil {
+		if err = s.client.balanceStore.Append(ctx, point); err != nil {
+			return nil, fmt.Errorf("failed to save balance snapshot: %w", err)
+		}
+	}
+	return &point, nil
+}
+
+// RunSnapshots takes a snapshot every interval until ctx is done. A failed
+// snapshot leaves a gap in the history rather than stopping the loop; pass
+// onError to be told about it.
+func (s *BillingService) RunSnapshots(ctx context.Context, interval time.Duration, onError func(ctx context.Context, err error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Snapshot(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(ctx, err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// BalanceHistory returns the balance over the days of dateRange, from the
+// snapshots in the balance store. With a granularity above zero, the
+// snapshots are grouped into periods of that length starting at midnight
+// of the first day, in Tehran time unless dateRange.From has another
+// location, and each period is represented by its last snapshot, so that
+// the points are the balance at the end of each period. Periods without
+// snapshots are left out.
+func (s *BillingService) BalanceHistory(ctx context.Context, dateRange DateRange, granularity time.Duration) ([]BalancePoint, error) {
+	if s.client.balanceStore == nil {
+		return nil, ErrNoBalanceStore
+	}
+
+	loc := Tehran
+	if !dateRange.From.IsZero() {
+		loc = dateRange.From.Location()
+	}
+	var from, to time.Time
+	if !dateRange.From.IsZero() {
+		from = startOfDay(dateRange.From, loc)
+	}
+	if !dateRange.To.IsZero() {
+		to = startOfDay(dateRange.To, loc).AddDate(0, 0, 1)
+	}
+
+	points, err := s.client.balanceStore.Range(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balance history: %w", err)
+	}
+	if granularity <= 0 || len(points) == 0 {
+		return points, nil
+	}
+
+	anchor := from
+	if anchor.IsZero() {
+		anchor = startOfDay(points[0].Time, loc)
+	}
+	var history []BalancePoint
+	period := int64(-1)
+	for _, point := range points {
+		p := int64(point.Time.Sub(anchor) / granularity)
+		if p == period {
+			history[len(history)-1] = point
+			continue
+		}
+		period = p
+		history = append(history, point)
+	}
+	return history, nil
+}
+
+// startOfDay returns midnight of the day of t in loc.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// MemoryBalanceStore is a BalanceStore that keeps snapshots in memory. It
+// does not survive restarts, so use it for tests and short-lived processes.
+type MemoryBalanceStore struct {
+	mu     sync.Mutex
+	points []BalancePoint
+}
+
+// NewMemoryBalanceStore creates an empty MemoryBalanceStore.
+func NewMemoryBalanceStore() *MemoryBalanceStore {
+	return &MemoryBalanceStore{}
+}
+
+// Append implements BalanceStore.
+func (s *MemoryBalanceStore) Append(_ context.Context, point BalancePoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := sort.Search(len(s.points), func(i int) bool { return s.points[i].Time.After(point.Time) })
+	s.points = append(s.points, BalancePoint{})
+	copy(s.points[i+1:], s.points[i:])
+	s.points[i] = point
+	return nil
+}
+
+// Range implements BalanceStore.
+func (s *MemoryBalanceStore) Range(_ context.Context, from, to time.Time) ([]BalancePoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var points []BalancePoint
+	for _, point := range s.points {
+		if (from.IsZero() || !point.Time.Before(from)) && (to.IsZero() || point.Time.Before(to)) {
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
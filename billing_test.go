@@ -0,0 +1,82 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBillingService_Snapshot(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/info" {
+			t.Errorf("Expected path '/user/info', got '%s'", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "user-1", "balance": 1500, "credit": 200}`))
+	}
+
+	client := setupTestClient(handler)
+	store := NewMemoryBalanceStore()
+	WithBalanceStore(store)(client)
+	ctx := context.Background()
+
+	point, err := client.Billing.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if point.Balance != 1500 || point.Credit != 200 {
+		t.Errorf("Expected balance 1500 and credit 200, got %+v", point)
+	}
+
+	history, err := client.Billing.BalanceHistory(ctx, DateRange{}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].Balance != 1500 {
+		t.Errorf("Expected the snapshot in the history, got %+v", history)
+	}
+}
+
+func TestBillingService_BalanceHistory(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {})
+	store := NewMemoryBalanceStore()
+	WithBalanceStore(store)(client)
+	ctx := context.Background()
+
+	day := time.Date(2026, 3, 21, 0, 0, 0, 0, Tehran)
+	// Two snapshots a day, appended out of order
+	for _, point := range []BalancePoint{
+		{Time: day.Add(49 * time.Hour), Balance: 600},
+		{Time: day.Add(13 * time.Hour), Balance: 900},
+		{Time: day.Add(time.Hour), Balance: 1000},
+		{Time: day.Add(37 * time.Hour), Balance: 700},
+		{Time: day.Add(25 * time.Hour), Balance: 800},
+	} {
+		store.Append(ctx, point)
+	}
+
+	history, err := client.Billing.BalanceHistory(ctx, DateRange{From: day, To: day.AddDate(0, 0, 1)}, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 daily points, got %+v", history)
+	}
+	if want := day.Add(13 * time.Hour); !history[0].Time.Equal(want) || history[0].Balance != 900 {
+		t.Errorf("Expected the first day to end at 900 at %v, got %+v", want, history[0])
+	}
+	if history[1].Balance != 700 {
+		t.Errorf("Expected the second day to end at 700, got %+v", history[1])
+	}
+}
+
+func TestBillingService_BalanceHistoryWithoutStore(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {})
+
+	_, err := client.Billing.BalanceHistory(context.Background(), DateRange{}, time.Hour)
+	if !errors.Is(err, ErrNoBalanceStore) {
+		t.Errorf("Expected ErrNoBalanceStore, got %v", err)
+	}
+}
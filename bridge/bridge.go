@@ -0,0 +1,320 @@
+// Package bridge consumes send-message commands from a message broker and
+// dispatches them through a SignalAds client, retrying transient failures
+// and publishing commands that cannot be sent to a dead-letter queue.
+//
+// The bridge talks to brokers through the small Consumer, Message and
+// Publisher interfaces, so the SDK does not depend on any broker client.
+// Adapting NATS JetStream or Kafka takes a few lines; see the README for
+// reference adapters.
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+const (
+	DefaultMaxAttempts = 5
+	DefaultConcurrency = 1
+)
+
+// ErrInvalidCommand is wrapped by the errors of commands that cannot be
+// decoded or do not set exactly one request. They are dead-lettered
+// without a send attempt.
+var ErrInvalidCommand = errors.New("invalid command")
+
+// Command is a send-message command, encoded as JSON in broker messages.
+// Exactly one of Message, Template and Voice must be set.
+type Command struct {
+	// Sent as the idempotency key of the send, so that a command
+	// redelivered by the broker is not sent twice by the API (optional)
+	ID string `json:"id,omitempty"`
+
+	Message  *signalads.SendMessageRequest         `json:"message,omitempty"`
+	Template *signalads.SendTemplateMessageRequest `json:"template,omitempty"`
+	Voice    *signalads.SendVoiceMessageRequest    `json:"voice,omitempty"`
+}
+
+// Message is a message received from a broker.
+type Message interface {
+	// Data returns the message payload.
+	Data() []byte
+
+	// Ack confirms that the message has been handled.
+	Ack(ctx context.Context) error
+
+	// Nack asks the broker to redeliver the message later.
+	Nack(ctx context.Context) error
+}
+
+// Consumer receives messages from a broker.
+type Consumer interface {
+	// Receive blocks until a message is available or ctx is done.
+	Receive(ctx context.Context) (Message, error)
+}
+
+// Publisher publishes messages to a broker.
+type Publisher interface {
+	Publish(ctx context.Context, data []byte) error
+}
+
+// DeadLetter is the JSON payload published to the dead-letter queue for a
+// command that could not be sent.
+type DeadLetter struct {
+	// The broker message payload as received
+	Payload string `json:"payload"`
+
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// Option configures a Bridge.
+type Option func(*Bridge)
+
+// WithDeadLetterPublisher publishes commands that fail permanently, or
+// still fail after the last attempt, to p. Without one, such commands are
+// only reported to the error handler and acknowledged.
+func WithDeadLetterPublisher(p Publisher) Option {
+	return func(b *Bridge) {
+		b.deadLetters = p
+	}
+}
+
+// WithMaxAttempts sets how many times a command is tried before it is
+// dead-lettered. It defaults to DefaultMaxAttempts.
+func WithMaxAttempts(n int) Option {
+	return func(b *Bridge) {
+		if n > 0 {
+			b.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the delay before retry attempt n (starting at 1).
+func WithBackoff(backoff func(attempt int) time.Duration) Option {
+	return func(b *Bridge) {
+		if backoff != nil {
+			b.backoff = backoff
+		}
+	}
+}
+
+// WithConcurrency sets how many commands are handled at once. It defaults
+// to DefaultConcurrency.
+func WithConcurrency(n int) Option {
+	return func(b *Bridge) {
+		if n > 0 {
+			b.concurrency = n
+		}
+	}
+}
+
+// WithErrorHandler sets a function called with every command that is
+// dead-lettered and every broker error, for logging.
+func WithErrorHandler(fn func(ctx context.Context, err error)) Option {
+	return func(b *Bridge) {
+		b.onError = fn
+	}
+}
+
+// Bridge dispatches commands from a Consumer through a client.
+type Bridge struct {
+	client      *signalads.Client
+	consumer    Consumer
+	deadLetters Publisher
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	concurrency int
+	onError     func(ctx context.Context, err error)
+}
+
+// New creates a Bridge that sends the commands consumer receives through
+// client.
+func New(client *signalads.Client, consumer Consumer, opts ...Option) *Bridge {
+	b := &Bridge{
+		client:      client,
+		consumer:    consumer,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     exponentialBackoff,
+		concurrency: DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	if attempt > 10 {
+		attempt = 10
+	}
+	return time.Second << uint(attempt-1)
+}
+
+// Run receives and handles commands until ctx is done or the consumer
+// fails, and waits for the commands in progress before returning.
+func (b *Bridge) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	slots := make(chan struct{}, b.concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		msg, err := b.consumer.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to receive command: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if handleErr := b.Handle(ctx, msg); handleErr != nil {
+				b.reportError(ctx, handleErr)
+			}
+		}()
+	}
+}
+
+// Handle sends the command in msg, retrying transient failures, and then
+// acknowledges msg. A command that cannot be sent is dead-lettered and
+// acknowledged. If ctx is done first, the client's RetryBudget denies a
+// retry, or the dead letter cannot be published, msg is negatively
+// acknowledged so that the broker redelivers it.
+func (b *Bridge) Handle(ctx context.Context, msg Message) error {
+	attempts, sendErr := b.dispatch(ctx, msg.Data())
+	if sendErr == nil {
+		return b.ack(ctx, msg)
+	}
+	if ctx.Err() != nil {
+		return b.nack(ctx, msg, ctx.Err())
+	}
+	if errors.Is(sendErr, signalads.ErrRetryBudgetExhausted) {
+		return b.nack(ctx, msg, sendErr)
+	}
+
+	b.reportError(ctx, sendErr)
+	if b.deadLetters != nil {
+		letter, err := json.Marshal(DeadLetter{
+			Payload:  string(msg.Data()),
+			Error:    sendErr.Error(),
+			Attempts: attempts,
+			FailedAt: time.Now(),
+		})
+		if err != nil {
+			return b.nack(ctx, msg, fmt.Errorf("failed to encode dead letter: %w", err))
+		}
+		if err = b.deadLetters.Publish(ctx, letter); err != nil {
+			return b.nack(ctx, msg, fmt.Errorf("failed to publish dead letter: %w", err))
+		}
+	}
+	return b.ack(ctx, msg)
+}
+
+// dispatch decodes and sends a command, returning the number of send
+// attempts made and the last error.
+func (b *Bridge) dispatch(ctx context.Context, data []byte) (int, error) {
+	cmd, err := decodeCommand(data)
+	if err != nil {
+		return 0, err
+	}
+	if cmd.ID != "" {
+		ctx = signalads.WithIdempotencyKey(ctx, cmd.ID)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = b.send(ctx, cmd)
+		if err == nil || !retryable(err) || attempt >= b.maxAttempts {
+			return attempt, err
+		}
+		if budget := b.client.RetryBudget(); budget != nil && !budget.Retry() {
+			return attempt, fmt.Errorf("%w: %w", signalads.ErrRetryBudgetExhausted, err)
+		}
+
+		timer := time.NewTimer(b.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, ctx.Err()
+		}
+	}
+}
+
+func (b *Bridge) send(ctx context.Context, cmd *Command) error {
+	var err error
+	switch {
+	case cmd.Message != nil:
+		_, err = b.client.Messages.SendSingleMessage(ctx, cmd.Message)
+	case cmd.Template != nil:
+		_, err = b.client.Messages.SendTemplateMessage(ctx, cmd.Template)
+	default:
+		_, err = b.client.Messages.SendVoiceMessage(ctx, cmd.Voice)
+	}
+	return err
+}
+
+func decodeCommand(data []byte) (*Command, error) {
+	var cmd Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCommand, err)
+	}
+
+	set := 0
+	for _, present := range []bool{cmd.Message != nil, cmd.Template != nil, cmd.Voice != nil} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("%w: exactly one of message, template and voice must be set", ErrInvalidCommand)
+	}
+	return &cmd, nil
+}
+
+// retryable reports whether a failed send may succeed if repeated: the API
+// was unreachable, rate limiting or unavailable.
+func retryable(err error) bool {
+	var apiErr *signalads.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return signalads.IsTransportError(err)
+}
+
+func (b *Bridge) ack(ctx context.Context, msg Message) error {
+	if err := msg.Ack(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge command: %w", err)
+	}
+	return nil
+}
+
+// nack asks for redelivery of msg after cause, even if ctx is done.
+func (b *Bridge) nack(ctx context.Context, msg Message, cause error) error {
+	if err := msg.Nack(context.WithoutCancel(ctx)); err != nil {
+		return errors.Join(cause, fmt.Errorf("failed to negatively acknowledge command: %w", err))
+	}
+	return cause
+}
+
+func (b *Bridge) reportError(ctx context.Context, err error) {
+	if b.onError != nil {
+		b.onError(ctx, err)
+	}
+}
@@ -0,0 +1,280 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+type fakeMessage struct {
+	data   []byte
+	mu     sync.Mutex
+	acked  bool
+	nacked bool
+}
+
+func (m *fakeMessage) Data() []byte { return m.data }
+
+func (m *fakeMessage) Ack(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acked = true
+	return nil
+}
+
+func (m *fakeMessage) Nack(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nacked = true
+	return nil
+}
+
+func (m *fakeMessage) state() (acked, nacked bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.acked, m.nacked
+}
+
+type chanConsumer chan Message
+
+func (c chanConsumer) Receive(ctx context.Context) (Message, error) {
+	select {
+	case msg := <-c:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type recordingPublisher struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, data []byte) error {
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.letters = append(p.letters, letter)
+	return nil
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *signalads.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return signalads.NewClient("key", "secret", signalads.WithBaseURL(server.URL))
+}
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestHandle_Success(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/template" {
+			t.Errorf("Expected path /send-message/template, got %s", r.URL.Path)
+		}
+		if key := r.Header.Get("Idempotency-Key"); key != "cmd-1" {
+			t.Errorf("Expected idempotency key cmd-1, got %q", key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	})
+	b := New(client, nil)
+
+	msg := &fakeMessage{data: []byte(`{"id": "cmd-1", "template": {"to": "+989123456789", "template_id": "welcome"}}`)}
+	if err := b.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if acked, _ := msg.state(); !acked {
+		t.Error("Expected message to be acknowledged")
+	}
+}
+
+func TestHandle_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message": "unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	})
+	dlq := &recordingPublisher{}
+	b := New(client, nil, WithBackoff(noBackoff), WithDeadLetterPublisher(dlq))
+
+	msg := &fakeMessage{data: []byte(`{"message": {"to": "+989123456789", "message": "hi"}}`)}
+	if err := b.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+	if len(dlq.letters) != 0 {
+		t.Errorf("Expected no dead letters, got %+v", dlq.letters)
+	}
+}
+
+func TestHandle_DeadLetters(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		payload      string
+		wantAttempts int
+	}{
+		{"permanent failure", http.StatusBadRequest, `{"message": {"to": "+989123456789", "message": "hi"}}`, 1},
+		{"attempts exhausted", http.StatusInternalServerError, `{"message": {"to": "+989123456789", "message": "hi"}}`, 2},
+		{"invalid json", http.StatusOK, `not json`, 0},
+		{"no request", http.StatusOK, `{"id": "cmd-1"}`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message": "failed"}`))
+			})
+			dlq := &recordingPublisher{}
+			var reported []error
+			b := New(client, nil,
+				WithBackoff(noBackoff),
+				WithMaxAttempts(2),
+				WithDeadLetterPublisher(dlq),
+				WithErrorHandler(func(_ context.Context, err error) { reported = append(reported, err) }),
+			)
+
+			msg := &fakeMessage{data: []byte(tt.payload)}
+			if err := b.Handle(context.Background(), msg); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if acked, _ := msg.state(); !acked {
+				t.Error("Expected dead-lettered message to be acknowledged")
+			}
+			if int(calls.Load()) != tt.wantAttempts {
+				t.Errorf("Expected %d requests, got %d", tt.wantAttempts, calls.Load())
+			}
+			if len(dlq.letters) != 1 {
+				t.Fatalf("Expected 1 dead letter, got %d", len(dlq.letters))
+			}
+			letter := dlq.letters[0]
+			if letter.Payload != tt.payload || letter.Attempts != tt.wantAttempts || letter.Error == "" {
+				t.Errorf("Unexpected dead letter: %+v", letter)
+			}
+			if len(reported) != 1 {
+				t.Errorf("Expected 1 reported error, got %d", len(reported))
+			}
+			if tt.wantAttempts == 0 && !errors.Is(reported[0], ErrInvalidCommand) {
+				t.Errorf("Expected ErrInvalidCommand, got %v", reported[0])
+			}
+		})
+	}
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, []byte) error {
+	return errors.New("broker down")
+}
+
+func TestHandle_NacksWhenDeadLetterFails(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "bad"}`))
+	})
+	b := New(client, nil, WithDeadLetterPublisher(failingPublisher{}))
+
+	msg := &fakeMessage{data: []byte(`{"message": {"to": "+989123456789", "message": "hi"}}`)}
+	if err := b.Handle(context.Background(), msg); err == nil {
+		t.Fatal("Expected an error when the dead letter cannot be published")
+	}
+	if acked, nacked := msg.state(); acked || !nacked {
+		t.Errorf("Expected message to be negatively acknowledged, got acked=%v nacked=%v", acked, nacked)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	})
+	consumer := make(chanConsumer, 10)
+	messages := make([]*fakeMessage, 5)
+	for i := range messages {
+		messages[i] = &fakeMessage{data: []byte(`{"voice": {"to": "+989123456789", "message": "hello"}}`)}
+		consumer <- messages[i]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := New(client, consumer, WithConcurrency(3))
+	done := make(chan error, 1)
+	go func() { done <- b.Run(ctx) }()
+
+	allAcked := func() bool {
+		for _, msg := range messages {
+			if acked, _ := msg.state(); !acked {
+				return false
+			}
+		}
+		return true
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !allAcked() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if !allAcked() {
+		t.Error("Expected all messages to be acknowledged")
+	}
+	if calls.Load() != 5 {
+		t.Errorf("Expected 5 requests, got %d", calls.Load())
+	}
+}
+
+func TestHandle_NacksWhenRetryBudgetExhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message": "unavailable"}`))
+	}))
+	defer server.Close()
+	client := signalads.NewClient("key", "secret",
+		signalads.WithBaseURL(server.URL),
+		signalads.WithRetryBudget(signalads.NewRetryBudget(0, 0, time.Minute)),
+	)
+	dlq := &recordingPublisher{}
+	b := New(client, nil, WithBackoff(noBackoff), WithDeadLetterPublisher(dlq))
+
+	msg := &fakeMessage{data: []byte(`{"message": {"to": "+989123456789", "message": "hi"}}`)}
+	err := b.Handle(context.Background(), msg)
+	if !errors.Is(err, signalads.ErrRetryBudgetExhausted) {
+		t.Errorf("Expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if acked, nacked := msg.state(); acked || !nacked {
+		t.Errorf("Expected message to be negatively acknowledged, got acked=%v nacked=%v", acked, nacked)
+	}
+	if calls.Load() != 1 || len(dlq.letters) != 0 {
+		t.Errorf("Expected 1 request and no dead letters, got %d and %d", calls.Load(), len(dlq.letters))
+	}
+}
@@ -0,0 +1,169 @@
+package signalads
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+)
+
+// maxPooledBufferSize keeps unusually large bulk payloads from being
+// retained by the pool after they have been sent.
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// WithRequestCompression gzips request bodies of at least minSize bytes and
+// marks them with Content-Encoding: gzip. Only enable it if the API endpoint
+// accepts compressed requests.
+func WithRequestCompression(minSize int) ClientOption {
+	return func(c *Client) {
+		c.compressRequests = true
+		c.compressMinSize = minSize
+	}
+}
+
+// encodeBody marshals body into a pooled buffer, as a form if it is
+// url.Values and as JSON otherwise, compressing it when request compression
+// applies. The caller owns the returned buffer and must hand it back with
+// putBuffer.
+func (c *Client) encodeBody(body interface{}) (buf *bytes.Buffer, gzipped bool, err error) {
+	buf = getBuffer()
+	if form, ok := body.(url.Values); ok {
+		buf.WriteString(form.Encode())
+	} else if err := json.NewEncoder(buf).Encode(body); err != nil {
+		putBuffer(buf)
+		return nil, false, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	if c.maxRequestSize > 0 && int64(buf.Len()) > c.maxRequestSize {
+		size := buf.Len()
+		putBuffer(buf)
+		return nil, false, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrRequestTooLarge, size, c.maxRequestSize)
+	}
+
+	if !c.compressRequests || buf.Len() < c.compressMinSize {
+		return buf, false, nil
+	}
+
+	compressed := getBuffer()
+	zw := gzipWriterPool.Get().(*gzip.Writer)
+	zw.Reset(compressed)
+	_, err = zw.Write(buf.Bytes())
+	if err == nil {
+		err = zw.Close()
+	}
+	gzipWriterPool.Put(zw)
+	putBuffer(buf)
+
+	if err != nil {
+		putBuffer(compressed)
+		return nil, false, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	return compressed, true, nil
+}
+
+// pooledBody is a request body that returns its buffer to the pool once the
+// transport has closed it and every replay made through getBody.
+type pooledBody struct {
+	mu     sync.Mutex
+	reader *bytes.Reader
+	owner  *pooledBuffer
+}
+
+// pooledBuffer is a pooled buffer shared by a request body and its replays.
+// It goes back to the pool when the last reference is released.
+type pooledBuffer struct {
+	mu   sync.Mutex
+	buf  *bytes.Buffer
+	refs int
+}
+
+func newPooledBody(buf *bytes.Buffer) *pooledBody {
+	owner := &pooledBuffer{buf: buf, refs: 1}
+	return &pooledBody{reader: bytes.NewReader(buf.Bytes()), owner: owner}
+}
+
+// hold keeps the buffer out of the pool, so that getBody can still replay
+// it after the transport closes the body, until the returned function is
+// called.
+func (b *pooledBody) hold() (release func()) {
+	b.owner.mu.Lock()
+	b.owner.refs++
+	b.owner.mu.Unlock()
+
+	var once sync.Once
+	return func() { once.Do(b.owner.release) }
+}
+
+// getBody returns a fresh body over the same bytes, for use as
+// http.Request.GetBody when a redirect or a retry on a stale connection
+// has to send the body again.
+func (b *pooledBody) getBody() (io.ReadCloser, error) {
+	b.owner.mu.Lock()
+	defer b.owner.mu.Unlock()
+
+	if b.owner.buf == nil {
+		return nil, errors.New("request body already released")
+	}
+	b.owner.refs++
+	return &pooledBody{reader: bytes.NewReader(b.owner.buf.Bytes()), owner: b.owner}, nil
+}
+
+func (b *pooledBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.reader == nil {
+		return 0, io.EOF
+	}
+	return b.reader.Read(p)
+}
+
+func (b *pooledBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.reader != nil {
+		b.reader = nil
+		b.owner.release()
+	}
+	return nil
+}
+
+func (p *pooledBuffer) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.refs--
+	if p.refs == 0 && p.buf != nil {
+		putBuffer(p.buf)
+		p.buf = nil
+	}
+}
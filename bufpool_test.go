@@ -0,0 +1,150 @@
+package signalads
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_RequestCompression(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Fatalf("Failed to open gzip body: %v", err)
+			}
+			reader = zr
+		} else if r.URL.Path == "/large" {
+			t.Error("Expected large body to be gzipped")
+		}
+
+		var req SendBulkMessageRequest
+		if err := json.NewDecoder(reader).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SendBulkMessageResponse{Total: len(req.Messages), Status: "success"})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithRequestCompression(1024))
+	ctx := context.Background()
+
+	large := make([]BulkMessageItem, 100)
+	for i := range large {
+		large[i] = BulkMessageItem{To: "+989123456789", Message: strings.Repeat("x", 50)}
+	}
+
+	var result SendBulkMessageResponse
+	if err := client.Post(ctx, "/large", &SendBulkMessageRequest{Messages: large}, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Total != 100 {
+		t.Errorf("Expected total 100, got %d", result.Total)
+	}
+
+	small := []BulkMessageItem{{To: "+989123456789", Message: "Hi"}}
+	if err := client.Post(ctx, "/small", &SendBulkMessageRequest{Messages: small}, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Total != 1 {
+		t.Errorf("Expected total 1, got %d", result.Total)
+	}
+}
+
+func TestPooledBody_Close(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("payload")
+
+	body := newPooledBody(buf)
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, []byte("payload")) {
+		t.Errorf("Expected 'payload', got %q", data)
+	}
+
+	body.Close()
+	body.Close()
+	if n, err := body.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Expected EOF after close, got %d, %v", n, err)
+	}
+}
+
+func TestPooledBody_GetBody(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("payload")
+
+	body := newPooledBody(buf)
+	release := body.hold()
+	io.ReadAll(body)
+	body.Close()
+
+	replay, err := body.getBody()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(replay)
+	if string(data) != "payload" {
+		t.Errorf("Expected replayed 'payload', got %q", data)
+	}
+	replay.Close()
+
+	release()
+	release()
+	if _, err := body.getBody(); err == nil {
+		t.Error("Expected an error replaying a released body, got nil")
+	}
+}
+
+func TestClient_PostFollowsPermanentRedirect(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusPermanentRedirect)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		got = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL))
+	if err := client.Post(context.Background(), "/old", map[string]string{"to": "+989123456789"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != "{\"to\":\"+989123456789\"}\n" {
+		t.Errorf("Expected the body to be sent again after the redirect, got %q", got)
+	}
+}
+
+func BenchmarkBulkMarshal(b *testing.B) {
+	client := NewClient("test-key", "test-secret")
+	req := &SendBulkMessageRequest{Messages: make([]BulkMessageItem, 1000)}
+	for i := range req.Messages {
+		req.Messages[i] = BulkMessageItem{To: "+989123456789", Message: "Your order has shipped and will arrive tomorrow"}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _, err := client.encodeBody(req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		putBuffer(buf)
+	}
+}
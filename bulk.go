@@ -0,0 +1,128 @@
+package signalads
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// UnmarshalJSON accepts the field spellings used by different API versions:
+// "recipient", "to" or "phone" for the recipient, "code" or "error_code" for
+// the code, and "message" or "error" for the description.
+func (f *BulkFailure) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Index     *int   `json:"index"`
+		Recipient string `json:"recipient"`
+		To        string `json:"to"`
+		Phone     string `json:"phone"`
+		Code      string `json:"code"`
+		ErrorCode string `json:"error_code"`
+		Message   string `json:"message"`
+		Error     string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*f = BulkFailure{
+		Index:     -1,
+		Recipient: firstNonEmpty(raw.Recipient, raw.To, raw.Phone),
+		Code:      firstNonEmpty(raw.Code, raw.ErrorCode),
+		Message:   firstNonEmpty(raw.Message, raw.Error),
+	}
+	if raw.Index != nil {
+		f.Index = *raw.Index
+	}
+	return nil
+}
+
+// FailedRecipients returns the phone numbers of messages that were not
+// accepted, in request order.
+func (r *SendBulkMessageResponse) FailedRecipients() []string {
+	recipients := make([]string, 0, len(r.FailedItems))
+	for i := range r.FailedItems {
+		recipients = append(recipients, r.FailedItems[i].Recipient)
+	}
+	return recipients
+}
+
+// fillFailedItems derives FailedItems from per-message results when the API
+// reports failures only there. Request order is used to recover recipients
+// and indexes that the results omit.
+func (r *SendBulkMessageResponse) fillFailedItems(req *SendBulkMessageRequest) {
+	if len(r.FailedItems) > 0 {
+		for i := range r.FailedItems {
+			item := &r.FailedItems[i]
+			if item.Recipient == "" && item.Index >= 0 && item.Index < len(req.Messages) {
+				item.Recipient = req.Messages[item.Index].To
+			}
+		}
+		return
+	}
+
+	for i := range r.Results {
+		result := &r.Results[i]
+		if !isFailedStatus(result.Status) {
+			continue
+		}
+
+		failure := BulkFailure{
+			Index:     i,
+			Recipient: result.To,
+			Message:   result.Message,
+		}
+		if failure.Recipient == "" && i < len(req.Messages) {
+			failure.Recipient = req.Messages[i].To
+		}
+		if code, ok := result.Data["error_code"].(string); ok {
+			failure.Code = code
+		}
+		r.FailedItems = append(r.FailedItems, failure)
+	}
+}
+
+func isFailedStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "failed", "error", "rejected":
+		return true
+	default:
+		return false
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// sentMessages returns the accepted messages of a bulk send. Recipients are
+// taken from the results, or from the request when the API returns only IDs
+// in request order.
+func (r *SendBulkMessageResponse) sentMessages(req *SendBulkMessageRequest) []SendMessageResponse {
+	var sent []SendMessageResponse
+	if len(r.Results) > 0 {
+		for i := range r.Results {
+			result := r.Results[i]
+			if result.ID == "" || isFailedStatus(result.Status) {
+				continue
+			}
+			if result.To == "" && i < len(req.Messages) {
+				result.To = req.Messages[i].To
+			}
+			sent = append(sent, result)
+		}
+		return sent
+	}
+
+	for i, id := range r.MessageIDs {
+		msg := SendMessageResponse{ID: id}
+		if len(r.MessageIDs) == len(req.Messages) {
+			msg.To = req.Messages[i].To
+		}
+		sent = append(sent, msg)
+	}
+	return sent
+}
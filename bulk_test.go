@@ -0,0 +1,75 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSendBulkMessages_FailedItems(t *testing.T) {
+	req := &SendBulkMessageRequest{
+		Messages: []BulkMessageItem{
+			{To: "+989123456789", Message: "Message 1"},
+			{To: "+989123456790", Message: "Message 2"},
+			{To: "+989123456791", Message: "Message 3"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []BulkFailure
+	}{
+		{
+			name: "explicit failed items",
+			body: `{"total":3,"success":2,"failed":1,"status":"partial","failed_items":[{"index":1,"to":"+989123456790","error_code":"INVALID_PHONE_NUMBER","error":"Invalid phone number"}]}`,
+			expected: []BulkFailure{
+				{Index: 1, Recipient: "+989123456790", Code: ErrCodeInvalidPhoneNumber, Message: "Invalid phone number"},
+			},
+		},
+		{
+			name: "failed item without recipient",
+			body: `{"total":3,"failed":1,"failed_items":[{"index":2,"code":"BLACKLISTED"}]}`,
+			expected: []BulkFailure{
+				{Index: 2, Recipient: "+989123456791", Code: "BLACKLISTED"},
+			},
+		},
+		{
+			name: "derived from results",
+			body: `{"total":3,"failed":2,"results":[{"status":"sent"},{"status":"failed","message":"Blocked"},{"status":"error","to":"+989123456791","data":{"error_code":"FILTERED"}}]}`,
+			expected: []BulkFailure{
+				{Index: 1, Recipient: "+989123456790", Message: "Blocked"},
+				{Index: 2, Recipient: "+989123456791", Code: "FILTERED"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			})
+
+			response, err := client.Messages.SendBulkMessages(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(response.FailedItems, tt.expected) {
+				t.Errorf("Expected failed items %+v, got %+v", tt.expected, response.FailedItems)
+			}
+
+			recipients := response.FailedRecipients()
+			if len(recipients) != len(tt.expected) {
+				t.Fatalf("Expected %d failed recipients, got %v", len(tt.expected), recipients)
+			}
+			for i := range recipients {
+				if recipients[i] != tt.expected[i].Recipient {
+					t.Errorf("Expected recipient '%s', got '%s'", tt.expected[i].Recipient, recipients[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package signalads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CacheStore stores raw response bodies for cached GET endpoints.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+// WithCache caches successful GET responses for the endpoints listed in
+// ttlByEndpoint, keyed by account, base URL, endpoint path (for example
+// "/user/info") and query parameters, so that clients for different accounts
+// or environments can share a store. Entries can be dropped early with
+// Client.InvalidateCache. The client has no pricing endpoint, so pricing is
+// not cached.
+func WithCache(store CacheStore, ttlByEndpoint map[string]time.Duration) ClientOption {
+	return func(c *Client) {
+		ttls := make(map[string]time.Duration, len(ttlByEndpoint))
+		for endpoint, ttl := range ttlByEndpoint {
+			if ttl > 0 {
+				ttls[endpoint] = ttl
+			}
+		}
+		c.cache = &responseCache{
+			store: store,
+			ttls:  ttls,
+			keys:  make(map[string]map[string]bool),
+		}
+	}
+}
+
+// responseCache remembers the keys it has written per endpoint, so that an
+// endpoint can be invalidated without the store supporting enumeration.
+type responseCache struct {
+	store CacheStore
+	ttls  map[string]time.Duration
+
+	mu   sync.Mutex
+	keys map[string]map[string]bool
+}
+
+// cacheKey identifies a response by the account and base URL it was fetched
+// with, so that a shared store never serves one account's data to another.
+// The API key is hashed rather than stored in the key.
+func cacheKey(baseURL, apiKey, endpoint string, queryParams map[string]string) string {
+	account := sha256.Sum256([]byte(baseURL + "\x00" + apiKey))
+
+	q := make(url.Values, len(queryParams))
+	for k, v := range queryParams {
+		q.Set(k, v)
+	}
+	return hex.EncodeToString(account[:8]) + endpoint + "?" + q.Encode()
+}
+
+func (rc *responseCache) set(endpoint, key string, body []byte, ttl time.Duration) {
+	rc.mu.Lock()
+	if rc.keys[endpoint] == nil {
+		rc.keys[endpoint] = make(map[string]bool)
+	}
+	rc.keys[endpoint][key] = true
+	rc.mu.Unlock()
+
+	rc.store.Set(key, body, ttl)
+}
+
+// InvalidateCache deletes cached responses for the given endpoints, or for
+// all endpoints when none are given, from the store. Only entries written by
+// this client are deleted; entries written by other processes sharing the
+// store expire with their TTL. It is a no-op when caching is disabled.
+func (c *Client) InvalidateCache(endpoints ...string) {
+	if c.cache == nil {
+		return
+	}
+
+	c.cache.mu.Lock()
+	if len(endpoints) == 0 {
+		for endpoint := range c.cache.keys {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	var keys []string
+	for _, endpoint := range endpoints {
+		for key := range c.cache.keys[endpoint] {
+			keys = append(keys, key)
+		}
+		delete(c.cache.keys, endpoint)
+	}
+	c.cache.mu.Unlock()
+
+	for _, key := range keys {
+		c.cache.store.Delete(key)
+	}
+}
+
+func (c *Client) cachedGet(ctx context.Context, endpoint string, result interface{}, queryParams map[string]string, ttl time.Duration) error {
+	creds, err := c.currentCredentials().Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	key := cacheKey(c.BaseURL(), creds.APIKey, endpoint, queryParams)
+	if !cacheBypassed(ctx) {
+		if body, ok := c.cache.store.Get(key); ok {
+			if err := c.decodeBody(body, result); err != nil {
+				return err
+			}
+			c.attachRaw(result, body, true)
+			return nil
+		}
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, queryParams)
+	if err != nil {
+		return err
+	}
+	body, err := c.readResponse(resp)
+	if err != nil {
+		return err
+	}
+	if err := c.decodeBody(body, result); err != nil {
+		return err
+	}
+	c.attachRaw(result, body, true)
+	if c.strictDecoding && resp.Request != nil {
+		c.checkUnknownFields(ctx, resp.Request.URL.Path, body, result)
+	}
+
+	c.cache.set(endpoint, key, body, ttl)
+	return nil
+}
+
+// MemoryCache is an in-process CacheStore with per-entry expiry.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+// Get implements CacheStore.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set implements CacheStore. Expired entries are swept on each call.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = memoryCacheEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// Delete implements CacheStore.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+}
@@ -0,0 +1,146 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Cache(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(UserInfo{ID: "user-123", Balance: 100})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithCache(NewMemoryCache(), map[string]time.Duration{"/user/info": time.Minute}),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		info, err := client.Messages.GetUserInfo(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.ID != "user-123" {
+			t.Errorf("Expected ID 'user-123', got '%s'", info.ID)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 API call, got %d", got)
+	}
+
+	client.InvalidateCache("/user/info")
+	client.Messages.GetUserInfo(ctx)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 API calls after invalidation, got %d", got)
+	}
+
+	client.InvalidateCache()
+	client.Messages.GetUserInfo(ctx)
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 API calls after full invalidation, got %d", got)
+	}
+}
+
+func TestClient_CacheSkipsUnlistedAndErrors(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithCache(NewMemoryCache(), map[string]time.Duration{"/user/info": time.Minute}),
+	)
+	ctx := context.Background()
+
+	client.Messages.GetUserInfo(ctx)
+	client.Messages.GetUserInfo(ctx)
+	client.Messages.ListMessages(ctx, nil)
+	client.Messages.ListMessages(ctx, nil)
+
+	if got := atomic.LoadInt32(&calls); got != 4 {
+		t.Errorf("Expected 4 API calls, got %d", got)
+	}
+}
+
+func TestClient_CacheKeyedByAccount(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UserInfo{ID: r.Header.Get("X-API-Key")})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	store := NewMemoryCache()
+	ttls := map[string]time.Duration{"/user/info": time.Minute}
+	ctx := context.Background()
+
+	for _, key := range []string{"key-a", "key-b"} {
+		client := NewClient(key, "test-secret", WithBaseURL(server.URL), WithCache(store, ttls))
+		info, err := client.Messages.GetUserInfo(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.ID != key {
+			t.Errorf("Expected the response for %s, got '%s'", key, info.ID)
+		}
+	}
+}
+
+func TestClient_InvalidateCacheDeletesEntries(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UserInfo{ID: "user-123"})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	store := NewMemoryCache()
+	ttls := map[string]time.Duration{"/user/info": time.Minute}
+	ctx := context.Background()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithCache(store, ttls))
+	client.Messages.GetUserInfo(ctx)
+	client.InvalidateCache()
+	if len(store.entries) != 0 {
+		t.Errorf("Expected invalidation to delete the stored entries, got %d", len(store.entries))
+	}
+
+	// A restarted client sharing the store must not see the dropped entry.
+	restarted := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithCache(store, ttls))
+	restarted.Messages.GetUserInfo(ctx)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 API calls, got %d", got)
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("key", []byte("value"), time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected entry to expire")
+	}
+}
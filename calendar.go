@@ -0,0 +1,309 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrBlackoutDate is wrapped by the *BlackoutError returned for sends on a
+// blackout date.
+var ErrBlackoutDate = errors.New("sending is blacked out on this date")
+
+// BlackoutError is returned, before anything is sent, when a send or
+// schedule falls on a blackout date of the client's Calendar.
+type BlackoutError struct {
+	Date   time.Time
+	Reason string
+}
+
+func (e *BlackoutError) Error() string {
+	return fmt.Sprintf("%v: %s (%s)", ErrBlackoutDate, e.Date.Format(time.DateOnly), e.Reason)
+}
+
+func (e *BlackoutError) Unwrap() error {
+	return ErrBlackoutDate
+}
+
+// Tehran is Iran Standard Time. Iran no longer observes daylight saving
+// time, so a fixed zone is used rather than relying on the system's time
+// zone database.
+var Tehran = time.FixedZone("IRST", 3*60*60+30*60)
+
+// Holiday is a named day.
+type Holiday struct {
+	Date time.Time
+	Name string
+
+	// Whether the day is a religious mourning day, on which promotional
+	// messages are especially inappropriate
+	Mourning bool
+}
+
+// Calendar holds the days on which messages must not be sent. Days are
+// evaluated in the calendar's location. A Calendar is safe for concurrent
+// use.
+type Calendar struct {
+	loc *time.Location
+
+	mu        sync.RWMutex
+	blackouts map[string]string
+	exempt    map[Category]bool
+}
+
+// NewCalendar returns an empty calendar evaluating days in loc, or in
+// Tehran when loc is nil. CategoryOTP is exempt, so that verification codes
+// are still sent on blackout days; use Unexempt to change that.
+func NewCalendar(loc *time.Location) *Calendar {
+	if loc == nil {
+		loc = Tehran
+	}
+	return &Calendar{loc: loc, blackouts: make(map[string]string), exempt: map[Category]bool{CategoryOTP: true}}
+}
+
+// Exempt lets messages of the given categories, such as
+// CategoryTransactional, be sent on blackout days.
+func (c *Calendar) Exempt(categories ...Category) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, category := range categories {
+		c.exempt[category] = true
+	}
+}
+
+// Unexempt makes blackout days apply to messages of the given categories
+// again.
+func (c *Calendar) Unexempt(categories ...Category) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, category := range categories {
+		delete(c.exempt, category)
+	}
+}
+
+// Add blacks out the day containing date.
+func (c *Calendar) Add(date time.Time, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blackouts[c.dayKey(date)] = reason
+}
+
+// AddRange blacks out every day from from to to, inclusive.
+func (c *Calendar) AddRange(from, to time.Time, reason string) {
+	last := c.dayKey(to)
+	from = from.In(c.loc)
+	for d := time.Date(from.Year(), from.Month(), from.Day(), 12, 0, 0, 0, c.loc); c.dayKey(d) <= last; d = d.AddDate(0, 0, 1) {
+		c.Add(d, reason)
+	}
+}
+
+// AddHolidays blacks out every day in holidays.
+func (c *Calendar) AddHolidays(holidays []Holiday) {
+	for _, h := range holidays {
+		c.Add(h.Date, h.Name)
+	}
+}
+
+// Check returns a *BlackoutError if t falls on a blackout day.
+func (c *Calendar) Check(t time.Time) error {
+	c.mu.RLock()
+	reason, ok := c.blackouts[c.dayKey(t)]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return &BlackoutError{Date: t.In(c.loc), Reason: reason}
+}
+
+func (c *Calendar) dayKey(t time.Time) string {
+	return t.In(c.loc).Format(time.DateOnly)
+}
+
+// WithCalendar makes every send, and every reschedule to a new time, fail
+// with a *BlackoutError on the calendar's blackout days, except for the
+// calendar's exempt categories, CategoryOTP by default. Use
+// WithCalendarOverride to send other messages through it.
+func WithCalendar(cal *Calendar) ClientOption {
+	return func(c *Client) {
+		c.calendar = cal
+	}
+}
+
+type calendarContextKey struct{}
+
+// WithCalendarOverride returns a context whose sends are checked against
+// cal instead of the client's calendar. A nil cal disables the check.
+func WithCalendarOverride(ctx context.Context, cal *Calendar) context.Context {
+	return context.WithValue(ctx, calendarContextKey{}, cal)
+}
+
+// checkCalendar returns a *BlackoutError if t is a blackout day of the
+// calendar in effect for ctx.
+func (c *Client) checkCalendar(ctx context.Context, t time.Time) error {
+	cal := c.calendar
+	if override, ok := ctx.Value(calendarContextKey{}).(*Calendar); ok {
+		cal = override
+	}
+	if cal == nil {
+		return nil
+	}
+	if category, ok := CategoryFromContext(ctx); ok && cal.exempted(category) {
+		return nil
+	}
+	return cal.Check(t)
+}
+
+func (c *Calendar) exempted(category Category) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.exempt[category]
+}
+
+type jalaliHoliday struct {
+	month, day int
+	name       string
+	mourning   bool
+}
+
+type hijriHoliday struct {
+	month, day int
+	name       string
+	mourning   bool
+}
+
+// iranSolarHolidays are the public holidays fixed in the Solar Hijri
+// calendar.
+var iranSolarHolidays = []jalaliHoliday{
+	{1, 1, "Nowruz", false},
+	{1, 2, "Nowruz", false},
+	{1, 3, "Nowruz", false},
+	{1, 4, "Nowruz", false},
+	{1, 12, "Islamic Republic Day", false},
+	{1, 13, "Nature Day", false},
+	{3, 14, "Death of Imam Khomeini", true},
+	{3, 15, "15 Khordad Uprising", false},
+	{11, 22, "Victory of the Islamic Revolution", false},
+	{12, 29, "Nationalization of the Oil Industry", false},
+}
+
+// iranLunarHolidays are the public holidays fixed in the lunar Hijri
+// calendar.
+var iranLunarHolidays = []hijriHoliday{
+	{1, 9, "Tasua", true},
+	{1, 10, "Ashura", true},
+	{2, 20, "Arbaeen", true},
+	{2, 28, "Demise of the Prophet and Martyrdom of Imam Hasan", true},
+	{2, 29, "Martyrdom of Imam Reza", true},
+	{3, 8, "Martyrdom of Imam Hasan al-Askari", true},
+	{3, 17, "Birth of the Prophet", false},
+	{6, 3, "Martyrdom of Fatimah", true},
+	{7, 13, "Birth of Imam Ali", false},
+	{7, 27, "Mab'ath", false},
+	{8, 15, "Birth of Imam Mahdi", false},
+	{9, 21, "Martyrdom of Imam Ali", true},
+	{10, 1, "Eid al-Fitr", false},
+	{10, 2, "Eid al-Fitr", false},
+	{10, 25, "Martyrdom of Imam Sadiq", true},
+	{12, 10, "Eid al-Adha", false},
+	{12, 18, "Eid al-Ghadir", false},
+}
+
+// IranianPublicHolidays returns Iran's official public holidays falling in
+// the Gregorian year, sorted by date. Solar holidays are exact. Lunar
+// holidays are computed with the tabular Islamic calendar and can differ by
+// a day from the dates announced each year from moon sighting; add the
+// announced dates with Calendar.Add where that matters.
+func IranianPublicHolidays(year int) []Holiday {
+	var holidays []Holiday
+
+	for jy := year - 622; jy <= year-621; jy++ {
+		for _, h := range iranSolarHolidays {
+			date := jdnToDate(jalaliToJDN(jy, h.month, h.day))
+			if date.Year() == year {
+				holidays = append(holidays, Holiday{Date: date, Name: h.name, Mourning: h.mourning})
+			}
+		}
+	}
+
+	// A Gregorian year overlaps two or three lunar years.
+	firstHY := (year-622)*33/32 - 1
+	for hy := firstHY; hy <= firstHY+3; hy++ {
+		for _, h := range iranLunarHolidays {
+			date := jdnToDate(hijriToJDN(hy, h.month, h.day))
+			if date.Year() == year {
+				holidays = append(holidays, Holiday{Date: date, Name: h.name, Mourning: h.mourning})
+			}
+		}
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// jalaliBreaks are the years in which the Jalali leap cycle changes.
+var jalaliBreaks = []int{
+	-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210,
+	1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178,
+}
+
+// jalaliMarch returns the Gregorian year in which Jalali year jy begins and
+// the day of March on which it begins.
+func jalaliMarch(jy int) (gy, march int) {
+	gy = jy + 621
+	leapJ := -14
+	jp := jalaliBreaks[0]
+	jump := 0
+	for _, jm := range jalaliBreaks[1:] {
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := jy - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	return gy, 20 + leapJ - leapG
+}
+
+// jalaliToJDN returns the Julian Day Number of a Jalali date.
+func jalaliToJDN(jy, jm, jd int) int {
+	gy, march := jalaliMarch(jy)
+	return gregorianToJDN(gy, 3, march) + (jm-1)*31 - jm/7*(jm-7) + jd - 1
+}
+
+// hijriToJDN returns the Julian Day Number of a date in the tabular Islamic
+// calendar. The tabular calendar follows a fixed 30-year leap cycle, while
+// Iran starts each lunar month on the sighting of the new moon, so computed
+// dates can be a day off: Ashura 1446 is computed as 2024-07-17, but was
+// observed on 2024-07-16.
+func hijriToJDN(hy, hm, hd int) int {
+	return hd + (59*(hm-1)+1)/2 + (hy-1)*354 + (3+11*hy)/30 + 1948439
+}
+
+func gregorianToJDN(gy, gm, gd int) int {
+	d := (gy+(gm-8)/6+100100)*1461/4 + (153*((gm+9)%12)+2)/5 + gd - 34840408
+	return d - (gy+100100+(gm-8)/6)/100*3/4 + 752
+}
+
+// jdnToDate returns the Gregorian date of a Julian Day Number at midday in
+// Tehran.
+func jdnToDate(jdn int) time.Time {
+	j := 4*jdn + 139361631
+	j += (4*jdn+183187720)/146097*3/4*4 - 3908
+	i := j%1461/4*5 + 308
+	gd := i%153/5 + 1
+	gm := i/153%12 + 1
+	gy := j/1461 - 100100 + (8-gm)/6
+	return time.Date(gy, time.Month(gm), gd, 12, 0, 0, 0, Tehran)
+}
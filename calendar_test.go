@@ -0,0 +1,154 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJalaliToGregorian(t *testing.T) {
+	tests := []struct {
+		jy, jm, jd int
+		expected   string
+	}{
+		{1403, 1, 1, "2024-03-20"},
+		{1404, 1, 1, "2025-03-21"},
+		{1402, 11, 22, "2024-02-11"},
+		{1403, 12, 30, "2025-03-20"},
+	}
+
+	for _, tt := range tests {
+		got := jdnToDate(jalaliToJDN(tt.jy, tt.jm, tt.jd)).Format(time.DateOnly)
+		if got != tt.expected {
+			t.Errorf("Expected %d/%d/%d to be %s, got %s", tt.jy, tt.jm, tt.jd, tt.expected, got)
+		}
+	}
+}
+
+func TestHijriToGregorian(t *testing.T) {
+	// 1 Muharram 1446 in the tabular calendar
+	got := jdnToDate(hijriToJDN(1446, 1, 1)).Format(time.DateOnly)
+	if got != "2024-07-07" && got != "2024-07-08" {
+		t.Errorf("Expected 1 Muharram 1446 around 2024-07-07, got %s", got)
+	}
+}
+
+func TestIranianPublicHolidays(t *testing.T) {
+	holidays := IranianPublicHolidays(2024)
+
+	byName := make(map[string][]Holiday)
+	for i, h := range holidays {
+		if h.Date.Year() != 2024 {
+			t.Errorf("Expected holidays in 2024, got %s", h.Date.Format(time.DateOnly))
+		}
+		if i > 0 && h.Date.Before(holidays[i-1].Date) {
+			t.Errorf("Expected holidays sorted by date")
+		}
+		byName[h.Name] = append(byName[h.Name], h)
+	}
+
+	if n := len(byName["Nowruz"]); n != 4 {
+		t.Errorf("Expected 4 Nowruz days, got %d", n)
+	}
+	if got := byName["Nowruz"][0].Date.Format(time.DateOnly); got != "2024-03-20" {
+		t.Errorf("Expected Nowruz on 2024-03-20, got %s", got)
+	}
+
+	ashura := byName["Ashura"]
+	if len(ashura) != 1 || !ashura[0].Mourning {
+		t.Fatalf("Expected one mourning Ashura, got %+v", ashura)
+	}
+	// Officially observed on 2024-07-16; the tabular date may be a day off.
+	if d := ashura[0].Date.Sub(time.Date(2024, 7, 16, 12, 0, 0, 0, Tehran)); d < -24*time.Hour || d > 24*time.Hour {
+		t.Errorf("Expected Ashura around 2024-07-16, got %s", ashura[0].Date.Format(time.DateOnly))
+	}
+}
+
+func TestCalendar_Check(t *testing.T) {
+	cal := NewCalendar(nil)
+	cal.Add(time.Date(2024, 7, 16, 0, 0, 0, 0, Tehran), "Ashura")
+	cal.AddRange(time.Date(2024, 3, 20, 0, 0, 0, 0, Tehran), time.Date(2024, 3, 23, 0, 0, 0, 0, Tehran), "Nowruz")
+
+	// 21:00 UTC on July 15 is already July 16 in Tehran.
+	err := cal.Check(time.Date(2024, 7, 15, 21, 0, 0, 0, time.UTC))
+	var be *BlackoutError
+	if !errors.As(err, &be) {
+		t.Fatalf("Expected *BlackoutError, got %v", err)
+	}
+	if be.Reason != "Ashura" {
+		t.Errorf("Expected reason Ashura, got %s", be.Reason)
+	}
+	if !errors.Is(err, ErrBlackoutDate) {
+		t.Error("Expected error to wrap ErrBlackoutDate")
+	}
+
+	if err := cal.Check(time.Date(2024, 3, 23, 18, 0, 0, 0, Tehran)); err == nil {
+		t.Error("Expected the last day of the range to be blacked out")
+	}
+	if err := cal.Check(time.Date(2024, 3, 24, 9, 0, 0, 0, Tehran)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestMessagesService_Calendar(t *testing.T) {
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	cal := NewCalendar(nil)
+	cal.Add(time.Now(), "Company holiday")
+
+	client := setupTestClient(handler)
+	WithCalendar(cal)(client)
+
+	ctx := context.Background()
+	_, err := client.Messages.SendMessage(ctx, "+989123456789", "Sale!")
+	if !errors.Is(err, ErrBlackoutDate) {
+		t.Fatalf("Expected ErrBlackoutDate, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+
+	_, err = client.Messages.SendMessage(WithCalendarOverride(ctx, nil), "+989123456789", "Your code is 1234")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+
+	_, err = client.Messages.RescheduleMessage(ctx, "msg_1", time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err = client.Messages.RescheduleMessage(ctx, "msg_1", time.Now())
+	if !errors.Is(err, ErrBlackoutDate) {
+		t.Errorf("Expected ErrBlackoutDate, got %v", err)
+	}
+}
+
+func TestCalendar_ExemptsOTPByDefault(t *testing.T) {
+	cal := NewCalendar(nil)
+	cal.Add(time.Now(), "Ashura")
+
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	})
+	WithCalendar(cal)(client)
+
+	ctx := WithCategory(context.Background(), CategoryOTP)
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Your code is 1234"); err != nil {
+		t.Errorf("Expected OTPs to pass the calendar, got %v", err)
+	}
+
+	cal.Unexempt(CategoryOTP)
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Your code is 1234"); !errors.Is(err, ErrBlackoutDate) {
+		t.Errorf("Expected ErrBlackoutDate after Unexempt, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+)
+
+// CallsService provides methods for reading the detail records of voice
+// calls placed by voice messages.
+type CallsService struct {
+	client *Client
+}
+
+// ListCalls retrieves call detail records, newest first.
+func (s *CallsService) ListCalls(ctx context.Context, params *PaginationParams) (*ListCallsResponse, error) {
+	var response ListCallsResponse
+	if err := s.client.Get(ctx, "/calls", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to list calls: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetCall retrieves the detail record of a call by its ID.
+func (s *CallsService) GetCall(ctx context.Context, callID string) (*Call, error) {
+	if callID == "" {
+		return nil, &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "call ID is required"}}}
+	}
+
+	var call Call
+	if err := s.client.Get(ctx, "/calls/"+pathSegment(callID), &call, nil); err != nil {
+		return nil, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	return &call, nil
+}
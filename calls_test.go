@@ -0,0 +1,66 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCallsService_ListCalls(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/calls" {
+			t.Errorf("Expected path '/calls', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") != "2" {
+			t.Errorf("Expected page '2', got '%s'", r.URL.Query().Get("page"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListCallsResponse{
+			Calls: []Call{{ID: "call-1", To: "+989123456789", AnswerStatus: CallAnswered, Duration: 42, DTMF: "1#", Cost: 850}},
+			Page:  2,
+			Total: 11,
+		})
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Calls.ListCalls(context.Background(), &PaginationParams{Page: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Calls) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(response.Calls))
+	}
+	call := response.Calls[0]
+	if call.AnswerStatus != CallAnswered || call.Duration != 42 || call.DTMF != "1#" {
+		t.Errorf("Unexpected call: %+v", call)
+	}
+}
+
+func TestCallsService_GetCall(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calls/call-1" {
+			t.Errorf("Expected path '/calls/call-1', got '%s'", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "call-1", "message_id": "msg-9", "answer_status": "no_answer", "duration": 0}`))
+	}
+
+	client := setupTestClient(handler)
+	call, err := client.Calls.GetCall(context.Background(), "call-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if call.MessageID != "msg-9" || call.AnswerStatus != CallNoAnswer {
+		t.Errorf("Unexpected call: %+v", call)
+	}
+
+	if _, err := client.Calls.GetCall(context.Background(), ""); !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
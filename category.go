@@ -0,0 +1,45 @@
+package signalads
+
+import "context"
+
+// Category classifies messages so that policies such as quotas, blackout
+// dates and frequency caps can treat them differently. It is sent to the API
+// with the message. Custom categories are allowed.
+type Category string
+
+// Message categories.
+const (
+	// CategoryOTP marks one-time passwords and other verification codes.
+	CategoryOTP Category = "otp"
+
+	// CategoryTransactional marks messages triggered by a user's own
+	// action, such as receipts and shipping updates.
+	CategoryTransactional Category = "transactional"
+
+	// CategoryPromotional marks marketing messages.
+	CategoryPromotional Category = "promotional"
+)
+
+type categoryContextKey struct{}
+
+// WithCategory returns a context whose sends are classified as category,
+// unless the request sets its own Category.
+func WithCategory(ctx context.Context, category Category) context.Context {
+	return context.WithValue(ctx, categoryContextKey{}, category)
+}
+
+// CategoryFromContext returns the category set with WithCategory.
+func CategoryFromContext(ctx context.Context) (Category, bool) {
+	category, ok := ctx.Value(categoryContextKey{}).(Category)
+	return category, ok
+}
+
+// resolveCategory returns the category of a send whose request sets
+// category, and a context carrying it for the policy checks.
+func resolveCategory(ctx context.Context, category Category) (context.Context, Category) {
+	if category == "" {
+		category, _ = CategoryFromContext(ctx)
+		return ctx, category
+	}
+	return WithCategory(ctx, category), category
+}
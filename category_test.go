@@ -0,0 +1,80 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMessagesService_CategoryPropagation(t *testing.T) {
+	var sent SendMessageRequest
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	client := setupTestClient(handler)
+	ctx := WithCategory(context.Background(), CategoryPromotional)
+
+	req := &SendMessageRequest{To: "+989123456789", Message: "Sale"}
+	if _, err := client.Messages.SendSingleMessage(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent.Category != CategoryPromotional {
+		t.Errorf("Expected category %s from the context, got %q", CategoryPromotional, sent.Category)
+	}
+	if req.Category != "" {
+		t.Error("Expected the caller's request not to be modified")
+	}
+
+	req.Category = CategoryTransactional
+	if _, err := client.Messages.SendSingleMessage(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent.Category != CategoryTransactional {
+		t.Errorf("Expected the request category to win, got %q", sent.Category)
+	}
+}
+
+func TestMessagesService_CategoryPolicies(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	cal := NewCalendar(nil)
+	cal.Add(time.Now(), "Ashura")
+	cal.Exempt(CategoryOTP, CategoryTransactional)
+
+	client := setupTestClient(handler)
+	WithCalendar(cal)(client)
+	WithGovernor(NewGovernor(PerDay(1).ForCategories(CategoryPromotional)))(client)
+	ctx := context.Background()
+
+	_, err := client.Messages.SendSingleMessage(ctx, &SendMessageRequest{To: "+989123456789", Message: "Sale", Category: CategoryPromotional})
+	if !errors.Is(err, ErrBlackoutDate) {
+		t.Errorf("Expected promotional messages to be blacked out, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err = client.Messages.SendSingleMessage(ctx, &SendMessageRequest{To: "+989123456789", Message: "Code 1234", Category: CategoryOTP})
+		if err != nil {
+			t.Fatalf("Expected OTPs to pass the calendar and quota, got %v", err)
+		}
+	}
+
+	g := NewGovernor(PerDay(1).ForCategories(CategoryPromotional))
+	if err := g.ReserveCategory(CategoryPromotional, "+989123456789"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := g.ReserveCategory(CategoryPromotional, "+989123456789"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if err := g.Reserve("+989123456789"); err != nil {
+		t.Errorf("Expected uncategorized messages not to count, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned by Checkpointer.Load for keys without a
+// checkpoint.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// IndexRange is a half-open range [From, To) of recipient indexes.
+type IndexRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Checkpoint records which recipients of a chunked bulk send have been
+// sent, by their index in the original request.
+type Checkpoint struct {
+	Sent      []IndexRange `json:"sent"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Contains reports whether the recipient at index i has been sent.
+func (cp *Checkpoint) Contains(i int) bool {
+	n := sort.Search(len(cp.Sent), func(j int) bool { return cp.Sent[j].To > i })
+	return n < len(cp.Sent) && cp.Sent[n].From <= i
+}
+
+// add marks the recipients at indexes as sent, merging adjacent ranges.
+func (cp *Checkpoint) add(indexes []int) {
+	for _, i := range indexes {
+		cp.Sent = append(cp.Sent, IndexRange{From: i, To: i + 1})
+	}
+	sort.Slice(cp.Sent, func(a, b int) bool { return cp.Sent[a].From < cp.Sent[b].From })
+
+	merged := cp.Sent[:0]
+	for _, r := range cp.Sent {
+		if last := len(merged) - 1; last >= 0 && r.From <= merged[last].To {
+			merged[last].To = max(merged[last].To, r.To)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	cp.Sent = merged
+	cp.UpdatedAt = time.Now()
+}
+
+// Checkpointer persists the progress of chunked bulk sends, so that a send
+// interrupted by a crash or deploy can be resumed without resending the
+// recipients already sent. Implementations must be safe for concurrent use.
+type Checkpointer interface {
+	// Load returns the checkpoint saved under key, or
+	// ErrCheckpointNotFound.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+
+	// Save replaces the checkpoint saved under key.
+	Save(ctx context.Context, key string, cp *Checkpoint) error
+
+	// Delete removes the checkpoint saved under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// WithCheckpointer makes SendBulkVoiceMessages record its progress in cp
+// under the request's campaign ID after every chunk, and skip the
+// recipients already sent when the same campaign is sent again. Requests
+// without a CampaignID are not checkpointed.
+func WithCheckpointer(cp Checkpointer) ClientOption {
+	return func(c *Client) {
+		c.checkpointer = cp
+	}
+}
+
+// loadCheckpoint returns the checkpoint of the campaign with the given ID,
+// or nil if sends are not checkpointed.
+func (c *Client) loadCheckpoint(ctx context.Context, campaignID string) (*Checkpoint, error) {
+	if c.checkpointer == nil || campaignID == "" {
+		return nil, nil
+	}
+	cp, err := c.checkpointer.Load(ctx, campaignID)
+	if errors.Is(err, ErrCheckpointNotFound) {
+		return &Checkpoint{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// MemoryCheckpointer is a Checkpointer that keeps checkpoints in memory. It
+// only helps to resume sends within one process; use FileCheckpointer or
+// a database-backed implementation to survive restarts.
+type MemoryCheckpointer struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointer creates an empty MemoryCheckpointer.
+func NewMemoryCheckpointer() *MemoryCheckpointer {
+	return &MemoryCheckpointer{checkpoints: make(map[string]Checkpoint)}
+}
+
+// Load implements Checkpointer.
+func (m *MemoryCheckpointer) Load(_ context.Context, key string) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cp, ok := m.checkpoints[key]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	cp.Sent = append([]IndexRange(nil), cp.Sent...)
+	return &cp, nil
+}
+
+// Save implements Checkpointer.
+func (m *MemoryCheckpointer) Save(_ context.Context, key string, cp *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *cp
+	stored.Sent = append([]IndexRange(nil), cp.Sent...)
+	m.checkpoints[key] = stored
+	return nil
+}
+
+// Delete implements Checkpointer.
+func (m *MemoryCheckpointer) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.checkpoints, key)
+	return nil
+}
+
+// FileCheckpointer is a Checkpointer backed by a JSON file. Every change is
+// written to disk before it returns.
+type FileCheckpointer struct {
+	mu          sync.Mutex
+	path        string
+	checkpoints map[string]Checkpoint
+	encrypter   Encrypter
+}
+
+// FileCheckpointerOption configures a FileCheckpointer.
+type FileCheckpointerOption func(*FileCheckpointer)
+
+// WithFileCheckpointerEncrypter encrypts the file, which holds the campaign
+// IDs and progress of bulk sends, with e. A file written without
+// encryption is still read, and encrypted on the next change.
+func WithFileCheckpointerEncrypter(e Encrypter) FileCheckpointerOption {
+	return func(f *FileCheckpointer) {
+		f.encrypter = e
+	}
+}
+
+// NewFileCheckpointer opens the checkpoints at path, loading any left by a
+// previous process.
+func NewFileCheckpointer(path string, opts ...FileCheckpointerOption) (*FileCheckpointer, error) {
+	f := &FileCheckpointer{
+		path:        path,
+		checkpoints: make(map[string]Checkpoint),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return f, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	if data, err = decryptFile(f.encrypter, data); err != nil {
+		return nil, fmt.Errorf("failed to decrypt checkpoint file: %w", err)
+	}
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &f.checkpoints); err != nil {
+			return nil, fmt.Errorf("failed to decode checkpoint file: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// Load implements Checkpointer.
+func (f *FileCheckpointer) Load(_ context.Context, key string) (*Checkpoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cp, ok := f.checkpoints[key]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	cp.Sent = append([]IndexRange(nil), cp.Sent...)
+	return &cp, nil
+}
+
+// Save implements Checkpointer.
+func (f *FileCheckpointer) Save(_ context.Context, key string, cp *Checkpoint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prev, existed := f.checkpoints[key]
+	stored := *cp
+	stored.Sent = append([]IndexRange(nil), cp.Sent...)
+	f.checkpoints[key] = stored
+	if err := f.persist(); err != nil {
+		if existed {
+			f.checkpoints[key] = prev
+		} else {
+			delete(f.checkpoints, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete implements Checkpointer.
+func (f *FileCheckpointer) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prev, existed := f.checkpoints[key]
+	if !existed {
+		return nil
+	}
+	delete(f.checkpoints, key)
+	if err := f.persist(); err != nil {
+		f.checkpoints[key] = prev
+		return err
+	}
+	return nil
+}
+
+func (f *FileCheckpointer) persist() error {
+	data, err := json.Marshal(f.checkpoints)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+	if data, err = encryptFile(f.encrypter, data); err != nil {
+		return fmt.Errorf("failed to encrypt checkpoints: %w", err)
+	}
+	if err = writeFileAtomic(f.path, data); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	return nil
+}
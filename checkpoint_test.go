@@ -0,0 +1,148 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCheckpoint_Add(t *testing.T) {
+	var cp Checkpoint
+	cp.add([]int{4, 5})
+	cp.add([]int{0, 1})
+	cp.add([]int{2, 3})
+	cp.add([]int{8})
+
+	want := []IndexRange{{From: 0, To: 6}, {From: 8, To: 9}}
+	if !reflect.DeepEqual(cp.Sent, want) {
+		t.Errorf("Expected ranges %v, got %v", want, cp.Sent)
+	}
+	for i, sent := range []bool{true, true, true, true, true, true, false, false, true, false} {
+		if cp.Contains(i) != sent {
+			t.Errorf("Contains(%d): expected %v", i, sent)
+		}
+	}
+}
+
+func TestSendBulkVoiceMessages_Resume(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []string
+		fail = true
+	)
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		var req SendBulkVoiceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if fail && req.Recipients[0] == "+989120000003" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message": "unavailable"}`))
+			return
+		}
+		sent = append(sent, req.Recipients...)
+
+		var resp SendBulkMessageResponse
+		for _, to := range req.Recipients {
+			resp.Results = append(resp.Results, SendMessageResponse{ID: "call-" + to, To: to, Status: "queued"})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	checkpointer := NewMemoryCheckpointer()
+	WithCheckpointer(checkpointer)(client)
+
+	req := &SendBulkVoiceRequest{
+		Recipients: []string{"+989120000001", "+989120000002", "+989120000003", "+989120000004", "+989120000005"},
+		AudioURL:   "https://example.com/a.mp3",
+		CampaignID: "camp-1",
+		ChunkSize:  2,
+	}
+	if _, err := client.Messages.SendBulkVoiceMessages(context.Background(), req); err == nil {
+		t.Fatal("Expected the failed chunk to be reported")
+	}
+
+	cp, err := checkpointer.Load(context.Background(), "camp-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []IndexRange{{From: 0, To: 2}, {From: 4, To: 5}}
+	if !reflect.DeepEqual(cp.Sent, want) {
+		t.Errorf("Expected checkpoint %v, got %v", want, cp.Sent)
+	}
+
+	mu.Lock()
+	fail = false
+	sent = nil
+	mu.Unlock()
+
+	response, err := client.Messages.SendBulkVoiceMessages(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Skipped != 3 {
+		t.Errorf("Expected 3 skipped recipients, got %d", response.Skipped)
+	}
+	if !reflect.DeepEqual(sent, []string{"+989120000003", "+989120000004"}) {
+		t.Errorf("Expected only the failed chunk to be resent, got %v", sent)
+	}
+	if len(response.Messages) != 2 {
+		t.Errorf("Expected 2 accepted calls, got %d", len(response.Messages))
+	}
+
+	cp, err = checkpointer.Load(context.Background(), "camp-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cp.Sent, []IndexRange{{From: 0, To: 5}}) {
+		t.Errorf("Expected every recipient checkpointed, got %v", cp.Sent)
+	}
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	ctx := context.Background()
+
+	f, err := NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = f.Load(ctx, "camp-1"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Fatalf("Expected ErrCheckpointNotFound, got %v", err)
+	}
+	cp := &Checkpoint{}
+	cp.add([]int{0, 1, 2})
+	if err = f.Save(ctx, "camp-1", cp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = f.Save(ctx, "camp-2", cp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = f.Delete(ctx, "camp-2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reopened, err := NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded, err := reopened.Load(ctx, "camp-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Sent, cp.Sent) {
+		t.Errorf("Expected %v, got %v", cp.Sent, loaded.Sent)
+	}
+	if _, err = reopened.Load(ctx, "camp-2"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Expected deleted checkpoint to stay deleted, got %v", err)
+	}
+}
@@ -1,13 +1,14 @@
 package signalads
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -18,6 +19,9 @@ const (
 
 type ClientOption func(*Client)
 
+// WithBaseURL sends requests to baseURL instead of DefaultBaseURL. It may
+// end in a slash and may include a path prefix, such as a gateway's
+// "https://gateway.example.com/sms/api/v1".
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
 		c.baseURL = baseURL
@@ -27,65 +31,201 @@ func WithBaseURL(baseURL string) ClientOption {
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *Client) {
 		c.httpClient = httpClient
+		c.ownsHTTPClient = false
+		c.ownsTransport = false
 	}
 }
 
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
-		if c.httpClient != nil {
-			c.httpClient.Timeout = timeout
+		if hc := c.ownHTTPClient(); hc != nil {
+			hc.Timeout = timeout
 		}
 	}
 }
 
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, body interface{}, queryParams map[string]string) (*http.Response, error) {
-	reqURL := c.baseURL + endpoint
-	if len(queryParams) > 0 {
-		u, err := url.Parse(reqURL)
-		if err != nil {
-			return nil, fmt.Errorf("invalid URL: %w", err)
-		}
-		q := u.Query()
-		for k, v := range queryParams {
-			q.Set(k, v)
-		}
-		u.RawQuery = q.Encode()
-		reqURL = u.String()
+	ctx, cancel := c.withDefaultDeadline(ctx, method, endpoint)
+	if cancel == nil {
+		return c.dispatchRequest(ctx, method, endpoint, body, queryParams)
+	}
+
+	resp, err := c.dispatchRequest(ctx, method, endpoint, body, queryParams)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// dispatchRequest waits for a send slot and sends the request, hedging it
+// if configured.
+func (c *Client) dispatchRequest(ctx context.Context, method, endpoint string, body interface{}, queryParams map[string]string) (*http.Response, error) {
+	release, err := c.acquireSend(ctx, method, endpoint)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
+	var resp *http.Response
+	start := time.Now()
+	if _, streamed := body.(*streamedBody); !streamed && c.shouldHedge(ctx, method) {
+		resp, err = c.doHedged(ctx, func(ctx context.Context) (*http.Response, error) {
+			return c.doAttempt(ctx, method, endpoint, body, queryParams)
+		})
+	} else {
+		resp, err = c.doAttempt(ctx, method, endpoint, body, queryParams)
+	}
+	c.observeLatency(ctx, method, endpoint, time.Since(start), resp, err)
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		c.recordRequest(resp, err)
+	}
+	return resp, err
+}
+
+// endpointURL joins endpoint to the base URL and adds queryParams. Either
+// may have or lack the slash between them, and the base URL may have a
+// path prefix, such as a gateway's, which is kept. endpoint may carry a
+// query string of its own.
+func (c *Client) endpointURL(endpoint string, queryParams map[string]string) (string, error) {
+	base, err := url.Parse(c.BaseURL())
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	endpointPath, rawQuery, _ := strings.Cut(endpoint, "?")
+	u := base.JoinPath(endpointPath)
+	if rawQuery == "" && len(queryParams) == 0 {
+		return u.String(), nil
+	}
+
+	q := u.Query()
+	extra, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	for k, v := range extra {
+		q[k] = v
+	}
+	for k, v := range queryParams {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// pathSegment escapes id for use as one segment of an endpoint path, so
+// that an ID containing "/", "?" or "#", or an ID of "." or "..", cannot
+// address another endpoint.
+func pathSegment(id string) string {
+	if id == "." || id == ".." {
+		return strings.ReplaceAll(id, ".", "%2E")
+	}
+	return url.PathEscape(id)
+}
+
+// doAttempt builds and sends a single HTTP request.
+func (c *Client) doAttempt(ctx context.Context, method, endpoint string, body interface{}, queryParams map[string]string) (*http.Response, error) {
+	reqURL, err := c.endpointURL(endpoint, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := c.currentCredentials().Credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	var (
+		reqBody       io.Reader
+		pooled        *pooledBody
+		contentLength int64
+		gzipped       bool
+	)
+	streamed, isStreamed := body.(*streamedBody)
+	if isStreamed {
+		reqBody = streamed.r
+	} else if body != nil {
+		buf, compressed, err := c.encodeBody(body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, err
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		contentLength = int64(buf.Len())
+		gzipped = compressed
+		pooled = newPooledBody(buf)
+		reqBody = pooled
+		// Keep the bytes until the exchange, including any redirects and
+		// retries the transport makes through GetBody, has finished.
+		defer pooled.hold()()
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
+		if closer, ok := reqBody.(io.Closer); ok {
+			closer.Close()
+		}
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.ContentLength = contentLength
+	if pooled != nil {
+		req.GetBody = pooled.getBody
+	}
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if _, ok := body.(url.Values); ok {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else if isStreamed {
+		req.Header.Set("Content-Type", streamed.contentType)
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-API-Key", c.apiKey)
-	req.Header.Set("X-API-Secret", c.apiSecret)
+	if c.locale != "" {
+		req.Header.Set("Accept-Language", c.locale)
+	}
+	req.Header.Set("X-API-Key", creds.APIKey)
+	req.Header.Set("X-API-Secret", creds.APISecret)
+	if key := idempotencyKey(ctx); key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.sendWithCredentials(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &TransportError{Op: "request failed", Err: err}
 	}
+	c.recordRateLimit(resp.Header)
 
 	return resp, nil
 }
 
 func (c *Client) parseResponse(resp *http.Response, v interface{}) error {
+	body, err := c.readResponse(resp)
+	if err != nil {
+		return err
+	}
+	if err = c.decodeBody(body, v); err != nil {
+		return err
+	}
+	c.attachRaw(v, body, false)
+	if c.strictDecoding && resp.Request != nil {
+		c.checkUnknownFields(resp.Request.Context(), resp.Request.URL.Path, body, v)
+	}
+	return nil
+}
+
+// readResponse reads and closes the response body, converting non-2xx
+// responses into errors.
+func (c *Client) readResponse(resp *http.Response) ([]byte, error) {
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readLimited(resp.Body)
+	if errors.Is(err, ErrResponseTooLarge) {
+		return nil, err
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, &TransportError{Op: "failed to read response body", Err: err}
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -95,19 +235,37 @@ func (c *Client) parseResponse(resp *http.Response, v interface{}) error {
 				apiErr.StatusCode = resp.StatusCode
 			}
 			if apiErr.Message != "" || apiErr.Code != "" || apiErr.ErrorMsg != "" {
-				return &apiErr
+				normalizeErrorCode(&apiErr)
+				c.localizeError(&apiErr)
+				return nil, &apiErr
 			}
 		}
-		return NewAPIError(
+		statusErr := NewAPIError(
 			getErrorCodeFromStatusCode(resp.StatusCode),
 			fmt.Sprintf("API error: status %d, body: %s", resp.StatusCode, string(body)),
 			resp.StatusCode,
 		)
+		normalizeErrorCode(statusErr)
+		c.localizeError(statusErr)
+		return nil, statusErr
+	}
+
+	return body, nil
+}
+
+// decodeBody decodes a successful response body into v, unwrapping
+// response envelopes.
+func (c *Client) decodeBody(body []byte, v interface{}) error {
+	payload, apiErr := unwrapEnvelope(body)
+	if apiErr != nil {
+		normalizeErrorCode(apiErr)
+		c.localizeError(apiErr)
+		return apiErr
 	}
 
 	if v != nil {
-		if unmarshalErr := json.Unmarshal(body, v); unmarshalErr != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", unmarshalErr)
+		if unmarshalErr := json.Unmarshal(payload, v); unmarshalErr != nil {
+			return &DecodeError{Body: body, Err: unmarshalErr}
 		}
 	}
 
@@ -139,6 +297,12 @@ func getErrorCodeFromStatusCode(statusCode int) string {
 
 // Get performs a GET request to the specified endpoint.
 func (c *Client) Get(ctx context.Context, endpoint string, result interface{}, queryParams map[string]string) error {
+	if c.cache != nil {
+		if ttl, ok := c.cache.ttls[endpoint]; ok {
+			return c.cachedGet(ctx, endpoint, result, queryParams, ttl)
+		}
+	}
+
 	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, queryParams)
 	if err != nil {
 		return err
@@ -155,6 +319,13 @@ func (c *Client) Post(ctx context.Context, endpoint string, body, result interfa
 	return c.parseResponse(resp, result)
 }
 
+// PostForm performs a POST request to the specified endpoint with values
+// form-encoded, for endpoints that do not accept JSON. Services choose the
+// encoding per endpoint by passing url.Values as the body of Post or Put.
+func (c *Client) PostForm(ctx context.Context, endpoint string, values url.Values, result interface{}) error {
+	return c.Post(ctx, endpoint, values, result)
+}
+
 // Put performs a PUT request to the specified endpoint.
 func (c *Client) Put(ctx context.Context, endpoint string, body, result interface{}) error {
 	resp, err := c.doRequest(ctx, http.MethodPut, endpoint, body, nil)
@@ -164,6 +335,30 @@ func (c *Client) Put(ctx context.Context, endpoint string, body, result interfac
 	return c.parseResponse(resp, result)
 }
 
+// Patch performs a PATCH request to the specified endpoint, for partial
+// updates.
+func (c *Client) Patch(ctx context.Context, endpoint string, body, result interface{}) error {
+	resp, err := c.doRequest(ctx, http.MethodPatch, endpoint, body, nil)
+	if err != nil {
+		return err
+	}
+	return c.parseResponse(resp, result)
+}
+
+// Head performs a HEAD request to the specified endpoint and returns the
+// response headers, for checking that a resource exists without fetching
+// it. Error statuses are returned as an *APIError.
+func (c *Client) Head(ctx context.Context, endpoint string, queryParams map[string]string) (http.Header, error) {
+	resp, err := c.doRequest(ctx, http.MethodHead, endpoint, nil, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = c.readResponse(resp); err != nil {
+		return nil, err
+	}
+	return resp.Header, nil
+}
+
 // Delete performs a DELETE request to the specified endpoint.
 func (c *Client) Delete(ctx context.Context, endpoint string, result interface{}) error {
 	resp, err := c.doRequest(ctx, http.MethodDelete, endpoint, nil, nil)
@@ -3,8 +3,10 @@ package signalads
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 )
@@ -15,11 +17,15 @@ func TestNewClient(t *testing.T) {
 	if client == nil {
 		t.Fatal("Expected client, got nil")
 	}
-	if client.apiKey != "test-key" {
-		t.Errorf("Expected apiKey 'test-key', got '%s'", client.apiKey)
+	creds, err := client.credentials.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "test-key" {
+		t.Errorf("Expected apiKey 'test-key', got '%s'", creds.APIKey)
 	}
-	if client.apiSecret != "test-secret" {
-		t.Errorf("Expected apiSecret 'test-secret', got '%s'", client.apiSecret)
+	if creds.APISecret != "test-secret" {
+		t.Errorf("Expected apiSecret 'test-secret', got '%s'", creds.APISecret)
 	}
 	if client.baseURL != DefaultBaseURL {
 		t.Errorf("Expected baseURL '%s', got '%s'", DefaultBaseURL, client.baseURL)
@@ -137,6 +143,95 @@ func TestClient_Post(t *testing.T) {
 	}
 }
 
+func TestClient_PostForm(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("Expected a form Content-Type, got %q", r.Header.Get("Content-Type"))
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		if r.PostForm.Get("to") != "+989123456789" || r.PostForm.Get("text") != "سلام & hi" {
+			t.Errorf("Unexpected form values: %v", r.PostForm)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id": "123"})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL))
+
+	var result map[string]string
+	values := url.Values{"to": {"+989123456789"}, "text": {"سلام & hi"}}
+	if err := client.PostForm(context.Background(), "/test", values, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result["id"] != "123" {
+		t.Errorf("Expected id '123', got '%s'", result["id"])
+	}
+}
+
+func TestClient_Patch(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] != "Ali" {
+			t.Errorf("Expected body name='Ali', got '%s'", body["name"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id": "123", "name": "Ali"})
+	}
+
+	client := setupTestClient(handler)
+
+	var result map[string]string
+	if err := client.Patch(context.Background(), "/items/123", map[string]string{"name": "Ali"}, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result["name"] != "Ali" {
+		t.Errorf("Expected name 'Ali', got '%s'", result["name"])
+	}
+}
+
+func TestClient_Head(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected HEAD, got %s", r.Method)
+		}
+		if r.URL.Path == "/files/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "2048")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	client := setupTestClient(handler)
+	ctx := context.Background()
+
+	header, err := client.Head(ctx, "/files/file-1", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if header.Get("Content-Length") != "2048" {
+		t.Errorf("Expected Content-Length 2048, got %q", header.Get("Content-Length"))
+	}
+
+	_, err = client.Head(ctx, "/files/missing", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a 404 *APIError, got %v", err)
+	}
+}
+
 func TestClient_ParseResponse_Error(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -213,3 +308,73 @@ func TestClient_ContextCancellation(t *testing.T) {
 		t.Error("Expected error due to context cancellation, got nil")
 	}
 }
+
+func TestClient_EndpointURL(t *testing.T) {
+	tests := []struct {
+		baseURL, endpoint string
+		query             map[string]string
+		want              string
+	}{
+		{"https://api.example.com/api/v1", "/messages", nil, "https://api.example.com/api/v1/messages"},
+		{"https://api.example.com/api/v1/", "/messages", nil, "https://api.example.com/api/v1/messages"},
+		{"https://api.example.com/api/v1/", "messages", nil, "https://api.example.com/api/v1/messages"},
+		{"https://gw.example.com/sms/api/v1", "messages/msg-1/status", nil, "https://gw.example.com/sms/api/v1/messages/msg-1/status"},
+		{"https://api.example.com", "/messages", map[string]string{"page": "2"}, "https://api.example.com/messages?page=2"},
+		{"https://api.example.com/", "/messages?status=failed", map[string]string{"page": "2"}, "https://api.example.com/messages?page=2&status=failed"},
+	}
+	for _, tt := range tests {
+		client := NewClient("test-key", "test-secret", WithBaseURL(tt.baseURL))
+		got, err := client.endpointURL(tt.endpoint, tt.query)
+		if err != nil {
+			t.Errorf("%s + %s: Unexpected error: %v", tt.baseURL, tt.endpoint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s + %s: Expected %s, got %s", tt.baseURL, tt.endpoint, tt.want, got)
+		}
+	}
+}
+
+func TestClient_EscapesIDs(t *testing.T) {
+	var path string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MessageStatus{})
+	}
+
+	client := setupTestClient(handler)
+	tests := map[string]string{
+		"msg/1":      "/messages/msg%2F1/status",
+		"msg?x=1#f":  "/messages/msg%3Fx=1%23f/status",
+		"..":         "/messages/%2E%2E/status",
+		"msg-1 copy": "/messages/msg-1%20copy/status",
+	}
+	for id, want := range tests {
+		client.Messages.GetMessageStatus(context.Background(), id)
+		if path != want {
+			t.Errorf("%q: Expected path %s, got %s", id, want, path)
+		}
+	}
+}
+
+func TestClient_BaseURLPrefix(t *testing.T) {
+	var path string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL+"/gateway/api/v1/"))
+	var result map[string]string
+	if err := client.Get(context.Background(), "/test", &result, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if path != "/gateway/api/v1/test" {
+		t.Errorf("Expected path /gateway/api/v1/test, got %s", path)
+	}
+}
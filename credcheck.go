@@ -0,0 +1,121 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Problems reported in CredentialsError.Problem.
+const (
+	// No API key or secret is configured
+	CredentialsMissing = "missing"
+
+	// The API does not know the API key
+	CredentialsInvalidKey = "invalid_key"
+
+	// The API key exists but the secret does not match it
+	CredentialsInvalidSecret = "invalid_secret"
+
+	// The API rejected the credentials without saying which part is wrong
+	CredentialsRejected = "rejected"
+
+	// The credentials are valid but the account is suspended or blocked
+	CredentialsAccountSuspended = "account_suspended"
+)
+
+// Account statuses reported in UserInfo.Status.
+const (
+	AccountActive    = "active"
+	AccountSuspended = "suspended"
+	AccountBlocked   = "blocked"
+)
+
+// CredentialsError is returned by Client.ValidateCredentials when the
+// client's credentials cannot be used to send.
+type CredentialsError struct {
+	// What is wrong, one of the Credentials* constants
+	Problem string
+
+	// Error returned by the credentials provider or the API, if any
+	Err error
+}
+
+func (e *CredentialsError) Error() string {
+	var msg string
+	switch e.Problem {
+	case CredentialsMissing:
+		msg = "API credentials are not configured"
+	case CredentialsInvalidKey:
+		msg = "API key is not valid"
+	case CredentialsInvalidSecret:
+		msg = "API secret does not match the API key"
+	case CredentialsAccountSuspended:
+		msg = "account is suspended"
+	default:
+		msg = "API credentials were rejected"
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *CredentialsError) Unwrap() error {
+	return e.Err
+}
+
+// Suspended reports whether the account is suspended or blocked.
+func (u *UserInfo) Suspended() bool {
+	return strings.EqualFold(u.Status, AccountSuspended) || strings.EqualFold(u.Status, AccountBlocked)
+}
+
+// ValidateCredentials checks the client's credentials with a cheap
+// authenticated call and returns a *CredentialsError saying what is wrong
+// if they cannot be used to send. Call it at startup to fail fast instead of
+// on the first message. Errors unrelated to the credentials, such as
+// network failures, are returned as they are.
+func (c *Client) ValidateCredentials(ctx context.Context) error {
+	creds, err := c.currentCredentials().Credentials(ctx)
+	if errors.Is(err, ErrMissingCredentials) || (err == nil && (creds.APIKey == "" || creds.APISecret == "")) {
+		return &CredentialsError{Problem: CredentialsMissing, Err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	info, err := c.Messages.GetUserInfo(ctx)
+	if err != nil {
+		if problem := credentialsProblem(err); problem != "" {
+			return &CredentialsError{Problem: problem, Err: err}
+		}
+		return fmt.Errorf("failed to validate credentials: %w", err)
+	}
+	if info.Suspended() {
+		return &CredentialsError{Problem: CredentialsAccountSuspended}
+	}
+	return nil
+}
+
+// credentialsProblem classifies an API error caused by the credentials, or
+// returns "" for other errors.
+func credentialsProblem(err error) string {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	switch {
+	case apiErr.Code == ErrCodeInvalidAPIKey:
+		return CredentialsInvalidKey
+	case apiErr.Code == ErrCodeInvalidAPISecret:
+		return CredentialsInvalidSecret
+	case apiErr.Code == ErrCodeAccountSuspended:
+		return CredentialsAccountSuspended
+	case apiErr.Code == ErrCodeInvalidCredentials, apiErr.Code == ErrCodeUnauthorized,
+		apiErr.StatusCode == http.StatusUnauthorized:
+		return CredentialsRejected
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    interface{}
+		problem string
+	}{
+		{name: "valid", status: http.StatusOK, body: UserInfo{ID: "u1", Status: AccountActive}},
+		{name: "bad key", status: http.StatusUnauthorized, body: APIError{Code: ErrCodeInvalidAPIKey, Message: "Unknown API key"}, problem: CredentialsInvalidKey},
+		{name: "bad secret", status: http.StatusUnauthorized, body: APIError{Code: ErrCodeInvalidAPISecret, Message: "Secret mismatch"}, problem: CredentialsInvalidSecret},
+		{name: "unspecified", status: http.StatusUnauthorized, body: map[string]string{}, problem: CredentialsRejected},
+		{name: "suspended error", status: http.StatusForbidden, body: APIError{Code: ErrCodeAccountSuspended, Message: "Account suspended"}, problem: CredentialsAccountSuspended},
+		{name: "suspended status", status: http.StatusOK, body: UserInfo{ID: "u1", Status: AccountBlocked}, problem: CredentialsAccountSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/user/info" {
+					t.Errorf("Expected path /user/info, got %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(tt.body)
+			})
+
+			err := client.ValidateCredentials(context.Background())
+			if tt.problem == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var credErr *CredentialsError
+			if !errors.As(err, &credErr) {
+				t.Fatalf("Expected *CredentialsError, got %v", err)
+			}
+			if credErr.Problem != tt.problem {
+				t.Errorf("Expected problem %s, got %s", tt.problem, credErr.Problem)
+			}
+		})
+	}
+}
+
+func TestValidateCredentials_Missing(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no API call without credentials")
+	})
+	client.SetCredentials("", "")
+
+	var credErr *CredentialsError
+	if err := client.ValidateCredentials(context.Background()); !errors.As(err, &credErr) || credErr.Problem != CredentialsMissing {
+		t.Errorf("Expected missing credentials error, got %v", err)
+	}
+}
+
+func TestValidateCredentials_ServerError(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := client.ValidateCredentials(context.Background())
+	var credErr *CredentialsError
+	if err == nil || errors.As(err, &credErr) {
+		t.Errorf("Expected a non-credentials error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Environment variables read by EnvCredentials by default.
+const (
+	EnvAPIKey    = "SIGNALADS_API_KEY"
+	EnvAPISecret = "SIGNALADS_API_SECRET"
+)
+
+// ErrMissingCredentials is returned by a CredentialsProvider that has no
+// credentials to offer.
+var ErrMissingCredentials = errors.New("missing API credentials")
+
+// Credentials are the API key and secret sent with every request.
+type Credentials struct {
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+}
+
+// CredentialsProvider supplies the credentials for each request. It is
+// called once per request, so implementations that fetch secrets remotely
+// should cache them until they expire. Implementations must be safe for
+// concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc adapts a function to CredentialsProvider, for
+// example to read short-lived secrets from Vault.
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls f(ctx).
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// WithCredentialsProvider makes the client take its credentials from
+// provider instead of the key and secret passed to NewClient, so that
+// secrets can rotate without rebuilding the client.
+func WithCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		if provider != nil {
+			c.credentials = provider
+		}
+	}
+}
+
+// StaticCredentials is a CredentialsProvider that always returns the same
+// credentials. NewClient uses it for the key and secret it is given.
+type StaticCredentials Credentials
+
+// Credentials returns the fixed credentials.
+func (s StaticCredentials) Credentials(context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+// EnvCredentials reads credentials from environment variables on every
+// request. Empty variable names default to EnvAPIKey and EnvAPISecret.
+type EnvCredentials struct {
+	KeyVar    string
+	SecretVar string
+}
+
+// Credentials returns the current values of the environment variables.
+func (e EnvCredentials) Credentials(context.Context) (Credentials, error) {
+	keyVar, secretVar := e.KeyVar, e.SecretVar
+	if keyVar == "" {
+		keyVar = EnvAPIKey
+	}
+	if secretVar == "" {
+		secretVar = EnvAPISecret
+	}
+
+	creds := Credentials{APIKey: os.Getenv(keyVar), APISecret: os.Getenv(secretVar)}
+	if creds.APIKey == "" || creds.APISecret == "" {
+		return Credentials{}, fmt.Errorf("%w: %s and %s must be set", ErrMissingCredentials, keyVar, secretVar)
+	}
+	return creds, nil
+}
+
+// FileCredentials reads credentials from a JSON file with "api_key" and
+// "api_secret" fields and reloads it whenever the file changes, which suits
+// secrets written to disk by an agent such as Vault Agent.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	creds   Credentials
+}
+
+// NewFileCredentials returns a provider reading from path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Credentials returns the credentials in the file, reloading it if its
+// modification time or size changed since the last read.
+func (f *FileCredentials) Credentials(context.Context) (Credentials, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.creds.APIKey != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.creds, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("failed to decode credentials file: %w", err)
+	}
+	if creds.APIKey == "" || creds.APISecret == "" {
+		return Credentials{}, fmt.Errorf("%w in %s", ErrMissingCredentials, f.path)
+	}
+
+	f.creds = creds
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return creds, nil
+}
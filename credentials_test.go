@@ -0,0 +1,103 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithCredentialsProvider(t *testing.T) {
+	var gotKey, gotSecret string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-API-Key")
+		gotSecret = r.Header.Get("X-API-Secret")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	client := setupTestClient(handler)
+	version := 1
+	WithCredentialsProvider(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		if version == 1 {
+			return Credentials{APIKey: "key-1", APISecret: "secret-1"}, nil
+		}
+		return Credentials{APIKey: "key-2", APISecret: "secret-2"}, nil
+	}))(client)
+
+	if err := client.Get(context.Background(), "/user", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotKey != "key-1" || gotSecret != "secret-1" {
+		t.Errorf("Expected key-1/secret-1, got %s/%s", gotKey, gotSecret)
+	}
+
+	version = 2
+	if err := client.Get(context.Background(), "/user", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotKey != "key-2" || gotSecret != "secret-2" {
+		t.Errorf("Expected rotated key-2/secret-2, got %s/%s", gotKey, gotSecret)
+	}
+}
+
+func TestCredentialsProvider_Error(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request without credentials")
+	}
+
+	client := setupTestClient(handler)
+	WithCredentialsProvider(EnvCredentials{KeyVar: "SIGNALADS_TEST_MISSING_KEY", SecretVar: "SIGNALADS_TEST_MISSING_SECRET"})(client)
+
+	err := client.Get(context.Background(), "/user", nil, nil)
+	if !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("Expected ErrMissingCredentials, got %v", err)
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvAPISecret, "env-secret")
+
+	creds, err := EnvCredentials{}.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "env-key" || creds.APISecret != "env-secret" {
+		t.Errorf("Expected env-key/env-secret, got %s/%s", creds.APIKey, creds.APISecret)
+	}
+}
+
+func TestFileCredentials_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, []byte(`{"api_key": "file-key", "api_secret": "file-secret"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileCredentials(path)
+	creds, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "file-key" {
+		t.Errorf("Expected 'file-key', got '%s'", creds.APIKey)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"api_key": "rotated-key", "api_secret": "rotated-secret"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err = provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "rotated-key" {
+		t.Errorf("Expected 'rotated-key' after reload, got '%s'", creds.APIKey)
+	}
+}
@@ -0,0 +1,72 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+)
+
+// UnlimitedQuota is returned by RemainingToday for accounts without a daily
+// send quota.
+const UnlimitedQuota = -1
+
+// QuotaService provides the account's daily send quota, as enforced by the
+// API. Client-side quotas are set with a Governor instead.
+type QuotaService struct {
+	client *Client
+}
+
+// GetDailyQuota retrieves the account's daily send quota and today's usage.
+func (s *QuotaService) GetDailyQuota(ctx context.Context) (*DailyQuota, error) {
+	var quota DailyQuota
+	if err := s.client.Get(ctx, "/account/quota", &quota, nil); err != nil {
+		return nil, fmt.Errorf("failed to get daily quota: %w", err)
+	}
+	if quota.Limit > 0 && quota.Remaining == 0 {
+		quota.Remaining = max(quota.Limit-quota.Used, 0)
+	}
+
+	return &quota, nil
+}
+
+// RemainingToday returns how many more messages the account may send today,
+// or UnlimitedQuota if it has no daily quota.
+func (s *QuotaService) RemainingToday(ctx context.Context) (int, error) {
+	quota, err := s.GetDailyQuota(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if quota.Unlimited() {
+		return UnlimitedQuota, nil
+	}
+	return quota.Remaining, nil
+}
+
+// Forecast checks whether planned messages fit in what remains of today's
+// quota. Run it before a bulk send so that a campaign is not cut off
+// halfway through.
+func (s *QuotaService) Forecast(ctx context.Context, planned int) (*QuotaForecast, error) {
+	quota, err := s.GetDailyQuota(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return quota.Forecast(planned), nil
+}
+
+// Unlimited reports whether the account has no daily quota.
+func (q *DailyQuota) Unlimited() bool {
+	return q.Limit <= 0
+}
+
+// Forecast checks whether planned messages fit in the remaining quota.
+func (q *DailyQuota) Forecast(planned int) *QuotaForecast {
+	forecast := &QuotaForecast{Planned: planned, Remaining: q.Remaining, ResetsAt: q.ResetsAt, Fits: true}
+	if q.Unlimited() {
+		forecast.Remaining = UnlimitedQuota
+		return forecast
+	}
+	if planned > q.Remaining {
+		forecast.Fits = false
+		forecast.Shortfall = planned - q.Remaining
+	}
+	return forecast
+}
@@ -0,0 +1,97 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetDailyQuota(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account/quota" {
+			t.Errorf("Expected path /account/quota, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"limit": 10000, "used": 9200, "resets_at": "2024-03-02T00:00:00Z"}`))
+	})
+
+	quota, err := client.Quota.GetDailyQuota(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if quota.Remaining != 800 {
+		t.Errorf("Expected remaining 800 derived from limit and used, got %d", quota.Remaining)
+	}
+	if !quota.ResetsAt.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected reset at midnight, got %s", quota.ResetsAt)
+	}
+
+	remaining, err := client.Quota.RemainingToday(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining != 800 {
+		t.Errorf("Expected remaining 800, got %d", remaining)
+	}
+}
+
+func TestRemainingToday_Unlimited(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"limit": 0, "used": 52}`))
+	})
+
+	remaining, err := client.Quota.RemainingToday(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining != UnlimitedQuota {
+		t.Errorf("Expected UnlimitedQuota, got %d", remaining)
+	}
+}
+
+func TestDailyQuota_Forecast(t *testing.T) {
+	tests := []struct {
+		name          string
+		quota         DailyQuota
+		planned       int
+		wantFits      bool
+		wantShortfall int
+	}{
+		{"fits", DailyQuota{Limit: 1000, Used: 200, Remaining: 800}, 800, true, 0},
+		{"too large", DailyQuota{Limit: 1000, Used: 200, Remaining: 800}, 1500, false, 700},
+		{"exhausted", DailyQuota{Limit: 1000, Used: 1000}, 1, false, 1},
+		{"unlimited", DailyQuota{}, 1000000, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := tt.quota.Forecast(tt.planned)
+			if forecast.Fits != tt.wantFits {
+				t.Errorf("Expected fits %v, got %v", tt.wantFits, forecast.Fits)
+			}
+			if forecast.Shortfall != tt.wantShortfall {
+				t.Errorf("Expected shortfall %d, got %d", tt.wantShortfall, forecast.Shortfall)
+			}
+			if forecast.Planned != tt.planned {
+				t.Errorf("Expected planned %d, got %d", tt.planned, forecast.Planned)
+			}
+		})
+	}
+}
+
+func TestQuotaService_Forecast(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"limit": 500, "used": 450, "remaining": 50}`))
+	})
+
+	forecast, err := client.Quota.Forecast(context.Background(), 120)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forecast.Fits || forecast.Shortfall != 70 || forecast.Remaining != 50 {
+		t.Errorf("Expected shortfall of 70 with 50 remaining, got %+v", forecast)
+	}
+}
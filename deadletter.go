@@ -0,0 +1,182 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeadLetter is a webhook event whose callbacks failed.
+type DeadLetter struct {
+	Event WebhookEvent `json:"event"`
+
+	// Error returned by the last failed attempt
+	Error string `json:"error"`
+
+	// Number of failed attempts, including the original delivery
+	Attempts int `json:"attempts"`
+
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+}
+
+// DeadLetterStore keeps webhook events that failed processing until they
+// are replayed. Implementations must be safe for concurrent use.
+type DeadLetterStore interface {
+	// Save inserts or replaces the dead letter for letter.Event.ID.
+	Save(ctx context.Context, letter *DeadLetter) error
+
+	// List returns stored dead letters, oldest failure first. A limit above
+	// zero caps the result.
+	List(ctx context.Context, limit int) ([]*DeadLetter, error)
+
+	// Delete removes the dead letter of eventID. Deleting an unknown event
+	// is not an error.
+	Delete(ctx context.Context, eventID string) error
+}
+
+// WithDeadLetterStore makes the handler keep events whose callbacks fail in
+// store and acknowledge them, instead of answering with a server error and
+// relying on the provider to deliver them again. Use Replay to process them
+// once the cause is fixed. If saving to store fails, the handler still
+// answers with a server error.
+func WithDeadLetterStore(store DeadLetterStore) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.deadLetters = store
+	}
+}
+
+// Fail records event in the dead-letter store as failed with cause, so that
+// Replay delivers it again. Consumers of an event channel (see
+// WithEventChannel) report failures with Done instead, which keeps the
+// event here when the handler has a dead-letter store. It returns
+// an error if the handler has no dead-letter store.
+func (h *WebhookHandler) Fail(ctx context.Context, event *WebhookEvent, cause error) error {
+	if h.deadLetters == nil {
+		return fmt.Errorf("failed to save dead letter %s: no dead-letter store", event.ID)
+	}
+	now := time.Now()
+	letter := &DeadLetter{Event: *event, Error: cause.Error(), Attempts: 1, FirstFailedAt: now, LastFailedAt: now}
+	if err := h.deadLetters.Save(ctx, letter); err != nil {
+		return fmt.Errorf("failed to save dead letter %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// Replay dispatches the stored dead letters again, oldest first, and
+// deletes those that now succeed. Letters that fail again stay in the store
+// with their attempt count increased. In channel mode, the letters are
+// queued on the channel instead, waiting for room as needed, and succeed
+// once the consumer reports them done. It returns the
+// number of events processed successfully and, if any failed or ctx ended
+// the replay early, a *PartialResult listing the event IDs replayed and
+// not replayed. Replay does nothing on a handler without a dead-letter
+// store.
+func (h *WebhookHandler) Replay(ctx context.Context) (int, error) {
+	if h.deadLetters == nil {
+		return 0, nil
+	}
+
+	letters, err := h.deadLetters.List(ctx, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	var errs []error
+	partial := &PartialResult{}
+	for i, letter := range letters {
+		if ctx.Err() != nil {
+			for _, remaining := range letters[i:] {
+				partial.Failed = append(partial.Failed, remaining.Event.ID)
+			}
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		event := letter.Event
+		if dispatchErr := h.replay(ctx, &event); dispatchErr != nil {
+			letter.Error = dispatchErr.Error()
+			letter.Attempts++
+			letter.LastFailedAt = time.Now()
+			if saveErr := h.deadLetters.Save(ctx, letter); saveErr != nil {
+				dispatchErr = errors.Join(dispatchErr, fmt.Errorf("failed to save dead letter %s: %w", event.ID, saveErr))
+			}
+			errs = append(errs, dispatchErr)
+			partial.Failed = append(partial.Failed, event.ID)
+			continue
+		}
+
+		if deleteErr := h.deadLetters.Delete(ctx, event.ID); deleteErr != nil {
+			errs = append(errs, fmt.Errorf("failed to delete dead letter %s: %w", event.ID, deleteErr))
+		}
+		partial.Completed = append(partial.Completed, event.ID)
+	}
+	if len(errs) == 0 {
+		return len(partial.Completed), nil
+	}
+	partial.Err = errors.Join(errs...)
+	return len(partial.Completed), partial
+}
+
+func (h *WebhookHandler) replay(ctx context.Context, event *WebhookEvent) error {
+	if h.events != nil {
+		if err := h.enqueue(ctx, event, OverflowBlock); err != nil {
+			return err
+		}
+		return h.awaitDone(ctx, event)
+	}
+	return h.Dispatch(ctx, event)
+}
+
+// MemoryDeadLetterStore is a DeadLetterStore that keeps events in memory.
+// Dead letters are lost on restart, so production deployments should use a
+// persistent store.
+type MemoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters map[string]DeadLetter
+}
+
+// NewMemoryDeadLetterStore creates an empty MemoryDeadLetterStore.
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{letters: make(map[string]DeadLetter)}
+}
+
+// Save implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) Save(_ context.Context, letter *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters[letter.Event.ID] = *letter
+	return nil
+}
+
+// List implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) List(_ context.Context, limit int) ([]*DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := make([]*DeadLetter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		l := letter
+		letters = append(letters, &l)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].FirstFailedAt.Before(letters[j].FirstFailedAt)
+	})
+	if limit > 0 && len(letters) > limit {
+		letters = letters[:limit]
+	}
+	return letters, nil
+}
+
+// Delete implements DeadLetterStore.
+func (s *MemoryDeadLetterStore) Delete(_ context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.letters, eventID)
+	return nil
+}
@@ -0,0 +1,88 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler_DeadLetterReplay(t *testing.T) {
+	store := NewMemoryDeadLetterStore()
+	h := newTestWebhookHandler(WithDeadLetterStore(store))
+
+	failing := true
+	var handled []string
+	h.OnInboundMessage(func(ctx context.Context, msg *InboundMessageEvent) error {
+		if failing {
+			return errors.New("database down")
+		}
+		handled = append(handled, msg.ID)
+		return nil
+	})
+
+	req := signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected dead-lettered event to be acknowledged, got status %d", rec.Code)
+	}
+
+	letters, err := store.List(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(letters) != 1 || letters[0].Event.ID != "evt-1" || letters[0].Attempts != 1 {
+		t.Fatalf("Expected one dead letter for evt-1, got %+v", letters)
+	}
+	if !strings.Contains(letters[0].Error, "database down") {
+		t.Errorf("Expected the callback error to be recorded, got '%s'", letters[0].Error)
+	}
+
+	n, err := h.Replay(context.Background())
+	if err == nil || n != 0 {
+		t.Errorf("Expected replay to fail again, got %d replayed, err %v", n, err)
+	}
+	letters, _ = store.List(context.Background(), 0)
+	if len(letters) != 1 || letters[0].Attempts != 2 {
+		t.Fatalf("Expected the letter to stay with 2 attempts, got %+v", letters)
+	}
+
+	failing = false
+	n, err = h.Replay(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 1 || len(handled) != 1 || handled[0] != "in-1" {
+		t.Errorf("Expected in-1 to be replayed once, got %d replayed, handled %v", n, handled)
+	}
+	if letters, _ = store.List(context.Background(), 0); len(letters) != 0 {
+		t.Errorf("Expected the store to be empty, got %d letters", len(letters))
+	}
+}
+
+type failingDeadLetterStore struct {
+	MemoryDeadLetterStore
+}
+
+func (s *failingDeadLetterStore) Save(context.Context, *DeadLetter) error {
+	return errors.New("disk full")
+}
+
+func TestWebhookHandler_DeadLetterSaveError(t *testing.T) {
+	h := newTestWebhookHandler(WithDeadLetterStore(&failingDeadLetterStore{}))
+	h.On(EventInboundMessage, func(ctx context.Context, event *WebhookEvent) error {
+		return errors.New("boom")
+	})
+
+	req := signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 when the dead letter cannot be saved, got %d", rec.Code)
+	}
+}
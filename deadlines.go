@@ -0,0 +1,71 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Default deadlines applied by WithDefaultDeadlines.
+const (
+	DefaultStatusDeadline  = 5 * time.Second
+	DefaultBulkDeadline    = 2 * time.Minute
+	DefaultRequestDeadline = 30 * time.Second
+)
+
+// Deadlines are the deadlines WithDefaultDeadlines gives requests whose
+// context has none, by endpoint class. A zero field uses the matching
+// Default*Deadline constant and a negative field leaves that class without
+// a deadline.
+type Deadlines struct {
+	// Message status lookups
+	Status time.Duration
+
+	// Bulk sends and recording downloads
+	Bulk time.Duration
+
+	// All other requests
+	Default time.Duration
+}
+
+// WithDefaultDeadlines makes every request whose context has no deadline
+// run under one from d, covering the wait for a priority queue slot, the
+// request itself, and the reading of the response. Contexts that already
+// have a deadline are left alone. The HTTP client's timeout still applies
+// on top; raise it with WithTimeout for bulk deadlines above it.
+func WithDefaultDeadlines(d Deadlines) ClientOption {
+	return func(c *Client) {
+		c.deadlines = &d
+	}
+}
+
+// withDefaultDeadline returns ctx with the default deadline for the
+// endpoint if it has none, and the function releasing it. The function is
+// nil if ctx is returned unchanged.
+func (c *Client) withDefaultDeadline(ctx context.Context, method, endpoint string) (context.Context, context.CancelFunc) {
+	if c.deadlines == nil {
+		return ctx, nil
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, nil
+	}
+
+	var d, fallback time.Duration
+	switch {
+	case method == http.MethodGet && strings.HasPrefix(endpoint, "/messages/") && strings.HasSuffix(endpoint, "/status"):
+		d, fallback = c.deadlines.Status, DefaultStatusDeadline
+	case method == http.MethodPost && strings.HasSuffix(endpoint, "/bulk"),
+		method == http.MethodGet && strings.HasSuffix(endpoint, "/recording"):
+		d, fallback = c.deadlines.Bulk, DefaultBulkDeadline
+	default:
+		d, fallback = c.deadlines.Default, DefaultRequestDeadline
+	}
+	switch {
+	case d < 0:
+		return ctx, nil
+	case d == 0:
+		d = fallback
+	}
+	return context.WithTimeout(ctx, d)
+}
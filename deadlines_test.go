@@ -0,0 +1,89 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithDefaultDeadlines_AppliesToContextsWithoutDeadline(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/messages/msg-1/status" {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "delivered"}`))
+	}
+	client := setupTestClient(handler)
+	WithDefaultDeadlines(Deadlines{Status: 20 * time.Millisecond})(client)
+
+	start := time.Now()
+	_, err := client.Messages.GetMessageStatus(context.Background(), "msg-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the status deadline to cut the request short, took %v", elapsed)
+	}
+
+	// Other endpoints use the default deadline, and the response body can
+	// still be read after the request returns.
+	if _, err = client.Messages.GetUserInfo(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWithDefaultDeadlines_KeepsCallerDeadline(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "delivered"}`))
+	}
+	client := setupTestClient(handler)
+	WithDefaultDeadlines(Deadlines{Status: time.Millisecond})(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Messages.GetMessageStatus(ctx, "msg-1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWithDefaultDeadline_Classes(t *testing.T) {
+	client := NewClient("key", "secret", WithDefaultDeadlines(Deadlines{Bulk: time.Hour, Default: -1}))
+
+	tests := []struct {
+		method   string
+		endpoint string
+		want     time.Duration
+	}{
+		{http.MethodGet, "/messages/msg-1/status", DefaultStatusDeadline},
+		{http.MethodPost, "/send-message/bulk", time.Hour},
+		{http.MethodPost, "/send-message/voice/bulk", time.Hour},
+		{http.MethodGet, "/calls/call-1/recording", time.Hour},
+		{http.MethodPost, "/send-message/single", 0},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := client.withDefaultDeadline(context.Background(), tt.method, tt.endpoint)
+		deadline, ok := ctx.Deadline()
+		if tt.want == 0 {
+			if ok || cancel != nil {
+				t.Errorf("%s %s: expected no deadline, got %v", tt.method, tt.endpoint, deadline)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s %s: expected a deadline", tt.method, tt.endpoint)
+		} else if got := time.Until(deadline); got > tt.want || got < tt.want-time.Second {
+			t.Errorf("%s %s: expected deadline in %v, got %v", tt.method, tt.endpoint, tt.want, got)
+		}
+		cancel()
+	}
+}
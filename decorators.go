@@ -0,0 +1,283 @@
+package signalads
+
+import (
+	"context"
+	"log/slog"
+	"runtime/trace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// callWrapper runs fn, the service call named method, and may act before
+// and after it.
+type callWrapper func(ctx context.Context, method string, fn func(ctx context.Context) error) error
+
+func wrapCall[T any](ctx context.Context, wrap callWrapper, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := wrap(ctx, method, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// LoggingMessages returns api with every call that reaches the API logged
+// to logger: the method and duration at Info level, and failures with
+// their error at Error level. Phone numbers in errors are redacted by the
+// Redactor set with SetRedactor.
+func LoggingMessages(api MessagesAPI, logger *slog.Logger) MessagesAPI {
+	return &messagesDecorator{MessagesAPI: api, wrap: func(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+		start := time.Now()
+		err := fn(ctx)
+		attrs := []slog.Attr{slog.String("method", method), slog.Duration("duration", time.Since(start))}
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "signalads call failed", append(attrs, slog.String("error", RedactText(err.Error())))...)
+		} else {
+			logger.LogAttrs(ctx, slog.LevelInfo, "signalads call", attrs...)
+		}
+		return err
+	}}
+}
+
+// TracedMessages returns api with every call that reaches the API run as a
+// runtime/trace task named after the method, such as
+// "signalads.SendSingleMessage", so that calls show up in execution traces
+// viewed with go tool trace. Failures are logged to the task.
+func TracedMessages(api MessagesAPI) MessagesAPI {
+	return &messagesDecorator{MessagesAPI: api, wrap: func(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+		ctx, task := trace.NewTask(ctx, "signalads."+method)
+		defer task.End()
+		err := fn(ctx)
+		if err != nil {
+			trace.Log(ctx, "error", RedactText(err.Error()))
+		}
+		return err
+	}}
+}
+
+// messagesDecorator runs every MessagesAPI call that reaches the API
+// through wrap. The convenience methods are rebuilt on the wrapped ones, so
+// that they are wrapped too, and async senders and aggregators it creates
+// send through it; the local helpers are passed through.
+type messagesDecorator struct {
+	MessagesAPI
+	wrap callWrapper
+}
+
+func (d *messagesDecorator) SendSingleMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
+	return wrapCall(ctx, d.wrap, "SendSingleMessage", func(ctx context.Context) (*SendMessageResponse, error) {
+		return d.MessagesAPI.SendSingleMessage(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendMessage(ctx context.Context, to, message string) (*SendMessageResponse, error) {
+	return d.SendSingleMessage(ctx, &SendMessageRequest{To: to, Message: message})
+}
+
+func (d *messagesDecorator) SendMessageWithDocument(ctx context.Context, to, message, documentLink, caption string) (*SendMessageResponse, error) {
+	return d.SendSingleMessage(ctx, &SendMessageRequest{To: to, Message: message, DocumentLink: documentLink, DocumentCaption: caption})
+}
+
+func (d *messagesDecorator) SendBulkMessages(ctx context.Context, req *SendBulkMessageRequest) (*SendBulkMessageResponse, error) {
+	return wrapCall(ctx, d.wrap, "SendBulkMessages", func(ctx context.Context) (*SendBulkMessageResponse, error) {
+		return d.MessagesAPI.SendBulkMessages(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendBulkMessage(ctx context.Context, messages []BulkMessageItem, from string) (*SendBulkMessageResponse, error) {
+	return d.SendBulkMessages(ctx, &SendBulkMessageRequest{Messages: messages, From: from})
+}
+
+func (d *messagesDecorator) SendTemplateMessage(ctx context.Context, req *SendTemplateMessageRequest) (*SendMessageResponse, error) {
+	return wrapCall(ctx, d.wrap, "SendTemplateMessage", func(ctx context.Context) (*SendMessageResponse, error) {
+		return d.MessagesAPI.SendTemplateMessage(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendTemplate(ctx context.Context, to, templateID string, params map[string]string) (*SendMessageResponse, error) {
+	return d.SendTemplateMessage(ctx, &SendTemplateMessageRequest{To: to, TemplateID: templateID, TemplateParams: params})
+}
+
+func (d *messagesDecorator) SendVoiceMessage(ctx context.Context, req *SendVoiceMessageRequest) (*SendMessageResponse, error) {
+	return wrapCall(ctx, d.wrap, "SendVoiceMessage", func(ctx context.Context) (*SendMessageResponse, error) {
+		return d.MessagesAPI.SendVoiceMessage(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendVoice(ctx context.Context, to, message, voiceType, language string) (*SendMessageResponse, error) {
+	return d.SendVoiceMessage(ctx, &SendVoiceMessageRequest{To: to, Message: message, VoiceType: voiceType, Language: language})
+}
+
+func (d *messagesDecorator) SendBulkVoiceMessages(ctx context.Context, req *SendBulkVoiceRequest) (*SendBulkVoiceResponse, error) {
+	return wrapCall(ctx, d.wrap, "SendBulkVoiceMessages", func(ctx context.Context) (*SendBulkVoiceResponse, error) {
+		return d.MessagesAPI.SendBulkVoiceMessages(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendBulkVoice(ctx context.Context, recipients []string, message, voiceType, language string) (*SendBulkVoiceResponse, error) {
+	return d.SendBulkVoiceMessages(ctx, &SendBulkVoiceRequest{Recipients: recipients, Message: message, VoiceType: voiceType, Language: language})
+}
+
+func (d *messagesDecorator) SendOTPMessage(ctx context.Context, req *SendOTPRequest) (*OTPSendResult, error) {
+	return wrapCall(ctx, d.wrap, "SendOTPMessage", func(ctx context.Context) (*OTPSendResult, error) {
+		return d.MessagesAPI.SendOTPMessage(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendOTP(ctx context.Context, phone, code, templateID string) (*OTPSendResult, error) {
+	return d.SendOTPMessage(ctx, &SendOTPRequest{To: phone, Code: code, TemplateID: templateID})
+}
+
+func (d *messagesDecorator) SendPatternMessage(ctx context.Context, req *SendPatternRequest) (*SendPatternResponse, error) {
+	return wrapCall(ctx, d.wrap, "SendPatternMessage", func(ctx context.Context) (*SendPatternResponse, error) {
+		return d.MessagesAPI.SendPatternMessage(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) SendPattern(ctx context.Context, to, patternCode string, values map[string]string) (*SendPatternResponse, error) {
+	return d.SendPatternMessage(ctx, &SendPatternRequest{To: to, PatternCode: patternCode, Values: values})
+}
+
+func (d *messagesDecorator) ListMessages(ctx context.Context, params *PaginationParams) (*ListMessagesResponse, error) {
+	return wrapCall(ctx, d.wrap, "ListMessages", func(ctx context.Context) (*ListMessagesResponse, error) {
+		return d.MessagesAPI.ListMessages(ctx, params)
+	})
+}
+
+func (d *messagesDecorator) StreamMessages(ctx context.Context, params *PaginationParams, fn func(*Message) error) error {
+	return d.wrap(ctx, "StreamMessages", func(ctx context.Context) error {
+		return d.MessagesAPI.StreamMessages(ctx, params, fn)
+	})
+}
+
+func (d *messagesDecorator) ListScheduledMessages(ctx context.Context, params *PaginationParams) (*ListScheduledMessagesResponse, error) {
+	return wrapCall(ctx, d.wrap, "ListScheduledMessages", func(ctx context.Context) (*ListScheduledMessagesResponse, error) {
+		return d.MessagesAPI.ListScheduledMessages(ctx, params)
+	})
+}
+
+func (d *messagesDecorator) RescheduleMessage(ctx context.Context, messageID string, newTime time.Time) (*ScheduledMessage, error) {
+	return wrapCall(ctx, d.wrap, "RescheduleMessage", func(ctx context.Context) (*ScheduledMessage, error) {
+		return d.MessagesAPI.RescheduleMessage(ctx, messageID, newTime)
+	})
+}
+
+func (d *messagesDecorator) GetMessageStatus(ctx context.Context, messageID string) (*MessageStatus, error) {
+	return wrapCall(ctx, d.wrap, "GetMessageStatus", func(ctx context.Context) (*MessageStatus, error) {
+		return d.MessagesAPI.GetMessageStatus(ctx, messageID)
+	})
+}
+
+func (d *messagesDecorator) GetVoiceCampaign(ctx context.Context, campaignID string) (*VoiceCampaign, error) {
+	return wrapCall(ctx, d.wrap, "GetVoiceCampaign", func(ctx context.Context) (*VoiceCampaign, error) {
+		return d.MessagesAPI.GetVoiceCampaign(ctx, campaignID)
+	})
+}
+
+func (d *messagesDecorator) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	return wrapCall(ctx, d.wrap, "GetUserInfo", func(ctx context.Context) (*UserInfo, error) {
+		return d.MessagesAPI.GetUserInfo(ctx)
+	})
+}
+
+func (d *messagesDecorator) CheckDocumentLink(ctx context.Context, link string) error {
+	return d.wrap(ctx, "CheckDocumentLink", func(ctx context.Context) error {
+		return d.MessagesAPI.CheckDocumentLink(ctx, link)
+	})
+}
+
+func (d *messagesDecorator) EstimateAndReserve(ctx context.Context, req *SendBulkMessageRequest) (*CostEstimate, error) {
+	return wrapCall(ctx, d.wrap, "EstimateAndReserve", func(ctx context.Context) (*CostEstimate, error) {
+		return d.MessagesAPI.EstimateAndReserve(ctx, req)
+	})
+}
+
+func (d *messagesDecorator) RunExperiment(ctx context.Context, exp *Experiment, recipients []string) (*ExperimentRun, error) {
+	return wrapCall(ctx, d.wrap, "RunExperiment", func(ctx context.Context) (*ExperimentRun, error) {
+		return d.MessagesAPI.RunExperiment(ctx, exp, recipients)
+	})
+}
+
+func (d *messagesDecorator) ExperimentStats(ctx context.Context, run *ExperimentRun) ([]VariantStats, error) {
+	return wrapCall(ctx, d.wrap, "ExperimentStats", func(ctx context.Context) ([]VariantStats, error) {
+		return d.MessagesAPI.ExperimentStats(ctx, run)
+	})
+}
+
+func (d *messagesDecorator) Async(workers int, opts ...AsyncOption) *AsyncSender {
+	return d.MessagesAPI.Async(workers, append(opts[:len(opts):len(opts)], withAsyncMessages(d))...)
+}
+
+func (d *messagesDecorator) Aggregator(opts ...AggregatorOption) *Aggregator {
+	return d.MessagesAPI.Aggregator(append(opts[:len(opts):len(opts)], withAggregatorMessages(d))...)
+}
+
+// CachedTemplates returns api with the results of ListTemplates and
+// GetUsageStats kept for ttl, so that code rendering templates on every
+// send does not list them from the API every time. Errors are not cached.
+// Other calls are passed through.
+func CachedTemplates(api TemplatesAPI, ttl time.Duration) TemplatesAPI {
+	return &cachedTemplates{TemplatesAPI: api, ttl: ttl, now: time.Now, entries: make(map[string]cachedTemplatesEntry)}
+}
+
+type cachedTemplatesEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+type cachedTemplates struct {
+	TemplatesAPI
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedTemplatesEntry
+}
+
+func (c *cachedTemplates) ListTemplates(ctx context.Context, params *PaginationParams) (*ListTemplatesResponse, error) {
+	return cachedCall(c, "list?"+queryKey(paginationQuery(params)), func() (*ListTemplatesResponse, error) {
+		return c.TemplatesAPI.ListTemplates(ctx, params)
+	})
+}
+
+func (c *cachedTemplates) GetUsageStats(ctx context.Context, templateID string, dateRange DateRange) (*TemplateUsageStats, error) {
+	return cachedCall(c, "stats/"+templateID+"?"+queryKey(dateRange.query()), func() (*TemplateUsageStats, error) {
+		return c.TemplatesAPI.GetUsageStats(ctx, templateID, dateRange)
+	})
+}
+
+func cachedCall[T any](c *cachedTemplates, key string, fn func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.value.(T), nil
+	}
+
+	value, err := fn()
+	if err != nil {
+		return value, err
+	}
+	c.mu.Lock()
+	c.entries[key] = cachedTemplatesEntry{value: value, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// queryKey returns query with its keys sorted, for use as a cache key.
+func queryKey(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + query[k] + "&")
+	}
+	return b.String()
+}
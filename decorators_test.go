@@ -0,0 +1,145 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggingMessages(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/status") {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"code": "not_found", "message": "Message not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	var buf bytes.Buffer
+	client.Messages = LoggingMessages(client.Messages, slog.New(slog.NewTextHandler(&buf, nil)))
+
+	ctx := context.Background()
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Test"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Messages.GetMessageStatus(ctx, "msg-1"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "level=INFO") || !strings.Contains(lines[0], "method=SendSingleMessage") {
+		t.Errorf("Expected the send logged at info level, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "level=ERROR") || !strings.Contains(lines[1], "method=GetMessageStatus") || !strings.Contains(lines[1], "error=") {
+		t.Errorf("Expected the failed status lookup logged at error level, got %q", lines[1])
+	}
+}
+
+func TestLoggingMessages_AsyncAndAggregator(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "bulk") {
+			json.NewEncoder(w).Encode(SendBulkMessageResponse{Total: 1, Status: "sent", Results: []SendMessageResponse{{ID: "msg-2", Status: "sent", To: "+989123456780"}}})
+			return
+		}
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	var buf bytes.Buffer
+	logged := LoggingMessages(client.Messages, slog.New(slog.NewTextHandler(&buf, nil)))
+
+	ctx := context.Background()
+	sender := logged.Async(1)
+	if _, err := sender.Send(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"}).Result(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sender.Close()
+
+	agg := logged.Aggregator(WithAggregatorWindow(10 * time.Millisecond))
+	if _, err := agg.Send(ctx, &SendMessageRequest{To: "+989123456780", Message: "Test"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	agg.Close()
+
+	out := buf.String()
+	if !strings.Contains(out, "method=SendSingleMessage") {
+		t.Errorf("Expected the async send logged, got %q", out)
+	}
+	if !strings.Contains(out, "method=SendBulkMessages") {
+		t.Errorf("Expected the aggregated send logged, got %q", out)
+	}
+}
+
+func TestTracedMessages(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	client.Messages = TracedMessages(client.Messages)
+
+	resp, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.ID != "msg-1" {
+		t.Errorf("Expected ID 'msg-1', got '%s'", resp.ID)
+	}
+}
+
+func TestCachedTemplates(t *testing.T) {
+	lists := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		lists++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListTemplatesResponse{Templates: []Template{{ID: "tpl-1"}}})
+	}
+
+	client := setupTestClient(handler)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cached := CachedTemplates(client.Templates, time.Minute)
+	cached.(*cachedTemplates).now = func() time.Time { return now }
+	client.Templates = cached
+
+	ctx := context.Background()
+	list := func(page int) {
+		t.Helper()
+		resp, err := client.Templates.ListTemplates(ctx, &PaginationParams{Page: page})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resp.Templates) != 1 {
+			t.Fatalf("Expected 1 template, got %d", len(resp.Templates))
+		}
+	}
+
+	list(1)
+	list(1)
+	if lists != 1 {
+		t.Errorf("Expected the second list served from the cache, got %d requests", lists)
+	}
+
+	list(2)
+	if lists != 2 {
+		t.Errorf("Expected another page to miss the cache, got %d requests", lists)
+	}
+
+	now = now.Add(2 * time.Minute)
+	list(1)
+	if lists != 3 {
+		t.Errorf("Expected an expired entry to be refreshed, got %d requests", lists)
+	}
+}
@@ -0,0 +1,154 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDocumentLink is wrapped by the *DocumentLinkError returned when
+// a document link fails the pre-flight check.
+var ErrInvalidDocumentLink = errors.New("invalid document link")
+
+// DocumentLinkError describes why a document link cannot be sent.
+type DocumentLinkError struct {
+	Link   string
+	Reason string
+
+	// HTTP status of the check, if the server answered
+	StatusCode int
+}
+
+func (e *DocumentLinkError) Error() string {
+	return fmt.Sprintf("%v %s: %s", ErrInvalidDocumentLink, e.Link, e.Reason)
+}
+
+func (e *DocumentLinkError) Unwrap() error {
+	return ErrInvalidDocumentLink
+}
+
+// defaultDocumentCheckTimeout bounds a document link check.
+const defaultDocumentCheckTimeout = 10 * time.Second
+
+type documentCheck struct {
+	contentTypes []string
+	maxSize      int64
+	httpClient   *http.Client
+}
+
+// WithDocumentLinkCheck makes sends with a DocumentLink first request the
+// link, and fail with a *DocumentLinkError unless it is reachable without
+// credentials, of one of contentTypes and at most maxSize bytes. Empty
+// contentTypes allows any type and a zero maxSize any size. Content types
+// are matched without parameters, and "image/*" matches any image type.
+func WithDocumentLinkCheck(contentTypes []string, maxSize int64) ClientOption {
+	return func(c *Client) {
+		c.documentCheck = &documentCheck{
+			contentTypes: contentTypes,
+			maxSize:      maxSize,
+			httpClient:   &http.Client{Timeout: defaultDocumentCheckTimeout},
+		}
+	}
+}
+
+// CheckDocumentLink runs the client's document link check on link. Without
+// WithDocumentLinkCheck, it only checks that link is reachable.
+func (s *MessagesService) CheckDocumentLink(ctx context.Context, link string) error {
+	check := s.client.documentCheck
+	if check == nil {
+		check = &documentCheck{httpClient: &http.Client{Timeout: defaultDocumentCheckTimeout}}
+	}
+	return check.run(ctx, link)
+}
+
+func (d *documentCheck) run(ctx context.Context, link string) error {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &DocumentLinkError{Link: link, Reason: "not an absolute http(s) URL"}
+	}
+
+	resp, err := d.request(ctx, http.MethodHead, link)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = d.request(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &DocumentLinkError{Link: link, Reason: "not reachable: " + err.Error()}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &DocumentLinkError{Link: link, Reason: "not publicly accessible", StatusCode: resp.StatusCode}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return &DocumentLinkError{Link: link, Reason: "not reachable, status " + strconv.Itoa(resp.StatusCode), StatusCode: resp.StatusCode}
+	}
+
+	if len(d.contentTypes) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if !matchContentType(mediaType, d.contentTypes) {
+			return &DocumentLinkError{Link: link, Reason: fmt.Sprintf("content type %q is not allowed", mediaType), StatusCode: resp.StatusCode}
+		}
+	}
+
+	if d.maxSize > 0 {
+		if size := responseSize(resp); size > d.maxSize {
+			return &DocumentLinkError{Link: link, Reason: fmt.Sprintf("size %d bytes exceeds the limit of %d", size, d.maxSize), StatusCode: resp.StatusCode}
+		}
+	}
+	return nil
+}
+
+// request sends a bodiless request for link. GET requests ask for the
+// first byte only.
+func (d *documentCheck) request(ctx context.Context, method, link string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// responseSize returns the full size of the resource, from Content-Range
+// for partial responses, or -1 if unknown.
+func responseSize(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		cr := resp.Header.Get("Content-Range")
+		if i := strings.LastIndexByte(cr, '/'); i >= 0 {
+			if size, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil {
+				return size
+			}
+		}
+		return -1
+	}
+	return resp.ContentLength
+}
+
+func matchContentType(mediaType string, allowed []string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newDocumentServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/invoice.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Length", "2048")
+		case "/big.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Length", "50000000")
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		case "/private.pdf":
+			w.WriteHeader(http.StatusForbidden)
+			return
+		case "/no-head.png":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.Header.Get("Range") != "bytes=0-0" {
+				t.Errorf("Expected a range request, got '%s'", r.Header.Get("Range"))
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Range", "bytes 0-0/4096")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte{0})
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckDocumentLink(t *testing.T) {
+	docs := newDocumentServer(t)
+	client := NewClient("key", "secret", WithDocumentLinkCheck([]string{"application/pdf", "image/*"}, 10<<20))
+
+	tests := []struct {
+		path   string
+		reason string
+	}{
+		{"/invoice.pdf", ""},
+		{"/no-head.png", ""},
+		{"/big.pdf", "exceeds the limit"},
+		{"/page.html", "content type \"text/html\" is not allowed"},
+		{"/private.pdf", "not publicly accessible"},
+		{"/missing.pdf", "status 404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := client.Messages.CheckDocumentLink(context.Background(), docs.URL+tt.path)
+			if tt.reason == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var linkErr *DocumentLinkError
+			if !errors.As(err, &linkErr) {
+				t.Fatalf("Expected *DocumentLinkError, got %v", err)
+			}
+			if !strings.Contains(linkErr.Reason, tt.reason) {
+				t.Errorf("Expected reason containing %q, got %q", tt.reason, linkErr.Reason)
+			}
+		})
+	}
+
+	if err := client.Messages.CheckDocumentLink(context.Background(), "ftp://example.com/a.pdf"); !errors.Is(err, ErrInvalidDocumentLink) {
+		t.Errorf("Expected ErrInvalidDocumentLink for a non-http URL, got %v", err)
+	}
+}
+
+func TestSendMessageWithDocument_LinkCheck(t *testing.T) {
+	docs := newDocumentServer(t)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no send for a broken document link")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	client := NewClient("key", "secret", WithBaseURL(server.URL), WithDocumentLinkCheck(nil, 0))
+
+	_, err := client.Messages.SendMessageWithDocument(context.Background(), "+989123456789", "Your invoice", docs.URL+"/missing.pdf", "")
+	if !errors.Is(err, ErrInvalidDocumentLink) {
+		t.Errorf("Expected ErrInvalidDocumentLink, got %v", err)
+	}
+}
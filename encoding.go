@@ -0,0 +1,95 @@
+package signalads
+
+import "strings"
+
+// Encoding selects the character encoding of an SMS.
+type Encoding string
+
+const (
+	// EncodingAuto lets the gateway pick GSM-7 when the text allows it and
+	// UCS-2 otherwise. It is the default.
+	EncodingAuto Encoding = "auto"
+
+	// EncodingGSM7 forces the GSM 7-bit alphabet. Requests whose text
+	// contains other characters are rejected before sending; use
+	// ToGSM7Safe to replace them.
+	EncodingGSM7 Encoding = "gsm7"
+
+	// EncodingUCS2 forces UCS-2, for example so that a message is billed
+	// consistently whatever its content.
+	EncodingUCS2 Encoding = "ucs2"
+)
+
+// CountSegmentsWithEncoding returns how many SMS segments text occupies
+// when sent with encoding. EncodingAuto and the zero value behave like
+// CountSegments.
+func CountSegmentsWithEncoding(text string, encoding Encoding) int {
+	switch encoding {
+	case EncodingGSM7:
+		return segmentsFor(gsm7Length(text), GSM7SingleSegmentLength, GSM7MultiSegmentLength)
+	case EncodingUCS2:
+		return segmentsFor(ucs2Length(text), UCS2SingleSegmentLength, UCS2MultiSegmentLength)
+	default:
+		return CountSegments(text)
+	}
+}
+
+// gsm7Transliterations maps common characters outside the GSM alphabet to
+// close GSM equivalents.
+var gsm7Transliterations = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '′': "'",
+	'“': "\"", '”': "\"", '„': "\"", '«': "\"", '»': "\"", '″': "\"",
+	'–': "-", '—': "-", '‐': "-", '−': "-",
+	'…': "...", '•': "*", '·': ".",
+	'\u00a0': " ", '\u200b': "", '\u200c': "", '\t': " ",
+	'á': "a", 'â': "a", 'ã': "a", 'ā': "a",
+	'Á': "A", 'À': "A", 'Â': "A", 'Ã': "A",
+	'ç': "c", 'ê': "e", 'ë': "e", 'È': "E", 'Ê': "E", 'Ë': "E",
+	'í': "i", 'î': "i", 'ï': "i", 'Í': "I", 'Ì': "I", 'Î': "I", 'Ï': "I",
+	'ó': "o", 'ô': "o", 'õ': "o", 'Ó': "O", 'Ò': "O", 'Ô': "O", 'Õ': "O",
+	'ú': "u", 'û': "u", 'Ú': "U", 'Ù': "U", 'Û': "U",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y",
+	'œ': "oe", 'Œ': "OE", 'ş': "s", 'Ş': "S", 'ğ': "g", 'Ğ': "G", 'ı': "i", 'İ': "I",
+	'۰': "0", '۱': "1", '۲': "2", '۳': "3", '۴': "4",
+	'۵': "5", '۶': "6", '۷': "7", '۸': "8", '۹': "9",
+	'٠': "0", '١': "1", '٢': "2", '٣': "3", '٤': "4",
+	'٥': "5", '٦': "6", '٧': "7", '٨': "8", '٩': "9",
+	'،': ",", '؛': ";", '؟': "?",
+}
+
+// ToGSM7Safe returns text with every character outside the GSM 7-bit
+// alphabet replaced: typographic quotes, dashes and accented letters by
+// their plain equivalents, Persian and Arabic digits by ASCII digits and
+// anything else by '?'. The result can always be sent with EncodingGSM7.
+func ToGSM7Safe(text string) string {
+	if IsGSM7(text) {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		if gsm7BasicSet[r] || gsm7ExtendedSet[r] {
+			b.WriteRune(r)
+		} else if repl, ok := gsm7Transliterations[r]; ok {
+			b.WriteString(repl)
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// encoding records a field error when text cannot be sent with
+// encoding.
+func (v *validator) encoding(field, text string, encoding Encoding) {
+	switch encoding {
+	case "", EncodingAuto, EncodingUCS2:
+	case EncodingGSM7:
+		if !IsGSM7(text) {
+			v.add(field, RuleEncoding, "text contains characters outside the GSM-7 alphabet; use ToGSM7Safe")
+		}
+	default:
+		v.add("encoding", RuleEncoding, "unknown encoding "+string(encoding))
+	}
+}
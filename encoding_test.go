@@ -0,0 +1,75 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestToGSM7Safe(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "Hello", expected: "Hello"},
+		{text: "“Sale” – 50% off…", expected: "\"Sale\" - 50% off..."},
+		{text: "Café crème brûlée", expected: "Café crème brulée"},
+		{text: "Code: ۴۸۲۱", expected: "Code: 4821"},
+		{text: "سلام", expected: "????"},
+	}
+
+	for _, tt := range tests {
+		got := ToGSM7Safe(tt.text)
+		if got != tt.expected {
+			t.Errorf("ToGSM7Safe(%q) = %q, want %q", tt.text, got, tt.expected)
+		}
+		if !IsGSM7(got) {
+			t.Errorf("Expected %q to be GSM-7", got)
+		}
+	}
+}
+
+func TestCountSegmentsWithEncoding(t *testing.T) {
+	text := strings.Repeat("a", 100)
+	if got := CountSegmentsWithEncoding(text, EncodingAuto); got != 1 {
+		t.Errorf("Expected 1 segment with auto encoding, got %d", got)
+	}
+	if got := CountSegmentsWithEncoding(text, EncodingUCS2); got != 2 {
+		t.Errorf("Expected 2 segments with forced UCS-2, got %d", got)
+	}
+}
+
+func TestSendMessage_ForcedEncoding(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Encoding != EncodingGSM7 {
+			t.Errorf("Expected encoding 'gsm7', got '%s'", req.Encoding)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	ctx := context.Background()
+
+	_, err := client.Messages.SendSingleMessage(ctx, &SendMessageRequest{
+		To:       "+989123456789",
+		Message:  "Your code is ۴۸۲۱",
+		Encoding: EncodingGSM7,
+	})
+	if !IsValidationError(err) {
+		t.Fatalf("Expected validation error for non-GSM text, got %v", err)
+	}
+
+	_, err = client.Messages.SendSingleMessage(ctx, &SendMessageRequest{
+		To:       "+989123456789",
+		Message:  ToGSM7Safe("Your code is ۴۸۲۱"),
+		Encoding: EncodingGSM7,
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package signalads
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// encryptedPrefix marks values encrypted by a store's Encrypter, so that
+// rows written before encryption was enabled can still be read.
+const encryptedPrefix = "enc:v1:"
+
+// ErrDecrypt is wrapped by the errors of stores that cannot decrypt a
+// value, for example because it was encrypted with another key.
+var ErrDecrypt = errors.New("failed to decrypt value")
+
+// Encrypter encrypts the message bodies and phone numbers that stores
+// persist outside the process. SQLOutboxStore, FileOutboxStore,
+// SQLTrackerStore, RedisTrackerStore and FileCheckpointer accept one.
+// Implementations must be safe for concurrent use.
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AESGCMEncrypter is an Encrypter using AES-GCM with a random nonce per
+// value. Besides the current key it can hold old keys, so that values
+// written before a key rotation can still be read.
+type AESGCMEncrypter struct {
+	current cipher.AEAD
+	old     []cipher.AEAD
+}
+
+// NewAESGCMEncrypter creates an encrypter that encrypts with key, which
+// must be 16, 24 or 32 bytes long, and decrypts with key or any of
+// oldKeys.
+func NewAESGCMEncrypter(key []byte, oldKeys ...[]byte) (*AESGCMEncrypter, error) {
+	current, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &AESGCMEncrypter{current: current}
+	for _, k := range oldKeys {
+		old, oldErr := newGCM(k)
+		if oldErr != nil {
+			return nil, oldErr
+		}
+		e.old = append(e.old, old)
+	}
+	return e, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt implements Encrypter. The nonce is prepended to the ciphertext.
+func (e *AESGCMEncrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, e.current.NonceSize(), e.current.NonceSize()+len(plaintext)+e.current.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return e.current.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt implements Encrypter.
+func (e *AESGCMEncrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	for _, aead := range append([]cipher.AEAD{e.current}, e.old...) {
+		if len(ciphertext) < aead.NonceSize() {
+			continue
+		}
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+		if plaintext, err := aead.Open(nil, nonce, sealed, nil); err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, ErrDecrypt
+}
+
+// encryptString encrypts s with e into a text value, or returns s as is if
+// e is nil.
+func encryptString(e Encrypter, s string) (string, error) {
+	if e == nil {
+		return s, nil
+	}
+	ciphertext, err := e.Encrypt([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptString reverses encryptString. Values without the encrypted
+// prefix are returned as is, so that stores keep reading rows written
+// before encryption was enabled.
+func decryptString(e Encrypter, s string) (string, error) {
+	encoded, ok := strings.CutPrefix(s, encryptedPrefix)
+	if !ok {
+		return s, nil
+	}
+	if e == nil {
+		return "", fmt.Errorf("%w: no encrypter configured", ErrDecrypt)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	plaintext, err := e.Decrypt(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// encryptFile encrypts the contents of a file-backed store with e, or
+// returns data as is if e is nil.
+func encryptFile(e Encrypter, data []byte) ([]byte, error) {
+	if e == nil {
+		return data, nil
+	}
+	sealed, err := encryptString(e, string(data))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(sealed), nil
+}
+
+// decryptFile reverses encryptFile. Contents without the encrypted prefix
+// are returned as is.
+func decryptFile(e Encrypter, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedPrefix)) {
+		return data, nil
+	}
+	plaintext, err := decryptString(e, string(data))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(plaintext), nil
+}
@@ -0,0 +1,202 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAESGCMEncrypter(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	old, err := NewAESGCMEncrypter(oldKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ciphertext, err := old.Encrypt([]byte("Code: 1234"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("1234")) {
+		t.Error("Expected the code not to appear in the ciphertext")
+	}
+
+	rotated, err := NewAESGCMEncrypter(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	plaintext, err := rotated.Decrypt(ciphertext)
+	if err != nil || string(plaintext) != "Code: 1234" {
+		t.Errorf("Expected the old key to decrypt, got %q and %v", plaintext, err)
+	}
+
+	other, _ := NewAESGCMEncrypter(newKey)
+	if _, err = other.Decrypt(ciphertext); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt with the wrong key, got %v", err)
+	}
+
+	if _, err = NewAESGCMEncrypter([]byte("short")); err == nil {
+		t.Error("Expected an invalid key length to be rejected")
+	}
+}
+
+func TestRedisTrackerStore_Encrypted(t *testing.T) {
+	redis := newFakeRedis()
+	e, _ := NewAESGCMEncrypter(bytes.Repeat([]byte{1}, 16))
+	store := NewRedisTrackerStore(redis, "test:", WithRedisEncrypter(e))
+	ctx := context.Background()
+
+	// Written before encryption was enabled
+	redis.Set(ctx, "test:msg:msg_old", `{"message_id": "msg_old", "to": "+989121111111", "status": "sent"}`)
+
+	if err := store.Save(ctx, &TrackedMessage{MessageID: "msg_1", To: "+989123456789", Status: "sent", SentAt: time.Now()}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if raw := redis.strings["test:msg:msg_1"]; strings.Contains(raw, "989123456789") {
+		t.Errorf("Expected the number to be encrypted, got %s", raw)
+	}
+
+	for id, to := range map[string]string{"msg_1": "+989123456789", "msg_old": "+989121111111"} {
+		msg, err := store.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if msg.To != to {
+			t.Errorf("Expected recipient %s for %s, got %s", to, id, msg.To)
+		}
+	}
+}
+
+func TestSQLTrackerStore_Encrypted(t *testing.T) {
+	fake := &fakeSQLDriver{rows: make(map[string][]driver.Value)}
+	sql.Register("signalads-fake-encrypted", fake)
+	db, err := sql.Open("signalads-fake-encrypted", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	e, _ := NewAESGCMEncrypter(bytes.Repeat([]byte{1}, 32))
+	store, err := NewSQLTrackerStore(db, WithSQLEncrypter(e))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	if err = store.CreateTable(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(fake.queries[0], "recipient VARCHAR(128)") {
+		t.Errorf("Expected a wider recipient column, got %s", fake.queries[0])
+	}
+
+	if err = store.Save(ctx, &TrackedMessage{MessageID: "msg_1", To: "+989123456789", Status: "sent", SentAt: time.Now()}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	raw := fake.rows["msg_1"][1].(string)
+	if strings.Contains(raw, "989123456789") || len(raw) > 128 {
+		t.Errorf("Expected an encrypted recipient within 128 characters, got %s", raw)
+	}
+
+	msg, err := store.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.To != "+989123456789" {
+		t.Errorf("Expected recipient +989123456789, got %s", msg.To)
+	}
+}
+
+func TestSQLOutboxStore_Encrypted(t *testing.T) {
+	db := openFakeOutboxDB(t, "signalads-fake-outbox-encrypted")
+	e, _ := NewAESGCMEncrypter(bytes.Repeat([]byte{1}, 32))
+	store, err := NewSQLOutboxStore(db, WithSQLOutboxEncrypter(e))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	id, err := store.EnqueueTx(ctx, tx, &SendMessageRequest{To: "+989123456789", Message: "Code: 1234"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tx.Commit()
+
+	var raw string
+	if err = db.QueryRowContext(ctx, `SELECT `+sqlOutboxColumns+` FROM signalads_outbox WHERE id = ?`, id).Scan(
+		new(string), &raw, new(string), new(int), new(string), new(string), new(int64), new(int64), new(int64)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(raw, "1234") || strings.Contains(raw, "989123456789") {
+		t.Errorf("Expected the request to be encrypted, got %s", raw)
+	}
+
+	msg, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Request.Message != "Code: 1234" || msg.Request.To != "+989123456789" {
+		t.Errorf("Expected the decrypted request, got %+v", msg.Request)
+	}
+}
+
+func TestFileStores_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+	e, _ := NewAESGCMEncrypter(bytes.Repeat([]byte{1}, 32))
+	ctx := context.Background()
+
+	outboxPath := filepath.Join(dir, "outbox.json")
+	store, err := NewFileOutboxStore(outboxPath, WithFileOutboxEncrypter(e))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	msg := &OutboxMessage{ID: "m1", Request: SendMessageRequest{To: "+989123456789", Message: "Code: 1234"}, Status: OutboxStatusPending}
+	if err = store.Save(ctx, msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	raw, _ := os.ReadFile(outboxPath)
+	if bytes.Contains(raw, []byte("1234")) || bytes.Contains(raw, []byte("989123456789")) {
+		t.Errorf("Expected the outbox file to be encrypted, got %s", raw)
+	}
+	reopened, err := NewFileOutboxStore(outboxPath, WithFileOutboxEncrypter(e))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, err := reopened.Get(ctx, "m1"); err != nil || got.Request.Message != "Code: 1234" {
+		t.Errorf("Expected the decrypted message, got %+v, %v", got, err)
+	}
+	if _, err = NewFileOutboxStore(outboxPath); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt without the encrypter, got %v", err)
+	}
+
+	cpPath := filepath.Join(dir, "checkpoints.json")
+	checkpoints, err := NewFileCheckpointer(cpPath, WithFileCheckpointerEncrypter(e))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = checkpoints.Save(ctx, "campaign-42", &Checkpoint{Sent: []IndexRange{{From: 0, To: 10}}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	raw, _ = os.ReadFile(cpPath)
+	if bytes.Contains(raw, []byte("campaign-42")) {
+		t.Errorf("Expected the checkpoint file to be encrypted, got %s", raw)
+	}
+	reopenedCheckpoints, err := NewFileCheckpointer(cpPath, WithFileCheckpointerEncrypter(e))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cp, err := reopenedCheckpoints.Load(ctx, "campaign-42"); err != nil || !cp.Contains(9) {
+		t.Errorf("Expected the decrypted checkpoint, got %+v, %v", cp, err)
+	}
+}
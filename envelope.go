@@ -0,0 +1,121 @@
+package signalads
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// envelopeKeys are the only top-level fields of a response envelope. A
+// response with any other field is a bare object, even if it has status and
+// data fields of its own.
+var envelopeKeys = map[string]bool{
+	"status":     true,
+	"data":       true,
+	"message":    true,
+	"code":       true,
+	"error":      true,
+	"meta":       true,
+	"pagination": true,
+}
+
+// unwrapEnvelope returns the payload of a {"status": "success", "data": ...}
+// envelope, or an *APIError for a {"status": "error", ...} envelope. Other
+// bodies are returned unchanged. Pagination metadata next to data is moved
+// into it, so that list responses see it.
+func unwrapEnvelope(body []byte) ([]byte, *APIError) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' || !bytes.Contains(trimmed, []byte(`"status"`)) {
+		return body, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return body, nil
+	}
+	for key := range fields {
+		if !envelopeKeys[key] {
+			return body, nil
+		}
+	}
+	var status string
+	if err := json.Unmarshal(fields["status"], &status); err != nil {
+		return body, nil
+	}
+
+	switch strings.ToLower(status) {
+	case "error":
+		apiErr := &APIError{}
+		if err := json.Unmarshal(trimmed, apiErr); err != nil {
+			apiErr = &APIError{}
+		}
+		if apiErr.Message == "" && apiErr.ErrorMsg == "" {
+			apiErr.Message = "API returned an error response"
+		}
+		return nil, apiErr
+	case "success":
+		data, ok := fields["data"]
+		if !ok {
+			return body, nil
+		}
+		return withEnvelopeMeta(data, fields), nil
+	default:
+		return body, nil
+	}
+}
+
+// withEnvelopeMeta copies the envelope's meta and pagination fields into
+// data, if data is an object without them.
+func withEnvelopeMeta(data json.RawMessage, envelope map[string]json.RawMessage) []byte {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return data
+	}
+	_, hasMeta := envelope["meta"]
+	_, hasPagination := envelope["pagination"]
+	if !hasMeta && !hasPagination {
+		return data
+	}
+
+	var inner map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &inner); err != nil {
+		return data
+	}
+	for _, key := range []string{"meta", "pagination"} {
+		if value, ok := envelope[key]; ok {
+			if _, taken := inner[key]; !taken {
+				inner[key] = value
+			}
+		}
+	}
+	merged, err := json.Marshal(inner)
+	if err != nil {
+		return data
+	}
+	return merged
+}
+
+// isErrorEnvelope reports whether the top-level members in fields, collected
+// so far, mark a {"status": "error"} envelope.
+func isErrorEnvelope(fields map[string]json.RawMessage) bool {
+	var status string
+	if err := json.Unmarshal(fields["status"], &status); err != nil {
+		return false
+	}
+	return strings.EqualFold(status, "error")
+}
+
+// envelopeError returns the *APIError of an error envelope with the given
+// top-level members, or nil if they do not form one. It lets streamed
+// responses report the same errors as unwrapEnvelope.
+func envelopeError(fields map[string]json.RawMessage) *APIError {
+	if !isErrorEnvelope(fields) {
+		return nil
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	_, apiErr := unwrapEnvelope(body)
+	return apiErr
+}
@@ -0,0 +1,104 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestEnvelope_Unwrap(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "success", "data": {"messages": [{"id": "m1"}, {"id": "m2"}]}, "meta": {"page": 1, "per_page": 2, "total": 3}}`))
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Messages.ListMessages(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Messages) != 2 || response.Messages[1].ID != "m2" {
+		t.Errorf("Expected the enveloped messages, got %+v", response.Messages)
+	}
+	if response.Pagination.Total != 3 || !response.Pagination.HasNext {
+		t.Errorf("Expected pagination from the envelope meta, got %+v", response.Pagination)
+	}
+}
+
+func TestEnvelope_BareObjectWithStatusAndData(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "m1", "status": "success", "data": {"operator": "mci"}}`))
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Messages.SendMessage(context.Background(), "+989123456789", "hi")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.ID != "m1" || response.Data["operator"] != "mci" {
+		t.Errorf("Expected the bare object to decode as is, got %+v", response)
+	}
+}
+
+func TestEnvelope_Error(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "error", "code": "INSUFFICIENT_BALANCE", "message": "balance too low"}`))
+	}
+
+	client := setupTestClient(handler)
+	_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "hi")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+	if apiErr.Code != ErrCodeInsufficientBalance || apiErr.Message != "balance too low" {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestEnvelope_Stream(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "success", "data": {"messages": [{"id": "m1"}, {"id": "m2"}]}}`))
+	}
+
+	client := setupTestClient(handler)
+	var ids []string
+	err := client.Messages.StreamMessages(context.Background(), nil, func(msg *Message) error {
+		ids = append(ids, msg.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Errorf("Expected 2 streamed messages, got %v", ids)
+	}
+}
+
+func TestEnvelope_StreamError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "error", "code": "INVALID_CREDENTIALS", "message": "bad key", "data": [{"id": "m1"}]}`))
+	}
+
+	client := setupTestClient(handler)
+	var ids []string
+	err := client.Messages.StreamMessages(context.Background(), nil, func(msg *Message) error {
+		ids = append(ids, msg.ID)
+		return nil
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+	if apiErr.Code != ErrCodeInvalidCredentials || apiErr.Message != "bad key" {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+	if len(ids) != 0 {
+		t.Errorf("Expected nothing streamed from an error envelope, got %v", ids)
+	}
+}
@@ -1,6 +1,7 @@
 package signalads
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -11,6 +12,10 @@ type APIError struct {
 	StatusCode int                    `json:"status_code,omitempty"`
 	ErrorMsg   string                 `json:"error,omitempty"`
 	Details    map[string]interface{} `json:"details,omitempty"`
+
+	// Message in the locale configured with WithLocale, when the API sent
+	// one or the SDK has a translation for Code; empty otherwise
+	LocalizedMessage string `json:"localized_message,omitempty"`
 }
 
 func (e *APIError) Error() string {
@@ -26,6 +31,17 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error: status %d", e.StatusCode)
 }
 
+// Is reports whether target is an *APIError with the same error code, so
+// that errors.Is(err, ErrInsufficientBalance) matches any error carrying
+// that code.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok || t.Code == "" {
+		return false
+	}
+	return e.Code == t.Code
+}
+
 const (
 	//nolint:gosec // This is an error code constant, not a credential
 	ErrCodeInvalidCredentials  = "INVALID_CREDENTIALS"
@@ -43,6 +59,9 @@ const (
 	ErrCodeTemplateNotApproved = "TEMPLATE_NOT_APPROVED"
 	ErrCodeInvalidDocument     = "INVALID_DOCUMENT"
 	ErrCodeInvalidVoiceFormat  = "INVALID_VOICE_FORMAT"
+	ErrCodeInvalidAPIKey       = "INVALID_API_KEY"
+	ErrCodeInvalidAPISecret    = "INVALID_API_SECRET" //nolint:gosec // This is an error code constant, not a credential
+	ErrCodeAccountSuspended    = "ACCOUNT_SUSPENDED"
 )
 
 var (
@@ -77,27 +96,84 @@ var (
 	}
 )
 
+// TransportError is returned when a request could not be completed because
+// of a network problem, such as a refused connection, a timeout or a
+// response body that broke off. The API may or may not have acted on the
+// request, so only idempotent calls should be retried blindly.
+type TransportError struct {
+	// Operation that failed, such as "request failed" or "failed to read
+	// response body"
+	Op string
+
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a successful response could not be decoded
+// into the expected type. It usually means the SDK and the API disagree on
+// the response format and is worth reporting as a bug.
+type DecodeError struct {
+	// Raw response body, when it was read in full
+	Body []byte
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransportError reports whether err is or wraps a *TransportError.
+func IsTransportError(err error) bool {
+	var transportErr *TransportError
+	return errors.As(err, &transportErr)
+}
+
+// IsDecodeError reports whether err is or wraps a *DecodeError.
+func IsDecodeError(err error) bool {
+	var decodeErr *DecodeError
+	return errors.As(err, &decodeErr)
+}
+
 func IsAPIError(err error) bool {
-	_, ok := err.(*APIError)
+	_, ok := asAPIError(err)
 	return ok
 }
 
+// asAPIError returns the *APIError err is or wraps.
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
 func GetStatusCode(err error) int {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.StatusCode
 	}
 	return 0
 }
 
 func GetErrorCode(err error) string {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.Code
 	}
 	return ""
 }
 
 func IsErrorCode(err error, code string) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.Code == code
 	}
 	return false
@@ -116,7 +192,7 @@ func WrapError(err error, statusCode int) *APIError {
 		return nil
 	}
 
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr
 	}
 
@@ -127,35 +203,35 @@ func WrapError(err error, statusCode int) *APIError {
 }
 
 func IsNotFound(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.StatusCode == http.StatusNotFound || apiErr.Code == ErrCodeNotFound
 	}
 	return false
 }
 
 func IsUnauthorized(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.StatusCode == http.StatusUnauthorized || apiErr.Code == ErrCodeInvalidCredentials
 	}
 	return false
 }
 
 func IsRateLimited(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Code == ErrCodeRateLimitExceeded
 	}
 	return false
 }
 
 func IsInsufficientBalance(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.Code == ErrCodeInsufficientBalance || apiErr.StatusCode == http.StatusPaymentRequired
 	}
 	return false
 }
 
 func IsBadRequest(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	if apiErr, ok := asAPIError(err); ok {
 		return apiErr.StatusCode == http.StatusBadRequest || apiErr.Code == ErrCodeBadRequest
 	}
 	return false
@@ -1,8 +1,12 @@
 package signalads
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -88,6 +92,40 @@ func TestIsAPIError(t *testing.T) {
 	}
 }
 
+func TestAPIErrorHelpers_Wrapped(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"code": "INSUFFICIENT_BALANCE", "message": "Balance too low"}`))
+	}
+	client := setupTestClient(handler)
+
+	_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Test")
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	err = fmt.Errorf("notify customer: %w", err)
+
+	if !IsAPIError(err) {
+		t.Error("Expected IsAPIError to see through wrapping")
+	}
+	if code := GetStatusCode(err); code != http.StatusPaymentRequired {
+		t.Errorf("Expected status code %d, got %d", http.StatusPaymentRequired, code)
+	}
+	if code := GetErrorCode(err); code != ErrCodeInsufficientBalance {
+		t.Errorf("Expected error code %s, got %s", ErrCodeInsufficientBalance, code)
+	}
+	if !IsErrorCode(err, ErrCodeInsufficientBalance) {
+		t.Error("Expected IsErrorCode to see through wrapping")
+	}
+	if !IsInsufficientBalance(err) {
+		t.Error("Expected IsInsufficientBalance to see through wrapping")
+	}
+	if IsNotFound(err) || IsUnauthorized(err) || IsRateLimited(err) || IsBadRequest(err) {
+		t.Error("Expected the other helpers not to match")
+	}
+}
+
 func TestGetStatusCode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -492,3 +530,79 @@ func TestPredefinedErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIError_Is(t *testing.T) {
+	err := fmt.Errorf("send failed: %w", NewAPIError(ErrCodeRateLimitExceeded, "slow down", http.StatusTooManyRequests))
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("Expected wrapped error to match ErrRateLimited")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("Expected wrapped error not to match ErrNotFound")
+	}
+	if errors.Is(&APIError{Message: "no code"}, &APIError{Message: "no code"}) {
+		t.Error("Expected errors without a code not to match")
+	}
+}
+
+func TestErrorTaxonomy(t *testing.T) {
+	t.Run("transport", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		client := NewClient("test-key", "test-secret", WithBaseURL(server.URL))
+		_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi")
+
+		var transportErr *TransportError
+		if !errors.As(err, &transportErr) {
+			t.Fatalf("Expected *TransportError, got %T: %v", err, err)
+		}
+		if IsDecodeError(err) {
+			t.Error("Expected transport error not to be a decode error")
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": 42}`))
+		})
+		_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi")
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("Expected *DecodeError, got %T: %v", err, err)
+		}
+		if string(decodeErr.Body) != `{"id": 42}` {
+			t.Errorf("Expected raw body to be kept, got %q", decodeErr.Body)
+		}
+		if IsTransportError(err) {
+			t.Error("Expected decode error not to be a transport error")
+		}
+	})
+
+	t.Run("api", func(t *testing.T) {
+		client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": "INVALID_PHONE_NUMBER", "message": "Invalid phone number"}`))
+		})
+		_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi")
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected *APIError, got %T: %v", err, err)
+		}
+		if IsTransportError(err) || IsDecodeError(err) {
+			t.Error("Expected API error to be neither transport nor decode error")
+		}
+	})
+}
+
+func TestStreamError(t *testing.T) {
+	if !IsDecodeError(streamError(io.ErrUnexpectedEOF)) {
+		t.Error("Expected truncated JSON to be a decode error")
+	}
+	if !IsTransportError(streamError(fmt.Errorf("connection reset"))) {
+		t.Error("Expected read failure to be a transport error")
+	}
+}
@@ -0,0 +1,82 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// WithSegmentPrice sets the price of one SMS segment in the account's
+// currency, used by EstimateAndReserve to estimate campaign cost.
+func WithSegmentPrice(price float64) ClientOption {
+	return func(c *Client) {
+		c.segmentPrice = price
+	}
+}
+
+// CostEstimate is the projected cost of a bulk send.
+type CostEstimate struct {
+	// Number of messages in the request
+	Messages int
+
+	// Total number of SMS segments across all messages
+	Segments int
+
+	// Estimated cost (Segments multiplied by the segment price)
+	Cost float64
+
+	// Account balance at the time of the estimate
+	Balance float64
+
+	// Amount missing to cover Cost; zero when the balance is sufficient
+	Shortfall float64
+}
+
+// EstimateAndReserve estimates the segment count and cost of req and checks
+// it against the current account balance before anything is submitted. When
+// the balance is too low it returns the estimate together with an
+// insufficient-balance *APIError whose Details carry "required",
+// "available" and "shortfall". The check does not hold funds on the server,
+// so concurrent campaigns must still coordinate their spending.
+func (s *MessagesService) EstimateAndReserve(ctx context.Context, req *SendBulkMessageRequest) (*CostEstimate, error) {
+	if err := validateSendBulkMessageRequest(req); err != nil {
+		return nil, err
+	}
+	req, err := s.client.expandBulkRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if s.client.segmentPrice <= 0 {
+		return nil, fmt.Errorf("segment price is not configured; use WithSegmentPrice")
+	}
+
+	estimate := &CostEstimate{Messages: len(req.Messages)}
+	for i := range req.Messages {
+		estimate.Segments += CountSegmentsWithEncoding(req.Messages[i].Message, req.Encoding)
+	}
+	estimate.Cost = float64(estimate.Segments) * s.client.segmentPrice
+
+	info, err := s.GetUserInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	estimate.Balance = info.Balance
+
+	if estimate.Cost > estimate.Balance {
+		estimate.Shortfall = estimate.Cost - estimate.Balance
+		apiErr := &APIError{
+			Code:       ErrCodeInsufficientBalance,
+			Message:    fmt.Sprintf("Insufficient balance: campaign needs %.2f, balance is %.2f (short %.2f)", estimate.Cost, estimate.Balance, estimate.Shortfall),
+			StatusCode: http.StatusPaymentRequired,
+			Details: map[string]interface{}{
+				"required":  estimate.Cost,
+				"available": estimate.Balance,
+				"shortfall": estimate.Shortfall,
+			},
+		}
+		s.client.localizeError(apiErr)
+		return estimate, apiErr
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,63 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEstimateAndReserve(t *testing.T) {
+	req := &SendBulkMessageRequest{
+		Messages: []BulkMessageItem{
+			{To: "+989123456789", Message: "Hello"},
+			{To: "+989123456790", Message: strings.Repeat("a", 200)},
+		},
+	}
+	ctx := context.Background()
+
+	t.Run("sufficient balance", func(t *testing.T) {
+		server := httptest.NewServer(userInfoHandler(100))
+		defer server.Close()
+		client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithSegmentPrice(10))
+
+		estimate, err := client.Messages.EstimateAndReserve(ctx, req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if estimate.Segments != 3 {
+			t.Errorf("Expected 3 segments, got %d", estimate.Segments)
+		}
+		if estimate.Cost != 30 {
+			t.Errorf("Expected cost 30, got %.2f", estimate.Cost)
+		}
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		server := httptest.NewServer(userInfoHandler(25))
+		defer server.Close()
+		client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithSegmentPrice(10))
+
+		estimate, err := client.Messages.EstimateAndReserve(ctx, req)
+		if !errors.Is(err, ErrInsufficientBalance) {
+			t.Fatalf("Expected ErrInsufficientBalance, got %v", err)
+		}
+		if estimate == nil || estimate.Shortfall != 5 {
+			t.Errorf("Expected shortfall 5, got %+v", estimate)
+		}
+		if shortfall := err.(*APIError).Details["shortfall"]; shortfall != 5.0 {
+			t.Errorf("Expected shortfall 5 in error details, got %v", shortfall)
+		}
+	})
+
+	t.Run("price not configured", func(t *testing.T) {
+		client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Expected no API call")
+		})
+		if _, err := client.Messages.EstimateAndReserve(ctx, req); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
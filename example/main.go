@@ -105,7 +105,7 @@ func main() {
 	if err != nil {
 		log.Printf("Error listing messages: %v\n", err)
 	} else {
-		fmt.Printf("Found %d messages (Total: %d)\n", len(messagesList.Messages), messagesList.Total)
+		fmt.Printf("Found %d messages (Total: %d)\n", len(messagesList.Messages), messagesList.Pagination.Total)
 		for i := range messagesList.Messages {
 			if i >= 5 {
 				break
@@ -0,0 +1,372 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// Params keys that tag the messages of an experiment.
+const (
+	ParamExperimentID = "experiment_id"
+	ParamVariantID    = "variant_id"
+)
+
+// Experiment is an A/B test of message variants over a recipient list.
+type Experiment struct {
+	// Identifier of the experiment, also used to assign recipients
+	ID string
+
+	Variants []Variant
+
+	// Sender ID or phone number (optional)
+	From string
+}
+
+// Variant is one version of an experiment's message. Exactly one of Message
+// and TemplateID must be set.
+type Variant struct {
+	ID string
+
+	// Relative share of recipients; zero or less counts as 1
+	Weight int
+
+	// Message text
+	Message string
+
+	// Template to send instead of a text
+	TemplateID     string
+	TemplateParams map[string]string
+
+	// Short link included in the message, whose clicks are attributed to the
+	// variant (optional)
+	LinkID string
+}
+
+// ExperimentRun records what RunExperiment sent, for ExperimentStats. It can
+// be stored as JSON and evaluated later.
+type ExperimentRun struct {
+	ExperimentID string       `json:"experiment_id"`
+	Variants     []VariantRun `json:"variants"`
+}
+
+// VariantRun records the messages sent for one variant.
+type VariantRun struct {
+	VariantID string `json:"variant_id"`
+	LinkID    string `json:"link_id,omitempty"`
+
+	// Recipients assigned to the variant
+	Recipients []string `json:"recipients"`
+
+	// Messages accepted by the API
+	Messages []SendMessageResponse `json:"messages"`
+
+	// Recipients whose message was not accepted
+	Failed []BulkFailure `json:"failed,omitempty"`
+}
+
+// VariantStats aggregates the outcome of one variant.
+type VariantStats struct {
+	VariantID string
+
+	// Messages accepted by the API
+	Sent int
+
+	// Messages not accepted by the API
+	SendFailed int
+
+	Delivered   int
+	Undelivered int
+	Pending     int
+
+	// Number of clicks on the variant's links, and number of distinct
+	// recipients who clicked
+	Clicks   int
+	Clickers int
+}
+
+// DeliveryRate returns the share of accepted messages that were delivered.
+func (v *VariantStats) DeliveryRate() float64 {
+	if v.Sent == 0 {
+		return 0
+	}
+	return float64(v.Delivered) / float64(v.Sent)
+}
+
+// ClickRate returns the share of delivered messages whose recipient clicked.
+func (v *VariantStats) ClickRate() float64 {
+	if v.Delivered == 0 {
+		return 0
+	}
+	return float64(v.Clickers) / float64(v.Delivered)
+}
+
+// Assign returns the variant recipient belongs to. Assignment is a stable
+// hash of the experiment ID and the recipient, so re-running an experiment
+// keeps every recipient in the same variant.
+func (e *Experiment) Assign(recipient string) *Variant {
+	total := 0
+	for i := range e.Variants {
+		total += variantWeight(&e.Variants[i])
+	}
+	if total == 0 {
+		return nil
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(e.ID + "\x00" + recipient))
+	bucket := int(h.Sum64() % uint64(total))
+	for i := range e.Variants {
+		bucket -= variantWeight(&e.Variants[i])
+		if bucket < 0 {
+			return &e.Variants[i]
+		}
+	}
+	return &e.Variants[len(e.Variants)-1]
+}
+
+func variantWeight(v *Variant) int {
+	if v.Weight <= 0 {
+		return 1
+	}
+	return v.Weight
+}
+
+func validateExperiment(exp *Experiment, recipients []string) error {
+	if exp == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	v.required("id", exp.ID, "experiment ID is required")
+	if len(exp.Variants) == 0 {
+		v.add("variants", RuleMinItems, "at least one variant is required")
+	}
+	if len(recipients) == 0 {
+		v.add("recipients", RuleMinItems, "at least one recipient is required")
+	}
+
+	seen := make(map[string]bool, len(exp.Variants))
+	for i := range exp.Variants {
+		variant := &exp.Variants[i]
+		v.required(indexedField("variants", i, "id"), variant.ID, "variant ID is required")
+		if seen[variant.ID] {
+			v.add(indexedField("variants", i, "id"), RuleUnique, "variant ID "+strconv.Quote(variant.ID)+" is used twice")
+		}
+		seen[variant.ID] = true
+		if (variant.Message == "") == (variant.TemplateID == "") {
+			v.add(indexedField("variants", i, "message"), RuleRequiredWithout, "exactly one of message and template_id is required")
+		}
+	}
+	return v.err()
+}
+
+// RunExperiment splits recipients between the experiment's variants and
+// sends each variant's message, tagged with ParamExperimentID and
+// ParamVariantID. Text variants go out as one bulk send each; template
+// variants as one send per recipient. A variant that fails does not stop
+// the others: the returned run covers everything that was sent, and the
+// returned *PartialResult joins the failures and lists the recipients
+// reached. ctx is checked before every send, so a canceled run marks the
+// recipients it did not reach as failed and stops.
+func (s *MessagesService) RunExperiment(ctx context.Context, exp *Experiment, recipients []string) (*ExperimentRun, error) {
+	if err := validateExperiment(exp, recipients); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]string, len(exp.Variants))
+	for _, recipient := range recipients {
+		variant := exp.Assign(recipient)
+		groups[variant.ID] = append(groups[variant.ID], recipient)
+	}
+
+	run := &ExperimentRun{ExperimentID: exp.ID}
+	var errs []error
+	for i := range exp.Variants {
+		variant := &exp.Variants[i]
+		vr := VariantRun{VariantID: variant.ID, LinkID: variant.LinkID, Recipients: groups[variant.ID]}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			vr.failRemaining(0, ctxErr)
+			if len(vr.Recipients) > 0 {
+				errs = append(errs, fmt.Errorf("variant %s: %w", variant.ID, ctxErr))
+			}
+		} else if len(vr.Recipients) > 0 {
+			params := map[string]interface{}{ParamExperimentID: exp.ID, ParamVariantID: variant.ID}
+			var err error
+			if variant.TemplateID != "" {
+				err = s.sendTemplateVariant(ctx, exp, variant, params, &vr)
+			} else {
+				err = s.sendTextVariant(ctx, exp, variant, params, &vr)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("variant %s: %w", variant.ID, err))
+			}
+		}
+		run.Variants = append(run.Variants, vr)
+	}
+
+	if len(errs) == 0 {
+		return run, nil
+	}
+	var sent []SendMessageResponse
+	var failed []BulkFailure
+	for i := range run.Variants {
+		sent = append(sent, run.Variants[i].Messages...)
+		failed = append(failed, run.Variants[i].Failed...)
+	}
+	return run, sentPartially(sent, failed, errors.Join(errs...))
+}
+
+func (s *MessagesService) sendTextVariant(ctx context.Context, exp *Experiment, variant *Variant, params map[string]interface{}, vr *VariantRun) error {
+	req := &SendBulkMessageRequest{From: exp.From, Params: params}
+	for _, recipient := range vr.Recipients {
+		req.Messages = append(req.Messages, BulkMessageItem{To: recipient, Message: variant.Message})
+	}
+
+	response, err := s.SendBulkMessages(ctx, req)
+	if err != nil {
+		for i, recipient := range vr.Recipients {
+			vr.Failed = append(vr.Failed, BulkFailure{Index: i, Recipient: recipient, Message: err.Error()})
+		}
+		return err
+	}
+
+	vr.Messages = response.sentMessages(req)
+	vr.Failed = append(vr.Failed, response.Rejected...)
+	vr.Failed = append(vr.Failed, response.FailedItems...)
+	return nil
+}
+
+func (s *MessagesService) sendTemplateVariant(ctx context.Context, exp *Experiment, variant *Variant, params map[string]interface{}, vr *VariantRun) error {
+	var errs []error
+	for i, recipient := range vr.Recipients {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			vr.failRemaining(i, ctxErr)
+			errs = append(errs, ctxErr)
+			break
+		}
+		response, err := s.SendTemplateMessage(ctx, &SendTemplateMessageRequest{
+			To:             recipient,
+			TemplateID:     variant.TemplateID,
+			TemplateParams: variant.TemplateParams,
+			From:           exp.From,
+			Params:         params,
+		})
+		if err != nil {
+			vr.Failed = append(vr.Failed, BulkFailure{Index: i, Recipient: recipient, Message: err.Error()})
+			errs = append(errs, err)
+			continue
+		}
+		if response.To == "" {
+			response.To = recipient
+		}
+		vr.Messages = append(vr.Messages, *response)
+	}
+	return errors.Join(errs...)
+}
+
+// ExperimentStats aggregates delivery and click statistics per variant of
+// run. Delivery statuses come from the client's StatusTracker when one is
+// configured and knows the message, and from GetMessageStatus otherwise.
+// Clicks come from the variants' short links.
+func (s *MessagesService) ExperimentStats(ctx context.Context, run *ExperimentRun) ([]VariantStats, error) {
+	variantOf := make(map[string]int)
+	recipientOf := make(map[string]string)
+	recipientVariant := make(map[string]int)
+	stats := make([]VariantStats, len(run.Variants))
+
+	for i := range run.Variants {
+		vr := &run.Variants[i]
+		st := &stats[i]
+		st.VariantID = vr.VariantID
+		st.Sent = len(vr.Messages)
+		st.SendFailed = len(vr.Failed)
+
+		for _, msg := range vr.Messages {
+			variantOf[msg.ID] = i
+			recipientOf[msg.ID] = msg.To
+			recipientVariant[msg.To] = i
+
+			status, err := s.experimentMessageStatus(ctx, msg.ID)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case !status.Terminal():
+				st.Pending++
+			case status == StatusDelivered:
+				st.Delivered++
+			default:
+				st.Undelivered++
+			}
+		}
+	}
+
+	clickers := make(map[string]bool)
+	for _, linkID := range experimentLinks(run) {
+		err := eachClick(ctx, s.client.Shortlinks, linkID, func(click *LinkClickEvent) {
+			recipient := click.Recipient
+			if recipient == "" {
+				recipient = recipientOf[click.MessageID]
+			}
+			i, ok := variantOf[click.MessageID]
+			if !ok {
+				i, ok = recipientVariant[recipient]
+			}
+			if !ok {
+				return
+			}
+			stats[i].Clicks++
+			key := strconv.Itoa(i) + "\x00" + recipient
+			if recipient != "" && !clickers[key] {
+				clickers[key] = true
+				stats[i].Clickers++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *MessagesService) experimentMessageStatus(ctx context.Context, messageID string) (MessageStatusCode, error) {
+	if s.client.tracker != nil {
+		msg, err := s.client.tracker.Get(ctx, messageID)
+		if err == nil {
+			return NormalizeStatus(msg.Status), nil
+		}
+		if !errors.Is(err, ErrTrackedMessageNotFound) {
+			return "", err
+		}
+	}
+
+	status, err := s.GetMessageStatus(ctx, messageID)
+	if err != nil {
+		return "", err
+	}
+	return status.StatusCode(), nil
+}
+
+// experimentLinks returns the distinct link IDs of run's variants.
+func experimentLinks(run *ExperimentRun) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for i := range run.Variants {
+		if id := run.Variants[i].LinkID; id != "" && !seen[id] {
+			seen[id] = true
+			links = append(links, id)
+		}
+	}
+	return links
+}
+
+// failRemaining marks the recipients from index start on as failed with err.
+func (vr *VariantRun) failRemaining(start int, err error) {
+	for i := start; i < len(vr.Recipients); i++ {
+		vr.Failed = append(vr.Failed, BulkFailure{Index: i, Recipient: vr.Recipients[i], Message: err.Error()})
+	}
+}
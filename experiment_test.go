@@ -0,0 +1,139 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExperiment_Assign(t *testing.T) {
+	exp := &Experiment{ID: "spring-sale", Variants: []Variant{
+		{ID: "a", Weight: 3, Message: "A"},
+		{ID: "b", Weight: 1, Message: "B"},
+	}}
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		recipient := fmt.Sprintf("+98912%07d", i)
+		v := exp.Assign(recipient)
+		if exp.Assign(recipient) != v {
+			t.Fatalf("Expected a stable assignment for %s", recipient)
+		}
+		counts[v.ID]++
+	}
+
+	if counts["a"] < 2800 || counts["a"] > 3200 {
+		t.Errorf("Expected about 3000 recipients in a, got %d", counts["a"])
+	}
+}
+
+func TestMessagesService_RunExperiment(t *testing.T) {
+	var mu sync.Mutex
+	bulkParams := make(map[string]interface{})
+	templateSends := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+
+		switch r.URL.Path {
+		case "/send-message/bulk":
+			var req SendBulkMessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			bulkParams = req.Params
+			var results []string
+			for i, m := range req.Messages {
+				results = append(results, fmt.Sprintf(`{"id": "bulk_%d", "to": %q, "status": "sent"}`, i, m.To))
+			}
+			fmt.Fprintf(w, `{"total": %d, "results": [%s]}`, len(req.Messages), strings.Join(results, ","))
+		case "/send-message/template":
+			var req SendTemplateMessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			templateSends++
+			fmt.Fprintf(w, `{"id": "tmpl_%d", "status": "sent"}`, templateSends)
+		case "/shortlinks/link_1/clicks":
+			w.Write([]byte(`{"clicks": [
+				{"link_id": "link_1", "message_id": "bulk_0", "recipient": "r"},
+				{"link_id": "link_1", "message_id": "bulk_0", "recipient": "r"},
+				{"link_id": "link_1", "message_id": "tmpl_1"}
+			], "total": 3}`))
+		default:
+			if strings.HasSuffix(r.URL.Path, "/status") {
+				w.Write([]byte(`{"status": "delivered"}`))
+				return
+			}
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}
+
+	client := setupTestClient(handler)
+	exp := &Experiment{ID: "exp_1", Variants: []Variant{
+		{ID: "text", Message: "20% off", LinkID: "link_1"},
+		{ID: "template", TemplateID: "tpl_sale", LinkID: "link_1"},
+	}}
+
+	var recipients []string
+	for i := 0; i < 20; i++ {
+		recipients = append(recipients, fmt.Sprintf("+98912%07d", i))
+	}
+
+	run, err := client.Messages.RunExperiment(context.Background(), exp, recipients)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(run.Variants) != 2 {
+		t.Fatalf("Expected 2 variant runs, got %d", len(run.Variants))
+	}
+	if got := len(run.Variants[0].Recipients) + len(run.Variants[1].Recipients); got != 20 {
+		t.Errorf("Expected 20 recipients assigned, got %d", got)
+	}
+	if bulkParams[ParamVariantID] != "text" || bulkParams[ParamExperimentID] != "exp_1" {
+		t.Errorf("Expected bulk send tagged with the variant, got %v", bulkParams)
+	}
+	if templateSends != len(run.Variants[1].Recipients) {
+		t.Errorf("Expected %d template sends, got %d", len(run.Variants[1].Recipients), templateSends)
+	}
+
+	stats, err := client.Messages.ExperimentStats(context.Background(), run)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text, tmpl := stats[0], stats[1]
+	if text.Sent != len(run.Variants[0].Recipients) || text.Delivered != text.Sent {
+		t.Errorf("Expected all text messages delivered, got %+v", text)
+	}
+	if text.Clicks != 2 || text.Clickers != 1 {
+		t.Errorf("Expected 2 clicks by 1 clicker for text, got %+v", text)
+	}
+	if tmpl.Clicks != 1 || tmpl.Clickers != 1 {
+		t.Errorf("Expected 1 click by 1 clicker for template, got %+v", tmpl)
+	}
+	if text.DeliveryRate() != 1 {
+		t.Errorf("Expected delivery rate 1, got %v", text.DeliveryRate())
+	}
+}
+
+func TestMessagesService_RunExperiment_Validation(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for an invalid experiment")
+	})
+
+	_, err := client.Messages.RunExperiment(context.Background(), &Experiment{ID: "exp", Variants: []Variant{
+		{ID: "a", Message: "A"},
+		{ID: "a", Message: "A", TemplateID: "tpl"},
+	}}, []string{"+989123456789"})
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	if len(ve.Errors) != 2 || ve.Errors[0].Rule != RuleUnique || ve.Errors[1].Rule != RuleRequiredWithout {
+		t.Errorf("Expected unique and required_without errors, got %+v", ve.Errors)
+	}
+}
@@ -0,0 +1,96 @@
+package signalads
+
+import "strings"
+
+// FailureReason classifies why a message was not delivered. Gateways report
+// failures as carrier-specific codes and strings; ParseFailureReason maps
+// the common ones to these values.
+type FailureReason string
+
+// Failure reasons.
+const (
+	// FailureBlacklisted means the recipient opted out or is on a
+	// do-not-disturb list.
+	FailureBlacklisted FailureReason = "blacklisted"
+
+	// FailureHandsetOff means the handset was switched off or out of
+	// coverage until the message expired.
+	FailureHandsetOff FailureReason = "handset_off"
+
+	// FailureInvalidNumber means the number does not exist or cannot
+	// receive messages.
+	FailureInvalidNumber FailureReason = "invalid_number"
+
+	// FailureFiltered means the carrier blocked the message content.
+	FailureFiltered FailureReason = "filtered"
+
+	// FailureExpired means the message's validity period ran out before
+	// it could be delivered.
+	FailureExpired FailureReason = "expired"
+
+	// FailureUnknown is any failure not covered by the reasons above.
+	FailureUnknown FailureReason = "unknown"
+)
+
+// failureSpellings maps lower-cased failure codes, including the GSM MAP
+// and SMPP error codes that carriers pass through, to failure reasons.
+var failureSpellings = map[string]FailureReason{
+	"blacklisted":         FailureBlacklisted,
+	"blacklist":           FailureBlacklisted,
+	"dnd":                 FailureBlacklisted,
+	"do_not_disturb":      FailureBlacklisted,
+	"opted_out":           FailureBlacklisted,
+	"opt_out":             FailureBlacklisted,
+	"handset_off":         FailureHandsetOff,
+	"absent_subscriber":   FailureHandsetOff,
+	"subscriber_absent":   FailureHandsetOff,
+	"phone_off":           FailureHandsetOff,
+	"27":                  FailureHandsetOff,
+	"invalid_number":      FailureInvalidNumber,
+	"unknown_subscriber":  FailureInvalidNumber,
+	"invalid_destination": FailureInvalidNumber,
+	"1":                   FailureInvalidNumber,
+	"11":                  FailureInvalidNumber,
+	"filtered":            FailureFiltered,
+	"content_filtered":    FailureFiltered,
+	"spam":                FailureFiltered,
+	"expired":             FailureExpired,
+	"validity_expired":    FailureExpired,
+	"ttl_expired":         FailureExpired,
+}
+
+// ParseFailureReason returns the failure reason for a gateway failure code
+// or string, or FailureUnknown if it is not recognized. An empty code
+// stays empty.
+func ParseFailureReason(code string) FailureReason {
+	key := strings.ToLower(strings.TrimSpace(code))
+	if key == "" {
+		return ""
+	}
+	key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+	if reason, ok := failureSpellings[key]; ok {
+		return reason
+	}
+	return FailureUnknown
+}
+
+// IsPermanent reports whether resending the same message to the same
+// recipient would fail again. Unknown failures are not considered
+// permanent.
+func (r FailureReason) IsPermanent() bool {
+	switch r {
+	case FailureBlacklisted, FailureInvalidNumber, FailureFiltered:
+		return true
+	default:
+		return false
+	}
+}
+
+// failureReason derives the failure reason of a message from the error
+// code and error string the API reported.
+func failureReason(errorCode, errMsg string) FailureReason {
+	if errorCode != "" {
+		return ParseFailureReason(errorCode)
+	}
+	return ParseFailureReason(errMsg)
+}
@@ -0,0 +1,90 @@
+package signalads
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseFailureReason(t *testing.T) {
+	tests := []struct {
+		code string
+		want FailureReason
+	}{
+		{"blacklisted", FailureBlacklisted},
+		{"DND", FailureBlacklisted},
+		{"Absent Subscriber", FailureHandsetOff},
+		{"27", FailureHandsetOff},
+		{"unknown-subscriber", FailureInvalidNumber},
+		{"1", FailureInvalidNumber},
+		{"SPAM", FailureFiltered},
+		{"validity_expired", FailureExpired},
+		{"", ""},
+		{"E9931", FailureUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseFailureReason(tt.code); got != tt.want {
+			t.Errorf("ParseFailureReason(%q): expected %q, got %q", tt.code, tt.want, got)
+		}
+	}
+}
+
+func TestFailureReason_IsPermanent(t *testing.T) {
+	tests := []struct {
+		reason FailureReason
+		want   bool
+	}{
+		{FailureBlacklisted, true},
+		{FailureInvalidNumber, true},
+		{FailureFiltered, true},
+		{FailureHandsetOff, false},
+		{FailureExpired, false},
+		{FailureUnknown, false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.reason.IsPermanent(); got != tt.want {
+			t.Errorf("%q.IsPermanent(): expected %v, got %v", tt.reason, tt.want, got)
+		}
+	}
+}
+
+func TestMessageStatus_Reason(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want FailureReason
+	}{
+		{"error code", `{"id": "m1", "status": "UNDELIV", "error": "carrier error", "error_code": "27"}`, FailureHandsetOff},
+		{"error string", `{"id": "m1", "status": "failed", "error": "blacklisted"}`, FailureBlacklisted},
+		{"reported reason", `{"id": "m1", "status": "failed", "error_code": "27", "reason": "filtered"}`, FailureFiltered},
+		{"delivered", `{"id": "m1", "status": "delivered"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status MessageStatus
+			if err := json.Unmarshal([]byte(tt.body), &status); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if status.Reason != tt.want {
+				t.Errorf("Expected reason %q, got %q", tt.want, status.Reason)
+			}
+		})
+	}
+}
+
+func TestDeliveryReportEvent_Reason(t *testing.T) {
+	event := &WebhookEvent{Type: EventDeliveryReport, Data: json.RawMessage(`{"message_id": "m1", "status": "REJECTD", "error_code": "11"}`)}
+	report, err := event.DeliveryReport()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Reason != FailureInvalidNumber {
+		t.Errorf("Expected reason %q, got %q", FailureInvalidNumber, report.Reason)
+	}
+	if !report.Reason.IsPermanent() {
+		t.Error("Expected invalid number to be permanent")
+	}
+}
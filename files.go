@@ -0,0 +1,52 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// FilesService provides methods for hosting documents and audio files, so
+// that they can be sent as DocumentLink or AudioURL without external
+// hosting.
+type FilesService struct {
+	client *Client
+}
+
+// UploadFile uploads the contents of r under name and returns the hosted
+// file. The content type is derived from the extension of name. The upload
+// is streamed, so r is read only once and is not retried.
+func (s *FilesService) UploadFile(ctx context.Context, r io.Reader, name string) (*File, error) {
+	if name == "" {
+		return nil, &ValidationError{Errors: []FieldError{{Field: "name", Rule: RuleRequired, Message: "file name is required"}}}
+	}
+
+	var file File
+	if err := s.client.PostMultipart(ctx, "/files", nil, []FilePart{{Name: name, Reader: r}}, &file); err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+	return &file, nil
+}
+
+// ListFiles retrieves the hosted files, newest first.
+func (s *FilesService) ListFiles(ctx context.Context, params *PaginationParams) (*ListFilesResponse, error) {
+	var response ListFilesResponse
+	if err := s.client.Get(ctx, "/files", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return &response, nil
+}
+
+// DeleteFile deletes a hosted file. Links to it in messages already sent
+// stop working.
+func (s *FilesService) DeleteFile(ctx context.Context, fileID string) error {
+	if fileID == "" {
+		return &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "file ID is required"}}}
+	}
+
+	if err := s.client.Delete(ctx, "/files/"+pathSegment(fileID), nil); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
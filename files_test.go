@@ -0,0 +1,88 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFilesService_UploadFile(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/files" {
+			t.Errorf("Expected POST /files, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "test-api-key" {
+			t.Errorf("Expected API key header, got '%s'", r.Header.Get("X-API-Key"))
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Expected a multipart file, got %v", err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		if header.Filename != "invoice.pdf" || string(data) != "%PDF-1.7" {
+			t.Errorf("Unexpected upload %s: %q", header.Filename, data)
+		}
+		if ct := header.Header.Get("Content-Type"); ct != "application/pdf" {
+			t.Errorf("Expected content type 'application/pdf', got '%s'", ct)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(File{ID: "file-1", Name: header.Filename, URL: "https://cdn.signalads.com/f/file-1.pdf", Size: int64(len(data))})
+	}
+
+	client := setupTestClient(handler)
+	file, err := client.Files.UploadFile(context.Background(), strings.NewReader("%PDF-1.7"), "docs/invoice.pdf")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file.URL != "https://cdn.signalads.com/f/file-1.pdf" || file.Size != 8 {
+		t.Errorf("Unexpected file: %+v", file)
+	}
+}
+
+func TestFilesService_UploadFile_APIError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}
+
+	client := setupTestClient(handler)
+	if _, err := client.Files.UploadFile(context.Background(), strings.NewReader("data"), "a.mp3"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestFilesService_ListAndDelete(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/files":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(ListFilesResponse{Files: []File{{ID: "file-1", Name: "a.mp3"}}, Total: 1})
+		case r.Method == http.MethodDelete && r.URL.Path == "/files/file-1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Files.ListFiles(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Files) != 1 || response.Files[0].Name != "a.mp3" {
+		t.Errorf("Unexpected files: %+v", response.Files)
+	}
+
+	if err := client.Files.DeleteFile(context.Background(), "file-1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := client.Files.DeleteFile(context.Background(), ""); !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
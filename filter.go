@@ -0,0 +1,83 @@
+package signalads
+
+import (
+	"strings"
+	"time"
+)
+
+// Filter narrows a list request. Build one with F and attach it to
+// PaginationParams.Filter; the same filter works for every list endpoint
+// that supports the fields it sets:
+//
+//	params := &signalads.PaginationParams{
+//		Filter: signalads.F().Status(signalads.StatusFailed).Between(from, to).Recipient("+989123456789"),
+//	}
+//
+// The zero Filter is empty and ready to use.
+type Filter struct {
+	params map[string]string
+}
+
+// F returns an empty filter.
+func F() *Filter {
+	return &Filter{params: make(map[string]string)}
+}
+
+// Status keeps items with any of statuses.
+func (f *Filter) Status(statuses ...MessageStatusCode) *Filter {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return f.Set("status", strings.Join(values, ","))
+}
+
+// Between keeps items created within [from, to]. A zero bound leaves that
+// side of the range open.
+func (f *Filter) Between(from, to time.Time) *Filter {
+	if !from.IsZero() {
+		f.Set("since", from.UTC().Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		f.Set("until", to.UTC().Format(time.RFC3339))
+	}
+	return f
+}
+
+// Recipient keeps items sent to phone.
+func (f *Filter) Recipient(phone string) *Filter {
+	return f.Set("recipient", phone)
+}
+
+// Sender keeps items sent from line, a sender ID or phone number.
+func (f *Filter) Sender(line string) *Filter {
+	return f.Set("sender", line)
+}
+
+// Search keeps items whose text contains text.
+func (f *Filter) Search(text string) *Filter {
+	return f.Set("q", text)
+}
+
+// Set adds a query parameter the builder does not model. An empty value
+// removes key.
+func (f *Filter) Set(key, value string) *Filter {
+	if value == "" {
+		delete(f.params, key)
+		return f
+	}
+	if f.params == nil {
+		f.params = make(map[string]string)
+	}
+	f.params[key] = value
+	return f
+}
+
+// Query returns the filter as query parameters.
+func (f *Filter) Query() map[string]string {
+	query := make(map[string]string, len(f.params))
+	for k, v := range f.params {
+		query[k] = v
+	}
+	return query
+}
@@ -0,0 +1,59 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilter_Query(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, Tehran)
+	filter := F().
+		Status(StatusFailed, StatusUndelivered).
+		Between(from, time.Time{}).
+		Recipient("+989123456789").
+		Set("campaign", "spring").
+		Set("campaign", "")
+
+	want := map[string]string{
+		"status":    "failed,undelivered",
+		"since":     "2026-02-28T20:30:00Z",
+		"recipient": "+989123456789",
+	}
+	if got := filter.Query(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestFilter_ZeroValue(t *testing.T) {
+	var filter Filter
+	filter.Status(StatusDelivered).Search("code")
+
+	want := map[string]string{"status": "delivered", "q": "code"}
+	if got := filter.Query(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestListMessages_Filter(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("status") != "failed" || q.Get("recipient") != "+989123456789" || q.Get("page") != "2" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListMessagesResponse{})
+	}
+
+	client := setupTestClient(handler)
+	_, err := client.Messages.ListMessages(context.Background(), &PaginationParams{
+		Page:   2,
+		Filter: F().Status("failed").Recipient("+989123456789"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
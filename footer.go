@@ -0,0 +1,88 @@
+package signalads
+
+import (
+	"context"
+	"strings"
+)
+
+// FooterWarning reports that appending the footer made a message take more
+// segments, and so cost more, than its text alone.
+type FooterWarning struct {
+	// Recipient of the message
+	To string
+
+	Footer string
+
+	// Segments of the text without and with the footer
+	SegmentsWithout int
+	SegmentsWith    int
+}
+
+// WithMessageFooter appends footer on its own line to every SMS text sent by
+// the client, for example a sender identification or opt-out line required
+// by regulation. Text that already ends with the footer is left alone.
+// Template and voice messages are not affected. Use WithFooter to override
+// the footer for a single send.
+func WithMessageFooter(footer string) ClientOption {
+	return func(c *Client) {
+		c.footer = footer
+	}
+}
+
+// WithFooterWarning registers fn to be called when the footer pushes a
+// message into an additional segment.
+func WithFooterWarning(fn func(ctx context.Context, warning FooterWarning)) ClientOption {
+	return func(c *Client) {
+		c.footerWarning = fn
+	}
+}
+
+type footerContextKey struct{}
+
+// WithFooter returns a context whose sends use footer instead of the one
+// set with WithMessageFooter. An empty footer sends the text without one.
+func WithFooter(ctx context.Context, footer string) context.Context {
+	return context.WithValue(ctx, footerContextKey{}, footer)
+}
+
+// footerFor returns the footer to append to text sent with ctx, or an empty
+// string if none applies.
+func (c *Client) footerFor(ctx context.Context, text string) string {
+	footer := c.footer
+	if override, ok := ctx.Value(footerContextKey{}).(string); ok {
+		footer = override
+	}
+	if footer == "" || strings.HasSuffix(text, footer) {
+		return ""
+	}
+	return footer
+}
+
+func withFooter(text, footer string) string {
+	if footer == "" {
+		return text
+	}
+	return text + "\n" + footer
+}
+
+// prepareText applies the footer and the long message policy to text,
+// returning the texts to send. field names the text in validation errors.
+func (c *Client) prepareText(ctx context.Context, field, to, text string, encoding Encoding) ([]string, error) {
+	footer := c.footerFor(ctx, text)
+	body, full := text, withFooter(text, footer)
+	if footer == "" {
+		// Text the caller already ended with the footer still needs it on
+		// every part if the long message policy splits or truncates it.
+		if configured := c.footerFor(ctx, ""); configured != "" && strings.HasSuffix(text, configured) {
+			footer = configured
+			body = strings.TrimSuffix(strings.TrimSuffix(text, configured), "\n")
+		}
+	} else if c.footerWarning != nil {
+		without := CountSegmentsWithEncoding(text, encoding)
+		with := CountSegmentsWithEncoding(withFooter(text, footer), encoding)
+		if with > without {
+			c.footerWarning(ctx, FooterWarning{To: to, Footer: footer, SegmentsWithout: without, SegmentsWith: with})
+		}
+	}
+	return c.applyLongMessagePolicy(field, full, body, footer, encoding)
+}
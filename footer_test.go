@@ -0,0 +1,106 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMessageFooter(t *testing.T) {
+	var received []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req.Message)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg", Status: "sent"})
+	}
+
+	var warnings []FooterWarning
+	client := setupTestClient(handler)
+	WithMessageFooter("Opt out: 11")(client)
+	WithFooterWarning(func(ctx context.Context, w FooterWarning) {
+		warnings = append(warnings, w)
+	})(client)
+
+	ctx := context.Background()
+	client.Messages.SendMessage(ctx, "+989123456789", "Sale today")
+	client.Messages.SendMessage(ctx, "+989123456789", "Sale today\nOpt out: 11")
+	client.Messages.SendMessage(WithFooter(ctx, "Shop Co."), "+989123456789", "Sale today")
+	client.Messages.SendMessage(WithFooter(ctx, ""), "+989123456789", "Sale today")
+
+	expected := []string{
+		"Sale today\nOpt out: 11",
+		"Sale today\nOpt out: 11",
+		"Sale today\nShop Co.",
+		"Sale today",
+	}
+	if strings.Join(received, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, received)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings for short messages, got %+v", warnings)
+	}
+
+	client.Messages.SendMessage(ctx, "+989123456789", strings.Repeat("a", 155))
+	if len(warnings) != 1 || warnings[0].SegmentsWithout != 1 || warnings[0].SegmentsWith != 2 {
+		t.Errorf("Expected warning for footer adding a segment, got %+v", warnings)
+	}
+}
+
+func TestMessageFooter_WithTruncation(t *testing.T) {
+	var received string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = req.Message
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	WithMessageFooter("Opt out: 11")(client)
+	WithLongMessagePolicy(LongMessageTruncate, 1)(client)
+
+	if _, err := client.Messages.SendMessage(context.Background(), "+989123456789", strings.Repeat("a", 300)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(received, "\nOpt out: 11") {
+		t.Errorf("Expected footer to survive truncation, got %q", received)
+	}
+	if CountSegments(received) != 1 {
+		t.Errorf("Expected one segment, got %d", CountSegments(received))
+	}
+}
+
+func TestMessageFooter_WithSplit(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {})
+	WithMessageFooter("Opt out: 11")(client)
+	WithLongMessagePolicy(LongMessageSplit, 1)(client)
+	ctx := context.Background()
+
+	// Text that already ends with the footer still gets it on every part
+	text := strings.Repeat("word ", 80) + "\nOpt out: 11"
+	parts, err := client.prepareText(ctx, "message", "+989123456789", text, EncodingAuto)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(parts) < 2 {
+		t.Fatalf("Expected the text to be split, got %d parts", len(parts))
+	}
+	for i, part := range parts {
+		if !strings.HasSuffix(part, ")\nOpt out: 11") {
+			t.Errorf("Expected part %d to end with its number and the footer, got %q", i+1, part)
+		}
+		if CountSegments(part) != 1 {
+			t.Errorf("Expected part %d to fit one segment, got %d", i+1, CountSegments(part))
+		}
+	}
+
+	_, err = client.prepareText(WithFooter(ctx, strings.Repeat("f", 170)), "message", "+989123456789", text, EncodingAuto)
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error for a footer filling the segment, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrFrequencyCapped is wrapped by the *FrequencyCapError returned when a
+// recipient has already received the maximum number of messages.
+var ErrFrequencyCapped = errors.New("recipient frequency cap reached")
+
+// RejectFrequencyCapped is the BulkFailure.Code of bulk recipients dropped
+// by the frequency cap.
+const RejectFrequencyCapped = "frequency_capped"
+
+// FrequencyCapError is returned, before anything is sent, when a message
+// would exceed the frequency cap of its recipient.
+type FrequencyCapError struct {
+	Recipient string
+
+	// When the recipient can be messaged again
+	RetryAt time.Time
+}
+
+func (e *FrequencyCapError) Error() string {
+	return fmt.Sprintf("%v for %s until %s", ErrFrequencyCapped, RedactPhone(e.Recipient), e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *FrequencyCapError) Unwrap() error {
+	return ErrFrequencyCapped
+}
+
+// FrequencyStore records recent sends per phone number. Sharing one store,
+// such as a database or Redis, between services caps what a recipient gets
+// from all of them together. Implementations must be safe for concurrent
+// use.
+type FrequencyStore interface {
+	// Reserve records a send to phone at now, unless limit sends are already
+	// recorded within window before now. In that case it records nothing,
+	// returns false and the time the oldest of those sends leaves the
+	// window. The check and the record must be atomic.
+	Reserve(ctx context.Context, phone string, now time.Time, window time.Duration, limit int) (ok bool, retryAt time.Time, err error)
+
+	// Release removes the send to phone that Reserve recorded at at, when
+	// the message is not sent after all. Releasing an unknown send is not
+	// an error.
+	Release(ctx context.Context, phone string, at time.Time) error
+}
+
+// FrequencyCapOption configures the frequency cap.
+type FrequencyCapOption func(*frequencyCap)
+
+// WithFrequencyDefer makes a capped send wait until the recipient can be
+// messaged again, if that is at most maxDelay away and before the context
+// deadline, instead of failing. Bulk sends never wait; their capped
+// recipients are always dropped.
+func WithFrequencyDefer(maxDelay time.Duration) FrequencyCapOption {
+	return func(f *frequencyCap) {
+		f.maxDelay = maxDelay
+	}
+}
+
+// WithFrequencyExempt sets the categories the cap does not apply to. It
+// replaces the default, which exempts CategoryOTP.
+func WithFrequencyExempt(categories ...Category) FrequencyCapOption {
+	return func(f *frequencyCap) {
+		f.exempt = make(map[Category]bool, len(categories))
+		for _, c := range categories {
+			f.exempt[c] = true
+		}
+	}
+}
+
+// WithFrequencyCap limits every recipient to limit messages within a rolling
+// window, across all services sharing store. Single sends over the cap fail
+// with a *FrequencyCapError; bulk sends drop the capped recipients and
+// report them in SendBulkMessageResponse.Rejected.
+func WithFrequencyCap(store FrequencyStore, limit int, window time.Duration, opts ...FrequencyCapOption) ClientOption {
+	return func(c *Client) {
+		f := &frequencyCap{
+			store:  store,
+			limit:  limit,
+			window: window,
+			exempt: map[Category]bool{CategoryOTP: true},
+		}
+		for _, opt := range opts {
+			opt(f)
+		}
+		c.frequencyCap = f
+	}
+}
+
+type frequencyCap struct {
+	store    FrequencyStore
+	limit    int
+	window   time.Duration
+	maxDelay time.Duration
+	exempt   map[Category]bool
+}
+
+func (f *frequencyCap) applies(ctx context.Context) bool {
+	category, _ := CategoryFromContext(ctx)
+	return !f.exempt[category]
+}
+
+// checkFrequency reserves a send to phone under the client's frequency cap,
+// waiting if deferral is enabled. The returned function gives the slot back
+// and must be called if the message is not sent after all, for example
+// because the governor rejects it.
+func (c *Client) checkFrequency(ctx context.Context, phone string) (release func(), err error) {
+	f := c.frequencyCap
+	if f == nil || !f.applies(ctx) {
+		return func() {}, nil
+	}
+
+	for {
+		now := time.Now()
+		ok, retryAt, err := f.store.Reserve(ctx, phone, now, f.window, f.limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check frequency cap: %w", err)
+		}
+		if ok {
+			release := f.releaser(ctx, now)
+			return func() { release(phone) }, nil
+		}
+
+		capErr := &FrequencyCapError{Recipient: phone, RetryAt: retryAt}
+		wait := time.Until(retryAt)
+		if wait > f.maxDelay {
+			return nil, capErr
+		}
+		if deadline, ok := ctx.Deadline(); ok && retryAt.After(deadline) {
+			return nil, capErr
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// releaser returns a function that releases sends reserved at at. A failed
+// release only leaves the slot taken until it leaves the window, so its
+// error is dropped.
+func (f *frequencyCap) releaser(ctx context.Context, at time.Time) func(phones ...string) {
+	ctx = context.WithoutCancel(ctx)
+	return func(phones ...string) {
+		for _, phone := range phones {
+			_ = f.store.Release(ctx, phone, at)
+		}
+	}
+}
+
+// filterFrequency drops the bulk items over the client's frequency cap.
+// Rejections carry their index in items. The returned function gives back
+// the slots of kept items that are not sent after all, as for
+// checkFrequency.
+func (c *Client) filterFrequency(ctx context.Context, items []BulkMessageItem) ([]BulkMessageItem, []BulkFailure, func(phones ...string), error) {
+	f := c.frequencyCap
+	if f == nil || !f.applies(ctx) {
+		return items, nil, func(...string) {}, nil
+	}
+
+	var rejected []BulkFailure
+	var reserved []string
+	kept := make([]BulkMessageItem, 0, len(items))
+	now := time.Now()
+	for i, item := range items {
+		ok, retryAt, err := f.store.Reserve(ctx, item.To, now, f.window, f.limit)
+		if err != nil {
+			f.releaser(ctx, now)(reserved...)
+			return nil, nil, nil, fmt.Errorf("failed to check frequency cap: %w", err)
+		}
+		if ok {
+			kept = append(kept, item)
+			reserved = append(reserved, item.To)
+			continue
+		}
+		capErr := &FrequencyCapError{Recipient: item.To, RetryAt: retryAt}
+		rejected = append(rejected, BulkFailure{Index: i, Recipient: item.To, Code: RejectFrequencyCapped, Message: capErr.Error()})
+	}
+	return kept, rejected, f.releaser(ctx, now), nil
+}
+
+// MemoryFrequencyStore is an in-process FrequencyStore. It only caps sends
+// made through the same process.
+type MemoryFrequencyStore struct {
+	mu    sync.Mutex
+	sends map[string][]time.Time
+	ops   int
+}
+
+// NewMemoryFrequencyStore creates an empty MemoryFrequencyStore.
+func NewMemoryFrequencyStore() *MemoryFrequencyStore {
+	return &MemoryFrequencyStore{sends: make(map[string][]time.Time)}
+}
+
+// Reserve implements FrequencyStore.
+func (m *MemoryFrequencyStore) Reserve(_ context.Context, phone string, now time.Time, window time.Duration, limit int) (bool, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	since := now.Add(-window)
+	m.ops++
+	if m.ops%1024 == 0 {
+		m.sweep(since)
+	}
+
+	recent := m.sends[phone]
+	first := sort.Search(len(recent), func(i int) bool {
+		return recent[i].After(since)
+	})
+	recent = recent[first:]
+
+	if len(recent) >= limit {
+		m.sends[phone] = recent
+		if limit <= 0 {
+			return false, now.Add(window), nil
+		}
+		return false, recent[len(recent)-limit].Add(window), nil
+	}
+
+	m.sends[phone] = append(recent, now)
+	return true, time.Time{}, nil
+}
+
+// Release implements FrequencyStore.
+func (m *MemoryFrequencyStore) Release(_ context.Context, phone string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sends := m.sends[phone]
+	for i := len(sends) - 1; i >= 0; i-- {
+		if sends[i].Equal(at) {
+			m.sends[phone] = append(sends[:i], sends[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// sweep forgets phone numbers with no sends after since.
+func (m *MemoryFrequencyStore) sweep(since time.Time) {
+	for phone, sends := range m.sends {
+		if len(sends) == 0 || !sends[len(sends)-1].After(since) {
+			delete(m.sends, phone)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMemoryFrequencyStore_Reserve(t *testing.T) {
+	store := NewMemoryFrequencyStore()
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		ok, _, err := store.Reserve(ctx, "+989123456789", start.Add(time.Duration(i)*time.Hour), 24*time.Hour, 2)
+		if err != nil || !ok {
+			t.Fatalf("Expected send %d to be allowed, got %v, %v", i, ok, err)
+		}
+	}
+
+	ok, retryAt, _ := store.Reserve(ctx, "+989123456789", start.Add(3*time.Hour), 24*time.Hour, 2)
+	if ok {
+		t.Fatal("Expected the third send to be capped")
+	}
+	if !retryAt.Equal(start.Add(24 * time.Hour)) {
+		t.Errorf("Expected retry at %v, got %v", start.Add(24*time.Hour), retryAt)
+	}
+
+	ok, _, _ = store.Reserve(ctx, "+989123456789", start.Add(24*time.Hour+time.Second), 24*time.Hour, 2)
+	if !ok {
+		t.Error("Expected a send after the oldest one left the window to be allowed")
+	}
+	ok, _, _ = store.Reserve(ctx, "+989120000000", start, 24*time.Hour, 2)
+	if !ok {
+		t.Error("Expected other recipients to be unaffected")
+	}
+}
+
+func TestMessagesService_FrequencyCap(t *testing.T) {
+	var bulk SendBulkMessageRequest
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/send-message/bulk" {
+			json.NewDecoder(r.Body).Decode(&bulk)
+			w.Write([]byte(`{"total": 1, "success": 1}`))
+			return
+		}
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	client := setupTestClient(handler)
+	WithFrequencyCap(NewMemoryFrequencyStore(), 1, 24*time.Hour)(client)
+	WithRecipientPreFilter()(client)
+	ctx := context.Background()
+
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Sale"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := client.Messages.SendMessage(ctx, "+989123456789", "Another sale")
+	var fe *FrequencyCapError
+	if !errors.As(err, &fe) {
+		t.Fatalf("Expected *FrequencyCapError, got %v", err)
+	}
+	if fe.Recipient != "+989123456789" || time.Until(fe.RetryAt) < 23*time.Hour {
+		t.Errorf("Expected a cap for +989123456789 for about a day, got %+v", fe)
+	}
+
+	if _, err := client.Messages.SendMessage(WithCategory(ctx, CategoryOTP), "+989123456789", "Code 1234"); err != nil {
+		t.Errorf("Expected OTPs to be exempt, got %v", err)
+	}
+
+	response, err := client.Messages.SendBulkMessage(ctx, []BulkMessageItem{
+		{To: "bad", Message: "Hi"},
+		{To: "+989123456789", Message: "Hi"},
+		{To: "+989120000000", Message: "Hi"},
+	}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(bulk.Messages) != 1 || bulk.Messages[0].To != "+989120000000" {
+		t.Errorf("Expected only +989120000000 sent, got %+v", bulk.Messages)
+	}
+	if len(response.Rejected) != 2 {
+		t.Fatalf("Expected 2 rejected, got %+v", response.Rejected)
+	}
+	if response.Rejected[1].Index != 1 || response.Rejected[1].Code != RejectFrequencyCapped {
+		t.Errorf("Expected index 1 frequency capped, got %+v", response.Rejected[1])
+	}
+}
+
+func TestMessagesService_FrequencyCapDefer(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	client := setupTestClient(handler)
+	WithFrequencyCap(NewMemoryFrequencyStore(), 1, 50*time.Millisecond, WithFrequencyDefer(time.Second))(client)
+	ctx := context.Background()
+
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "One"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	start := time.Now()
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Two"); err != nil {
+		t.Fatalf("Expected the send to be deferred, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Expected the send to wait, took %v", elapsed)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Messages.SendMessage(short, "+989123456789", "Three"); !errors.Is(err, ErrFrequencyCapped) {
+		t.Errorf("Expected ErrFrequencyCapped when the deadline is too close, got %v", err)
+	}
+}
+
+func TestMessagesService_FrequencyCapReleasedOnQuota(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	store := NewMemoryFrequencyStore()
+	client := setupTestClient(handler)
+	WithFrequencyCap(store, 1, time.Hour)(client)
+	WithGovernor(NewGovernor(PerHour(1)))(client)
+	ctx := context.Background()
+
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "One"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var quotaErr *QuotaError
+	if _, err := client.Messages.SendMessage(ctx, "+989120000000", "Two"); !errors.As(err, &quotaErr) {
+		t.Fatalf("Expected a *QuotaError, got %v", err)
+	}
+
+	ok, _, err := store.Reserve(ctx, "+989120000000", time.Now(), time.Hour, 1)
+	if err != nil || !ok {
+		t.Errorf("Expected the rejected send's slot to be released, got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,200 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is wrapped by the *QuotaError returned when a send would
+// exceed a Governor quota.
+var ErrQuotaExceeded = errors.New("send quota exceeded")
+
+// QuotaError is returned, before anything is sent, when a send would exceed
+// a quota of the client's Governor.
+type QuotaError struct {
+	Quota Quota
+
+	// Recipient whose quota was exceeded; empty for overall quotas
+	Recipient string
+
+	// Time until the quota's window resets
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	scope := "overall"
+	if e.Recipient != "" {
+		scope = "for " + e.Recipient
+	}
+	return fmt.Sprintf("%v: %d per %s %s, resets in %s", ErrQuotaExceeded, e.Quota.Max, e.Quota.Window, scope, e.RetryAfter.Round(time.Second))
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// Quota caps the number of messages sent in a window. Windows are aligned
+// to multiples of their length since the Unix epoch, so a day window runs
+// from midnight to midnight UTC.
+type Quota struct {
+	Max    int
+	Window time.Duration
+
+	// Whether the cap applies to each recipient separately rather than to
+	// all messages together
+	PerRecipient bool
+
+	// Categories the quota applies to; empty means all messages
+	Categories []Category
+}
+
+// PerMinute returns a quota of n messages per minute.
+func PerMinute(n int) Quota {
+	return Quota{Max: n, Window: time.Minute}
+}
+
+// PerHour returns a quota of n messages per hour.
+func PerHour(n int) Quota {
+	return Quota{Max: n, Window: time.Hour}
+}
+
+// PerDay returns a quota of n messages per day.
+func PerDay(n int) Quota {
+	return Quota{Max: n, Window: 24 * time.Hour}
+}
+
+// ForEachRecipient returns a copy of q that applies to each recipient
+// separately.
+func (q Quota) ForEachRecipient() Quota {
+	q.PerRecipient = true
+	return q
+}
+
+// ForCategories returns a copy of q that only counts messages of the given
+// categories.
+func (q Quota) ForCategories(categories ...Category) Quota {
+	q.Categories = categories
+	return q
+}
+
+func (q *Quota) appliesTo(category Category) bool {
+	if len(q.Categories) == 0 {
+		return true
+	}
+	for _, c := range q.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Governor enforces send quotas, so that a runaway loop cannot drain the
+// account. Every message a send would produce counts, including each part
+// of a split message and each message of a bulk send, whether or not the
+// API then accepts it. A Governor is safe for concurrent use.
+type Governor struct {
+	mu       sync.Mutex
+	counters []quotaCounter
+	now      func() time.Time
+}
+
+type quotaCounter struct {
+	quota       Quota
+	windowStart time.Time
+	total       int
+	recipients  map[string]int
+}
+
+// NewGovernor returns a governor enforcing quotas. Quotas with a zero
+// window are ignored.
+func NewGovernor(quotas ...Quota) *Governor {
+	g := &Governor{now: time.Now}
+	for _, q := range quotas {
+		if q.Window > 0 {
+			g.counters = append(g.counters, quotaCounter{quota: q, recipients: make(map[string]int)})
+		}
+	}
+	return g
+}
+
+// WithGovernor makes every send count against g's quotas and fail with a
+// *QuotaError when it would exceed one.
+func WithGovernor(g *Governor) ClientOption {
+	return func(c *Client) {
+		c.governor = g
+	}
+}
+
+// Reserve counts one uncategorized message to each of recipients against
+// the quotas, or counts nothing and returns a *QuotaError if that would
+// exceed any of them.
+func (g *Governor) Reserve(recipients ...string) error {
+	return g.ReserveCategory("", recipients...)
+}
+
+// ReserveCategory is like Reserve for messages of category. Only quotas
+// that apply to category count them.
+func (g *Governor) ReserveCategory(category Category, recipients ...string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	perRecipient := make(map[string]int, len(recipients))
+	for _, r := range recipients {
+		perRecipient[r]++
+	}
+
+	for i := range g.counters {
+		c := &g.counters[i]
+		if !c.quota.appliesTo(category) {
+			continue
+		}
+		start := now.Truncate(c.quota.Window)
+		if !start.Equal(c.windowStart) {
+			c.windowStart = start
+			c.total = 0
+			c.recipients = make(map[string]int)
+		}
+		retryAfter := start.Add(c.quota.Window).Sub(now)
+
+		if !c.quota.PerRecipient {
+			if c.total+len(recipients) > c.quota.Max {
+				return &QuotaError{Quota: c.quota, RetryAfter: retryAfter}
+			}
+			continue
+		}
+		for _, r := range recipients {
+			if c.recipients[r]+perRecipient[r] > c.quota.Max {
+				return &QuotaError{Quota: c.quota, Recipient: r, RetryAfter: retryAfter}
+			}
+		}
+	}
+
+	for i := range g.counters {
+		c := &g.counters[i]
+		if !c.quota.appliesTo(category) {
+			continue
+		}
+		c.total += len(recipients)
+		if c.quota.PerRecipient {
+			for r, n := range perRecipient {
+				c.recipients[r] += n
+			}
+		}
+	}
+	return nil
+}
+
+// reserve counts recipients against the client's governor, if any, under
+// the category carried by ctx.
+func (c *Client) reserve(ctx context.Context, recipients ...string) error {
+	if c.governor == nil {
+		return nil
+	}
+	category, _ := CategoryFromContext(ctx)
+	return c.governor.ReserveCategory(category, recipients...)
+}
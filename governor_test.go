@@ -0,0 +1,79 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGovernor_Reserve(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 30, 0, time.UTC)
+	g := NewGovernor(PerDay(2).ForEachRecipient(), PerMinute(3))
+	g.now = func() time.Time { return now }
+
+	if err := g.Reserve("+989120000001", "+989120000002"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := g.Reserve("+989120000001", "+989120000001")
+	var qe *QuotaError
+	if !errors.As(err, &qe) {
+		t.Fatalf("Expected *QuotaError, got %v", err)
+	}
+	if qe.Recipient != "+989120000001" || !qe.Quota.PerRecipient {
+		t.Errorf("Expected per-recipient quota for +989120000001, got %+v", qe)
+	}
+
+	if err := g.Reserve("+989120000003"); err != nil {
+		t.Fatalf("Expected the rejected reservation not to count, got %v", err)
+	}
+
+	err = g.Reserve("+989120000004")
+	if !errors.As(err, &qe) {
+		t.Fatalf("Expected *QuotaError, got %v", err)
+	}
+	if qe.Recipient != "" || qe.RetryAfter != 30*time.Second {
+		t.Errorf("Expected overall quota resetting in 30s, got %+v", qe)
+	}
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Error("Expected error to wrap ErrQuotaExceeded")
+	}
+
+	now = now.Add(time.Minute)
+	if err := g.Reserve("+989120000004"); err != nil {
+		t.Errorf("Expected the minute quota to reset, got %v", err)
+	}
+	if err := g.Reserve("+989120000002"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := g.Reserve("+989120000002"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected the day quota to still apply, got %v", err)
+	}
+}
+
+func TestMessagesService_Governor(t *testing.T) {
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	client := setupTestClient(handler)
+	WithGovernor(NewGovernor(PerHour(2)))(client)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Hi"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := client.Messages.SendTemplate(ctx, "+989123456789", "tpl", nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,99 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrBalanceBelowMinimum is returned by Checker.Ready when the account
+// balance is lower than the configured minimum.
+var ErrBalanceBelowMinimum = errors.New("account balance is below the configured minimum")
+
+// HealthOption configures a Checker.
+type HealthOption func(*Checker)
+
+// WithMinBalance makes Ready fail when the account balance drops below min.
+func WithMinBalance(minBalance float64) HealthOption {
+	return func(c *Checker) {
+		c.minBalance = minBalance
+		c.checkBalance = true
+	}
+}
+
+// Checker reports SDK and account health for liveness and readiness probes.
+type Checker struct {
+	client       *Client
+	minBalance   float64
+	checkBalance bool
+}
+
+// NewChecker creates a Checker for client.
+func NewChecker(client *Client, opts ...HealthOption) *Checker {
+	c := &Checker{client: client}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Live reports whether the client itself is usable: its base URL is an
+// absolute http(s) URL and it has credentials. It does not contact the API,
+// so that an API outage never makes the application look dead and get
+// restarted; Ready covers the API.
+func (c *Checker) Live(ctx context.Context) error {
+	u, err := url.Parse(c.client.BaseURL())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid base URL %q", c.client.BaseURL())
+	}
+	if c.client.currentCredentials() == nil {
+		return errors.New("no credentials configured")
+	}
+	return nil
+}
+
+// Ready reports whether the client can send messages: the API is reachable,
+// the credentials are accepted and, when WithMinBalance is set, the balance
+// is sufficient.
+func (c *Checker) Ready(ctx context.Context) error {
+	info, err := c.client.Messages.GetUserInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.checkBalance && info.Balance < c.minBalance {
+		return fmt.Errorf("%w: balance %.2f, minimum %.2f", ErrBalanceBelowMinimum, info.Balance, c.minBalance)
+	}
+
+	return nil
+}
+
+// LiveHandler returns an http.Handler that responds 200 when Live succeeds
+// and 503 otherwise. The response body never contains error details.
+func (c *Checker) LiveHandler() http.Handler {
+	return healthHandler(c.Live)
+}
+
+// ReadyHandler returns an http.Handler that responds 200 when Ready succeeds
+// and 503 otherwise. The response body never contains error details, which
+// may include text from the API; call Ready to see them.
+func (c *Checker) ReadyHandler() http.Handler {
+	return healthHandler(c.Ready)
+}
+
+func healthHandler(check func(context.Context) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := check(r.Context()); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, "unavailable")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+}
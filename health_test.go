@@ -0,0 +1,109 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func userInfoHandler(balance float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(UserInfo{ID: "user-123", Balance: balance})
+	}
+}
+
+func TestChecker_Ready(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		opts        []HealthOption
+		expectError bool
+	}{
+		{
+			name:    "valid credentials",
+			handler: userInfoHandler(100),
+		},
+		{
+			name:    "balance above minimum",
+			handler: userInfoHandler(100),
+			opts:    []HealthOption{WithMinBalance(50)},
+		},
+		{
+			name:        "balance below minimum",
+			handler:     userInfoHandler(10),
+			opts:        []HealthOption{WithMinBalance(50)},
+			expectError: true,
+		},
+		{
+			name: "invalid credentials",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(setupTestClient(tt.handler), tt.opts...)
+			err := checker.Ready(context.Background())
+
+			if tt.expectError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestChecker_ReadyBalanceError(t *testing.T) {
+	checker := NewChecker(setupTestClient(userInfoHandler(10)), WithMinBalance(50))
+
+	err := checker.Ready(context.Background())
+	if !errors.Is(err, ErrBalanceBelowMinimum) {
+		t.Errorf("Expected ErrBalanceBelowMinimum, got %v", err)
+	}
+}
+
+func TestChecker_Live(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected Live not to contact the API")
+	}))
+	server.Close()
+
+	checker := NewChecker(NewClient("test-key", "test-secret", WithBaseURL(server.URL)))
+	if err := checker.Live(context.Background()); err != nil {
+		t.Errorf("Expected an unreachable API not to fail Live, got %v", err)
+	}
+
+	checker = NewChecker(NewClient("test-key", "test-secret", WithBaseURL("api.example.com")))
+	if err := checker.Live(context.Background()); err == nil {
+		t.Error("Expected error for a relative base URL, got nil")
+	}
+}
+
+func TestChecker_Handlers(t *testing.T) {
+	checker := NewChecker(setupTestClient(userInfoHandler(10)), WithMinBalance(50))
+
+	rec := httptest.NewRecorder()
+	checker.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if body := rec.Body.String(); body != "unavailable\n" {
+		t.Errorf("Expected a generic body, got %q", body)
+	}
+
+	rec = httptest.NewRecorder()
+	checker.LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -0,0 +1,146 @@
+package signalads
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that sends key in the Idempotency-Key
+// header of requests made with it, so the API can recognize repeated
+// submissions of the same send. Sends carrying a key may be hedged; see
+// WithHedging.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// WithHedging enables hedged requests: when a GET request, or a request whose
+// context carries an idempotency key, has not completed after delay, a second
+// identical attempt is started and whichever returns first without a
+// transport or server error wins. The other attempt is canceled. Hedging
+// trades a little extra load for lower tail latency, which matters most for
+// OTP delivery. Only hedge sends when the API deduplicates them by key. With
+// a RetryBudget, second attempts count as retries and are not started once
+// the budget is spent.
+func WithHedging(delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.hedgeDelay = delay
+	}
+}
+
+func (c *Client) shouldHedge(ctx context.Context, method string) bool {
+	if c.hedgeDelay <= 0 {
+		return false
+	}
+	return method == http.MethodGet || method == http.MethodHead || idempotencyKey(ctx) != ""
+}
+
+type hedgeResult struct {
+	index  int
+	resp   *http.Response
+	err    error
+	cancel context.CancelFunc
+}
+
+// succeeded reports whether the attempt produced a final answer. Client
+// errors count: repeating the request would not change them.
+func (r *hedgeResult) succeeded() bool {
+	return r.err == nil && r.resp.StatusCode < http.StatusInternalServerError
+}
+
+func (r *hedgeResult) discard() {
+	if r.resp != nil {
+		io.Copy(io.Discard, r.resp.Body)
+		r.resp.Body.Close()
+	}
+	r.cancel()
+}
+
+// doHedged runs attempt and, if it is still pending after the hedge delay, a
+// second copy of it, returning the first successful response. A failure of
+// the first attempt before the delay is returned as is; hedging is not a
+// retry mechanism.
+func (c *Client) doHedged(ctx context.Context, attempt func(context.Context) (*http.Response, error)) (*http.Response, error) {
+	results := make(chan hedgeResult, 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+	launch := func() {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := attempt(attemptCtx)
+			results <- hedgeResult{index: index, resp: resp, err: err, cancel: cancel}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	inflight := 1
+	var failed *hedgeResult
+	for {
+		select {
+		case <-timer.C:
+			if !c.allowRetry() {
+				continue
+			}
+			inflight++
+			launch()
+		case r := <-results:
+			inflight--
+			if r.succeeded() {
+				for i, cancel := range cancels {
+					if i != r.index {
+						cancel()
+					}
+				}
+				go drainHedges(results, inflight)
+				r.resp.Body = &cancelOnClose{ReadCloser: r.resp.Body, cancel: r.cancel}
+				return r.resp, nil
+			}
+
+			if failed != nil {
+				failed.discard()
+			}
+			failed = &r
+			if inflight == 0 {
+				if failed.resp != nil {
+					failed.resp.Body = &cancelOnClose{ReadCloser: failed.resp.Body, cancel: failed.cancel}
+				} else {
+					failed.cancel()
+				}
+				return failed.resp, failed.err
+			}
+		}
+	}
+}
+
+// drainHedges releases the attempts that lost the race.
+func drainHedges(results <-chan hedgeResult, n int) {
+	for i := 0; i < n; i++ {
+		r := <-results
+		r.discard()
+	}
+}
+
+// cancelOnClose ties an attempt's context to its response body, which is
+// read after doHedged returns.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,127 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowFirstHandler stalls the first request until it is canceled and
+// answers later ones immediately.
+func slowFirstHandler(t *testing.T, calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices a canceled request once the body is read.
+		io.Copy(io.Discard, r.Body)
+		if atomic.AddInt32(calls, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+				t.Error("Expected the slow attempt to be canceled")
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+}
+
+func TestHedging_GET(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(slowFirstHandler(t, &calls))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithHedging(20*time.Millisecond))
+
+	start := time.Now()
+	var result SendMessageResponse
+	if err := client.Get(context.Background(), "/status", &result, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected hedged response quickly, took %v", elapsed)
+	}
+	if result.ID != "msg-1" {
+		t.Errorf("Expected ID 'msg-1', got '%s'", result.ID)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestHedging_IdempotentSend(t *testing.T) {
+	var calls int32
+	slow := slowFirstHandler(t, &calls)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Idempotency-Key") != "otp-123" {
+			t.Errorf("Expected Idempotency-Key 'otp-123', got '%s'", r.Header.Get("Idempotency-Key"))
+		}
+		slow(w, r)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithHedging(20*time.Millisecond))
+
+	ctx := WithIdempotencyKey(context.Background(), "otp-123")
+	response, err := client.Messages.SendMessage(ctx, "+989123456789", "Your code is 1234")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.ID != "msg-1" {
+		t.Errorf("Expected ID 'msg-1', got '%s'", response.ID)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestHedging_SendWithoutKeyNotHedged(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(60 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithHedging(10*time.Millisecond))
+	if _, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestHedging_ServerErrorWaitsForHedge(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(40 * time.Millisecond)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		time.Sleep(80 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-2", Status: "sent"})
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithHedging(10*time.Millisecond))
+
+	var result SendMessageResponse
+	if err := client.Get(context.Background(), "/status", &result, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.ID != "msg-2" {
+		t.Errorf("Expected ID 'msg-2', got '%s'", result.ID)
+	}
+}
@@ -0,0 +1,98 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithReportHoldback makes the tracker hold delivery reports for messages it
+// has not recorded yet for up to d, instead of recording them right away.
+// A report can arrive before the send that produced the message returns
+// and records it; recorded first, it would be overwritten by the send and
+// the message would stay pending. A held report is applied as soon as the
+// message is tracked, or after d for messages sent by other processes.
+// Held reports live in memory and are lost if the process stops; the
+// poller run by Run catches up on them.
+func WithReportHoldback(d time.Duration) TrackerOption {
+	return func(t *StatusTracker) {
+		t.holdback = d
+	}
+}
+
+// heldReport is a delivery report waiting for its message to be tracked.
+type heldReport struct {
+	report *TrackedMessage
+	timer  *time.Timer
+}
+
+// report records a delivery report, holding it if the message is unknown
+// and the tracker has a holdback.
+func (t *StatusTracker) report(ctx context.Context, reported *TrackedMessage) error {
+	if t.holdback <= 0 {
+		return t.update(ctx, reported)
+	}
+
+	// Hold first and look the message up after, so that a concurrent Track
+	// either is seen by the lookup or finds the held report.
+	t.hold(reported)
+	_, err := t.store.Get(ctx, reported.MessageID)
+	switch {
+	case errors.Is(err, ErrTrackedMessageNotFound):
+		return nil
+	case err != nil:
+		t.takeHeld(reported.MessageID)
+		return fmt.Errorf("failed to load tracked message %s: %w", reported.MessageID, err)
+	}
+	if held := t.takeHeld(reported.MessageID); held != nil {
+		return t.update(ctx, held)
+	}
+	return nil
+}
+
+// hold keeps reported until its message is tracked or the holdback ends. A
+// report replaces the one held for the message only if the lifecycle allows
+// moving from the held status to it.
+func (t *StatusTracker) hold(reported *TrackedMessage) {
+	t.heldMu.Lock()
+	defer t.heldMu.Unlock()
+
+	if held, ok := t.held[reported.MessageID]; ok {
+		if NormalizeStatus(held.report.Status).CanTransition(NormalizeStatus(reported.Status)) {
+			held.report = reported
+		}
+		return
+	}
+	if t.held == nil {
+		t.held = make(map[string]*heldReport)
+	}
+	messageID := reported.MessageID
+	t.held[messageID] = &heldReport{
+		report: reported,
+		timer: time.AfterFunc(t.holdback, func() {
+			held := t.takeHeld(messageID)
+			if held == nil {
+				return
+			}
+			ctx := context.Background()
+			if err := t.update(ctx, held); err != nil {
+				t.reportError(ctx, err)
+			}
+		}),
+	}
+}
+
+// takeHeld removes and returns the report held for messageID, or nil.
+func (t *StatusTracker) takeHeld(messageID string) *TrackedMessage {
+	t.heldMu.Lock()
+	defer t.heldMu.Unlock()
+
+	held, ok := t.held[messageID]
+	if !ok {
+		return nil
+	}
+	held.timer.Stop()
+	delete(t.held, messageID)
+	return held.report
+}
@@ -0,0 +1,63 @@
+package signalads
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStatusTracker_ReportHoldback(t *testing.T) {
+	tracker := NewStatusTracker(NewMemoryTrackerStore(), WithReportHoldback(time.Minute))
+	h := newTestWebhookHandler()
+	tracker.Register(h)
+
+	ctx := context.Background()
+	report := &WebhookEvent{ID: "evt-1", Type: EventDeliveryReport, Data: []byte(`{"message_id": "msg_1", "status": "delivered"}`)}
+	if err := h.Dispatch(ctx, report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tracker.Track(ctx, "msg_1", "+989123456789", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" {
+		t.Errorf("Expected the held report to be applied after tracking, got status %s", msg.Status)
+	}
+	if msg.To != "+989123456789" {
+		t.Errorf("Expected recipient '+989123456789', got %q", msg.To)
+	}
+}
+
+func TestStatusTracker_ReportHoldbackExpiry(t *testing.T) {
+	tracker := NewStatusTracker(NewMemoryTrackerStore(), WithReportHoldback(10*time.Millisecond))
+	h := newTestWebhookHandler()
+	tracker.Register(h)
+
+	ctx := context.Background()
+	report := &WebhookEvent{ID: "evt-1", Type: EventDeliveryReport, Data: []byte(`{"message_id": "msg_other", "status": "delivered"}`)}
+	if err := h.Dispatch(ctx, report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tracker.Get(ctx, "msg_other"); err == nil {
+		t.Fatal("Expected the report to be held")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		msg, err := tracker.Get(ctx, "msg_other")
+		if err == nil {
+			if msg.Status != "delivered" {
+				t.Errorf("Expected status delivered, got %s", msg.Status)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the held report to be recorded after the holdback")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
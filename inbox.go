@@ -0,0 +1,88 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// InboxService provides methods for reading messages received on the
+// account's lines.
+type InboxService struct {
+	client *Client
+}
+
+// ListInboundMessages retrieves received messages, newest first.
+func (s *InboxService) ListInboundMessages(ctx context.Context, params *PaginationParams) (*ListInboundMessagesResponse, error) {
+	var response ListInboundMessagesResponse
+	if err := s.client.Get(ctx, "/inbox", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to list inbound messages: %w", err)
+	}
+
+	return &response, nil
+}
+
+// DownloadAttachment fetches the media file at attachmentURL, as found in
+// Attachment.URL, and writes it to w. The request carries the account's
+// credentials, so attachmentURL must be relative to the API base URL or
+// point to the same host; other URLs are refused. Redirects to another
+// host, such as a CDN, are followed without the credentials.
+func (s *InboxService) DownloadAttachment(ctx context.Context, attachmentURL string, w io.Writer) error {
+	u, err := s.attachmentURL(attachmentURL)
+	if err != nil {
+		return err
+	}
+
+	creds, err := s.client.currentCredentials().Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", creds.APIKey)
+	req.Header.Set("X-API-Secret", creds.APISecret)
+
+	resp, err := s.client.sendWithCredentials(req)
+	if err != nil {
+		return &TransportError{Op: "request failed", Err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, err = s.client.readResponse(resp)
+		return fmt.Errorf("failed to download attachment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return &TransportError{Op: "failed to download attachment", Err: err}
+	}
+	return nil
+}
+
+// attachmentURL resolves raw against the base URL and checks that it stays
+// on the API host.
+func (s *InboxService) attachmentURL(raw string) (*url.URL, error) {
+	base, err := url.Parse(s.client.BaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachment URL: %w", err)
+	}
+
+	var u *url.URL
+	if ref.IsAbs() {
+		u = ref
+	} else {
+		u = base.JoinPath(ref.Path)
+		u.RawQuery = ref.RawQuery
+	}
+	if u.Scheme != base.Scheme || u.Host != base.Host {
+		return nil, fmt.Errorf("refusing to send credentials to attachment host %q", u.Host)
+	}
+	return u, nil
+}
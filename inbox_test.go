@@ -0,0 +1,124 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInboxService_ListInboundMessages(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/inbox" {
+			t.Errorf("Expected path '/inbox', got '%s'", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListInboundMessagesResponse{
+			Messages: []InboundMessageEvent{{ID: "in-1", From: "+989123456789", To: "100020", Message: "YES"}},
+			Total:    1,
+		})
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Inbox.ListInboundMessages(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Messages) != 1 || response.Messages[0].Message != "YES" {
+		t.Errorf("Unexpected inbound messages: %+v", response.Messages)
+	}
+}
+
+func TestInboxService_DownloadAttachment(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/media/in-1/photo.jpg" {
+			t.Errorf("Expected path '/media/in-1/photo.jpg', got '%s'", r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "test-api-key" {
+			t.Errorf("Expected API key header, got '%s'", r.Header.Get("X-API-Key"))
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg-bytes"))
+	}
+
+	client := setupTestClient(handler)
+	var buf bytes.Buffer
+	if err := client.Inbox.DownloadAttachment(context.Background(), "/media/in-1/photo.jpg", &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "jpeg-bytes" {
+		t.Errorf("Expected attachment body, got '%s'", buf.String())
+	}
+
+	buf.Reset()
+	if err := client.Inbox.DownloadAttachment(context.Background(), client.baseURL+"/media/in-1/photo.jpg", &buf); err != nil {
+		t.Fatalf("Unexpected error for an absolute URL: %v", err)
+	}
+}
+
+func TestInboxService_DownloadAttachment_CrossHostRedirect(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "" || r.Header.Get("X-API-Secret") != "" {
+			t.Error("Expected the credentials not to be sent to another host")
+		}
+		w.Write([]byte("jpeg-bytes"))
+	}))
+	defer cdn.Close()
+
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, cdn.URL+"/in-1/photo.jpg", http.StatusFound)
+	})
+	var buf bytes.Buffer
+	if err := client.Inbox.DownloadAttachment(context.Background(), "/media/in-1/photo.jpg", &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "jpeg-bytes" {
+		t.Errorf("Expected attachment body, got '%s'", buf.String())
+	}
+}
+
+func TestInboxService_DownloadAttachment_Errors(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	client := setupTestClient(handler)
+	err := client.Inbox.DownloadAttachment(context.Background(), "https://evil.example.com/a.jpg", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "refusing") {
+		t.Errorf("Expected a foreign host to be refused, got %v", err)
+	}
+
+	err = client.Inbox.DownloadAttachment(context.Background(), "/media/missing.jpg", io.Discard)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestWebhookEvent_InboundAttachments(t *testing.T) {
+	event, err := ParseWebhookEvent(strings.NewReader(`{
+		"id": "evt-2",
+		"type": "message.inbound",
+		"data": {"id": "in-2", "from": "+989123456789", "to": "100020",
+			"attachments": [{"url": "/media/in-2/a.png", "content_type": "image/png", "size": 2048}]}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	msg, err := event.InboundMessage()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].ContentType != "image/png" || msg.Attachments[0].Size != 2048 {
+		t.Errorf("Unexpected attachments: %+v", msg.Attachments)
+	}
+}
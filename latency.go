@@ -0,0 +1,150 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SlowRequest describes a request that took longer than the threshold set
+// with WithSlowRequestThreshold.
+type SlowRequest struct {
+	Method string
+
+	// Endpoint path, with phone numbers redacted by the Redactor set with
+	// SetRedactor
+	Endpoint string
+
+	// Time from sending the request to receiving the response headers
+	Duration time.Duration
+
+	// HTTP status of the response; zero if the request failed without one
+	StatusCode int
+
+	// Error of a request that failed without a response
+	Err error
+}
+
+// WithSlowRequestThreshold registers fn to be called for every request that
+// takes longer than threshold, whether or not it succeeds. fn runs on the
+// request's goroutine and should return quickly.
+func WithSlowRequestThreshold(threshold time.Duration, fn func(ctx context.Context, slow SlowRequest)) ClientOption {
+	return func(c *Client) {
+		c.slowThreshold = threshold
+		c.slowRequest = fn
+	}
+}
+
+// DefaultLatencyBuckets are the bucket upper bounds of a latency histogram
+// created without bounds.
+var DefaultLatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyHistogram counts request latencies in buckets. It is safe for
+// concurrent use.
+type LatencyHistogram struct {
+	bounds []time.Duration
+
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+}
+
+// LatencySnapshot is the state of a LatencyHistogram at one point in time.
+type LatencySnapshot struct {
+	// Upper bounds of the buckets, ascending
+	Bounds []time.Duration
+
+	// Number of latencies at or below each bound, as in Prometheus
+	// histograms; Count includes those above the last bound
+	Cumulative []uint64
+
+	Count uint64
+	Sum   time.Duration
+}
+
+// NewLatencyHistogram creates a histogram with the given bucket upper
+// bounds, or DefaultLatencyBuckets if none are given.
+func NewLatencyHistogram(bounds ...time.Duration) *LatencyHistogram {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	bounds = append([]time.Duration(nil), bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+	return &LatencyHistogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+// WithLatencyHistogram records the latency of every request in h.
+func WithLatencyHistogram(h *LatencyHistogram) ClientOption {
+	return func(c *Client) {
+		c.latency = h
+	}
+}
+
+// LatencyHistogram returns the histogram set with WithLatencyHistogram, or
+// nil.
+func (c *Client) LatencyHistogram() *LatencyHistogram {
+	return c.latency
+}
+
+// Observe records one latency.
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += d
+}
+
+// Snapshot returns the current counts.
+func (h *LatencyHistogram) Snapshot() LatencySnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snapshot := LatencySnapshot{
+		Bounds:     append([]time.Duration(nil), h.bounds...),
+		Cumulative: make([]uint64, len(h.counts)),
+		Count:      h.count,
+		Sum:        h.sum,
+	}
+	var total uint64
+	for i, n := range h.counts {
+		total += n
+		snapshot.Cumulative[i] = total
+	}
+	return snapshot
+}
+
+// observeLatency reports a finished request to the latency histogram and
+// the slow request callback.
+func (c *Client) observeLatency(ctx context.Context, method, endpoint string, d time.Duration, resp *http.Response, err error) {
+	if c.latency != nil {
+		c.latency.Observe(d)
+	}
+	if c.slowRequest == nil || d <= c.slowThreshold {
+		return
+	}
+	slow := SlowRequest{Method: method, Endpoint: RedactText(endpoint), Duration: d, Err: err}
+	if resp != nil {
+		slow.StatusCode = resp.StatusCode
+	}
+	c.slowRequest(ctx, slow)
+}
@@ -0,0 +1,77 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithSlowRequestThreshold(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/messages/msg-1/status" {
+			time.Sleep(30 * time.Millisecond)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "user-1"}`))
+	}
+	client := setupTestClient(handler)
+
+	var slow []SlowRequest
+	WithSlowRequestThreshold(20*time.Millisecond, func(ctx context.Context, s SlowRequest) {
+		slow = append(slow, s)
+	})(client)
+
+	client.Messages.GetMessageStatus(context.Background(), "msg-1")
+	client.Messages.GetUserInfo(context.Background())
+
+	if len(slow) != 1 {
+		t.Fatalf("Expected 1 slow request, got %d", len(slow))
+	}
+	if slow[0].Method != http.MethodGet || slow[0].Endpoint != "/messages/msg-1/status" {
+		t.Errorf("Unexpected slow request: %+v", slow[0])
+	}
+	if slow[0].StatusCode != http.StatusServiceUnavailable || slow[0].Duration < 20*time.Millisecond {
+		t.Errorf("Expected status 503 and duration over 20ms, got %d and %v", slow[0].StatusCode, slow[0].Duration)
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram(time.Second, 100*time.Millisecond)
+	for _, d := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond, 3 * time.Second} {
+		h.Observe(d)
+	}
+
+	s := h.Snapshot()
+	if len(s.Bounds) != 2 || s.Bounds[0] != 100*time.Millisecond {
+		t.Fatalf("Expected sorted bounds, got %v", s.Bounds)
+	}
+	if s.Cumulative[0] != 2 || s.Cumulative[1] != 3 || s.Count != 4 {
+		t.Errorf("Unexpected counts: %v, total %d", s.Cumulative, s.Count)
+	}
+	if s.Sum != 3650*time.Millisecond {
+		t.Errorf("Expected sum 3.65s, got %v", s.Sum)
+	}
+}
+
+func TestWithLatencyHistogram(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "user-1"}`))
+	}
+	client := setupTestClient(handler)
+	h := NewLatencyHistogram()
+	WithLatencyHistogram(h)(client)
+
+	client.Messages.GetUserInfo(context.Background())
+	client.Messages.GetUserInfo(context.Background())
+
+	if client.LatencyHistogram() != h {
+		t.Error("Expected client to return its histogram")
+	}
+	if count := h.Snapshot().Count; count != 2 {
+		t.Errorf("Expected 2 observations, got %d", count)
+	}
+}
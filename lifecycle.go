@@ -0,0 +1,109 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIllegalTransition is wrapped by the *TransitionError returned by
+// CheckTransition.
+var ErrIllegalTransition = errors.New("illegal message status transition")
+
+// TransitionError describes a status change the message lifecycle does not
+// allow.
+type TransitionError struct {
+	From MessageStatusCode
+	To   MessageStatusCode
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%v from %q to %q", ErrIllegalTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// stage returns the position of s in the message lifecycle:
+//
+//	pending → scheduled → queued → sent → delivered, undelivered, failed,
+//	                                       rejected, expired or canceled
+//
+// A message can skip stages but never go back, and a final status is never
+// left. Unknown and empty statuses have no stage.
+func (s MessageStatusCode) stage() int {
+	switch s {
+	case StatusPending:
+		return 1
+	case StatusScheduled:
+		return 2
+	case StatusQueued:
+		return 3
+	case StatusSent:
+		return 4
+	}
+	if s.Terminal() {
+		return 5
+	}
+	return 0
+}
+
+// CanTransition reports whether a message in status s may move to status
+// to. Messages move forward through the lifecycle, skipping stages as
+// needed, and stay in the first final status they reach. A message whose
+// status is empty or unknown may move to any status, while one with a known
+// status never moves to an unknown one. No message moves to an empty
+// status, and moving to the same status is not a transition.
+func (s MessageStatusCode) CanTransition(to MessageStatusCode) bool {
+	from, next := s.stage(), to.stage()
+	switch {
+	case to == "" || s == to:
+		return false
+	case from == 0:
+		return true
+	default:
+		return next > from && !s.Terminal()
+	}
+}
+
+// CheckTransition returns a *TransitionError unless a message may move from
+// status from to status to. Both are normalized first.
+func CheckTransition(from, to string) error {
+	f, t := NormalizeStatus(from), NormalizeStatus(to)
+	if !f.CanTransition(t) {
+		return &TransitionError{From: f, To: t}
+	}
+	return nil
+}
+
+// Transition is a status change recorded by a StatusTracker.
+type Transition struct {
+	MessageID string
+
+	// Status before the change; empty for a newly tracked message
+	From MessageStatusCode
+
+	To MessageStatusCode
+	At time.Time
+}
+
+// WithTransitionHook registers fn to be called after every status change
+// the tracker records, including the first status of a tracked message.
+// Reports the lifecycle does not allow, such as a late "sent" after
+// "delivered", are not recorded and do not reach fn. fn runs on the
+// goroutine recording the change, such as the webhook request, and should
+// return quickly.
+func WithTransitionHook(fn func(ctx context.Context, t Transition)) TrackerOption {
+	return func(t *StatusTracker) {
+		t.hooks = append(t.hooks, fn)
+	}
+}
+
+// transitioned calls the transition hooks.
+func (t *StatusTracker) transitioned(ctx context.Context, transition Transition) {
+	for _, fn := range t.hooks {
+		fn(ctx, transition)
+	}
+}
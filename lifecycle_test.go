@@ -0,0 +1,144 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestMessageStatusCode_CanTransition(t *testing.T) {
+	tests := []struct {
+		from, to MessageStatusCode
+		want     bool
+	}{
+		{"", StatusQueued, true},
+		{StatusPending, StatusQueued, true},
+		{StatusQueued, StatusDelivered, true},
+		{StatusScheduled, StatusCanceled, true},
+		{StatusSent, StatusQueued, false},
+		{StatusSent, StatusSent, false},
+		{StatusDelivered, StatusSent, false},
+		{StatusDelivered, StatusFailed, false},
+		{StatusUnknown, StatusDelivered, true},
+		{StatusSent, StatusUnknown, false},
+		{StatusSent, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransition(tt.to); got != tt.want {
+			t.Errorf("Expected %q -> %q to be %v, got %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	if err := CheckTransition("ENROUTE", "DELIVRD"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	err := CheckTransition("delivered", "queued")
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("Expected *TransitionError, got %v", err)
+	}
+	if transitionErr.From != StatusDelivered || transitionErr.To != StatusQueued {
+		t.Errorf("Unexpected transition error: %+v", transitionErr)
+	}
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Error("Expected error to wrap ErrIllegalTransition")
+	}
+}
+
+func TestStatusTracker_TransitionHook(t *testing.T) {
+	var transitions []Transition
+	tracker := NewStatusTracker(NewMemoryTrackerStore(), WithTransitionHook(func(ctx context.Context, tr Transition) {
+		transitions = append(transitions, tr)
+	}))
+
+	ctx := context.Background()
+	if err := tracker.Track(ctx, "msg_1", "+989123456789", "queued"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, status := range []string{"sent", "DELIVRD", "sent", "failed"} {
+		if err := tracker.Update(ctx, "msg_1", status, ""); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	want := []Transition{
+		{MessageID: "msg_1", From: "", To: StatusQueued},
+		{MessageID: "msg_1", From: StatusQueued, To: StatusSent},
+		{MessageID: "msg_1", From: StatusSent, To: StatusDelivered},
+	}
+	if len(transitions) != len(want) {
+		t.Fatalf("Expected %d transitions, got %d: %+v", len(want), len(transitions), transitions)
+	}
+	for i, tr := range transitions {
+		if tr.MessageID != want[i].MessageID || tr.From != want[i].From || tr.To != want[i].To {
+			t.Errorf("Expected transition %+v, got %+v", want[i], tr)
+		}
+		if tr.At.IsZero() {
+			t.Errorf("Expected transition %d to have a time", i)
+		}
+	}
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if NormalizeStatus(msg.Status) != StatusDelivered {
+		t.Errorf("Expected status delivered, got %s", msg.Status)
+	}
+}
+
+func TestStatusTracker_TrackAfterReport(t *testing.T) {
+	var transitions []Transition
+	tracker := NewStatusTracker(NewMemoryTrackerStore(), WithTransitionHook(func(ctx context.Context, tr Transition) {
+		transitions = append(transitions, tr)
+	}))
+	ctx := context.Background()
+
+	if err := tracker.Update(ctx, "msg_1", "delivered", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tracker.Track(ctx, "msg_1", "+989123456789", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" || msg.To != "+989123456789" {
+		t.Errorf("Expected delivered with the recipient filled in, got %+v", msg)
+	}
+	if len(transitions) != 1 || transitions[0].To != StatusDelivered {
+		t.Errorf("Expected only the transition to delivered, got %+v", transitions)
+	}
+}
+
+func TestStatusTracker_ConcurrentUpdates(t *testing.T) {
+	tracker := NewStatusTracker(NewMemoryTrackerStore())
+	ctx := context.Background()
+
+	// Whatever the order, the message ends in the furthest status reported.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, status := range []string{"queued", "sent", "delivered"} {
+			wg.Add(1)
+			go func(status string) {
+				defer wg.Done()
+				tracker.Update(ctx, "msg_1", status, "")
+			}(status)
+		}
+	}
+	wg.Wait()
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" {
+		t.Errorf("Expected status delivered, got %s", msg.Status)
+	}
+}
@@ -0,0 +1,60 @@
+package signalads
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Default body size limits. Bulk requests near the request limit should be
+// split into several calls.
+const (
+	DefaultMaxResponseSize = 32 << 20
+	DefaultMaxRequestSize  = 32 << 20
+)
+
+var (
+	// ErrResponseTooLarge is returned when a response body exceeds the limit
+	// set with WithMaxResponseSize.
+	ErrResponseTooLarge = errors.New("response body too large")
+
+	// ErrRequestTooLarge is returned, before anything is sent, when a
+	// marshaled request body exceeds the limit set with WithMaxRequestSize.
+	ErrRequestTooLarge = errors.New("request body too large")
+)
+
+// WithMaxResponseSize limits how many bytes of a response body are read.
+// Larger responses fail with ErrResponseTooLarge instead of being buffered.
+// A size of zero or less removes the limit. Streaming calls such as
+// StreamMessages decode incrementally and are not limited.
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseSize = size
+	}
+}
+
+// WithMaxRequestSize limits the size of a marshaled request body, measured
+// before compression. Larger requests fail with ErrRequestTooLarge. A size
+// of zero or less removes the limit.
+func WithMaxRequestSize(size int64) ClientOption {
+	return func(c *Client) {
+		c.maxRequestSize = size
+	}
+}
+
+// readLimited reads r in full, failing once more than the response limit
+// has been read.
+func (c *Client) readLimited(r io.Reader) ([]byte, error) {
+	if c.maxResponseSize <= 0 {
+		return io.ReadAll(r)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, c.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxResponseSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, c.maxResponseSize)
+	}
+	return body, nil
+}
@@ -0,0 +1,46 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxResponseSize(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent", "message": "` + strings.Repeat("x", 1000) + `"}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithMaxResponseSize(512))
+	_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi")
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+
+	client = NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithMaxResponseSize(0))
+	if _, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi"); err != nil {
+		t.Errorf("Unexpected error without limit: %v", err)
+	}
+}
+
+func TestWithMaxRequestSize(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected oversized request not to be sent")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithMaxRequestSize(1024))
+	_, err := client.Messages.SendMessage(context.Background(), "+989123456789", strings.Repeat("x", 2048))
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("Expected ErrRequestTooLarge, got %v", err)
+	}
+}
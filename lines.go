@@ -0,0 +1,51 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Line types reported in Line.Type.
+const (
+	LineDedicated = "dedicated"
+	LineShared    = "shared"
+)
+
+// LinesService provides the sender lines of the account.
+type LinesService struct {
+	client *Client
+}
+
+// ListLines retrieves the lines of the account.
+func (s *LinesService) ListLines(ctx context.Context) (*ListLinesResponse, error) {
+	var response ListLinesResponse
+	if err := s.client.Get(ctx, "/lines", &response, nil); err != nil {
+		return nil, fmt.Errorf("failed to list lines: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ExpiringWithin returns the lines that expire within d, including lines
+// that have already expired, soonest first. Lines without an expiry date
+// are left out.
+func (s *LinesService) ExpiringWithin(ctx context.Context, d time.Duration) ([]Line, error) {
+	response, err := s.ListLines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(d)
+	var expiring []Line
+	for _, line := range response.Lines {
+		if !line.ExpiresAt.IsZero() && line.ExpiresAt.Before(deadline) {
+			expiring = append(expiring, line)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt)
+	})
+	return expiring, nil
+}
@@ -0,0 +1,79 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLinesService_ExpiringWithin(t *testing.T) {
+	now := time.Now().UTC()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/lines" {
+			t.Errorf("Expected path /lines, got %s", r.URL.Path)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"lines": [
+			{"number": "100020", "type": "dedicated", "expires_at": %q},
+			{"number": "100030", "type": "dedicated", "expires_at": %q},
+			{"number": "100040", "type": "dedicated", "expires_at": %q},
+			{"number": "5000", "type": "shared"}
+		]}`,
+			now.Add(10*24*time.Hour).Format(time.RFC3339),
+			now.Add(90*24*time.Hour).Format(time.RFC3339),
+			now.Add(2*24*time.Hour).Format(time.RFC3339))
+	}
+	client := setupTestClient(handler)
+
+	lines, err := client.Lines.ExpiringWithin(context.Background(), 14*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 expiring lines, got %d", len(lines))
+	}
+	if lines[0].Number != "100040" || lines[1].Number != "100020" {
+		t.Errorf("Expected lines soonest first, got %s, %s", lines[0].Number, lines[1].Number)
+	}
+}
+
+func TestAccountWatcher_LineExpiry(t *testing.T) {
+	expiry := time.Now().Add(3 * 24 * time.Hour).UTC().Truncate(time.Second)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"lines": [{"number": "100020", "type": "dedicated", "expires_at": %q}]}`, expiry.Format(time.RFC3339))
+	}
+	client := setupTestClient(handler)
+
+	h := newTestWebhookHandler()
+	var events []*LineExpiringEvent
+	h.OnLineExpiring(func(ctx context.Context, event *LineExpiringEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	watcher := NewAccountWatcher(client, h, WithLineExpiryWatch(7*24*time.Hour))
+
+	for i := 0; i < 2; i++ {
+		if err := watcher.Check(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if events[0].Line != "100020" || !events[0].ExpiresAt.Equal(expiry) {
+		t.Errorf("Unexpected event: %+v", events[0])
+	}
+
+	// A renewal that is still within the window is reported again.
+	expiry = expiry.Add(24 * time.Hour)
+	if err := watcher.Check(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected 2 events, got %d", len(events))
+	}
+}
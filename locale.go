@@ -0,0 +1,52 @@
+package signalads
+
+// Locales with built-in error message translations.
+const (
+	LocaleEnglish = "en"
+	LocalePersian = "fa"
+)
+
+// errorTranslations maps a locale to translations of known error codes.
+var errorTranslations = map[string]map[string]string{
+	LocalePersian: {
+		ErrCodeInvalidCredentials:  "اطلاعات احراز هویت نامعتبر است",
+		ErrCodeInvalidPhoneNumber:  "شماره تلفن نامعتبر است",
+		ErrCodeInvalidMessage:      "متن پیام نامعتبر است",
+		ErrCodeInsufficientBalance: "موجودی حساب کافی نیست",
+		ErrCodeRateLimitExceeded:   "تعداد درخواست‌ها بیش از حد مجاز است؛ لطفاً کمی بعد دوباره تلاش کنید",
+		ErrCodeNotFound:            "مورد درخواستی یافت نشد",
+		ErrCodeUnauthorized:        "دسترسی غیرمجاز است",
+		ErrCodeForbidden:           "اجازه دسترسی به این بخش را ندارید",
+		ErrCodeBadRequest:          "درخواست نامعتبر است",
+		ErrCodeInternalServerError: "خطای داخلی سرور",
+		ErrCodeServiceUnavailable:  "سرویس در حال حاضر در دسترس نیست",
+		ErrCodeInvalidTemplate:     "قالب پیام نامعتبر است",
+		ErrCodeTemplateNotApproved: "قالب پیام هنوز تأیید نشده است",
+		ErrCodeInvalidDocument:     "سند ارسالی نامعتبر است",
+		ErrCodeInvalidVoiceFormat:  "قالب فایل صوتی پشتیبانی نمی‌شود",
+		ErrCodeInvalidAPIKey:       "کلید API نامعتبر است",
+		ErrCodeInvalidAPISecret:    "رمز API با کلید آن مطابقت ندارد",
+		ErrCodeAccountSuspended:    "حساب کاربری تعلیق شده است",
+	},
+}
+
+// WithLocale asks the API for error messages in locale, such as
+// LocalePersian, through the Accept-Language header. Errors for codes the
+// SDK knows also get APIError.LocalizedMessage filled from a built-in
+// translation when the API does not provide one.
+func WithLocale(locale string) ClientOption {
+	return func(c *Client) {
+		c.locale = locale
+	}
+}
+
+// localizeError fills in the localized message of apiErr for the client's
+// locale, keeping any message the API already localized.
+func (c *Client) localizeError(apiErr *APIError) {
+	if c.locale == "" || apiErr.LocalizedMessage != "" {
+		return
+	}
+	if msg, ok := errorTranslations[c.locale][apiErr.Code]; ok {
+		apiErr.LocalizedMessage = msg
+	}
+}
@@ -0,0 +1,77 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithLocale(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Language") != LocalePersian {
+			t.Errorf("Expected Accept-Language 'fa', got '%s'", r.Header.Get("Accept-Language"))
+		}
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"code": "INSUFFICIENT_BALANCE", "message": "Insufficient balance"}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithLocale(LocalePersian))
+	_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.LocalizedMessage != "موجودی حساب کافی نیست" {
+		t.Errorf("Expected Persian message, got '%s'", apiErr.LocalizedMessage)
+	}
+	if apiErr.Message != "Insufficient balance" {
+		t.Errorf("Expected original message to be kept, got '%s'", apiErr.Message)
+	}
+}
+
+func TestWithLocale_KeepsServerTranslation(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code": "INVALID_PHONE_NUMBER", "message": "Invalid phone number", "localized_message": "شماره وارد شده معتبر نیست"}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithLocale(LocalePersian))
+	_, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.LocalizedMessage != "شماره وارد شده معتبر نیست" {
+		t.Errorf("Expected server translation, got '%s'", apiErr.LocalizedMessage)
+	}
+}
+
+func TestWithoutLocale(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Language") != "" {
+			t.Errorf("Expected no Accept-Language, got '%s'", r.Header.Get("Accept-Language"))
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	client := setupTestClient(handler)
+	_, err := client.Messages.GetMessageStatus(context.Background(), "msg-1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.LocalizedMessage != "" {
+		t.Errorf("Expected no localized message, got '%s'", apiErr.LocalizedMessage)
+	}
+}
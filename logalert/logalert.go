@@ -0,0 +1,368 @@
+// Package logalert provides a slog.Handler that delivers error-level log
+// records as SMS messages to on-call phone numbers through SignalAds.
+//
+// Records are batched over a flush interval, identical messages are
+// suppressed for a dedup window, and the number of SMS batches per hour is
+// capped so that an error storm cannot drain the account. zap users can
+// route through this handler with a zap-to-slog bridge such as zapslog.
+// Phone numbers in alerts are redacted by the Redactor set with
+// signalads.SetRedactor.
+package logalert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+const (
+	DefaultFlushInterval = 30 * time.Second
+	DefaultDedupWindow   = 10 * time.Minute
+	DefaultMaxPerHour    = 6
+	DefaultMaxLength     = 480
+)
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithLevel sets the minimum level that is sent as SMS. It defaults to
+// slog.LevelError.
+func WithLevel(level slog.Leveler) Option {
+	return func(h *Handler) {
+		h.level = level
+	}
+}
+
+// WithFlushInterval sets how long records are collected before a batch is
+// sent.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(h *Handler) {
+		if interval > 0 {
+			h.state.flushInterval = interval
+		}
+	}
+}
+
+// WithDedupWindow sets how long an identical message is suppressed after it
+// has been queued once. Zero disables deduplication.
+func WithDedupWindow(window time.Duration) Option {
+	return func(h *Handler) {
+		if window >= 0 {
+			h.state.dedupWindow = window
+		}
+	}
+}
+
+// WithMaxPerHour caps how many SMS batches are sent in any rolling hour.
+// Records that arrive while the cap is reached are counted and reported in
+// the next batch.
+func WithMaxPerHour(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.state.maxPerHour = n
+		}
+	}
+}
+
+// WithMaxLength sets the maximum length, in characters, of a batch message.
+func WithMaxLength(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.state.maxLength = n
+		}
+	}
+}
+
+// WithPrefix sets text placed at the start of every batch, such as the
+// service name.
+func WithPrefix(prefix string) Option {
+	return func(h *Handler) {
+		h.state.prefix = prefix
+	}
+}
+
+// WithNext forwards every record to next in addition to alerting, so the
+// Handler can wrap an existing logging pipeline.
+func WithNext(next slog.Handler) Option {
+	return func(h *Handler) {
+		h.next = next
+	}
+}
+
+// Handler is a slog.Handler that sends qualifying records as SMS alerts.
+// Close must be called to flush pending records and stop the background
+// flusher.
+type Handler struct {
+	level  slog.Leveler
+	next   slog.Handler
+	attrs  string
+	groups string
+	state  *state
+}
+
+type state struct {
+	client        *signalads.Client
+	recipients    []string
+	flushInterval time.Duration
+	dedupWindow   time.Duration
+	maxPerHour    int
+	maxLength     int
+	prefix        string
+
+	mu         sync.Mutex
+	pending    []string
+	seen       map[string]time.Time
+	sent       []time.Time
+	suppressed int
+	closed     bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a Handler that alerts recipients through client.
+func New(client *signalads.Client, recipients []string, opts ...Option) *Handler {
+	h := &Handler{
+		level: slog.LevelError,
+		state: &state{
+			client:        client,
+			recipients:    recipients,
+			flushInterval: DefaultFlushInterval,
+			dedupWindow:   DefaultDedupWindow,
+			maxPerHour:    DefaultMaxPerHour,
+			maxLength:     DefaultMaxLength,
+			seen:          make(map[string]time.Time),
+			stop:          make(chan struct{}),
+			done:          make(chan struct{}),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	go h.state.run()
+
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.level.Level() {
+		return true
+	}
+	return h.next != nil && h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler. It never blocks on the network.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		if err := h.next.Handle(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	if r.Level < h.level.Level() {
+		return nil
+	}
+
+	h.state.add(r.Level.String()+" "+r.Message, h.format(r))
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	if h.next != nil {
+		clone.next = h.next.WithAttrs(attrs)
+	}
+
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&b, h.groups, a)
+	}
+	clone.attrs = b.String()
+
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	if h.next != nil {
+		clone.next = h.next.WithGroup(name)
+	}
+	clone.groups = h.groups + name + "."
+
+	return &clone
+}
+
+// Close flushes pending records and stops the background flusher.
+func (h *Handler) Close() error {
+	s := h.state
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+func (h *Handler) format(r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.groups, a)
+		return true
+	})
+	return signalads.RedactText(b.String())
+}
+
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, groupPrefix, ga)
+		}
+		return
+	}
+
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, a.Value.String())
+}
+
+func (s *state) add(key, line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	now := time.Now()
+	if s.dedupWindow > 0 {
+		if last, ok := s.seen[key]; ok && now.Sub(last) < s.dedupWindow {
+			return
+		}
+		s.seen[key] = now
+	}
+
+	s.pending = append(s.pending, line)
+}
+
+func (s *state) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.stop:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *state) flush() {
+	text, ok := s.takeBatch(time.Now())
+	if !ok {
+		return
+	}
+
+	items := make([]signalads.BulkMessageItem, 0, len(s.recipients))
+	for _, to := range s.recipients {
+		items = append(items, signalads.BulkMessageItem{To: to, Message: text})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), signalads.DefaultTimeout)
+	defer cancel()
+
+	// There is nowhere sensible to report a failed alert from inside a log
+	// handler, so the error is dropped.
+	_, _ = s.client.Messages.SendBulkMessage(ctx, items, "")
+}
+
+// takeBatch removes pending lines and renders them as one message, applying
+// the hourly cap. It reports false when nothing should be sent.
+func (s *state) takeBatch(now time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, t := range s.seen {
+		if now.Sub(t) >= s.dedupWindow {
+			delete(s.seen, key)
+		}
+	}
+
+	if len(s.pending) == 0 || len(s.recipients) == 0 {
+		return "", false
+	}
+
+	recent := s.sent[:0]
+	for _, t := range s.sent {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	s.sent = recent
+
+	if len(s.sent) >= s.maxPerHour {
+		s.suppressed += len(s.pending)
+		s.pending = s.pending[:0]
+		return "", false
+	}
+
+	var b strings.Builder
+	if s.prefix != "" {
+		b.WriteString(s.prefix)
+		b.WriteByte('\n')
+	}
+	b.WriteString(strings.Join(s.pending, "\n"))
+	if s.suppressed > 0 {
+		fmt.Fprintf(&b, "\n(+%d suppressed)", s.suppressed)
+	}
+
+	s.pending = s.pending[:0]
+	s.suppressed = 0
+	s.sent = append(s.sent, now)
+
+	return truncate(b.String(), s.maxLength), true
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,148 @@
+package logalert
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	requests []signalads.SendBulkMessageRequest
+}
+
+func (rec *recorder) handler(w http.ResponseWriter, r *http.Request) {
+	var req signalads.SendBulkMessageRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	rec.mu.Lock()
+	rec.requests = append(rec.requests, req)
+	rec.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(signalads.SendBulkMessageResponse{Status: "success"})
+}
+
+func newTestHandler(t *testing.T, opts ...Option) (*Handler, *recorder) {
+	t.Helper()
+
+	rec := &recorder{}
+	server := httptest.NewServer(http.HandlerFunc(rec.handler))
+	t.Cleanup(server.Close)
+
+	client := signalads.NewClient("test-key", "test-secret", signalads.WithBaseURL(server.URL))
+	opts = append([]Option{WithFlushInterval(time.Hour)}, opts...)
+	return New(client, []string{"+989123456789", "+989123456790"}, opts...), rec
+}
+
+func TestHandler_SendsErrorRecords(t *testing.T) {
+	h, rec := newTestHandler(t, WithPrefix("[billing]"))
+	logger := slog.New(h).With("region", "eu")
+
+	logger.Info("ignored")
+	logger.Error("payment failed", "order", 42)
+	h.Close()
+
+	if len(rec.requests) != 1 {
+		t.Fatalf("Expected 1 bulk request, got %d", len(rec.requests))
+	}
+	req := rec.requests[0]
+	if len(req.Messages) != 2 {
+		t.Fatalf("Expected one message per recipient, got %d", len(req.Messages))
+	}
+
+	text := req.Messages[0].Message
+	if !strings.HasPrefix(text, "[billing]") {
+		t.Errorf("Expected prefix in message, got %q", text)
+	}
+	if !strings.Contains(text, "ERROR payment failed region=eu order=42") {
+		t.Errorf("Expected formatted record in message, got %q", text)
+	}
+	if strings.Contains(text, "ignored") {
+		t.Errorf("Expected info record to be skipped, got %q", text)
+	}
+}
+
+func TestHandler_Dedup(t *testing.T) {
+	h, rec := newTestHandler(t)
+	logger := slog.New(h)
+
+	logger.Error("database down", "attempt", 1)
+	logger.Error("database down", "attempt", 2)
+	h.Close()
+
+	if len(rec.requests) != 1 {
+		t.Fatalf("Expected 1 bulk request, got %d", len(rec.requests))
+	}
+	if n := strings.Count(rec.requests[0].Messages[0].Message, "database down"); n != 1 {
+		t.Errorf("Expected duplicate record to be suppressed, found %d occurrences", n)
+	}
+}
+
+func TestHandler_RateLimit(t *testing.T) {
+	h, _ := newTestHandler(t, WithMaxPerHour(1), WithDedupWindow(0))
+	defer h.Close()
+
+	now := time.Now()
+	h.state.add("a", "ERROR a")
+	if _, ok := h.state.takeBatch(now); !ok {
+		t.Fatal("Expected first batch to be sent")
+	}
+
+	h.state.add("b", "ERROR b")
+	if _, ok := h.state.takeBatch(now); ok {
+		t.Fatal("Expected second batch to be rate limited")
+	}
+
+	h.state.add("c", "ERROR c")
+	text, ok := h.state.takeBatch(now.Add(time.Hour))
+	if !ok {
+		t.Fatal("Expected batch after the hour to be sent")
+	}
+	if !strings.Contains(text, "(+1 suppressed)") {
+		t.Errorf("Expected suppressed count in message, got %q", text)
+	}
+}
+
+func TestHandler_Next(t *testing.T) {
+	var buf strings.Builder
+	next := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+
+	h, _ := newTestHandler(t, WithNext(next))
+	defer h.Close()
+
+	logger := slog.New(h)
+	if !h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Expected info level to be enabled through next handler")
+	}
+	logger.Info("hello")
+
+	if !strings.Contains(buf.String(), "hello") {
+		t.Errorf("Expected record forwarded to next handler, got %q", buf.String())
+	}
+}
+
+func TestHandler_RedactsPhoneNumbers(t *testing.T) {
+	signalads.SetRedactor(signalads.MaskRedactor)
+	defer signalads.SetRedactor(nil)
+
+	h, rec := newTestHandler(t)
+	slog.New(h).Error("delivery failed", "to", "+989121234567")
+	h.Close()
+
+	if len(rec.requests) != 1 {
+		t.Fatalf("Expected 1 bulk request, got %d", len(rec.requests))
+	}
+	if text := rec.requests[0].Messages[0].Message; !strings.Contains(text, "to=+98912***4567") {
+		t.Errorf("Expected the number to be masked, got %q", text)
+	}
+}
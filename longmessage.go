@@ -0,0 +1,236 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LongMessagePolicy decides what send methods do with text longer than the
+// segment cap set with WithLongMessagePolicy.
+type LongMessagePolicy int
+
+const (
+	// LongMessageAllow sends long text as is and leaves concatenation to
+	// the gateway. It is the default.
+	LongMessageAllow LongMessagePolicy = iota
+
+	// LongMessageReject fails the send with a validation error.
+	LongMessageReject
+
+	// LongMessageTruncate cuts the text to the longest prefix that fits.
+	LongMessageTruncate
+
+	// LongMessageSplit sends the text as several separate messages, each
+	// within the cap and numbered like "(1/3)".
+	LongMessageSplit
+)
+
+// WithLongMessagePolicy applies policy to SMS text that would take more than
+// maxSegments segments. Template and voice messages are not affected.
+func WithLongMessagePolicy(policy LongMessagePolicy, maxSegments int) ClientOption {
+	return func(c *Client) {
+		c.longMessagePolicy = policy
+		c.maxSegments = maxSegments
+	}
+}
+
+type sentPartsContextKey struct{}
+
+// WithSentParts returns a context for retrying a LongMessageSplit send that
+// failed with a *PartialError[int] listing the part numbers sent: the retry
+// skips the first n parts, so WithSentParts(ctx, len(partial.Completed))
+// sends only the rest.
+func WithSentParts(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, sentPartsContextKey{}, n)
+}
+
+func sentParts(ctx context.Context) int {
+	n, _ := ctx.Value(sentPartsContextKey{}).(int)
+	return max(n, 0)
+}
+
+// applyLongMessagePolicy returns the texts to send in place of full, which
+// is text followed by footer, each ending with footer. field names the text
+// in validation errors.
+func (c *Client) applyLongMessagePolicy(field, full, text, footer string, encoding Encoding) ([]string, error) {
+	if c.longMessagePolicy == LongMessageAllow || c.maxSegments <= 0 {
+		return []string{full}, nil
+	}
+	if CountSegmentsWithEncoding(full, encoding) <= c.maxSegments {
+		return []string{full}, nil
+	}
+
+	// The footer must survive truncation and appear in every part, so only
+	// the text is cut to the space the footer leaves.
+	ucs2 := isUCS2(full, encoding)
+	capacity := segmentCapacity(ucs2, c.maxSegments)
+	if footer != "" {
+		capacity -= unitLength("\n"+footer, ucs2)
+	}
+
+	var v validator
+	switch c.longMessagePolicy {
+	case LongMessageReject:
+		v.add(field, RuleMaxSegments, fmt.Sprintf("message exceeds %d segments", c.maxSegments))
+		return nil, v.err()
+	case LongMessageTruncate:
+		if capacity <= 0 {
+			v.add(field, RuleMaxSegments, fmt.Sprintf("footer leaves no room for text in %d segments", c.maxSegments))
+			return nil, v.err()
+		}
+		return []string{withFooter(truncateUnits(text, capacity, ucs2), footer)}, nil
+	case LongMessageSplit:
+		parts := splitMessage(text, capacity, ucs2)
+		if parts == nil {
+			v.add(field, RuleMaxSegments, fmt.Sprintf("footer leaves no room for text in %d segments", c.maxSegments))
+			return nil, v.err()
+		}
+		for i := range parts {
+			parts[i] = withFooter(parts[i], footer)
+		}
+		return parts, nil
+	default:
+		return nil, fmt.Errorf("unknown long message policy %d", c.longMessagePolicy)
+	}
+}
+
+// expandBulkRequest applies the footer and the long message policy to every
+// message of req. It returns req itself when nothing changes.
+func (c *Client) expandBulkRequest(ctx context.Context, req *SendBulkMessageRequest) (*SendBulkMessageRequest, error) {
+	expanded := *req
+	expanded.Messages = make([]BulkMessageItem, 0, len(req.Messages))
+	changed := false
+	var errs []FieldError
+	for i, item := range req.Messages {
+		parts, err := c.prepareText(ctx, indexedField("messages", i, "message"), item.To, item.Message, req.Encoding)
+		if err != nil {
+			var ve *ValidationError
+			if errors.As(err, &ve) {
+				errs = append(errs, ve.Errors...)
+				continue
+			}
+			return nil, err
+		}
+		if len(parts) != 1 || parts[0] != item.Message {
+			changed = true
+		}
+		for _, part := range parts {
+			item.Message = part
+			expanded.Messages = append(expanded.Messages, item)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	if !changed {
+		return req, nil
+	}
+	return &expanded, nil
+}
+
+func isUCS2(text string, encoding Encoding) bool {
+	switch encoding {
+	case EncodingGSM7:
+		return false
+	case EncodingUCS2:
+		return true
+	default:
+		return !IsGSM7(text)
+	}
+}
+
+// segmentCapacity returns how many encoding units fit in maxSegments
+// segments.
+func segmentCapacity(ucs2 bool, maxSegments int) int {
+	single, multi := GSM7SingleSegmentLength, GSM7MultiSegmentLength
+	if ucs2 {
+		single, multi = UCS2SingleSegmentLength, UCS2MultiSegmentLength
+	}
+	if maxSegments == 1 {
+		return single
+	}
+	return multi * maxSegments
+}
+
+func unitLength(text string, ucs2 bool) int {
+	n := 0
+	for _, r := range text {
+		n += runeUnits(r, ucs2)
+	}
+	return n
+}
+
+// runeUnits returns how many encoding units r takes.
+func runeUnits(r rune, ucs2 bool) int {
+	if ucs2 {
+		if r > 0xFFFF {
+			return 2
+		}
+		return 1
+	}
+	if gsm7ExtendedSet[r] {
+		return 2
+	}
+	return 1
+}
+
+// truncateUnits returns the longest prefix of text that fits in capacity
+// units.
+func truncateUnits(text string, capacity int, ucs2 bool) string {
+	n := 0
+	for i, r := range text {
+		n += runeUnits(r, ucs2)
+		if n > capacity {
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// splitMessage splits text into parts of at most capacity units including a
+// " (i/n)" suffix, breaking at whitespace where possible. It returns nil if
+// the suffix leaves no room for text.
+func splitMessage(text string, capacity int, ucs2 bool) []string {
+	// The suffix width depends on the number of parts, which depends on the
+	// suffix width; a few rounds settle it.
+	var chunks []string
+	for total := 1; ; {
+		suffixLen := len(fmt.Sprintf(" (%d/%d)", total, total))
+		if capacity-suffixLen < 1 {
+			return nil
+		}
+		chunks = chunkText(text, capacity-suffixLen, ucs2)
+		if len(chunks) <= total {
+			break
+		}
+		total = len(chunks)
+	}
+
+	parts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		parts[i] = fmt.Sprintf("%s (%d/%d)", chunk, i+1, len(chunks))
+	}
+	return parts
+}
+
+func chunkText(text string, capacity int, ucs2 bool) []string {
+	var chunks []string
+	for text != "" {
+		chunk := truncateUnits(text, capacity, ucs2)
+		if chunk == "" {
+			_, size := utf8.DecodeRuneInString(text)
+			chunk = text[:size]
+		} else if len(chunk) < len(text) {
+			if cut := strings.LastIndexFunc(chunk, unicode.IsSpace); cut > len(chunk)/2 {
+				chunk = chunk[:cut]
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(chunk))
+		text = strings.TrimLeftFunc(text[len(chunk):], unicode.IsSpace)
+	}
+	return chunks
+}
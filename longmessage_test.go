@@ -0,0 +1,171 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSplitMessage(t *testing.T) {
+	text := strings.Repeat("word ", 80) // 400 characters
+	parts := splitMessage(text, GSM7SingleSegmentLength, false)
+
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts, got %d: %q", len(parts), parts)
+	}
+	for i, part := range parts {
+		if CountSegments(part) != 1 {
+			t.Errorf("Expected part %d to fit one segment, got %d (%d chars)", i+1, CountSegments(part), len(part))
+		}
+		if !strings.HasSuffix(part, " ("+string(rune('1'+i))+"/3)") {
+			t.Errorf("Expected part %d to be numbered, got %q", i+1, part)
+		}
+		if strings.Contains(part, "wor (") || strings.Contains(part, "  ") {
+			t.Errorf("Expected part %d to break between words, got %q", i+1, part)
+		}
+	}
+}
+
+func TestTruncateUnits(t *testing.T) {
+	if got := truncateUnits(strings.Repeat("€", 100), GSM7SingleSegmentLength, false); len([]rune(got)) != 80 {
+		t.Errorf("Expected 80 extended characters, got %d", len([]rune(got)))
+	}
+	if got := truncateUnits(strings.Repeat("س", 100), UCS2SingleSegmentLength, true); len([]rune(got)) != 70 {
+		t.Errorf("Expected 70 Persian characters, got %d", len([]rune(got)))
+	}
+}
+
+func TestLongMessagePolicy_Send(t *testing.T) {
+	long := strings.Repeat("a", 400)
+
+	var received []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req.Message)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg", Status: "sent", Cost: 100})
+	}
+
+	client := setupTestClient(handler)
+	ctx := context.Background()
+
+	WithLongMessagePolicy(LongMessageReject, 2)(client)
+	_, err := client.Messages.SendMessage(ctx, "+989123456789", long)
+	if !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	WithLongMessagePolicy(LongMessageTruncate, 2)(client)
+	received = nil
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", long); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(received) != 1 || len(received[0]) != 2*GSM7MultiSegmentLength {
+		t.Errorf("Expected one message of %d characters, got %d messages", 2*GSM7MultiSegmentLength, len(received))
+	}
+
+	WithLongMessagePolicy(LongMessageSplit, 1)(client)
+	received = nil
+	response, err := client.Messages.SendMessage(ctx, "+989123456789", long)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(received) != 3 || len(response.Parts) != 3 {
+		t.Fatalf("Expected 3 separate messages, got %d sent and %d parts", len(received), len(response.Parts))
+	}
+	if response.Cost != 300 {
+		t.Errorf("Expected combined cost 300, got %.2f", response.Cost)
+	}
+	if !strings.HasSuffix(received[2], "(3/3)") {
+		t.Errorf("Expected last part numbered (3/3), got %q", received[2])
+	}
+
+	received = nil
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "short"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(received) != 1 || received[0] != "short" {
+		t.Errorf("Expected short message unchanged, got %q", received)
+	}
+}
+
+func TestLongMessagePolicy_SplitResume(t *testing.T) {
+	long := strings.Repeat("a", 400)
+
+	var received []string
+	var keys []string
+	fail := 2
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(received) == fail {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "rejected"}`))
+			return
+		}
+		received = append(received, req.Message)
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg", Status: "sent", Cost: 100})
+	}
+
+	client := setupTestClient(handler)
+	WithLongMessagePolicy(LongMessageSplit, 1)(client)
+	ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+	response, err := client.Messages.SendMessage(ctx, "+989123456789", long)
+	var partial *PartialError[int]
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a *PartialError[int], got %v", err)
+	}
+	if len(partial.Completed) != 2 || len(partial.Failed) != 1 || partial.Failed[0] != 3 {
+		t.Errorf("Expected parts 1 and 2 sent and 3 failed, got %v and %v", partial.Completed, partial.Failed)
+	}
+	if response == nil || len(response.Parts) != 2 || response.Cost != 200 {
+		t.Fatalf("Expected a response for the 2 sent parts, got %+v", response)
+	}
+
+	fail = -1
+	response, err = client.Messages.SendMessage(WithSentParts(ctx, len(partial.Completed)), "+989123456789", long)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(received) != 3 || !strings.HasSuffix(received[2], "(3/3)") {
+		t.Errorf("Expected only part 3 to be resent, got %q", received)
+	}
+	if response.Cost != 100 {
+		t.Errorf("Expected the cost of the resent part, got %.2f", response.Cost)
+	}
+	if keys[0] != "order-1/1" || keys[2] != "order-1/3" {
+		t.Errorf("Expected an idempotency key per part, got %q", keys)
+	}
+}
+
+func TestLongMessagePolicy_Bulk(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendBulkMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendBulkMessageResponse{Total: len(req.Messages), Status: "success"})
+	}
+
+	client := setupTestClient(handler)
+	WithLongMessagePolicy(LongMessageSplit, 1)(client)
+
+	response, err := client.Messages.SendBulkMessages(context.Background(), &SendBulkMessageRequest{
+		Messages: []BulkMessageItem{
+			{To: "+989123456781", Message: strings.Repeat("b", 200)},
+			{To: "+989123456782", Message: "short"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 3 {
+		t.Errorf("Expected 3 messages after splitting, got %d", response.Total)
+	}
+}
@@ -0,0 +1,95 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxListPages bounds the pages MessagesToday, MessagesBetween and
+// FailedSince read, 100,000 messages at 100 per page.
+const maxListPages = 1000
+
+// ErrPaginationStalled is returned when a listing does not advance: a page
+// has only messages already returned by earlier pages, or the page limit is
+// reached, as happens with gateways that ignore the page parameter.
+var ErrPaginationStalled = errors.New("pagination did not advance")
+
+// failedStatuses are the final statuses of messages that were not
+// delivered.
+var failedStatuses = []MessageStatusCode{StatusFailed, StatusUndelivered, StatusRejected, StatusExpired}
+
+// MessagesToday returns the messages sent since midnight in the client's
+// scheduling location (see SchedulingLocation), so that "today" is the
+// account's day rather than the server's.
+func (s *MessagesService) MessagesToday(ctx context.Context) ([]Message, error) {
+	now := time.Now().In(s.client.SchedulingLocation())
+	midnight := wallClock(now.Year(), now.Month(), now.Day(), 0, 0, now.Location())
+	return s.MessagesBetween(ctx, midnight, time.Time{})
+}
+
+// MessagesBetween returns the messages sent within [from, to], across all
+// pages. from and to may be in any location. A zero bound leaves that side
+// of the range open.
+func (s *MessagesService) MessagesBetween(ctx context.Context, from, to time.Time) ([]Message, error) {
+	return s.listAll(ctx, F().Between(from, to), nil)
+}
+
+// FailedSince returns the messages sent since t that ended undelivered:
+// failed, undelivered, rejected or expired.
+func (s *MessagesService) FailedSince(ctx context.Context, t time.Time) ([]Message, error) {
+	return s.listAll(ctx, F().Between(t, time.Time{}).Status(failedStatuses...), failedStatuses)
+}
+
+// listAll lists every page of messages matching filter, through the
+// client's Messages field so that decorators see the calls. Messages that
+// appear on more than one page are returned once. Non-empty
+// statuses drop the messages in other statuses, for gateways that ignore
+// the status filter.
+func (s *MessagesService) listAll(ctx context.Context, filter *Filter, statuses []MessageStatusCode) ([]Message, error) {
+	api := s.client.Messages
+	if api == nil {
+		api = s
+	}
+
+	params := &PaginationParams{Page: 1, PerPage: 100, Filter: filter}
+	var messages []Message
+	seen := make(map[string]bool)
+	for ; params.Page <= maxListPages; params.Page++ {
+		response, err := api.ListMessages(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		fresh := 0
+		for _, m := range response.Messages {
+			if m.ID != "" {
+				if seen[m.ID] {
+					continue
+				}
+				seen[m.ID] = true
+			}
+			fresh++
+			if len(statuses) == 0 || containsStatus(statuses, m.StatusCode()) {
+				messages = append(messages, m)
+			}
+		}
+		if len(response.Messages) == 0 || !response.Pagination.HasNext {
+			return messages, nil
+		}
+		if fresh == 0 {
+			return nil, fmt.Errorf("%w: page %d repeats earlier messages", ErrPaginationStalled, params.Page)
+		}
+	}
+	return nil, fmt.Errorf("%w: stopped after %d pages", ErrPaginationStalled, maxListPages)
+}
+
+func containsStatus(statuses []MessageStatusCode, status MessageStatusCode) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
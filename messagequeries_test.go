@@ -0,0 +1,85 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMessagesToday(t *testing.T) {
+	loc := time.FixedZone("UTC+3:30", 3*3600+1800)
+	var since string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		since = r.URL.Query().Get("since")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"messages": [{"id": "msg-1", "status": "sent"}]}`)
+	}
+
+	client := setupTestClient(handler)
+	WithSchedulingLocation(loc)(client)
+
+	messages, err := client.Messages.MessagesToday(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+
+	from, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		t.Fatalf("Expected an RFC 3339 since parameter, got %q", since)
+	}
+	from = from.In(loc)
+	now := time.Now().In(loc)
+	if from.Hour() != 0 || from.Minute() != 0 || from.Day() != now.Day() {
+		t.Errorf("Expected since to be today's midnight in the scheduling location, got %s", from)
+	}
+}
+
+func TestFailedSince(t *testing.T) {
+	var statusFilter string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		statusFilter = r.URL.Query().Get("status")
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"messages": [{"id": "msg-1", "status": "FAILED"}, {"id": "msg-2", "status": "delivered"}], "pagination": {"page": 1, "has_next": true}}`)
+			return
+		}
+		fmt.Fprint(w, `{"messages": [{"id": "msg-3", "status": "UNDELIV"}], "pagination": {"page": 2, "has_next": false}}`)
+	}
+
+	client := setupTestClient(handler)
+	messages, err := client.Messages.FailedSince(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if statusFilter != "failed,undelivered,rejected,expired" {
+		t.Errorf("Expected a filter on the failed statuses, got %q", statusFilter)
+	}
+	if len(messages) != 2 || messages[0].ID != "msg-1" || messages[1].ID != "msg-3" {
+		t.Errorf("Expected the failed messages of both pages, got %+v", messages)
+	}
+}
+
+func TestMessagesBetween_RepeatedPage(t *testing.T) {
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"messages": [{"id": "msg-1", "status": "sent"}], "pagination": {"has_next": true}}`)
+	}
+
+	client := setupTestClient(handler)
+	_, err := client.Messages.MessagesBetween(context.Background(), time.Now().Add(-time.Hour), time.Time{})
+	if !errors.Is(err, ErrPaginationStalled) {
+		t.Fatalf("Expected ErrPaginationStalled, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected listing to stop at the first repeated page, got %d requests", requests)
+	}
+}
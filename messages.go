@@ -3,6 +3,7 @@ package signalads
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // MessagesService provides methods for sending and managing SMS messages.
@@ -12,20 +13,108 @@ type MessagesService struct {
 
 // SendSingleMessage sends a single SMS message with optional document link.
 func (s *MessagesService) SendSingleMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
-	if req == nil {
-		return nil, fmt.Errorf("request cannot be nil")
+	if err := validateSendMessageRequest(req); err != nil {
+		return nil, err
 	}
-	if req.To == "" {
-		return nil, fmt.Errorf("recipient phone number is required")
+	ctx, category := resolveCategory(ctx, req.Category)
+	if from := s.client.fromOrDefault(req.From); category != req.Category || from != req.From {
+		resolved := *req
+		resolved.Category = category
+		resolved.From = from
+		req = &resolved
 	}
-	if req.Message == "" {
-		return nil, fmt.Errorf("message text is required")
+	if err := s.client.checkCalendar(ctx, time.Now()); err != nil {
+		return nil, err
 	}
+	if req.DocumentLink != "" && s.client.documentCheck != nil {
+		if err := s.client.documentCheck.run(ctx, req.DocumentLink); err != nil {
+			return nil, err
+		}
+	}
+
+	parts, err := s.client.prepareText(ctx, "message", req.To, req.Message, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	// A retry of a split send that failed part way skips the parts that
+	// went out, which already took their frequency slot.
+	skip := 0
+	if len(parts) > 1 {
+		skip = min(sentParts(ctx), len(parts))
+	}
+	release := func() {}
+	if skip == 0 {
+		release, err = s.client.checkFrequency(ctx, req.To)
+		if err != nil {
+			return nil, err
+		}
+	}
+	recipients := make([]string, len(parts)-skip)
+	for i := range recipients {
+		recipients[i] = req.To
+	}
+	if err = s.client.reserve(ctx, recipients...); err != nil {
+		release()
+		return nil, err
+	}
+	if len(parts) == 1 && parts[0] == req.Message {
+		return s.sendSingle(ctx, req)
+	}
+
+	// The footer or the long message policy changed the text. Parts go out
+	// as separate messages and the first response lists all of them in Parts.
+	// Each part gets its own idempotency key, so that the API does not take
+	// later parts for retries of the first.
+	key := idempotencyKey(ctx)
+	first := &SendMessageResponse{To: req.To}
+	for i := skip; i < len(parts); i++ {
+		partReq := *req
+		partReq.Message = parts[i]
+		partCtx := ctx
+		if key != "" {
+			partCtx = WithIdempotencyKey(ctx, fmt.Sprintf("%s/%d", key, i+1))
+		}
+		response, err := s.sendSingle(partCtx, &partReq)
+		if err != nil {
+			partial := &PartialError[int]{Err: err}
+			for n := 1; n <= len(parts); n++ {
+				if n <= i {
+					partial.Completed = append(partial.Completed, n)
+				} else {
+					partial.Failed = append(partial.Failed, n)
+				}
+			}
+			if len(first.Parts) == 0 {
+				return nil, partial
+			}
+			return first, partial
+		}
+		if len(first.Parts) == 0 {
+			*first = *response
+		} else {
+			first.Cost += response.Cost
+		}
+		first.Parts = append(first.Parts, *response)
+	}
+	if len(first.Parts) == 1 {
+		first.Parts = nil
+	}
+
+	return first, nil
+}
 
+func (s *MessagesService) sendSingle(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
 	var response SendMessageResponse
 	if err := s.client.Post(ctx, "/send-message/single", req, &response); err != nil {
 		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
+	s.client.trackSent(ctx, response.ID, req.To, response.Status)
+	s.client.recordUsage(ctx, Usage{
+		Channel:  UsageChannelSMS,
+		Messages: 1,
+		Segments: CountSegmentsWithEncoding(req.Message, req.Encoding),
+		Cost:     response.Cost,
+	})
 
 	return &response, nil
 }
@@ -50,17 +139,54 @@ func (s *MessagesService) SendMessageWithDocument(ctx context.Context, to, messa
 
 // SendBulkMessages sends multiple messages in a single request.
 func (s *MessagesService) SendBulkMessages(ctx context.Context, req *SendBulkMessageRequest) (*SendBulkMessageResponse, error) {
-	if req == nil {
-		return nil, fmt.Errorf("request cannot be nil")
+	if err := validateSendBulkMessageRequest(req); err != nil {
+		return nil, err
+	}
+	ctx, category := resolveCategory(ctx, req.Category)
+	if from := s.client.fromOrDefault(req.From); category != req.Category || from != req.From {
+		resolved := *req
+		resolved.Category = category
+		resolved.From = from
+		req = &resolved
+	}
+	if err := s.client.checkCalendar(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	req, rejected, release, err := s.filterBulkRecipients(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 	if len(req.Messages) == 0 {
-		return nil, fmt.Errorf("at least one message is required")
+		return &SendBulkMessageResponse{Rejected: rejected}, nil
+	}
+	kept := make([]string, len(req.Messages))
+	for i := range req.Messages {
+		kept[i] = req.Messages[i].To
+	}
+
+	req, err = s.client.expandBulkRequest(ctx, req)
+	if err != nil {
+		release(kept...)
+		return nil, err
+	}
+	recipients := make([]string, len(req.Messages))
+	for i := range req.Messages {
+		recipients[i] = req.Messages[i].To
+	}
+	if err = s.client.reserve(ctx, recipients...); err != nil {
+		release(kept...)
+		return nil, err
 	}
 
 	var response SendBulkMessageResponse
 	if err := s.client.Post(ctx, "/send-message/bulk", req, &response); err != nil {
 		return nil, fmt.Errorf("failed to send bulk messages: %w", err)
 	}
+	response.fillFailedItems(req)
+	response.Rejected = rejected
+	s.client.trackBulk(ctx, req, &response)
+	s.client.recordUsage(ctx, bulkUsage(req, &response))
 
 	return &response, nil
 }
@@ -75,20 +201,37 @@ func (s *MessagesService) SendBulkMessage(ctx context.Context, messages []BulkMe
 
 // SendTemplateMessage sends a message using a predefined template.
 func (s *MessagesService) SendTemplateMessage(ctx context.Context, req *SendTemplateMessageRequest) (*SendMessageResponse, error) {
-	if req == nil {
-		return nil, fmt.Errorf("request cannot be nil")
+	if err := validateSendTemplateMessageRequest(req); err != nil {
+		return nil, err
+	}
+	if err := s.client.checkTemplateParams(ctx, req); err != nil {
+		return nil, err
+	}
+	ctx, category := resolveCategory(ctx, req.Category)
+	if from := s.client.fromOrDefault(req.From); category != req.Category || from != req.From {
+		resolved := *req
+		resolved.Category = category
+		resolved.From = from
+		req = &resolved
+	}
+	if err := s.client.checkCalendar(ctx, time.Now()); err != nil {
+		return nil, err
 	}
-	if req.To == "" {
-		return nil, fmt.Errorf("recipient phone number is required")
+	release, err := s.client.checkFrequency(ctx, req.To)
+	if err != nil {
+		return nil, err
 	}
-	if req.TemplateID == "" {
-		return nil, fmt.Errorf("template ID is required")
+	if err = s.client.reserve(ctx, req.To); err != nil {
+		release()
+		return nil, err
 	}
 
 	var response SendMessageResponse
 	if err := s.client.Post(ctx, "/send-message/template", req, &response); err != nil {
 		return nil, fmt.Errorf("failed to send template message: %w", err)
 	}
+	s.client.trackSent(ctx, response.ID, req.To, response.Status)
+	s.client.recordUsage(ctx, Usage{Channel: UsageChannelTemplate, Messages: 1, Cost: response.Cost})
 
 	return &response, nil
 }
@@ -104,20 +247,34 @@ func (s *MessagesService) SendTemplate(ctx context.Context, to, templateID strin
 
 // SendVoiceMessage sends a voice or audio message.
 func (s *MessagesService) SendVoiceMessage(ctx context.Context, req *SendVoiceMessageRequest) (*SendMessageResponse, error) {
-	if req == nil {
-		return nil, fmt.Errorf("request cannot be nil")
+	if err := validateSendVoiceMessageRequest(req); err != nil {
+		return nil, err
 	}
-	if req.To == "" {
-		return nil, fmt.Errorf("recipient phone number is required")
+	ctx, category := resolveCategory(ctx, req.Category)
+	if from := s.client.fromOrDefault(req.From); category != req.Category || from != req.From {
+		resolved := *req
+		resolved.Category = category
+		resolved.From = from
+		req = &resolved
 	}
-	if req.Message == "" && req.AudioURL == "" {
-		return nil, fmt.Errorf("either message text or audio URL is required")
+	if err := s.client.checkCalendar(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+	release, err := s.client.checkFrequency(ctx, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.client.reserve(ctx, req.To); err != nil {
+		release()
+		return nil, err
 	}
 
 	var response SendMessageResponse
 	if err := s.client.Post(ctx, "/send-message/voice", req, &response); err != nil {
 		return nil, fmt.Errorf("failed to send voice message: %w", err)
 	}
+	s.client.trackSent(ctx, response.ID, req.To, response.Status)
+	s.client.recordUsage(ctx, Usage{Channel: UsageChannelVoice, Messages: 1, Cost: response.Cost})
 
 	return &response, nil
 }
@@ -134,8 +291,70 @@ func (s *MessagesService) SendVoice(ctx context.Context, to, message, voiceType,
 
 // ListMessages retrieves a list of messages with optional pagination.
 func (s *MessagesService) ListMessages(ctx context.Context, params *PaginationParams) (*ListMessagesResponse, error) {
+	var response ListMessagesResponse
+	if err := s.client.Get(ctx, "/messages", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	return &response, nil
+}
+
+// StreamMessages lists messages like ListMessages but decodes the response
+// incrementally, calling fn for each message instead of buffering the whole
+// list. It is intended for very large pages such as exports. Returning an
+// error from fn stops the stream and returns that error.
+func (s *MessagesService) StreamMessages(ctx context.Context, params *PaginationParams, fn func(*Message) error) error {
+	if fn == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+
+	return streamList(ctx, s.client, "/messages", paginationQuery(params), "messages", fn)
+}
+
+// ListScheduledMessages retrieves messages that are scheduled but not yet
+// sent.
+func (s *MessagesService) ListScheduledMessages(ctx context.Context, params *PaginationParams) (*ListScheduledMessagesResponse, error) {
+	var response ListScheduledMessagesResponse
+	if err := s.client.Get(ctx, "/messages/scheduled", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	return &response, nil
+}
+
+// RescheduleMessage moves a scheduled message to newTime without canceling
+// and recreating it. newTime may be in any location; it is sent to the API
+// in the client's scheduling location.
+func (s *MessagesService) RescheduleMessage(ctx context.Context, messageID string, newTime time.Time) (*ScheduledMessage, error) {
+	var v validator
+	v.required("id", messageID, "message ID is required")
+	if newTime.IsZero() {
+		v.add("scheduled_at", RuleRequired, "new schedule time is required")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if err := s.client.checkCalendar(ctx, newTime); err != nil {
+		return nil, err
+	}
+
+	var message ScheduledMessage
+	req := &RescheduleMessageRequest{ScheduledAt: s.client.scheduleTime(newTime)}
+	if err := s.client.Put(ctx, "/messages/"+pathSegment(messageID)+"/schedule", req, &message); err != nil {
+		return nil, fmt.Errorf("failed to reschedule message: %w", err)
+	}
+
+	return &message, nil
+}
+
+func paginationQuery(params *PaginationParams) map[string]string {
 	queryParams := make(map[string]string, 2)
 	if params != nil {
+		if params.Filter != nil {
+			for k, v := range params.Filter.params {
+				queryParams[k] = v
+			}
+		}
 		if params.Page > 0 {
 			queryParams["page"] = fmt.Sprintf("%d", params.Page)
 		}
@@ -143,25 +362,39 @@ func (s *MessagesService) ListMessages(ctx context.Context, params *PaginationPa
 			queryParams["per_page"] = fmt.Sprintf("%d", params.PerPage)
 		}
 	}
-
-	var response ListMessagesResponse
-	if err := s.client.Get(ctx, "/messages", &response, queryParams); err != nil {
-		return nil, fmt.Errorf("failed to list messages: %w", err)
-	}
-
-	return &response, nil
+	return queryParams
 }
 
 // GetMessageStatus retrieves the status of a specific message by its ID.
 func (s *MessagesService) GetMessageStatus(ctx context.Context, messageID string) (*MessageStatus, error) {
 	if messageID == "" {
-		return nil, fmt.Errorf("message ID is required")
+		return nil, &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "message ID is required"}}}
+	}
+
+	sc := s.client.statusCache
+	if sc != nil && !cacheBypassed(ctx) {
+		if status, stale, ok := sc.get(messageID); ok {
+			if stale && sc.startRefresh(messageID) {
+				go func() {
+					defer sc.endRefresh(messageID)
+					_, _ = s.fetchMessageStatus(context.WithoutCancel(ctx), messageID)
+				}()
+			}
+			return status, nil
+		}
 	}
 
+	return s.fetchMessageStatus(ctx, messageID)
+}
+
+func (s *MessagesService) fetchMessageStatus(ctx context.Context, messageID string) (*MessageStatus, error) {
 	var status MessageStatus
-	if err := s.client.Get(ctx, "/messages/"+messageID+"/status", &status, nil); err != nil {
+	if err := s.client.Get(ctx, "/messages/"+pathSegment(messageID)+"/status", &status, nil); err != nil {
 		return nil, fmt.Errorf("failed to get message status: %w", err)
 	}
+	if s.client.statusCache != nil {
+		s.client.statusCache.set(messageID, &status)
+	}
 
 	return &status, nil
 }
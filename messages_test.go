@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func setupTestClient(handler http.HandlerFunc) *Client {
@@ -467,22 +468,162 @@ func TestSendMessage_ErrorHandling(t *testing.T) {
 
 	// Check if error is wrapped APIError
 	var apiErr *APIError
-	if !IsAPIError(err) {
-		// Try to unwrap
-		if unwrapped := errors.Unwrap(err); unwrapped != nil {
-			if ae, ok := unwrapped.(*APIError); ok {
-				apiErr = ae
-			}
-		}
-		if apiErr == nil {
-			t.Errorf("Expected APIError, got %T: %v", err, err)
-			return
-		}
-	} else {
-		apiErr = err.(*APIError)
+	if !errors.As(err, &apiErr) {
+		t.Errorf("Expected APIError, got %T: %v", err, err)
+		return
 	}
 
 	if apiErr.Message != "Invalid phone number" {
 		t.Errorf("Expected error message 'Invalid phone number', got '%s'", apiErr.Message)
 	}
 }
+
+func TestStreamMessages(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" {
+			t.Errorf("Expected /messages, got %s", r.URL.Path)
+		}
+		if perPage := r.URL.Query().Get("per_page"); perPage != "" && perPage != "1000" {
+			t.Errorf("Expected per_page 1000, got '%s'", perPage)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"page":1,"meta":{"x":[1,2]},"messages":[{"id":"msg-1","status":"sent"},{"id":"msg-2","status":"delivered"},{"id":"msg-3","status":"failed"}],"total":3}`))
+	}
+
+	client := setupTestClient(handler)
+	ctx := context.Background()
+
+	var ids []string
+	err := client.Messages.StreamMessages(ctx, &PaginationParams{PerPage: 1000}, func(m *Message) error {
+		ids = append(ids, m.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != "msg-1" || ids[2] != "msg-3" {
+		t.Errorf("Expected messages msg-1..msg-3, got %v", ids)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = client.Messages.StreamMessages(ctx, nil, func(m *Message) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected stream to stop after 1 message, got %d", count)
+	}
+}
+
+func TestStreamMessages_Error(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	client := setupTestClient(handler)
+	err := client.Messages.StreamMessages(context.Background(), nil, func(m *Message) error {
+		t.Error("Expected no messages")
+		return nil
+	})
+	if !IsUnauthorized(err) {
+		t.Errorf("Expected unauthorized error, got %v", err)
+	}
+}
+
+func BenchmarkSendMessage(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"msg-123","status":"sent","to":"+989123456789"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Benchmark message"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestListScheduledMessages(t *testing.T) {
+	scheduledAt := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/messages/scheduled" {
+			t.Errorf("Expected path '/messages/scheduled', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") != "2" {
+			t.Errorf("Expected page 2, got '%s'", r.URL.Query().Get("page"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListScheduledMessagesResponse{
+			Messages: []ScheduledMessage{{ID: "msg-1", To: "+989123456789", Message: "Nowruz sale", ScheduledAt: scheduledAt}},
+			Page:     2,
+			Total:    11,
+		})
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Messages.ListScheduledMessages(context.Background(), &PaginationParams{Page: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(response.Messages))
+	}
+	if !response.Messages[0].ScheduledAt.Equal(scheduledAt) {
+		t.Errorf("Expected scheduled time %v, got %v", scheduledAt, response.Messages[0].ScheduledAt)
+	}
+}
+
+func TestRescheduleMessage(t *testing.T) {
+	newTime := time.Date(2026, 3, 21, 10, 30, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT request, got %s", r.Method)
+		}
+		if r.URL.Path != "/messages/msg-1/schedule" {
+			t.Errorf("Expected path '/messages/msg-1/schedule', got '%s'", r.URL.Path)
+		}
+
+		var req RescheduleMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if !req.ScheduledAt.Equal(newTime) {
+			t.Errorf("Expected scheduled_at %v, got %v", newTime, req.ScheduledAt)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ScheduledMessage{ID: "msg-1", ScheduledAt: req.ScheduledAt})
+	}
+
+	client := setupTestClient(handler)
+	message, err := client.Messages.RescheduleMessage(context.Background(), "msg-1", newTime)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !message.ScheduledAt.Equal(newTime) {
+		t.Errorf("Expected scheduled time %v, got %v", newTime, message.ScheduledAt)
+	}
+
+	_, err = client.Messages.RescheduleMessage(context.Background(), "", time.Time{})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Errors) != 2 {
+		t.Errorf("Expected 2 validation errors, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+)
+
+// FilePart is a file sent in a multipart request.
+type FilePart struct {
+	// Form field name; defaults to "file"
+	Field string
+
+	// File name sent to the API
+	Name string
+
+	// Content type of the file; derived from the extension of Name if empty
+	ContentType string
+
+	Reader io.Reader
+}
+
+// streamedBody is a request body that is sent as it is produced rather than
+// encoded up front. It can be read only once, so requests with one are
+// never hedged.
+type streamedBody struct {
+	r           io.Reader
+	contentType string
+}
+
+// PostMultipart performs a POST request to the specified endpoint with
+// fields and files as multipart/form-data. The body is streamed while the
+// files are read, so files of any size are sent without buffering them in
+// memory; in exchange each file is read once and the request is not
+// retried or hedged.
+func (c *Client) PostMultipart(ctx context.Context, endpoint string, fields map[string]string, files []FilePart, result interface{}) error {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, files))
+	}()
+
+	resp, err := c.doRequest(ctx, http.MethodPost, endpoint, &streamedBody{r: pr, contentType: mw.FormDataContentType()}, nil)
+	if err != nil {
+		return err
+	}
+	return c.parseResponse(resp, result)
+}
+
+// writeMultipart writes fields, in key order, then files to mw and closes
+// it.
+func writeMultipart(mw *multipart.Writer, fields map[string]string, files []FilePart) error {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, fields[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if err := writeFilePart(mw, file); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func writeFilePart(mw *multipart.Writer, file FilePart) error {
+	field := file.Field
+	if field == "" {
+		field = "file"
+	}
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(file.Name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filepath.Base(file.Name)))
+	header.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file.Reader)
+	return err
+}
@@ -0,0 +1,83 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_PostMultipart(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		if r.FormValue("caption") != "Invoice" {
+			t.Errorf("Expected caption 'Invoice', got '%s'", r.FormValue("caption"))
+		}
+
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		if string(data) != "%PDF-1.4" || header.Filename != "invoice.pdf" {
+			t.Errorf("Unexpected document %q with content %q", header.Filename, data)
+		}
+		if header.Header.Get("Content-Type") != "application/pdf" {
+			t.Errorf("Expected content type application/pdf, got %s", header.Header.Get("Content-Type"))
+		}
+
+		audio, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Expected a second file under the default field: %v", err)
+			return
+		}
+		audio.Close()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id": "upload-1"})
+	}
+
+	client := setupTestClient(handler)
+	files := []FilePart{
+		{Field: "document", Name: "invoice.pdf", Reader: strings.NewReader("%PDF-1.4")},
+		{Name: "greeting.mp3", ContentType: "audio/mpeg", Reader: strings.NewReader("ID3")},
+	}
+
+	var result map[string]string
+	if err := client.PostMultipart(context.Background(), "/uploads", map[string]string{"caption": "Invoice"}, files, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result["id"] != "upload-1" {
+		t.Errorf("Expected id 'upload-1', got '%s'", result["id"])
+	}
+}
+
+func TestClient_PostMultipartNotHedged(t *testing.T) {
+	var requests atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id": "upload-1"})
+	}
+
+	client := setupTestClient(handler)
+	WithHedging(1)(client)
+
+	ctx := WithIdempotencyKey(context.Background(), "upload-1")
+	files := []FilePart{{Name: "a.txt", Reader: strings.NewReader("hello")}}
+	if err := client.PostMultipart(ctx, "/uploads", nil, files, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
@@ -0,0 +1,71 @@
+package signalads
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Defaults for SendOTPRequest.
+const (
+	DefaultOTPCodeParam = "code"
+	DefaultOTPTTL       = 2 * time.Minute
+)
+
+// SendOTPMessage sends a one-time password with a template. The message is
+// sent as CategoryOTP, so policies meant for marketing, such as frequency
+// caps and low queue priority, do not hold it back, and it bypasses blackout
+// days. The result reports when the code expires.
+func (s *MessagesService) SendOTPMessage(ctx context.Context, req *SendOTPRequest) (*OTPSendResult, error) {
+	if err := validateSendOTPRequest(req); err != nil {
+		return nil, err
+	}
+
+	codeParam := req.CodeParam
+	if codeParam == "" {
+		codeParam = DefaultOTPCodeParam
+	}
+	ttl := req.TTL
+	if ttl <= 0 {
+		ttl = DefaultOTPTTL
+	}
+
+	params := make(map[string]string, len(req.Params)+2)
+	for k, v := range req.Params {
+		params[k] = v
+	}
+	params[codeParam] = req.Code
+	if req.TTLParam != "" {
+		params[req.TTLParam] = strconv.Itoa(int(ttl.Round(time.Minute) / time.Minute))
+	}
+
+	ctx = WithCalendarOverride(WithCategory(ctx, CategoryOTP), nil)
+	expiresAt := time.Now().Add(ttl)
+	response, err := s.SendTemplateMessage(ctx, &SendTemplateMessageRequest{
+		To:             req.To,
+		TemplateID:     req.TemplateID,
+		TemplateParams: params,
+		From:           req.From,
+		Category:       CategoryOTP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTPSendResult{
+		MessageID: response.ID,
+		Status:    response.Status,
+		Cost:      response.Cost,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// SendOTP is a convenience method for sending a one-time password with a
+// template that takes the code as its "code" parameter.
+func (s *MessagesService) SendOTP(ctx context.Context, phone, code, templateID string) (*OTPSendResult, error) {
+	return s.SendOTPMessage(ctx, &SendOTPRequest{
+		To:         phone,
+		Code:       code,
+		TemplateID: templateID,
+	})
+}
@@ -0,0 +1,100 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSendOTP(t *testing.T) {
+	var got SendTemplateMessageRequest
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/template" {
+			t.Errorf("Expected path /send-message/template, got %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent", "cost": 0.5}`))
+	})
+
+	// Blackout days do not apply to one-time passwords.
+	cal := NewCalendar(nil)
+	cal.Add(time.Now(), "holiday")
+	WithCalendar(cal)(client)
+
+	before := time.Now()
+	result, err := client.Messages.SendOTP(context.Background(), "+989123456789", "123456", "tpl-otp")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.MessageID != "msg-1" {
+		t.Errorf("Expected message ID msg-1, got %s", result.MessageID)
+	}
+	if result.ExpiresAt.Before(before.Add(DefaultOTPTTL)) || result.ExpiresAt.After(time.Now().Add(DefaultOTPTTL)) {
+		t.Errorf("Expected expiry %s after sending, got %s", DefaultOTPTTL, result.ExpiresAt)
+	}
+	if got.Category != CategoryOTP {
+		t.Errorf("Expected category %s, got %s", CategoryOTP, got.Category)
+	}
+	if got.TemplateID != "tpl-otp" || got.TemplateParams["code"] != "123456" {
+		t.Errorf("Expected template tpl-otp with code 123456, got %s %v", got.TemplateID, got.TemplateParams)
+	}
+}
+
+func TestSendOTPMessage_Params(t *testing.T) {
+	var got SendTemplateMessageRequest
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	})
+
+	_, err := client.Messages.SendOTPMessage(context.Background(), &SendOTPRequest{
+		To:         "+989123456789",
+		Code:       "4821",
+		TemplateID: "tpl-otp",
+		CodeParam:  "otp",
+		TTL:        5 * time.Minute,
+		TTLParam:   "minutes",
+		Params:     map[string]string{"app": "Shop"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]string{"otp": "4821", "minutes": "5", "app": "Shop"}
+	for k, v := range want {
+		if got.TemplateParams[k] != v {
+			t.Errorf("Expected param %s=%s, got %q", k, v, got.TemplateParams[k])
+		}
+	}
+	if len(got.TemplateParams) != len(want) {
+		t.Errorf("Expected %d params, got %v", len(want), got.TemplateParams)
+	}
+}
+
+func TestSendOTPMessage_Validation(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	})
+
+	_, err := client.Messages.SendOTPMessage(context.Background(), &SendOTPRequest{To: "+989123456789"})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	fields := ve.Fields()
+	if len(fields) != 2 || fields[0] != "code" || fields[1] != "template_id" {
+		t.Errorf("Expected fields [code template_id], got %v", fields)
+	}
+}
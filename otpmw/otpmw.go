@@ -0,0 +1,481 @@
+// Package otpmw adds SMS one-time-password verification to net/http
+// applications.
+//
+// A Manager issues codes through SignalAds and verifies them against a
+// pluggable Store. IssueHandler and VerifyMiddleware wire the flow into HTTP:
+// the challenge is bound to the browser by an HMAC-signed, HttpOnly,
+// SameSite=Strict cookie, so a code is only accepted from the client that
+// requested it and cross-site requests cannot complete a verification.
+package otpmw
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+const (
+	DefaultCodeLength  = 6
+	DefaultTTL         = 2 * time.Minute
+	DefaultMaxAttempts = 5
+	DefaultCookieName  = "signalads_otp"
+	DefaultMessage     = "Your verification code is %s"
+
+	// DefaultIssueLimit codes may be sent to one phone number per
+	// DefaultIssueWindow.
+	DefaultIssueLimit  = 3
+	DefaultIssueWindow = 10 * time.Minute
+)
+
+var (
+	// ErrNotFound is returned when a challenge does not exist or has expired.
+	ErrNotFound = errors.New("otp challenge not found or expired")
+
+	// ErrInvalidCode is returned when a code does not match its challenge.
+	ErrInvalidCode = errors.New("invalid otp code")
+
+	// ErrTooManyAttempts is returned once a challenge has been guessed
+	// at more than the allowed number of times.
+	ErrTooManyAttempts = errors.New("too many otp attempts")
+
+	// ErrInvalidToken is returned when a challenge token has been tampered
+	// with or is malformed.
+	ErrInvalidToken = errors.New("invalid otp token")
+
+	// ErrRateLimited is returned by Issue when a phone number has been sent
+	// the allowed number of codes in the current window.
+	ErrRateLimited = errors.New("too many otp codes requested")
+)
+
+// Record is a pending challenge held by a Store.
+type Record struct {
+	Phone     string
+	CodeHash  []byte
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+// Store persists pending challenges. Implementations must be safe for
+// concurrent use and return ErrNotFound for unknown or expired IDs.
+//
+// ConsumeIfMatch and IncrementIssues must be atomic, for example a Lua
+// script or a transaction in Redis, so that concurrent guesses cannot share
+// one attempt and concurrent requests cannot exceed the issue limit.
+type Store interface {
+	Put(ctx context.Context, id string, rec Record) error
+	Get(ctx context.Context, id string) (Record, error)
+	Delete(ctx context.Context, id string) error
+
+	// ConsumeIfMatch deletes the challenge and returns it if its CodeHash
+	// equals codeHash. Otherwise it counts a failed attempt and returns
+	// ErrInvalidCode, or deletes the challenge and returns
+	// ErrTooManyAttempts once maxAttempts attempts have failed.
+	ConsumeIfMatch(ctx context.Context, id string, codeHash []byte, maxAttempts int) (Record, error)
+
+	// IncrementIssues counts a code sent to phone and returns the number
+	// sent in the current window, which starts with the first code and
+	// lasts window.
+	IncrementIssues(ctx context.Context, phone string, window time.Duration) (int, error)
+}
+
+// MemoryStore is an in-process Store. Use a shared store such as Redis when
+// running more than one instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+	issues  map[string]issueWindow
+}
+
+type issueWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record), issues: make(map[string]issueWindow)}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, id string, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, r := range s.records {
+		if now.After(r.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+	for phone, w := range s.issues {
+		if now.After(w.resetAt) {
+			delete(s.issues, phone)
+		}
+	}
+	s.records[id] = rec
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, id string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[id]
+	if !ok || time.Now().After(rec.ExpiresAt) {
+		return Record{}, ErrNotFound
+	}
+	return rec, nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, id)
+	return nil
+}
+
+// ConsumeIfMatch implements Store.
+func (s *MemoryStore) ConsumeIfMatch(_ context.Context, id string, codeHash []byte, maxAttempts int) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[id]
+	if !ok || time.Now().After(rec.ExpiresAt) {
+		return Record{}, ErrNotFound
+	}
+	if rec.Attempts >= maxAttempts {
+		delete(s.records, id)
+		return Record{}, ErrTooManyAttempts
+	}
+	if !hmac.Equal(rec.CodeHash, codeHash) {
+		rec.Attempts++
+		s.records[id] = rec
+		return Record{}, ErrInvalidCode
+	}
+
+	delete(s.records, id)
+	return rec, nil
+}
+
+// IncrementIssues implements Store.
+func (s *MemoryStore) IncrementIssues(_ context.Context, phone string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	w := s.issues[phone]
+	if now.After(w.resetAt) {
+		w = issueWindow{resetAt: now.Add(window)}
+	}
+	w.count++
+	s.issues[phone] = w
+	return w.count, nil
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithStore sets where challenges are kept. It defaults to a MemoryStore.
+func WithStore(store Store) Option {
+	return func(m *Manager) {
+		m.store = store
+	}
+}
+
+// WithCodeLength sets the number of digits in a code.
+func WithCodeLength(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.codeLength = n
+		}
+	}
+}
+
+// WithTTL sets how long a code remains valid.
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		if ttl > 0 {
+			m.ttl = ttl
+		}
+	}
+}
+
+// WithMaxAttempts sets how many wrong codes are tolerated per challenge.
+func WithMaxAttempts(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.maxAttempts = n
+		}
+	}
+}
+
+// WithTemplate sends codes with the given template, passing the code as the
+// "code" parameter, instead of as plain text.
+func WithTemplate(templateID string) Option {
+	return func(m *Manager) {
+		m.templateID = templateID
+	}
+}
+
+// WithMessage sets the plain-text message format. It must contain one %s
+// verb, which is replaced by the code.
+func WithMessage(format string) Option {
+	return func(m *Manager) {
+		m.message = format
+	}
+}
+
+// WithIssueLimit sets how many codes may be sent to one phone number per
+// window. A limit of zero or less disables the check.
+func WithIssueLimit(limit int, window time.Duration) Option {
+	return func(m *Manager) {
+		m.issueLimit = limit
+		if window > 0 {
+			m.issueWindow = window
+		}
+	}
+}
+
+// WithCookieName sets the name of the challenge cookie.
+func WithCookieName(name string) Option {
+	return func(m *Manager) {
+		m.cookieName = name
+	}
+}
+
+// Manager issues and verifies one-time codes.
+type Manager struct {
+	client      *signalads.Client
+	secret      []byte
+	store       Store
+	codeLength  int
+	ttl         time.Duration
+	maxAttempts int
+	templateID  string
+	message     string
+	cookieName  string
+	issueLimit  int
+	issueWindow time.Duration
+}
+
+// New creates a Manager that sends codes through client. secret signs
+// challenge tokens and hashes stored codes; it must be kept private and be
+// identical across instances sharing a Store.
+func New(client *signalads.Client, secret []byte, opts ...Option) *Manager {
+	m := &Manager{
+		client:      client,
+		secret:      secret,
+		store:       NewMemoryStore(),
+		codeLength:  DefaultCodeLength,
+		ttl:         DefaultTTL,
+		maxAttempts: DefaultMaxAttempts,
+		message:     DefaultMessage,
+		cookieName:  DefaultCookieName,
+		issueLimit:  DefaultIssueLimit,
+		issueWindow: DefaultIssueWindow,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Issue sends a new code to phone and returns a signed challenge token that
+// must be presented together with the code to Verify. It returns
+// ErrRateLimited once phone has been sent the number of codes allowed by
+// WithIssueLimit.
+func (m *Manager) Issue(ctx context.Context, phone string) (string, error) {
+	if phone == "" {
+		return "", fmt.Errorf("phone number is required")
+	}
+	if m.issueLimit > 0 {
+		n, err := m.store.IncrementIssues(ctx, phone, m.issueWindow)
+		if err != nil {
+			return "", fmt.Errorf("failed to count otp codes: %w", err)
+		}
+		if n > m.issueLimit {
+			return "", ErrRateLimited
+		}
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return "", err
+	}
+	code, err := randomCode(m.codeLength)
+	if err != nil {
+		return "", err
+	}
+
+	rec := Record{
+		Phone:     phone,
+		CodeHash:  m.mac(id + ":" + code),
+		ExpiresAt: time.Now().Add(m.ttl),
+	}
+	if err := m.store.Put(ctx, id, rec); err != nil {
+		return "", fmt.Errorf("failed to store otp challenge: %w", err)
+	}
+
+	// Codes are exempt from policies meant for marketing messages.
+	ctx = signalads.WithCategory(ctx, signalads.CategoryOTP)
+	if m.templateID != "" {
+		_, err = m.client.Messages.SendTemplate(ctx, phone, m.templateID, map[string]string{"code": code})
+	} else {
+		_, err = m.client.Messages.SendMessage(ctx, phone, fmt.Sprintf(m.message, code))
+	}
+	if err != nil {
+		_ = m.store.Delete(ctx, id)
+		return "", err
+	}
+
+	return id + "." + base64.RawURLEncoding.EncodeToString(m.mac(id)), nil
+}
+
+// Verify checks code against the challenge identified by token and returns
+// the verified phone number. A challenge can be verified only once.
+func (m *Manager) Verify(ctx context.Context, token, code string) (string, error) {
+	id, err := m.parseToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	rec, err := m.store.ConsumeIfMatch(ctx, id, m.mac(id+":"+code), m.maxAttempts)
+	if err != nil {
+		return "", err
+	}
+	return rec.Phone, nil
+}
+
+// IssueHandler returns a handler that reads the "phone" form value, sends a
+// code, and stores the challenge token in a cookie. It responds 204 on
+// success and 429 when the phone number has reached its issue limit.
+func (m *Manager) IssueHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		token, err := m.Issue(r.Context(), r.FormValue("phone"))
+		if errors.Is(err, ErrRateLimited) {
+			w.Header().Set("Retry-After", fmt.Sprint(int(m.issueWindow.Seconds())))
+			http.Error(w, "too many verification codes requested", http.StatusTooManyRequests)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to send verification code", http.StatusBadGateway)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     m.cookieName,
+			Value:    token,
+			Path:     "/",
+			MaxAge:   int(m.ttl.Seconds()),
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// VerifyMiddleware checks the "code" form value against the challenge cookie.
+// On success it clears the cookie and calls next with the verified phone
+// number available via PhoneFromContext; otherwise it responds 401.
+func (m *Manager) VerifyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		cookie, err := r.Cookie(m.cookieName)
+		if err != nil {
+			http.Error(w, "verification required", http.StatusUnauthorized)
+			return
+		}
+
+		phone, err := m.Verify(r.Context(), cookie.Value, r.FormValue("code"))
+		if err != nil {
+			http.Error(w, "invalid verification code", http.StatusUnauthorized)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     m.cookieName,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), phoneKey{}, phone)))
+	})
+}
+
+type phoneKey struct{}
+
+// PhoneFromContext returns the phone number verified by VerifyMiddleware.
+func PhoneFromContext(ctx context.Context) (string, bool) {
+	phone, ok := ctx.Value(phoneKey{}).(string)
+	return phone, ok
+}
+
+func (m *Manager) parseToken(token string) (string, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(raw, m.mac(id)) {
+		return "", ErrInvalidToken
+	}
+	return id, nil
+}
+
+func (m *Manager) mac(data string) []byte {
+	h := hmac.New(sha256.New, m.secret)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate challenge ID: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func randomCode(length int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate code: %w", err)
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
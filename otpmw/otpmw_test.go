@@ -0,0 +1,189 @@
+package otpmw
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+type codeCatcher struct {
+	mu   sync.Mutex
+	last signalads.SendMessageRequest
+}
+
+func (c *codeCatcher) code() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last.Message[strings.LastIndex(c.last.Message, " ")+1:]
+}
+
+func newTestManager(t *testing.T, opts ...Option) (*Manager, *codeCatcher) {
+	t.Helper()
+
+	catcher := &codeCatcher{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		catcher.mu.Lock()
+		json.NewDecoder(r.Body).Decode(&catcher.last)
+		catcher.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(signalads.SendMessageResponse{ID: "msg-123", Status: "sent"})
+	}))
+	t.Cleanup(server.Close)
+
+	client := signalads.NewClient("test-key", "test-secret", signalads.WithBaseURL(server.URL))
+	return New(client, []byte("test-secret-key"), opts...), catcher
+}
+
+func TestManager_IssueAndVerify(t *testing.T) {
+	m, catcher := newTestManager(t)
+	ctx := context.Background()
+
+	token, err := m.Issue(ctx, "+989123456789")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	code := catcher.code()
+	if len(code) != DefaultCodeLength {
+		t.Fatalf("Expected %d digit code, got %q", DefaultCodeLength, code)
+	}
+
+	phone, err := m.Verify(ctx, token, code)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if phone != "+989123456789" {
+		t.Errorf("Expected phone '+989123456789', got '%s'", phone)
+	}
+
+	if _, err := m.Verify(ctx, token, code); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound on reuse, got %v", err)
+	}
+}
+
+func TestManager_VerifyFailures(t *testing.T) {
+	m, catcher := newTestManager(t, WithMaxAttempts(2))
+	ctx := context.Background()
+
+	token, _ := m.Issue(ctx, "+989123456789")
+	code := catcher.code()
+
+	if _, err := m.Verify(ctx, token+"x", code); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for tampered token, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := m.Verify(ctx, token, "000000x"); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Expected ErrInvalidCode, got %v", err)
+		}
+	}
+	if _, err := m.Verify(ctx, token, code); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Expected ErrTooManyAttempts, got %v", err)
+	}
+}
+
+func TestManager_VerifyConcurrentGuesses(t *testing.T) {
+	m, _ := newTestManager(t, WithMaxAttempts(3))
+	ctx := context.Background()
+	token, _ := m.Issue(ctx, "+989123456789")
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		invalid int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Verify(ctx, token, "wrong"); errors.Is(err, ErrInvalidCode) {
+				mu.Lock()
+				invalid++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if invalid != 3 {
+		t.Errorf("Expected exactly 3 guesses to be checked, got %d", invalid)
+	}
+}
+
+func TestManager_IssueLimit(t *testing.T) {
+	m, _ := newTestManager(t, WithIssueLimit(2, time.Hour))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.Issue(ctx, "+989123456789"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := m.Issue(ctx, "+989123456789"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if _, err := m.Issue(ctx, "+989120000000"); err != nil {
+		t.Errorf("Expected another phone number to be unaffected, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/otp", strings.NewReader(url.Values{"phone": {"+989123456789"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	m.IssueHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	m, catcher := newTestManager(t)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/otp", strings.NewReader(url.Values{"phone": {"+989123456789"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	m.IssueHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("Expected one HttpOnly SameSite=Strict cookie, got %v", cookies)
+	}
+
+	var verified string
+	protected := m.VerifyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, _ = PhoneFromContext(r.Context())
+	}))
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/otp/verify", strings.NewReader(url.Values{"code": {catcher.code()}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(cookies[0])
+	protected.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if verified != "+989123456789" {
+		t.Errorf("Expected verified phone in context, got '%s'", verified)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/otp/verify", strings.NewReader("code=123456"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	protected.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without cookie, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
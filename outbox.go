@@ -0,0 +1,529 @@
+package signalads
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OutboxStatus is the delivery state of a message held in an Outbox.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// OutboxMessage is a message persisted by an OutboxStore.
+type OutboxMessage struct {
+	ID          string             `json:"id"`
+	Request     SendMessageRequest `json:"request"`
+	Status      OutboxStatus       `json:"status"`
+	Attempts    int                `json:"attempts"`
+	LastError   string             `json:"last_error,omitempty"`
+	MessageID   string             `json:"message_id,omitempty"`
+	NextAttempt time.Time          `json:"next_attempt"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// OutboxStore persists outbox messages. Implementations must be safe for
+// concurrent use.
+type OutboxStore interface {
+	// Save inserts msg or replaces the stored message with the same ID.
+	Save(ctx context.Context, msg *OutboxMessage) error
+
+	// Due returns up to limit pending messages whose NextAttempt is not
+	// after now, oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]*OutboxMessage, error)
+
+	// Get returns the message with the given ID.
+	Get(ctx context.Context, id string) (*OutboxMessage, error)
+}
+
+// ErrOutboxMessageNotFound is returned by OutboxStore.Get for unknown IDs.
+var ErrOutboxMessageNotFound = errors.New("outbox message not found")
+
+// OutboxOption configures an Outbox.
+type OutboxOption func(*Outbox)
+
+// WithOutboxMaxAttempts sets how many times a message is tried before it is
+// marked as failed. It defaults to 5.
+func WithOutboxMaxAttempts(attempts int) OutboxOption {
+	return func(o *Outbox) {
+		if attempts > 0 {
+			o.maxAttempts = attempts
+		}
+	}
+}
+
+// WithOutboxBackoff sets the delay before retry attempt n (starting at 1).
+func WithOutboxBackoff(backoff func(attempt int) time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		if backoff != nil {
+			o.backoff = backoff
+		}
+	}
+}
+
+// WithOutboxPollInterval sets how often Run checks the store for due
+// messages. It defaults to one second.
+func WithOutboxPollInterval(interval time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
+	}
+}
+
+// WithOutboxBatchSize sets how many due messages are sent per poll.
+func WithOutboxBatchSize(size int) OutboxOption {
+	return func(o *Outbox) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// Outbox durably queues messages in an OutboxStore and sends them with
+// retries. Delivery is at-least-once: a message whose send succeeded but
+// whose state could not be saved is sent again. Retries denied by the
+// client's RetryBudget are put off until the next backoff.
+type Outbox struct {
+	client       *Client
+	messages     MessagesAPI
+	store        OutboxStore
+	maxAttempts  int
+	backoff      func(attempt int) time.Duration
+	pollInterval time.Duration
+	batchSize    int
+}
+
+// NewOutbox creates an Outbox that sends through client and persists to store.
+func NewOutbox(client *Client, store OutboxStore, opts ...OutboxOption) *Outbox {
+	o := &Outbox{
+		client:       client,
+		messages:     client.Messages,
+		store:        store,
+		maxAttempts:  5,
+		backoff:      exponentialBackoff,
+		pollInterval: time.Second,
+		batchSize:    50,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	if attempt > 10 {
+		attempt = 10
+	}
+	return time.Second << uint(attempt-1)
+}
+
+// Enqueue validates req and stores it for delivery, returning the outbox ID.
+func (o *Outbox) Enqueue(ctx context.Context, req *SendMessageRequest) (string, error) {
+	if err := validateSendMessageRequest(req); err != nil {
+		return "", err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	msg := &OutboxMessage{
+		ID:          id,
+		Request:     *req,
+		Status:      OutboxStatusPending,
+		NextAttempt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := o.store.Save(ctx, msg); err != nil {
+		return "", fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return id, nil
+}
+
+// Status returns the stored state of the message with the given outbox ID.
+func (o *Outbox) Status(ctx context.Context, id string) (*OutboxMessage, error) {
+	return o.store.Get(ctx, id)
+}
+
+// Flush sends every message that is currently due and returns how many
+// messages were attempted. Each message is attempted at most once per
+// Flush, so one that is due again straight away, because of a zero backoff,
+// waits for the next Flush. If it stops early, the error is a
+// *PartialResult listing the outbox IDs of the messages attempted, and of
+// those in the current batch that were not.
+func (o *Outbox) Flush(ctx context.Context) (int, error) {
+	var attempted []string
+	seen := make(map[string]bool)
+	for {
+		// Messages already attempted may be due again; look past them.
+		limit := o.batchSize + len(seen)
+		due, err := o.store.Due(ctx, time.Now(), limit)
+		if err != nil {
+			return len(attempted), &PartialResult{Completed: attempted, Err: fmt.Errorf("failed to load due messages: %w", err)}
+		}
+
+		fresh := due[:0:0]
+		for _, msg := range due {
+			if !seen[msg.ID] {
+				fresh = append(fresh, msg)
+			}
+		}
+		if len(fresh) == 0 {
+			return len(attempted), nil
+		}
+
+		for i, msg := range fresh {
+			if err = ctx.Err(); err == nil {
+				err = o.deliver(ctx, msg)
+			}
+			if err != nil {
+				partial := &PartialResult{Completed: attempted, Err: err}
+				for _, m := range fresh[i:] {
+					partial.Failed = append(partial.Failed, m.ID)
+				}
+				return len(attempted), partial
+			}
+			seen[msg.ID] = true
+			attempted = append(attempted, msg.ID)
+		}
+
+		if len(due) < limit {
+			return len(attempted), nil
+		}
+	}
+}
+
+// Run flushes the outbox every poll interval until ctx is done.
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := o.Flush(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Outbox) deliver(ctx context.Context, msg *OutboxMessage) error {
+	if msg.Attempts > 0 && !o.client.allowRetry() {
+		// Put the retry off without counting it as an attempt.
+		msg.UpdatedAt = time.Now()
+		msg.NextAttempt = msg.UpdatedAt.Add(o.backoff(msg.Attempts))
+		if err := o.store.Save(ctx, msg); err != nil {
+			return fmt.Errorf("failed to update outbox message %s: %w", msg.ID, err)
+		}
+		return nil
+	}
+
+	// The outbox ID keys every attempt, so that the API drops a retry of a
+	// send whose response was lost.
+	req := msg.Request
+	resp, sendErr := o.messages.SendSingleMessage(WithIdempotencyKey(ctx, msg.ID), &req)
+	if sendErr != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	msg.Attempts++
+	msg.UpdatedAt = time.Now()
+
+	switch {
+	case sendErr == nil:
+		msg.Status = OutboxStatusSent
+		msg.LastError = ""
+		msg.MessageID = resp.ID
+	case !isRetryable(sendErr) || msg.Attempts >= o.maxAttempts:
+		msg.Status = OutboxStatusFailed
+		msg.LastError = sendErr.Error()
+		o.client.reportSendFailure(ctx, &req, sendErr)
+	default:
+		msg.LastError = sendErr.Error()
+		msg.NextAttempt = msg.UpdatedAt.Add(o.backoff(msg.Attempts))
+	}
+
+	if err := o.store.Save(ctx, msg); err != nil {
+		return fmt.Errorf("failed to update outbox message %s: %w", msg.ID, err)
+	}
+	return nil
+}
+
+// isRetryable reports whether a failed send may succeed if repeated: only
+// network failures and API responses saying it was rate limiting or
+// unavailable. Everything else is final, including local rejections such
+// as validation or quota errors, and decode errors, which mean the API
+// already accepted the message.
+func isRetryable(err error) bool {
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MemoryOutboxStore is an OutboxStore that keeps messages in memory. It does
+// not survive restarts and is mainly useful for tests.
+type MemoryOutboxStore struct {
+	mu       sync.Mutex
+	messages map[string]OutboxMessage
+}
+
+// NewMemoryOutboxStore creates an empty MemoryOutboxStore.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{messages: make(map[string]OutboxMessage)}
+}
+
+// Save implements OutboxStore.
+func (s *MemoryOutboxStore) Save(_ context.Context, msg *OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[msg.ID] = *msg
+	return nil
+}
+
+// Due implements OutboxStore.
+func (s *MemoryOutboxStore) Due(_ context.Context, now time.Time, limit int) ([]*OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return dueMessages(s.messages, now, limit), nil
+}
+
+// Get implements OutboxStore.
+func (s *MemoryOutboxStore) Get(_ context.Context, id string) (*OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, ok := s.messages[id]
+	if !ok {
+		return nil, ErrOutboxMessageNotFound
+	}
+	return &msg, nil
+}
+
+// DefaultFileOutboxRetention is how long a FileOutboxStore keeps sent and
+// failed messages unless WithFileOutboxRetention says otherwise.
+const DefaultFileOutboxRetention = 24 * time.Hour
+
+// FileOutboxStore is an OutboxStore backed by a JSON file. Every change
+// rewrites the file atomically, so it suits low to moderate volumes. Sent
+// and failed messages are dropped once their retention has passed, so that
+// the file only grows with the messages still in flight.
+type FileOutboxStore struct {
+	mu        sync.Mutex
+	path      string
+	messages  map[string]OutboxMessage
+	retention time.Duration
+	encrypter Encrypter
+	now       func() time.Time
+}
+
+// FileOutboxOption configures a FileOutboxStore.
+type FileOutboxOption func(*FileOutboxStore)
+
+// WithFileOutboxRetention sets how long sent and failed messages stay in
+// the store, and so can be looked up with Outbox.Status, after their last
+// update. It defaults to DefaultFileOutboxRetention.
+func WithFileOutboxRetention(retention time.Duration) FileOutboxOption {
+	return func(s *FileOutboxStore) {
+		if retention > 0 {
+			s.retention = retention
+		}
+	}
+}
+
+// WithFileOutboxEncrypter encrypts the file, which holds the message texts
+// and recipients, with e. A file written without encryption is still read,
+// and encrypted on the next change.
+func WithFileOutboxEncrypter(e Encrypter) FileOutboxOption {
+	return func(s *FileOutboxStore) {
+		s.encrypter = e
+	}
+}
+
+// NewFileOutboxStore opens the store at path, loading any messages left by a
+// previous process.
+func NewFileOutboxStore(path string, opts ...FileOutboxOption) (*FileOutboxStore, error) {
+	s := &FileOutboxStore{
+		path:      path,
+		messages:  make(map[string]OutboxMessage),
+		retention: DefaultFileOutboxRetention,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read outbox file: %w", err)
+	}
+
+	if data, err = decryptFile(s.encrypter, data); err != nil {
+		return nil, fmt.Errorf("failed to decrypt outbox file: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.messages); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox file: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Save implements OutboxStore.
+func (s *FileOutboxStore) Save(_ context.Context, msg *OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, existed := s.messages[msg.ID]
+	s.messages[msg.ID] = *msg
+	pruned := s.pruneLocked()
+	if err := s.persist(); err != nil {
+		for id, m := range pruned {
+			s.messages[id] = m
+		}
+		if existed {
+			s.messages[msg.ID] = prev
+		} else {
+			delete(s.messages, msg.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// pruneLocked removes the finished messages whose retention has passed and
+// returns them.
+func (s *FileOutboxStore) pruneLocked() map[string]OutboxMessage {
+	cutoff := s.now().Add(-s.retention)
+	var pruned map[string]OutboxMessage
+	for id, msg := range s.messages {
+		if msg.Status != OutboxStatusPending && msg.UpdatedAt.Before(cutoff) {
+			if pruned == nil {
+				pruned = make(map[string]OutboxMessage)
+			}
+			pruned[id] = msg
+			delete(s.messages, id)
+		}
+	}
+	return pruned
+}
+
+// Due implements OutboxStore.
+func (s *FileOutboxStore) Due(_ context.Context, now time.Time, limit int) ([]*OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return dueMessages(s.messages, now, limit), nil
+}
+
+// Get implements OutboxStore.
+func (s *FileOutboxStore) Get(_ context.Context, id string) (*OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, ok := s.messages[id]
+	if !ok {
+		return nil, ErrOutboxMessageNotFound
+	}
+	return &msg, nil
+}
+
+func (s *FileOutboxStore) persist() error {
+	data, err := json.Marshal(s.messages)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox: %w", err)
+	}
+	if data, err = encryptFile(s.encrypter, data); err != nil {
+		return fmt.Errorf("failed to encrypt outbox: %w", err)
+	}
+	if err = writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write outbox file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces the file at path with data, so that a crash
+// leaves either the old or the new contents.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func dueMessages(messages map[string]OutboxMessage, now time.Time, limit int) []*OutboxMessage {
+	due := make([]*OutboxMessage, 0)
+	for id := range messages {
+		msg := messages[id]
+		if msg.Status == OutboxStatusPending && !msg.NextAttempt.After(now) {
+			due = append(due, &msg)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due
+}
@@ -0,0 +1,283 @@
+package signalads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// sqlOutboxColumns lists the columns of the outbox table in scan order.
+const sqlOutboxColumns = `id, request, status, attempts, last_error, message_id, next_attempt, created_at, updated_at`
+
+// SQLOutboxStore is an OutboxStore backed by a database/sql table, for
+// transactional outboxes: EnqueueTx writes a message in the same
+// transaction as the business change it belongs to, so that the message
+// is sent if and only if that transaction commits. Like SQLTrackerStore it
+// uses only portable SQL and stores times as Unix milliseconds.
+//
+// The table has this schema, which CreateTable creates:
+//
+//	id           VARCHAR(64)  NOT NULL PRIMARY KEY
+//	request      TEXT         NOT NULL  -- SendMessageRequest as JSON, encrypted with WithSQLOutboxEncrypter
+//	status       VARCHAR(16)  NOT NULL  -- pending, sent or failed
+//	attempts     INTEGER      NOT NULL
+//	last_error   TEXT         NOT NULL
+//	message_id   VARCHAR(128) NOT NULL  -- API message ID once sent
+//	next_attempt BIGINT       NOT NULL
+//	created_at   BIGINT       NOT NULL
+//	updated_at   BIGINT       NOT NULL
+type SQLOutboxStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+	lease       time.Duration
+	encrypter   Encrypter
+}
+
+// SQLOutboxOption configures an SQLOutboxStore.
+type SQLOutboxOption func(*SQLOutboxStore)
+
+// WithSQLOutboxTable sets the table name. It defaults to "signalads_outbox".
+func WithSQLOutboxTable(table string) SQLOutboxOption {
+	return func(s *SQLOutboxStore) {
+		s.table = table
+	}
+}
+
+// WithSQLOutboxDollarPlaceholders makes queries use $1-style placeholders,
+// as PostgreSQL drivers require, instead of "?".
+func WithSQLOutboxDollarPlaceholders() SQLOutboxOption {
+	return func(s *SQLOutboxStore) {
+		s.placeholder = dollarPlaceholder
+	}
+}
+
+// WithSQLOutboxLease sets how long a message returned by Due is hidden from
+// other workers. It must exceed the time a send takes; a worker that dies
+// mid-send leaves its messages to be retried once the lease runs out. It
+// defaults to one minute.
+func WithSQLOutboxLease(lease time.Duration) SQLOutboxOption {
+	return func(s *SQLOutboxStore) {
+		if lease > 0 {
+			s.lease = lease
+		}
+	}
+}
+
+// WithSQLOutboxEncrypter encrypts the request column, which holds the
+// message text and recipient, with e. Rows written without encryption are
+// still read.
+func WithSQLOutboxEncrypter(e Encrypter) SQLOutboxOption {
+	return func(s *SQLOutboxStore) {
+		s.encrypter = e
+	}
+}
+
+// NewSQLOutboxStore creates a store using db. Call CreateTable once to
+// create the table if it does not exist.
+func NewSQLOutboxStore(db *sql.DB, opts ...SQLOutboxOption) (*SQLOutboxStore, error) {
+	s := &SQLOutboxStore{
+		db:          db,
+		table:       "signalads_outbox",
+		placeholder: questionPlaceholder,
+		lease:       time.Minute,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if !sqlIdentifier.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	return s, nil
+}
+
+// CreateTable creates the store's table if it does not exist.
+func (s *SQLOutboxStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	id VARCHAR(64) NOT NULL PRIMARY KEY,
+	request TEXT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	attempts INTEGER NOT NULL,
+	last_error TEXT NOT NULL,
+	message_id VARCHAR(128) NOT NULL,
+	next_attempt BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLOutboxStore) query(q string) string {
+	return rebindSQL(q, s.placeholder)
+}
+
+// EnqueueTx validates req and inserts it as a pending message within tx,
+// returning the outbox ID. The message is only sent if tx commits.
+func (s *SQLOutboxStore) EnqueueTx(ctx context.Context, tx *sql.Tx, req *SendMessageRequest) (string, error) {
+	if err := validateSendMessageRequest(req); err != nil {
+		return "", err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	msg := &OutboxMessage{
+		ID:          id,
+		Request:     *req,
+		Status:      OutboxStatusPending,
+		NextAttempt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err = s.insert(ctx, tx, msg); err != nil {
+		return "", fmt.Errorf("failed to enqueue message: %w", err)
+	}
+	return id, nil
+}
+
+func (s *SQLOutboxStore) insert(ctx context.Context, tx *sql.Tx, msg *OutboxMessage) error {
+	data, err := json.Marshal(msg.Request)
+	if err != nil {
+		return err
+	}
+	request, err := encryptString(s.encrypter, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt request: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, s.query(`INSERT INTO `+s.table+` (`+sqlOutboxColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		msg.ID, request, string(msg.Status), msg.Attempts, msg.LastError, msg.MessageID,
+		msg.NextAttempt.UnixMilli(), msg.CreatedAt.UnixMilli(), msg.UpdatedAt.UnixMilli())
+	return err
+}
+
+// Save implements OutboxStore.
+func (s *SQLOutboxStore) Save(ctx context.Context, msg *OutboxMessage) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save outbox message: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, s.query(`SELECT 1 FROM `+s.table+` WHERE id = ?`), msg.ID).Scan(&exists)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = s.insert(ctx, tx, msg)
+	case err == nil:
+		_, err = tx.ExecContext(ctx, s.query(`UPDATE `+s.table+
+			` SET status = ?, attempts = ?, last_error = ?, message_id = ?, next_attempt = ?, updated_at = ? WHERE id = ?`),
+			string(msg.Status), msg.Attempts, msg.LastError, msg.MessageID, msg.NextAttempt.UnixMilli(), msg.UpdatedAt.UnixMilli(), msg.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save outbox message: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save outbox message: %w", err)
+	}
+	return nil
+}
+
+// Due implements OutboxStore. Within one transaction it claims the due
+// messages by moving their next attempt a lease into the future, so that
+// workers sharing the table do not send the same message concurrently.
+func (s *SQLOutboxStore) Due(ctx context.Context, now time.Time, limit int) ([]*OutboxMessage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due messages: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := `SELECT ` + sqlOutboxColumns + ` FROM ` + s.table +
+		` WHERE status = ? AND next_attempt <= ? ORDER BY created_at`
+	args := []interface{}{string(OutboxStatusPending), now.UnixMilli()}
+	if limit > 0 {
+		q += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := tx.QueryContext(ctx, s.query(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due messages: %w", err)
+	}
+	var due []*OutboxMessage
+	for rows.Next() {
+		msg, scanErr := s.scan(rows)
+		if scanErr != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read due message: %w", scanErr)
+		}
+		due = append(due, msg)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read due messages: %w", err)
+	}
+
+	// Another worker may have claimed a message since it was read; only
+	// keep the ones this update wins.
+	leaseEnd := now.Add(s.lease).UnixMilli()
+	claimed := due[:0]
+	for _, msg := range due {
+		res, execErr := tx.ExecContext(ctx, s.query(`UPDATE `+s.table+` SET next_attempt = ? WHERE id = ? AND next_attempt = ?`),
+			leaseEnd, msg.ID, msg.NextAttempt.UnixMilli())
+		if execErr != nil {
+			return nil, fmt.Errorf("failed to claim due message: %w", execErr)
+		}
+		if n, affectedErr := res.RowsAffected(); affectedErr == nil && n == 0 {
+			continue
+		}
+		claimed = append(claimed, msg)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to claim due messages: %w", err)
+	}
+	return claimed, nil
+}
+
+// Get implements OutboxStore.
+func (s *SQLOutboxStore) Get(ctx context.Context, id string) (*OutboxMessage, error) {
+	row := s.db.QueryRowContext(ctx, s.query(`SELECT `+sqlOutboxColumns+` FROM `+s.table+` WHERE id = ?`), id)
+	msg, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOutboxMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox message: %w", err)
+	}
+	return msg, nil
+}
+
+func (s *SQLOutboxStore) scan(row interface{ Scan(...interface{}) error }) (*OutboxMessage, error) {
+	var (
+		msg                               OutboxMessage
+		request, status                   string
+		nextAttempt, createdAt, updatedAt int64
+	)
+	if err := row.Scan(&msg.ID, &request, &status, &msg.Attempts, &msg.LastError, &msg.MessageID, &nextAttempt, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	request, err := decryptString(s.encrypter, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt request of outbox message %s: %w", msg.ID, err)
+	}
+	if err = json.Unmarshal([]byte(request), &msg.Request); err != nil {
+		return nil, fmt.Errorf("failed to decode request of outbox message %s: %w", msg.ID, err)
+	}
+	msg.Status = OutboxStatus(status)
+	msg.NextAttempt = time.UnixMilli(nextAttempt)
+	msg.CreatedAt = time.UnixMilli(createdAt)
+	msg.UpdatedAt = time.UnixMilli(updatedAt)
+	return &msg, nil
+}
@@ -0,0 +1,237 @@
+package signalads
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOutboxDriver understands just the statements SQLOutboxStore issues.
+// Rows hold the values of sqlOutboxColumns.
+type fakeOutboxDriver struct {
+	mu   sync.Mutex
+	rows map[string][]driver.Value
+}
+
+func (d *fakeOutboxDriver) Open(string) (driver.Conn, error) {
+	return &fakeOutboxConn{d: d}, nil
+}
+
+type fakeOutboxConn struct {
+	d *fakeOutboxDriver
+}
+
+func (c *fakeOutboxConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeOutboxStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeOutboxConn) Close() error { return nil }
+
+func (c *fakeOutboxConn) Begin() (driver.Tx, error) { return fakeSQLTx{}, nil }
+
+type fakeOutboxStmt struct {
+	d     *fakeOutboxDriver
+	query string
+}
+
+func (s *fakeOutboxStmt) Close() error  { return nil }
+func (s *fakeOutboxStmt) NumInput() int { return -1 }
+
+func (s *fakeOutboxStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT"):
+		d.rows[args[0].(string)] = args
+	case strings.Contains(s.query, "SET next_attempt = ? WHERE id = ? AND next_attempt = ?"):
+		row, ok := d.rows[args[1].(string)]
+		if !ok || row[6].(int64) != args[2].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+		row[6] = args[0]
+	case strings.HasPrefix(s.query, "UPDATE"):
+		// status, attempts, last_error, message_id, next_attempt, updated_at, id
+		row := d.rows[args[6].(string)]
+		row[2], row[3], row[4], row[5], row[6], row[8] = args[0], args[1], args[2], args[3], args[4], args[5]
+	default:
+		return nil, errors.New("unexpected exec: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeOutboxStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result [][]driver.Value
+	switch {
+	case strings.HasPrefix(s.query, "SELECT 1"):
+		rows := &fakeSQLRows{columns: []string{"1"}}
+		if _, ok := d.rows[args[0].(string)]; ok {
+			rows.rows = append(rows.rows, []driver.Value{int64(1)})
+		}
+		return rows, nil
+	case strings.HasSuffix(s.query, "WHERE id = ?"):
+		if row, ok := d.rows[args[0].(string)]; ok {
+			result = append(result, append([]driver.Value(nil), row...))
+		}
+	case strings.Contains(s.query, "WHERE status = ? AND next_attempt <= ?"):
+		for _, row := range d.rows {
+			if row[2] == args[0] && row[6].(int64) <= args[1].(int64) {
+				result = append(result, append([]driver.Value(nil), row...))
+			}
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i][7].(int64) < result[j][7].(int64) })
+		if len(args) > 2 && int(args[2].(int64)) < len(result) {
+			result = result[:args[2].(int64)]
+		}
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	return &fakeSQLRows{columns: strings.Split(sqlOutboxColumns, ", "), rows: result}, nil
+}
+
+func openFakeOutboxDB(t *testing.T, name string) *sql.DB {
+	t.Helper()
+	sql.Register(name, &fakeOutboxDriver{rows: make(map[string][]driver.Value)})
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLOutboxStore(t *testing.T) {
+	db := openFakeOutboxDB(t, "signalads-fake-outbox")
+	store, err := NewSQLOutboxStore(db, WithSQLOutboxLease(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	if err = store.CreateTable(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	id, err := store.EnqueueTx(ctx, tx, &SendMessageRequest{To: "+989123456789", Message: "Order confirmed", Tag: "checkout"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msg, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != OutboxStatusPending || msg.Request.Message != "Order confirmed" || msg.Request.Tag != "checkout" {
+		t.Errorf("Expected pending message with its request, got %+v", msg)
+	}
+
+	now := time.Now()
+	due, err := store.Due(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != id {
+		t.Fatalf("Expected %s due, got %+v", id, due)
+	}
+
+	// The lease hides the message from other workers.
+	due, err = store.Due(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("Expected claimed message to be hidden, got %+v", due)
+	}
+	due, err = store.Due(ctx, now.Add(2*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(due) != 1 {
+		t.Errorf("Expected message to be due again after the lease, got %+v", due)
+	}
+
+	if _, err = store.Get(ctx, "missing"); !errors.Is(err, ErrOutboxMessageNotFound) {
+		t.Errorf("Expected ErrOutboxMessageNotFound, got %v", err)
+	}
+	if _, err = NewSQLOutboxStore(db, WithSQLOutboxTable("outbox; DROP TABLE x")); err == nil {
+		t.Error("Expected an invalid table name to be rejected")
+	}
+}
+
+func TestSQLOutboxStore_Outbox(t *testing.T) {
+	db := openFakeOutboxDB(t, "signalads-fake-outbox-flush")
+	store, err := NewSQLOutboxStore(db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1", "status": "sent"}`))
+	})
+	outbox := NewOutbox(client, store)
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	id, err := store.EnqueueTx(ctx, tx, &SendMessageRequest{To: "+989123456789", Message: "hi"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	n, err := outbox.Flush(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 message sent, got %d", n)
+	}
+
+	msg, err := outbox.Status(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != OutboxStatusSent || msg.MessageID != "msg-1" || msg.Attempts != 1 {
+		t.Errorf("Expected message sent as msg-1 after 1 attempt, got %+v", msg)
+	}
+}
+
+func TestSQLOutboxStore_EnqueueValidation(t *testing.T) {
+	db := openFakeOutboxDB(t, "signalads-fake-outbox-validation")
+	store, err := NewSQLOutboxStore(db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = store.EnqueueTx(context.Background(), tx, &SendMessageRequest{To: "+989123456789"}); !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestOutbox_FlushSendsPending(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-123", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	outbox := NewOutbox(client, NewMemoryOutboxStore())
+	ctx := context.Background()
+
+	id, err := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	n, err := outbox.Flush(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 message attempted, got %d", n)
+	}
+
+	msg, err := outbox.Status(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != OutboxStatusSent {
+		t.Errorf("Expected status %q, got %q", OutboxStatusSent, msg.Status)
+	}
+	if msg.MessageID != "msg-123" {
+		t.Errorf("Expected message ID 'msg-123', got '%s'", msg.MessageID)
+	}
+}
+
+func TestOutbox_RetriesThenSucceeds(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-123", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	outbox := NewOutbox(client, NewMemoryOutboxStore(), WithOutboxBackoff(noBackoff))
+	ctx := context.Background()
+
+	id, _ := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	for i := 0; i < 3; i++ {
+		if _, err := outbox.Flush(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	msg, _ := outbox.Status(ctx, id)
+	if msg.Status != OutboxStatusSent {
+		t.Errorf("Expected status %q, got %q", OutboxStatusSent, msg.Status)
+	}
+	if msg.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", msg.Attempts)
+	}
+}
+
+func TestOutbox_PermanentFailure(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(APIError{Message: "Invalid phone number"})
+	}
+
+	client := setupTestClient(handler)
+	outbox := NewOutbox(client, NewMemoryOutboxStore(), WithOutboxBackoff(noBackoff))
+	ctx := context.Background()
+
+	id, _ := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	outbox.Flush(ctx)
+
+	msg, _ := outbox.Status(ctx, id)
+	if msg.Status != OutboxStatusFailed {
+		t.Errorf("Expected status %q, got %q", OutboxStatusFailed, msg.Status)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 API call for a rejected message, got %d", got)
+	}
+}
+
+func TestOutbox_IdempotencyKey(t *testing.T) {
+	var keys []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-123", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	outbox := NewOutbox(client, NewMemoryOutboxStore(), WithOutboxBackoff(noBackoff))
+	ctx := context.Background()
+
+	id, _ := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	outbox.Flush(ctx)
+	outbox.Flush(ctx)
+
+	if len(keys) != 2 || keys[0] != id || keys[1] != id {
+		t.Errorf("Expected every attempt keyed by %q, got %q", id, keys)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"transport", fmt.Errorf("failed to send message: %w", &TransportError{Op: "request failed", Err: io.ErrUnexpectedEOF}), true},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"unavailable", fmt.Errorf("failed to send message: %w", &APIError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"rejected", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"decode", &DecodeError{Err: io.ErrUnexpectedEOF}, false},
+		{"too large", ErrResponseTooLarge, false},
+		{"validation", validateSendMessageRequest(&SendMessageRequest{}), false},
+		{"quota", &QuotaError{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOutbox_MaxAttempts(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	client := setupTestClient(handler)
+	outbox := NewOutbox(client, NewMemoryOutboxStore(),
+		WithOutboxBackoff(noBackoff),
+		WithOutboxMaxAttempts(2),
+	)
+	ctx := context.Background()
+
+	id, _ := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	for i := 0; i < 3; i++ {
+		outbox.Flush(ctx)
+	}
+
+	msg, _ := outbox.Status(ctx, id)
+	if msg.Status != OutboxStatusFailed {
+		t.Errorf("Expected status %q, got %q", OutboxStatusFailed, msg.Status)
+	}
+	if msg.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", msg.Attempts)
+	}
+}
+
+func TestOutbox_FlushAttemptsOncePerFlush(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client := setupTestClient(handler)
+	outbox := NewOutbox(client, NewMemoryOutboxStore(),
+		WithOutboxBackoff(noBackoff),
+		WithOutboxBatchSize(1),
+		WithOutboxMaxAttempts(100),
+	)
+	ctx := context.Background()
+
+	outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "One"})
+	outbox.Enqueue(ctx, &SendMessageRequest{To: "+989120000000", Message: "Two"})
+	n, err := outbox.Flush(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 messages attempted, got %d", n)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected each message to be tried once, got %d API calls", got)
+	}
+}
+
+func TestOutbox_EnqueueValidation(t *testing.T) {
+	outbox := NewOutbox(NewClient("test-key", "test-secret"), NewMemoryOutboxStore())
+
+	if _, err := outbox.Enqueue(context.Background(), &SendMessageRequest{Message: "Test"}); err == nil {
+		t.Error("Expected validation error, got nil")
+	}
+}
+
+func TestFileOutboxStore_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	ctx := context.Background()
+
+	store, err := NewFileOutboxStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	outbox := NewOutbox(NewClient("test-key", "test-secret"), store)
+	id, err := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reopened, err := NewFileOutboxStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	due, err := reopened.Due(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != id {
+		t.Fatalf("Expected pending message %s after reopen, got %v", id, due)
+	}
+	if due[0].Request.To != "+989123456789" {
+		t.Errorf("Expected recipient to be persisted, got '%s'", due[0].Request.To)
+	}
+}
+
+func TestFileOutboxStore_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	ctx := context.Background()
+
+	store, err := NewFileOutboxStore(path, WithFileOutboxRetention(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Save(ctx, &OutboxMessage{ID: "sent", Status: OutboxStatusSent, UpdatedAt: now})
+	store.Save(ctx, &OutboxMessage{ID: "pending", Status: OutboxStatusPending, UpdatedAt: now})
+
+	now = now.Add(2 * time.Hour)
+	if err := store.Save(ctx, &OutboxMessage{ID: "new", Status: OutboxStatusPending, UpdatedAt: now}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reopened, err := NewFileOutboxStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := reopened.Get(ctx, "sent"); !errors.Is(err, ErrOutboxMessageNotFound) {
+		t.Errorf("Expected the sent message to be pruned, got %v", err)
+	}
+	if _, err := reopened.Get(ctx, "pending"); err != nil {
+		t.Errorf("Expected pending messages to be kept, got %v", err)
+	}
+}
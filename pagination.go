@@ -0,0 +1,156 @@
+package signalads
+
+import (
+	"encoding/json"
+)
+
+// Pagination describes where a page of a list response sits in the full
+// list. Total and TotalPages are zero when the API does not report them;
+// HasNext is then inferred from whether the page is full.
+type Pagination struct {
+	Page       int
+	PerPage    int
+	Total      int
+	TotalPages int
+	HasNext    bool
+}
+
+// rawPagination accepts the spellings of pagination fields used by
+// different endpoints.
+type rawPagination struct {
+	Page        *int  `json:"page"`
+	CurrentPage *int  `json:"current_page"`
+	PerPage     *int  `json:"per_page"`
+	Limit       *int  `json:"limit"`
+	Total       *int  `json:"total"`
+	TotalCount  *int  `json:"total_count"`
+	TotalPages  *int  `json:"total_pages"`
+	LastPage    *int  `json:"last_page"`
+	HasNext     *bool `json:"has_next"`
+}
+
+// merge copies the fields set in r over p's.
+func (r *rawPagination) merge(p *Pagination, hasNext **bool) {
+	setInt(&p.Page, r.Page, r.CurrentPage)
+	setInt(&p.PerPage, r.PerPage, r.Limit)
+	setInt(&p.Total, r.Total, r.TotalCount)
+	setInt(&p.TotalPages, r.TotalPages, r.LastPage)
+	if r.HasNext != nil {
+		*hasNext = r.HasNext
+	}
+}
+
+func setInt(dst *int, values ...*int) {
+	for _, v := range values {
+		if v != nil {
+			*dst = *v
+			return
+		}
+	}
+}
+
+// decode reads the pagination of a list response from its top-level
+// fields, or from a nested "pagination" or "meta" object, and fills in
+// what the API left out. items is the number of items in the page. The
+// deprecated flat fields page, perPage and total are updated to match.
+func (p *Pagination) decode(data []byte, items int, page, perPage, total *int) error {
+	var raw struct {
+		rawPagination
+		Pagination *rawPagination `json:"pagination"`
+		Meta       *rawPagination `json:"meta"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var hasNext *bool
+	raw.rawPagination.merge(p, &hasNext)
+	for _, nested := range []*rawPagination{raw.Meta, raw.Pagination} {
+		if nested != nil {
+			nested.merge(p, &hasNext)
+		}
+	}
+
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.TotalPages == 0 && p.Total > 0 && p.PerPage > 0 {
+		p.TotalPages = (p.Total + p.PerPage - 1) / p.PerPage
+	}
+	switch {
+	case hasNext != nil:
+		p.HasNext = *hasNext
+	case p.TotalPages > 0:
+		p.HasNext = p.Page < p.TotalPages
+	case p.Total > 0:
+		p.HasNext = (p.Page-1)*p.PerPage+items < p.Total
+	default:
+		p.HasNext = p.PerPage > 0 && items >= p.PerPage
+	}
+
+	*page, *perPage, *total = p.Page, p.PerPage, p.Total
+	return nil
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListFilesResponse) UnmarshalJSON(data []byte) error {
+	type plain ListFilesResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Files), &r.Page, &r.PerPage, &r.Total)
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListMessagesResponse) UnmarshalJSON(data []byte) error {
+	type plain ListMessagesResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Messages), &r.Page, &r.PerPage, &r.Total)
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListCallsResponse) UnmarshalJSON(data []byte) error {
+	type plain ListCallsResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Calls), &r.Page, &r.PerPage, &r.Total)
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListScheduledMessagesResponse) UnmarshalJSON(data []byte) error {
+	type plain ListScheduledMessagesResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Messages), &r.Page, &r.PerPage, &r.Total)
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListTemplatesResponse) UnmarshalJSON(data []byte) error {
+	type plain ListTemplatesResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Templates), &r.Page, &r.PerPage, &r.Total)
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListInboundMessagesResponse) UnmarshalJSON(data []byte) error {
+	type plain ListInboundMessagesResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Messages), &r.Page, &r.PerPage, &r.Total)
+}
+
+// UnmarshalJSON decodes the response and its Pagination.
+func (r *ListLinkClicksResponse) UnmarshalJSON(data []byte) error {
+	type plain ListLinkClicksResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	return r.Pagination.decode(data, len(r.Clicks), &r.Page, &r.PerPage, &r.Total)
+}
@@ -0,0 +1,60 @@
+package signalads
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPagination_Decode(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Pagination
+	}{
+		{
+			name: "flat fields",
+			body: `{"messages": [{}, {}], "page": 2, "per_page": 2, "total": 5}`,
+			want: Pagination{Page: 2, PerPage: 2, Total: 5, TotalPages: 3, HasNext: true},
+		},
+		{
+			name: "last page",
+			body: `{"messages": [{}], "page": 3, "per_page": 2, "total": 5}`,
+			want: Pagination{Page: 3, PerPage: 2, Total: 5, TotalPages: 3},
+		},
+		{
+			name: "nested meta",
+			body: `{"messages": [{}, {}], "meta": {"current_page": 1, "limit": 2, "total_count": 4, "last_page": 2}}`,
+			want: Pagination{Page: 1, PerPage: 2, Total: 4, TotalPages: 2, HasNext: true},
+		},
+		{
+			name: "explicit has_next",
+			body: `{"messages": [], "pagination": {"page": 4, "per_page": 50, "has_next": true}}`,
+			want: Pagination{Page: 4, PerPage: 50, HasNext: true},
+		},
+		{
+			name: "totals omitted, full page",
+			body: `{"messages": [{}, {}], "per_page": 2}`,
+			want: Pagination{Page: 1, PerPage: 2, HasNext: true},
+		},
+		{
+			name: "totals omitted, short page",
+			body: `{"messages": [{}], "per_page": 2}`,
+			want: Pagination{Page: 1, PerPage: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response ListMessagesResponse
+			if err := json.Unmarshal([]byte(tt.body), &response); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if response.Pagination != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, response.Pagination)
+			}
+			if response.Page != tt.want.Page || response.PerPage != tt.want.PerPage || response.Total != tt.want.Total {
+				t.Errorf("Expected flat fields to match Pagination, got page %d, per_page %d, total %d", response.Page, response.PerPage, response.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PartialError is the error returned by operations that work through many
+// items in chunks, pages or steps, such as bulk voice sends, split messages,
+// experiments, status backfills and polls, dead-letter replays, outbox
+// flushes and template syncs, when they stop early or some items fail. It
+// says exactly which items were done, so that callers can resume or
+// compensate for the rest. It wraps the underlying error, so errors.Is(err, context.Canceled)
+// still reports a cancellation.
+type PartialError[T any] struct {
+	// Items completed, such as the recipients reached or the message IDs
+	// recorded
+	Completed []T
+
+	// Items known not to be completed, because they failed or because the
+	// operation stopped before reaching them
+	Failed []T
+
+	Err error
+}
+
+func (e *PartialError[T]) Error() string {
+	return fmt.Sprintf("completed %d of %d items: %v", len(e.Completed), len(e.Completed)+len(e.Failed), e.Err)
+}
+
+func (e *PartialError[T]) Unwrap() error {
+	return e.Err
+}
+
+// Interrupted reports whether the operation stopped because its context
+// was canceled or timed out.
+func (e *PartialError[T]) Interrupted() bool {
+	return errors.Is(e.Err, context.Canceled) || errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// PartialResult is the PartialError returned by the SDK's operations, whose
+// items are identified by strings: recipients, or message, event, outbox or
+// template IDs.
+type PartialResult = PartialError[string]
+
+// sentPartially returns a *PartialResult listing the recipients of sent as
+// completed and those of failed as failed.
+func sentPartially(sent []SendMessageResponse, failed []BulkFailure, err error) *PartialResult {
+	partial := &PartialResult{Err: err}
+	for i := range sent {
+		partial.Completed = append(partial.Completed, sent[i].To)
+	}
+	for i := range failed {
+		partial.Failed = append(partial.Failed, failed[i].Recipient)
+	}
+	return partial
+}
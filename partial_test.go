@@ -0,0 +1,231 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendBulkVoiceMessages_CanceledBetweenChunks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		var req SendBulkVoiceRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		calls.Add(1)
+
+		var resp SendBulkMessageResponse
+		for _, to := range req.Recipients {
+			resp.Results = append(resp.Results, SendMessageResponse{ID: "call-" + to, To: to, Status: "queued"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+	// The caller gives up once the first chunk has been sent.
+	WithUsageRecorder(UsageRecorderFunc(func(context.Context, Usage) { cancel() }))(client)
+
+	response, err := client.Messages.SendBulkVoiceMessages(ctx, &SendBulkVoiceRequest{
+		Recipients: []string{"+989120000001", "+989120000002", "+989120000003", "+989120000004"},
+		AudioURL:   "https://example.com/a.mp3",
+		ChunkSize:  2,
+	})
+
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if !partial.Interrupted() || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the result to report the cancellation, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected no chunk to be sent after cancellation, got %d calls", calls.Load())
+	}
+	if !reflect.DeepEqual(partial.Completed, []string{"+989120000001", "+989120000002"}) {
+		t.Errorf("Expected the first chunk to be completed, got %v", partial.Completed)
+	}
+	if !reflect.DeepEqual(partial.Failed, []string{"+989120000003", "+989120000004"}) {
+		t.Errorf("Expected the second chunk to be failed, got %v", partial.Failed)
+	}
+	if len(response.Messages) != 2 || len(response.FailedItems) != 2 {
+		t.Errorf("Expected 2 sent and 2 failed, got %d and %d", len(response.Messages), len(response.FailedItems))
+	}
+}
+
+func TestRunExperiment_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sends atomic.Int32
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": "tmpl_%d", "status": "sent"}`, sends.Add(1))
+	})
+	WithUsageRecorder(UsageRecorderFunc(func(context.Context, Usage) {
+		if sends.Load() == 2 {
+			cancel()
+		}
+	}))(client)
+
+	exp := &Experiment{ID: "exp_1", Variants: []Variant{{ID: "only", TemplateID: "tpl_sale"}}}
+	recipients := []string{"+989120000001", "+989120000002", "+989120000003", "+989120000004"}
+	run, err := client.Messages.RunExperiment(ctx, exp, recipients)
+
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if !partial.Interrupted() {
+		t.Errorf("Expected an interrupted run, got %v", err)
+	}
+	if len(partial.Completed) != 2 || len(partial.Failed) != 2 {
+		t.Errorf("Expected 2 recipients reached and 2 not, got %v and %v", partial.Completed, partial.Failed)
+	}
+	if sends.Load() != 2 {
+		t.Errorf("Expected 2 sends, got %d", sends.Load())
+	}
+	if len(run.Variants) != 1 || len(run.Variants[0].Messages) != 2 {
+		t.Errorf("Expected the run to cover the 2 messages sent, got %+v", run.Variants)
+	}
+}
+
+func TestStatusTracker_BackfillPartial(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"messages": [{"id": "msg_1", "status": "delivered"}, {"id": "msg_2", "status": "sent"}], "page": 1, "per_page": 2, "total": 3}`))
+	})
+	tracker := NewStatusTracker(NewMemoryTrackerStore())
+
+	n, err := tracker.Backfill(context.Background(), client, time.Now().Add(-time.Hour))
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if n != 2 || !reflect.DeepEqual(partial.Completed, []string{"msg_1", "msg_2"}) {
+		t.Errorf("Expected msg_1 and msg_2 recorded, got %d %v", n, partial.Completed)
+	}
+	if partial.Interrupted() {
+		t.Error("Expected a failed page not to count as an interruption")
+	}
+}
+
+func TestWebhookHandler_ReplayCanceled(t *testing.T) {
+	store := NewMemoryDeadLetterStore()
+	h := newTestWebhookHandler(WithDeadLetterStore(store))
+	ctx := context.Background()
+	for _, id := range []string{"evt-1", "evt-2"} {
+		store.Save(ctx, &DeadLetter{Event: WebhookEvent{ID: id, Type: EventInboundMessage}, Attempts: 1})
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	n, err := h.Replay(canceled)
+
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if n != 0 || len(partial.Completed) != 0 || len(partial.Failed) != 2 {
+		t.Errorf("Expected no letters replayed and 2 left, got %d, %v and %v", n, partial.Completed, partial.Failed)
+	}
+}
+
+func TestStatusTracker_PollPartial(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/messages/msg_2/status" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": "msg_1", "status": "delivered"}`))
+	})
+	store := NewMemoryTrackerStore()
+	tracker := NewStatusTracker(store)
+	ctx := context.Background()
+	for i, id := range []string{"msg_1", "msg_2", "msg_3"} {
+		sent := time.Now().Add(-time.Hour + time.Duration(i)*time.Minute)
+		store.Save(ctx, &TrackedMessage{MessageID: id, Status: "sent", SentAt: sent, UpdatedAt: sent})
+	}
+
+	n, err := tracker.Poll(ctx, client, 10*time.Minute)
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if n != 1 || !reflect.DeepEqual(partial.Completed, []string{"msg_1"}) {
+		t.Errorf("Expected msg_1 refreshed, got %d %v", n, partial.Completed)
+	}
+	if !reflect.DeepEqual(partial.Failed, []string{"msg_2", "msg_3"}) {
+		t.Errorf("Expected msg_2 and msg_3 not refreshed, got %v", partial.Failed)
+	}
+}
+
+type failingSaveTemplateStore struct {
+	*MemoryTemplateStore
+	failID string
+}
+
+func (s failingSaveTemplateStore) Save(ctx context.Context, tpl *StoredTemplate) error {
+	if tpl.Template.ID == s.failID {
+		return errors.New("disk full")
+	}
+	return s.MemoryTemplateStore.Save(ctx, tpl)
+}
+
+func TestTemplatesService_SyncToPartial(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		templates := []Template{{ID: "otp", Body: "Code: {code}"}, {ID: "promo", Body: "Sale"}, {ID: "welcome", Body: "Hi"}}
+		json.NewEncoder(w).Encode(ListTemplatesResponse{Templates: templates, Page: 1, PerPage: 100, Total: len(templates)})
+	})
+	store := failingSaveTemplateStore{MemoryTemplateStore: NewMemoryTemplateStore(), failID: "promo"}
+	ctx := context.Background()
+	store.MemoryTemplateStore.Save(ctx, &StoredTemplate{Template: Template{ID: "retired"}, Hash: "old"})
+
+	result, err := client.Templates.SyncTo(ctx, store)
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if !reflect.DeepEqual(result.Added, []string{"otp"}) || !reflect.DeepEqual(partial.Completed, []string{"otp"}) {
+		t.Errorf("Expected only otp synced, got %v and %v", result.Added, partial.Completed)
+	}
+	if !reflect.DeepEqual(partial.Failed, []string{"promo", "welcome", "retired"}) {
+		t.Errorf("Expected promo, welcome and retired not synced, got %v", partial.Failed)
+	}
+}
+
+func TestOutbox_FlushCanceled(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request after cancellation, got %s", r.URL.Path)
+	})
+	outbox := NewOutbox(client, NewMemoryOutboxStore())
+	ctx := context.Background()
+	id, err := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	n, err := outbox.Flush(canceled)
+
+	var partial *PartialResult
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected *PartialResult, got %v", err)
+	}
+	if n != 0 || !partial.Interrupted() || !reflect.DeepEqual(partial.Failed, []string{id}) {
+		t.Errorf("Expected %s left after cancellation, got %d and %+v", id, n, partial)
+	}
+}
@@ -0,0 +1,74 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPatternEndpoint is the endpoint SendPatternMessage posts to unless
+// WithPatternEndpoint sets another. It has not been verified against the
+// provider's API documentation; check it against your account's docs.
+const DefaultPatternEndpoint = "/send-message/pattern"
+
+// WithPatternEndpoint sets the endpoint SendPatternMessage posts to,
+// relative to the base URL.
+func WithPatternEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.patternEndpoint = endpoint
+	}
+}
+
+// SendPatternMessage sends a message built from a pattern approved in the
+// panel through the pattern (verify lookup) endpoint, DefaultPatternEndpoint
+// unless WithPatternEndpoint sets another. Unless the request sets another
+// category the message is sent as CategoryOTP and, like SendOTPMessage,
+// bypasses blackout days.
+func (s *MessagesService) SendPatternMessage(ctx context.Context, req *SendPatternRequest) (*SendPatternResponse, error) {
+	if err := validateSendPatternRequest(req); err != nil {
+		return nil, err
+	}
+	category := req.Category
+	if category == "" {
+		category = CategoryOTP
+	}
+	ctx, _ = resolveCategory(ctx, category)
+	if category == CategoryOTP {
+		ctx = WithCalendarOverride(ctx, nil)
+	}
+	if from := s.client.fromOrDefault(req.From); category != req.Category || from != req.From {
+		resolved := *req
+		resolved.Category = category
+		resolved.From = from
+		req = &resolved
+	}
+	if err := s.client.checkCalendar(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+	release, err := s.client.checkFrequency(ctx, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.client.reserve(ctx, req.To); err != nil {
+		release()
+		return nil, err
+	}
+
+	var response SendPatternResponse
+	if err := s.client.Post(ctx, s.client.patternEndpoint, req, &response); err != nil {
+		return nil, fmt.Errorf("failed to send pattern message: %w", err)
+	}
+	s.client.trackSent(ctx, response.MessageID, req.To, response.Status)
+	s.client.recordUsage(ctx, Usage{Channel: UsageChannelPattern, Messages: 1, Cost: response.Cost})
+
+	return &response, nil
+}
+
+// SendPattern is a convenience method for sending a pattern message.
+func (s *MessagesService) SendPattern(ctx context.Context, to, patternCode string, values map[string]string) (*SendPatternResponse, error) {
+	return s.SendPatternMessage(ctx, &SendPatternRequest{
+		To:          to,
+		PatternCode: patternCode,
+		Values:      values,
+	})
+}
@@ -0,0 +1,86 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSendPattern(t *testing.T) {
+	var got map[string]interface{}
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/pattern" {
+			t.Errorf("Expected path /send-message/pattern, got %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message_id": "msg-1", "status": "sent", "recipient": "+989123456789", "cost": 0.5}`))
+	})
+
+	// Blackout days do not apply to OTP pattern messages.
+	cal := NewCalendar(nil)
+	cal.Add(time.Now(), "holiday")
+	WithCalendar(cal)(client)
+
+	var usage Usage
+	WithUsageRecorder(UsageRecorderFunc(func(_ context.Context, u Usage) { usage = u }))(client)
+
+	response, err := client.Messages.SendPattern(context.Background(), "+989123456789", "otp-login", map[string]string{"code": "123456"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.MessageID != "msg-1" || response.Cost != 0.5 {
+		t.Errorf("Expected message msg-1 costing 0.5, got %s costing %v", response.MessageID, response.Cost)
+	}
+	if got["recipient"] != "+989123456789" || got["pattern_code"] != "otp-login" {
+		t.Errorf("Expected recipient and pattern_code in request, got %v", got)
+	}
+	if values, _ := got["values"].(map[string]interface{}); values["code"] != "123456" {
+		t.Errorf("Expected values with code 123456, got %v", got["values"])
+	}
+	if got["category"] != string(CategoryOTP) {
+		t.Errorf("Expected category %s, got %v", CategoryOTP, got["category"])
+	}
+	if usage.Channel != UsageChannelPattern || usage.Messages != 1 {
+		t.Errorf("Expected 1 message of usage channel %s, got %+v", UsageChannelPattern, usage)
+	}
+}
+
+func TestWithPatternEndpoint(t *testing.T) {
+	var path string
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message_id": "msg-1", "status": "sent"}`))
+	})
+	WithPatternEndpoint("/verify/lookup")(client)
+
+	if _, err := client.Messages.SendPattern(context.Background(), "+989123456789", "otp-login", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if path != "/verify/lookup" {
+		t.Errorf("Expected path /verify/lookup, got %s", path)
+	}
+}
+
+func TestSendPatternMessage_Validation(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no API call for invalid request")
+	})
+
+	_, err := client.Messages.SendPatternMessage(context.Background(), &SendPatternRequest{To: "+989123456789"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if fields := verr.Fields(); len(fields) != 1 || fields[0] != "pattern_code" {
+		t.Errorf("Expected error on pattern_code, got %v", fields)
+	}
+}
@@ -0,0 +1,86 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingPermission is wrapped by the *PermissionError returned when the
+// API key lacks a permission.
+var ErrMissingPermission = errors.New("missing permission")
+
+// Permission is a scope granted to an API key, as listed in
+// UserInfo.Permissions.
+type Permission string
+
+// Permissions known to the SDK. Accounts may list others.
+const (
+	PermissionSMS        Permission = "sms"
+	PermissionBulk       Permission = "bulk"
+	PermissionVoice      Permission = "voice"
+	PermissionTemplates  Permission = "templates"
+	PermissionInbox      Permission = "inbox"
+	PermissionShortlinks Permission = "shortlinks"
+	PermissionCalls      Permission = "calls"
+	PermissionFiles      Permission = "files"
+
+	// PermissionAll grants every permission.
+	PermissionAll Permission = "*"
+)
+
+// PermissionError is returned by Client.RequirePermissions when the API key
+// lacks one or more permissions.
+type PermissionError struct {
+	Missing []Permission
+}
+
+func (e *PermissionError) Error() string {
+	names := make([]string, 0, len(e.Missing))
+	for _, p := range e.Missing {
+		names = append(names, string(p))
+	}
+	return fmt.Sprintf("%v: API key lacks %s", ErrMissingPermission, strings.Join(names, ", "))
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrMissingPermission
+}
+
+// HasPermission reports whether the account's API key has permission p.
+// Permissions are compared case-insensitively.
+func (u *UserInfo) HasPermission(p Permission) bool {
+	for _, granted := range u.Permissions {
+		if granted == string(PermissionAll) || strings.EqualFold(granted, string(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingPermissions returns those of perms that the API key lacks.
+func (u *UserInfo) MissingPermissions(perms ...Permission) []Permission {
+	var missing []Permission
+	for _, p := range perms {
+		if !u.HasPermission(p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// RequirePermissions fetches the account information and returns a
+// *PermissionError listing every permission in perms that the API key
+// lacks. Call it before a campaign to fail fast rather than on a 403
+// halfway through.
+func (c *Client) RequirePermissions(ctx context.Context, perms ...Permission) error {
+	info, err := c.Messages.GetUserInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get account permissions: %w", err)
+	}
+	if missing := info.MissingPermissions(perms...); len(missing) > 0 {
+		return &PermissionError{Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestUserInfo_HasPermission(t *testing.T) {
+	info := &UserInfo{Permissions: []string{"sms", "Bulk"}}
+
+	if !info.HasPermission(PermissionSMS) {
+		t.Error("Expected sms permission")
+	}
+	if !info.HasPermission(PermissionBulk) {
+		t.Error("Expected bulk permission to match case-insensitively")
+	}
+	if info.HasPermission(PermissionVoice) {
+		t.Error("Expected no voice permission")
+	}
+
+	all := &UserInfo{Permissions: []string{"*"}}
+	if !all.HasPermission(PermissionVoice) {
+		t.Error("Expected wildcard to grant voice permission")
+	}
+}
+
+func TestRequirePermissions(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/info" {
+			t.Errorf("Expected path /user/info, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "u1", "permissions": ["sms", "bulk"]}`))
+	})
+
+	if err := client.RequirePermissions(context.Background(), PermissionSMS, PermissionBulk); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := client.RequirePermissions(context.Background(), PermissionSMS, PermissionVoice, PermissionFiles)
+	if !errors.Is(err, ErrMissingPermission) {
+		t.Fatalf("Expected ErrMissingPermission, got %v", err)
+	}
+	var permErr *PermissionError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("Expected *PermissionError, got %T", err)
+	}
+	want := []Permission{PermissionVoice, PermissionFiles}
+	if !reflect.DeepEqual(permErr.Missing, want) {
+		t.Errorf("Expected missing %v, got %v", want, permErr.Missing)
+	}
+	if permErr.Error() != "missing permission: API key lacks voice, files" {
+		t.Errorf("Unexpected error message: %s", permErr.Error())
+	}
+}
+
+func TestRequirePermissions_APIError(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	err := client.RequirePermissions(context.Background(), PermissionSMS)
+	if err == nil || errors.Is(err, ErrMissingPermission) {
+		t.Fatalf("Expected API error, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 API error, got %v", err)
+	}
+}
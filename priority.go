@@ -0,0 +1,228 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Priority orders sends waiting in a PriorityQueue. Higher priorities are
+// dispatched first.
+type Priority int
+
+// Send priorities.
+const (
+	// PriorityLow is the default for promotional messages and bulk sends.
+	PriorityLow Priority = iota
+
+	// PriorityNormal is the default for transactional and uncategorized
+	// single sends.
+	PriorityNormal
+
+	// PriorityHigh is the default for one-time passwords.
+	PriorityHigh
+)
+
+type priorityContextKey struct{}
+
+// WithPriority returns a context whose sends are dispatched with priority p,
+// overriding the priority derived from their category.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// PriorityFromContext returns the priority set with WithPriority.
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityContextKey{}).(Priority)
+	return p, ok
+}
+
+// sendPriority returns the priority of a send carrying ctx.
+func sendPriority(ctx context.Context, bulk bool) Priority {
+	if p, ok := PriorityFromContext(ctx); ok {
+		return p
+	}
+	category, _ := CategoryFromContext(ctx)
+	switch category {
+	case CategoryOTP:
+		return PriorityHigh
+	case CategoryTransactional:
+		return PriorityNormal
+	case CategoryPromotional:
+		return PriorityLow
+	}
+	if bulk {
+		return PriorityLow
+	}
+	return PriorityNormal
+}
+
+// PriorityQueue limits the number of sends in flight and, when the limit is
+// reached, dispatches waiting sends by priority, so that one-time passwords
+// are not stuck behind a campaign. It can also hold back low-priority sends
+// while the API's rate limit is nearly exhausted. A PriorityQueue is safe for
+// concurrent use but must not be shared between clients.
+type PriorityQueue struct {
+	maxInFlight int
+	reserve     int
+	rateLimit   func() (RateLimit, bool)
+	now         func() time.Time
+
+	mu       sync.Mutex
+	inFlight int
+	waiting  [PriorityHigh + 1][]chan struct{}
+	timer    *time.Timer
+}
+
+// NewPriorityQueue returns a queue allowing maxInFlight sends at a time.
+// While the API reports rateLimitReserve or fewer requests remaining in its
+// current rate-limit window, PriorityLow sends wait for the window to reset;
+// a negative reserve disables this.
+func NewPriorityQueue(maxInFlight, rateLimitReserve int) *PriorityQueue {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	return &PriorityQueue{
+		maxInFlight: maxInFlight,
+		reserve:     rateLimitReserve,
+		now:         time.Now,
+	}
+}
+
+// WithPriorityQueue makes every send wait for a slot in q.
+func WithPriorityQueue(q *PriorityQueue) ClientOption {
+	return func(c *Client) {
+		if q != nil {
+			q.rateLimit = c.RateLimit
+			c.priorityQueue = q
+		}
+	}
+}
+
+// Acquire waits for a slot for a send with priority p. Every successful
+// Acquire must be followed by a call to Release.
+func (q *PriorityQueue) Acquire(ctx context.Context, p Priority) error {
+	p = min(max(p, PriorityLow), PriorityHigh)
+
+	q.mu.Lock()
+	if q.inFlight < q.maxInFlight && !q.waitingAtLeast(p) && q.allowed(p) {
+		q.inFlight++
+		q.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	q.waiting[p] = append(q.waiting[p], ready)
+	q.dispatchLocked()
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, ch := range q.waiting[p] {
+		if ch == ready {
+			q.waiting[p] = append(q.waiting[p][:i], q.waiting[p][i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// The slot was granted while ctx was being canceled; hand it on.
+	q.inFlight--
+	q.dispatchLocked()
+	return ctx.Err()
+}
+
+// Release frees the slot of a finished send.
+func (q *PriorityQueue) Release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inFlight--
+	q.dispatchLocked()
+}
+
+// Waiting returns the number of sends with priority p waiting for a slot.
+func (q *PriorityQueue) Waiting(p Priority) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if p < PriorityLow || p > PriorityHigh {
+		return 0
+	}
+	return len(q.waiting[p])
+}
+
+// waitingAtLeast reports whether a send with priority p or higher is
+// waiting.
+func (q *PriorityQueue) waitingAtLeast(p Priority) bool {
+	for ; p <= PriorityHigh; p++ {
+		if len(q.waiting[p]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed reports whether sends with priority p may start now.
+func (q *PriorityQueue) allowed(p Priority) bool {
+	return p > PriorityLow || q.rateLimitResetIn() <= 0
+}
+
+// rateLimitResetIn returns how long until the rate-limit window resets if
+// the remaining requests are within the reserve, and zero otherwise.
+func (q *PriorityQueue) rateLimitResetIn() time.Duration {
+	if q.reserve < 0 || q.rateLimit == nil {
+		return 0
+	}
+	rl, ok := q.rateLimit()
+	if !ok || rl.Remaining > q.reserve || rl.Reset.IsZero() {
+		return 0
+	}
+	return max(rl.Reset.Sub(q.now()), 0)
+}
+
+// dispatchLocked hands free slots to waiting sends, highest priority first.
+func (q *PriorityQueue) dispatchLocked() {
+	for p := PriorityHigh; p >= PriorityLow && q.inFlight < q.maxInFlight; {
+		if len(q.waiting[p]) == 0 {
+			p--
+			continue
+		}
+		if !q.allowed(p) {
+			q.wakeAfter(q.rateLimitResetIn())
+			return
+		}
+		close(q.waiting[p][0])
+		q.waiting[p] = q.waiting[p][1:]
+		q.inFlight++
+	}
+}
+
+// wakeAfter schedules a dispatch after d, for sends held back by the rate
+// limit.
+func (q *PriorityQueue) wakeAfter(d time.Duration) {
+	if q.timer != nil {
+		return
+	}
+	q.timer = time.AfterFunc(d, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.timer = nil
+		q.dispatchLocked()
+	})
+}
+
+// acquireSend waits for a priority queue slot if the request is a send.
+// The returned function releases it.
+func (c *Client) acquireSend(ctx context.Context, method, endpoint string) (func(), error) {
+	if c.priorityQueue == nil || method != http.MethodPost || !strings.HasPrefix(endpoint, "/send-message/") {
+		return func() {}, nil
+	}
+	if err := c.priorityQueue.Acquire(ctx, sendPriority(ctx, strings.HasSuffix(endpoint, "/bulk"))); err != nil {
+		return nil, err
+	}
+	return c.priorityQueue.Release, nil
+}
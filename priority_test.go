@@ -0,0 +1,166 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendPriority(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		bulk bool
+		want Priority
+	}{
+		{"otp", WithCategory(ctx, CategoryOTP), false, PriorityHigh},
+		{"otp bulk", WithCategory(ctx, CategoryOTP), true, PriorityHigh},
+		{"transactional", WithCategory(ctx, CategoryTransactional), false, PriorityNormal},
+		{"promotional", WithCategory(ctx, CategoryPromotional), false, PriorityLow},
+		{"uncategorized", ctx, false, PriorityNormal},
+		{"uncategorized bulk", ctx, true, PriorityLow},
+		{"explicit", WithPriority(WithCategory(ctx, CategoryPromotional), PriorityHigh), true, PriorityHigh},
+	}
+
+	for _, tt := range tests {
+		if got := sendPriority(tt.ctx, tt.bulk); got != tt.want {
+			t.Errorf("%s: expected priority %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+// waitForWaiting blocks until q has n sends of priority p waiting.
+func waitForWaiting(t *testing.T, q *PriorityQueue, p Priority, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for q.Waiting(p) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d sends of priority %d waiting, got %d", n, p, q.Waiting(p))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPriorityQueue_Order(t *testing.T) {
+	q := NewPriorityQueue(1, -1)
+	ctx := context.Background()
+	if err := q.Acquire(ctx, PriorityLow); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []Priority
+		wg    sync.WaitGroup
+	)
+	acquire := func(p Priority) {
+		defer wg.Done()
+		if err := q.Acquire(ctx, p); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		mu.Lock()
+		order = append(order, p)
+		mu.Unlock()
+		q.Release()
+	}
+
+	wg.Add(3)
+	go acquire(PriorityLow)
+	waitForWaiting(t, q, PriorityLow, 1)
+	go acquire(PriorityNormal)
+	waitForWaiting(t, q, PriorityNormal, 1)
+	go acquire(PriorityHigh)
+	waitForWaiting(t, q, PriorityHigh, 1)
+
+	q.Release()
+	wg.Wait()
+
+	want := []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected dispatch order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestPriorityQueue_Cancel(t *testing.T) {
+	q := NewPriorityQueue(1, -1)
+	if err := q.Acquire(context.Background(), PriorityHigh); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Acquire(ctx, PriorityLow); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if n := q.Waiting(PriorityLow); n != 0 {
+		t.Errorf("Expected no waiting sends, got %d", n)
+	}
+
+	q.Release()
+	if err := q.Acquire(context.Background(), PriorityLow); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPriorityQueue_RateLimitReserve(t *testing.T) {
+	q := NewPriorityQueue(10, 5)
+	reset := time.Now().Add(50 * time.Millisecond)
+	q.rateLimit = func() (RateLimit, bool) {
+		return RateLimit{Limit: 100, Remaining: 3, Reset: reset, ObservedAt: time.Now()}, true
+	}
+	ctx := context.Background()
+
+	if err := q.Acquire(ctx, PriorityHigh); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.Release()
+	if err := q.Acquire(ctx, PriorityNormal); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.Release()
+
+	start := time.Now()
+	if err := q.Acquire(ctx, PriorityLow); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.Release()
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Expected low priority send to wait for the rate limit reset, waited %s", elapsed)
+	}
+}
+
+func TestWithPriorityQueue(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "m1", "status": "sent"}`))
+	})
+	q := NewPriorityQueue(1, -1)
+	WithPriorityQueue(q)(client)
+
+	if _, err := client.Messages.SendMessage(context.Background(), "+989123456789", "hi"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := q.Acquire(context.Background(), PriorityHigh); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer q.Release()
+
+	if _, err := client.Messages.GetUserInfo(context.Background()); err != nil {
+		t.Errorf("Expected non-send requests to bypass the queue, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.Messages.SendMessage(ctx, "+989123456789", "hi")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected send to wait for a slot, got %v", err)
+	}
+}
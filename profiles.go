@@ -0,0 +1,108 @@
+package signalads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvProfile is the environment variable NewClientForProfile reads the
+// profile name from when it is given none.
+const EnvProfile = "SIGNALADS_PROFILE"
+
+// ErrUnknownProfile is returned by NewClientForProfile for a profile the
+// config does not define.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// Profile holds the settings of one environment, such as staging or
+// production.
+type Profile struct {
+	// API credentials. Leave them empty and set KeyVar and SecretVar to
+	// read them from environment variables instead of the config file.
+	APIKey    string `json:"api_key,omitempty"`
+	APISecret string `json:"api_secret,omitempty"`
+	KeyVar    string `json:"key_var,omitempty"`
+	SecretVar string `json:"secret_var,omitempty"`
+
+	// API base URL; defaults to DefaultBaseURL
+	BaseURL string `json:"base_url,omitempty"`
+
+	// Sender line used by sends whose request does not set From
+	From string `json:"from,omitempty"`
+}
+
+// Config holds named profiles, so that one application config can describe
+// all the accounts an application is deployed against.
+type Config struct {
+	Profiles map[string]Profile `json:"profiles"`
+
+	// Profile used when neither NewClientForProfile nor EnvProfile names
+	// one (optional)
+	DefaultProfile string `json:"default_profile,omitempty"`
+}
+
+// LoadConfig reads a Config from a JSON file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var cfg Config
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// NewClientForProfile creates a client with the credentials, base URL and
+// default sender line of the named profile. An empty name selects the
+// profile named by the EnvProfile environment variable, or else
+// cfg.DefaultProfile, so that the environment can be switched without
+// changing code. opts are applied after the profile's settings and may
+// override them.
+func NewClientForProfile(cfg *Config, name string, opts ...ClientOption) (*Client, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, have %s", ErrUnknownProfile, name, strings.Join(cfg.profileNames(), ", "))
+	}
+
+	var profileOpts []ClientOption
+	if profile.APIKey == "" && (profile.KeyVar != "" || profile.SecretVar != "") {
+		profileOpts = append(profileOpts, WithCredentialsProvider(EnvCredentials{KeyVar: profile.KeyVar, SecretVar: profile.SecretVar}))
+	} else if profile.APIKey == "" || profile.APISecret == "" {
+		return nil, fmt.Errorf("%w in profile %q", ErrMissingCredentials, name)
+	}
+	if profile.BaseURL != "" {
+		profileOpts = append(profileOpts, WithBaseURL(profile.BaseURL))
+	}
+	if profile.From != "" {
+		profileOpts = append(profileOpts, WithDefaultFrom(profile.From))
+	}
+	profileOpts = append(profileOpts, func(c *Client) { c.profile = name })
+
+	return NewClient(profile.APIKey, profile.APISecret, append(profileOpts, opts...)...), nil
+}
+
+// Profile returns the name of the profile the client was created for with
+// NewClientForProfile, or "".
+func (c *Client) Profile() string {
+	return c.profile
+}
+
+func (cfg *Config) profileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
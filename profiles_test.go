@@ -0,0 +1,97 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testProfilesConfig = `{
+	"default_profile": "staging",
+	"profiles": {
+		"staging": {"api_key": "stage-key", "api_secret": "stage-secret", "base_url": "https://staging.example.com/api/v1", "from": "3000111"},
+		"production": {"key_var": "SIGNALADS_TEST_PROD_KEY", "secret_var": "SIGNALADS_TEST_PROD_SECRET", "from": "3000222"}
+	}
+}`
+
+func loadTestProfiles(t *testing.T) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "signalads.json")
+	if err := os.WriteFile(path, []byte(testProfilesConfig), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return cfg
+}
+
+func TestNewClientForProfile(t *testing.T) {
+	cfg := loadTestProfiles(t)
+	t.Setenv(EnvProfile, "")
+
+	client, err := NewClientForProfile(cfg, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.Profile() != "staging" {
+		t.Errorf("Expected default profile staging, got %s", client.Profile())
+	}
+	if client.BaseURL() != "https://staging.example.com/api/v1" {
+		t.Errorf("Expected staging base URL, got %s", client.BaseURL())
+	}
+	if from := client.fromOrDefault(""); from != "3000111" {
+		t.Errorf("Expected staging line 3000111, got %s", from)
+	}
+	creds, err := client.currentCredentials().Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "stage-key" {
+		t.Errorf("Expected staging API key, got %s", creds.APIKey)
+	}
+}
+
+func TestNewClientForProfile_EnvSelectsProfile(t *testing.T) {
+	cfg := loadTestProfiles(t)
+	t.Setenv(EnvProfile, "production")
+	t.Setenv("SIGNALADS_TEST_PROD_KEY", "prod-key")
+	t.Setenv("SIGNALADS_TEST_PROD_SECRET", "prod-secret")
+
+	client, err := NewClientForProfile(cfg, "", WithDefaultFrom("3000999"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.Profile() != "production" {
+		t.Errorf("Expected profile production, got %s", client.Profile())
+	}
+	if client.BaseURL() != DefaultBaseURL {
+		t.Errorf("Expected default base URL, got %s", client.BaseURL())
+	}
+	if from := client.fromOrDefault(""); from != "3000999" {
+		t.Errorf("Expected option to override the profile's line, got %s", from)
+	}
+	creds, err := client.currentCredentials().Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "prod-key" {
+		t.Errorf("Expected API key from the environment, got %s", creds.APIKey)
+	}
+}
+
+func TestNewClientForProfile_Errors(t *testing.T) {
+	cfg := loadTestProfiles(t)
+
+	if _, err := NewClientForProfile(cfg, "prod"); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("Expected ErrUnknownProfile, got %v", err)
+	}
+
+	cfg.Profiles["broken"] = Profile{APIKey: "key-only"}
+	if _, err := NewClientForProfile(cfg, "broken"); !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("Expected ErrMissingCredentials, got %v", err)
+	}
+}
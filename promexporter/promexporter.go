@@ -0,0 +1,204 @@
+// Package promexporter periodically polls a SignalAds account and exposes
+// its balance, credit, suspension state, rate-limit headroom, and retry
+// budget as Prometheus gauges, along with the client's request latencies.
+//
+// The Exporter serves the Prometheus text exposition format directly, so it
+// can be mounted on any mux and scraped without adding the Prometheus client
+// library to the dependency graph.
+package promexporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+const (
+	DefaultInterval  = time.Minute
+	DefaultNamespace = "signalads"
+)
+
+// Option configures an Exporter.
+type Option func(*Exporter)
+
+// WithInterval sets how often the account is polled.
+func WithInterval(interval time.Duration) Option {
+	return func(e *Exporter) {
+		if interval > 0 {
+			e.interval = interval
+		}
+	}
+}
+
+// WithNamespace sets the prefix of every metric name.
+func WithNamespace(namespace string) Option {
+	return func(e *Exporter) {
+		if namespace != "" {
+			e.namespace = namespace
+		}
+	}
+}
+
+// Exporter polls account information and serves it as Prometheus metrics.
+type Exporter struct {
+	client    *signalads.Client
+	interval  time.Duration
+	namespace string
+
+	mu         sync.RWMutex
+	up         bool
+	balance    float64
+	credit     float64
+	lastPoll   time.Time
+	pollErrors uint64
+	suspended  bool
+}
+
+// New creates an Exporter for client.
+func New(client *signalads.Client, opts ...Option) *Exporter {
+	e := &Exporter{
+		client:    client,
+		interval:  DefaultInterval,
+		namespace: DefaultNamespace,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Poll fetches the current account state once.
+func (e *Exporter) Poll(ctx context.Context) error {
+	info, err := e.client.Messages.GetUserInfo(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastPoll = time.Now()
+	if err != nil {
+		e.up = false
+		e.pollErrors++
+		if signalads.IsAccountSuspended(err) {
+			e.suspended = true
+		}
+		return err
+	}
+
+	e.up = true
+	e.suspended = info.Suspended()
+	e.balance = info.Balance
+	e.credit = info.Credit
+	return nil
+}
+
+// Run polls the account every interval until ctx is done. Poll errors are
+// reflected in the up gauge and the error counter rather than returned.
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = e.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP writes the current metrics in the Prometheus text format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = e.WriteMetrics(w)
+}
+
+// WriteMetrics writes the current metrics in the Prometheus text format to w.
+func (e *Exporter) WriteMetrics(w io.Writer) error {
+	e.mu.RLock()
+	up := 0.0
+	if e.up {
+		up = 1
+	}
+	balance, credit := e.balance, e.credit
+	lastPoll, pollErrors := e.lastPoll, e.pollErrors
+	suspended := 0.0
+	if e.suspended {
+		suspended = 1
+	}
+	e.mu.RUnlock()
+
+	metrics := make([]metric, 0, 8)
+	metrics = append(metrics,
+		metric{"up", "gauge", "Whether the last account poll succeeded.", up},
+		metric{"poll_errors_total", "counter", "Total number of failed account polls.", float64(pollErrors)},
+	)
+	if !lastPoll.IsZero() {
+		metrics = append(metrics,
+			metric{"last_poll_timestamp_seconds", "gauge", "Unix time of the last account poll.", float64(lastPoll.UnixNano()) / 1e9},
+			metric{"account_suspended", "gauge", "Whether the account was found suspended or blocked.", suspended},
+		)
+	}
+	if up == 1 {
+		metrics = append(metrics,
+			metric{"balance", "gauge", "Current account balance.", balance},
+			metric{"credit", "gauge", "Current account credit.", credit},
+		)
+	}
+	if rl, ok := e.client.RateLimit(); ok {
+		metrics = append(metrics,
+			metric{"rate_limit_limit", "gauge", "Requests allowed in the current rate-limit window.", float64(rl.Limit)},
+			metric{"rate_limit_remaining", "gauge", "Requests remaining in the current rate-limit window.", float64(rl.Remaining)},
+		)
+	}
+	if budget := e.client.RetryBudget(); budget != nil {
+		stats := budget.Stats()
+		metrics = append(metrics,
+			metric{"window_requests", "gauge", "Requests made in the retry budget window.", float64(stats.Requests)},
+			metric{"window_error_rate", "gauge", "Fraction of requests in the retry budget window that failed.", stats.ErrorRate()},
+			metric{"window_retries", "gauge", "Retries allowed in the retry budget window.", float64(stats.Retries)},
+			metric{"window_retries_denied", "gauge", "Retries denied in the retry budget window.", float64(stats.Denied)},
+		)
+	}
+
+	for _, m := range metrics {
+		name := e.namespace + "_" + m.name
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", name, m.help, name, m.kind, name, m.value); err != nil {
+			return err
+		}
+	}
+	if h := e.client.LatencyHistogram(); h != nil {
+		return e.writeLatency(w, h.Snapshot())
+	}
+	return nil
+}
+
+// writeLatency writes the client's request latencies as a histogram.
+func (e *Exporter) writeLatency(w io.Writer, s signalads.LatencySnapshot) error {
+	name := e.namespace + "_request_duration_seconds"
+	if _, err := fmt.Fprintf(w, "# HELP %s Latency of API requests.\n# TYPE %s histogram\n", name, name); err != nil {
+		return err
+	}
+	for i, bound := range s.Bounds {
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, bound.Seconds(), s.Cumulative[i]); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %g\n%s_count %d\n", name, s.Count, name, s.Sum.Seconds(), name, s.Count)
+	return err
+}
+
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value float64
+}
@@ -0,0 +1,123 @@
+package promexporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+func TestExporter_Metrics(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(signalads.UserInfo{
+			ID:      "user-123",
+			Balance: 1000.5,
+			Credit:  250,
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := signalads.NewClient("test-key", "test-secret",
+		signalads.WithBaseURL(server.URL),
+		signalads.WithRetryBudget(signalads.NewRetryBudget(0.2, 0, time.Minute)),
+		signalads.WithLatencyHistogram(signalads.NewLatencyHistogram(time.Minute)),
+	)
+	exporter := New(client)
+
+	if err := exporter.Poll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"signalads_up 1\n",
+		"signalads_balance 1000.5\n",
+		"signalads_credit 250\n",
+		"signalads_rate_limit_limit 100\n",
+		"signalads_rate_limit_remaining 42\n",
+		"# TYPE signalads_poll_errors_total counter\n",
+		"signalads_window_requests 1\n",
+		"signalads_window_error_rate 0\n",
+		"# TYPE signalads_request_duration_seconds histogram\n",
+		"signalads_request_duration_seconds_bucket{le=\"60\"} 1\n",
+		"signalads_request_duration_seconds_bucket{le=\"+Inf\"} 1\n",
+		"signalads_request_duration_seconds_count 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestExporter_PollError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := signalads.NewClient("test-key", "test-secret", signalads.WithBaseURL(server.URL))
+	exporter := New(client, WithNamespace("sms"))
+
+	if err := exporter.Poll(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	var b strings.Builder
+	exporter.WriteMetrics(&b)
+	body := b.String()
+
+	if !strings.Contains(body, "sms_up 0\n") {
+		t.Errorf("Expected up gauge to be 0, got:\n%s", body)
+	}
+	if !strings.Contains(body, "sms_poll_errors_total 1\n") {
+		t.Errorf("Expected error counter to be 1, got:\n%s", body)
+	}
+	if strings.Contains(body, "sms_balance") {
+		t.Errorf("Expected no balance gauge after a failed poll, got:\n%s", body)
+	}
+}
+
+func TestExporter_AccountSuspended(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"code": "ACCOUNT_SUSPENDED", "message": "Account suspended"}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := signalads.NewClient("test-key", "test-secret", signalads.WithBaseURL(server.URL))
+	exporter := New(client, WithNamespace("sms"))
+
+	if err := exporter.Poll(context.Background()); !signalads.IsAccountSuspended(err) {
+		t.Fatalf("Expected suspension error, got %v", err)
+	}
+
+	var b strings.Builder
+	exporter.WriteMetrics(&b)
+	body := b.String()
+
+	if !strings.Contains(body, "sms_account_suspended 1\n") {
+		t.Errorf("Expected suspended gauge to be 1, got:\n%s", body)
+	}
+	if !strings.Contains(body, "sms_up 0\n") {
+		t.Errorf("Expected up gauge to be 0, got:\n%s", body)
+	}
+}
@@ -0,0 +1,65 @@
+package signalads
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate-limit state reported by the API in the headers of
+// its most recent response.
+type RateLimit struct {
+	// Maximum number of requests allowed in the current window
+	Limit int
+
+	// Requests remaining in the current window
+	Remaining int
+
+	// When the current window resets (zero if not reported)
+	Reset time.Time
+
+	// When these values were observed
+	ObservedAt time.Time
+}
+
+// RateLimit returns the most recently observed rate-limit state and whether
+// the API has reported one yet.
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c.rateMu.RLock()
+	defer c.rateMu.RUnlock()
+
+	return c.rateLimit, !c.rateLimit.ObservedAt.IsZero()
+}
+
+func (c *Client) recordRateLimit(header http.Header) {
+	rl, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	c.rateMu.Lock()
+	c.rateLimit = rl
+	c.rateMu.Unlock()
+}
+
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	rl := RateLimit{
+		Limit:      limit,
+		Remaining:  remaining,
+		ObservedAt: time.Now(),
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+
+	return rl, true
+}
@@ -0,0 +1,47 @@
+package signalads
+
+import "bytes"
+
+// RawResponse is embedded in response types to carry the body they were
+// decoded from, when the client is created with WithRawResponses.
+type RawResponse struct {
+	raw []byte
+}
+
+// Raw returns the exact response body, including any envelope, or nil if
+// raw responses are not enabled. The slice must not be modified.
+func (r *RawResponse) Raw() []byte {
+	return r.raw
+}
+
+func (r *RawResponse) setRaw(body []byte) {
+	r.raw = body
+}
+
+// rawSetter is implemented by types embedding RawResponse.
+type rawSetter interface {
+	setRaw(body []byte)
+}
+
+// WithRawResponses makes decoded responses keep their raw body, available
+// from their Raw method, so callers can persist exact payloads for audit
+// or read fields the SDK does not model yet.
+func WithRawResponses() ClientOption {
+	return func(c *Client) {
+		c.rawResponses = true
+	}
+}
+
+// attachRaw stores body in v if raw responses are enabled and v embeds
+// RawResponse. Cached bodies are copied, as the cache keeps its own.
+func (c *Client) attachRaw(v interface{}, body []byte, cached bool) {
+	if !c.rawResponses {
+		return
+	}
+	if setter, ok := v.(rawSetter); ok {
+		if cached {
+			body = bytes.Clone(body)
+		}
+		setter.setRaw(body)
+	}
+}
@@ -0,0 +1,48 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const rawStatusBody = `{"status": "success", "data": {"id": "m1", "status": "delivered", "operator": "mci"}}`
+
+func TestWithRawResponses(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(rawStatusBody))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	client := NewClient("key", "secret", WithBaseURL(server.URL), WithRawResponses())
+
+	status, err := client.Messages.GetMessageStatus(context.Background(), "m1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Status != "delivered" {
+		t.Errorf("Expected status 'delivered', got '%s'", status.Status)
+	}
+	if string(status.Raw()) != rawStatusBody {
+		t.Errorf("Expected the exact body, got %s", status.Raw())
+	}
+}
+
+func TestRawResponses_Disabled(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(rawStatusBody))
+	}
+
+	client := setupTestClient(handler)
+	status, err := client.Messages.GetMessageStatus(context.Background(), "m1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Raw() != nil {
+		t.Errorf("Expected no raw body by default, got %s", status.Raw())
+	}
+}
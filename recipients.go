@@ -0,0 +1,164 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Codes reported in BulkFailure.Code for recipients rejected before sending.
+const (
+	RejectInvalidNumber = "invalid_number"
+	RejectLookup        = "lookup_rejected"
+)
+
+// RecipientCheck decides whether a phone number can receive messages, for
+// example by querying an HLR lookup service. A non-empty reason rejects the
+// number. An error aborts the send.
+type RecipientCheck func(ctx context.Context, phone string) (reason string, err error)
+
+// WithRecipientPreFilter makes SendBulkMessages drop recipients whose number
+// is malformed, and those rejected by any of checks, before sending. The
+// dropped recipients are reported in SendBulkMessageResponse.Rejected.
+// Checks run once per distinct number, in order, only for numbers that pass
+// local validation.
+func WithRecipientPreFilter(checks ...RecipientCheck) ClientOption {
+	return func(c *Client) {
+		c.preFilter = true
+		c.recipientChecks = checks
+	}
+}
+
+// ValidatePhoneNumber reports why phone is not a valid mobile number, or
+// returns nil. It accepts international numbers with a "+" or "00" prefix
+// and Iranian mobile numbers in the 09xxxxxxxxx and 989xxxxxxxxx forms.
+// Spaces, dashes and parentheses are ignored.
+func ValidatePhoneNumber(phone string) error {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')':
+			return -1
+		}
+		return r
+	}, phone)
+
+	international := false
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits, international = digits[1:], true
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case strings.HasPrefix(digits, "09"):
+		digits = "98" + digits[1:]
+	}
+
+	if digits == "" {
+		return fmt.Errorf("phone number %q is empty", RedactPhone(phone))
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("phone number %q contains %q", RedactPhone(phone), r)
+		}
+	}
+
+	if strings.HasPrefix(digits, "98") {
+		if len(digits) != 12 || digits[2] != '9' {
+			return fmt.Errorf("phone number %q is not an Iranian mobile number", RedactPhone(phone))
+		}
+		return nil
+	}
+	if !international {
+		return fmt.Errorf("phone number %q has no country code", RedactPhone(phone))
+	}
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return fmt.Errorf("phone number %q is not a valid international number", RedactPhone(phone))
+	}
+	return nil
+}
+
+// FilterRecipients splits items into those that can be sent and those
+// rejected by local validation or by checks. Rejected items carry their
+// index in items.
+func (s *MessagesService) FilterRecipients(ctx context.Context, items []BulkMessageItem, checks ...RecipientCheck) ([]BulkMessageItem, []BulkFailure, error) {
+	sendable := make([]BulkMessageItem, 0, len(items))
+	var rejected []BulkFailure
+
+	verdicts := make(map[string]BulkFailure)
+	for i, item := range items {
+		verdict, seen := verdicts[item.To]
+		if !seen {
+			var err error
+			verdict, err = checkRecipient(ctx, item.To, checks)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to check recipient %s: %w", RedactPhone(item.To), err)
+			}
+			verdicts[item.To] = verdict
+		}
+
+		if verdict.Code == "" {
+			sendable = append(sendable, item)
+			continue
+		}
+		verdict.Index = i
+		verdict.Recipient = item.To
+		rejected = append(rejected, verdict)
+	}
+
+	return sendable, rejected, nil
+}
+
+// filterBulkRecipients drops the messages of req rejected by the pre-filter
+// or the frequency cap. Rejections carry their index in req. The returned
+// function gives back the frequency slots of recipients that are not sent
+// to after all.
+func (s *MessagesService) filterBulkRecipients(ctx context.Context, req *SendBulkMessageRequest) (*SendBulkMessageRequest, []BulkFailure, func(phones ...string), error) {
+	items := req.Messages
+	var rejected []BulkFailure
+	if s.client.preFilter {
+		sendable, r, err := s.FilterRecipients(ctx, items, s.client.recipientChecks...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		items, rejected = sendable, r
+	}
+
+	// Map positions in items back to positions in req.
+	index := keptIndexes(len(req.Messages), rejected)
+
+	items, capped, release, err := s.client.filterFrequency(ctx, items)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, r := range capped {
+		r.Index = index[r.Index]
+		rejected = append(rejected, r)
+	}
+
+	if len(rejected) == 0 {
+		return req, nil, release, nil
+	}
+	sort.SliceStable(rejected, func(i, j int) bool {
+		return rejected[i].Index < rejected[j].Index
+	})
+	filtered := *req
+	filtered.Messages = items
+	return &filtered, rejected, release, nil
+}
+
+// checkRecipient returns a BulkFailure with an empty Code if phone passes.
+func checkRecipient(ctx context.Context, phone string, checks []RecipientCheck) (BulkFailure, error) {
+	if err := ValidatePhoneNumber(phone); err != nil {
+		return BulkFailure{Code: RejectInvalidNumber, Message: err.Error()}, nil
+	}
+	for _, check := range checks {
+		reason, err := check(ctx, phone)
+		if err != nil {
+			return BulkFailure{}, err
+		}
+		if reason != "" {
+			return BulkFailure{Code: RejectLookup, Message: reason}, nil
+		}
+	}
+	return BulkFailure{}, nil
+}
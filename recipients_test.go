@@ -0,0 +1,107 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValidatePhoneNumber(t *testing.T) {
+	valid := []string{"+989123456789", "09123456789", "989123456789", "00989123456789", "0912 345 6789", "+44 7911 123456"}
+	for _, phone := range valid {
+		if err := ValidatePhoneNumber(phone); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", phone, err)
+		}
+	}
+
+	invalid := []string{"", "+", "12345", "0912345678", "+982112345678", "+98912345678a", "+0123456789", "+1234567"}
+	for _, phone := range invalid {
+		if err := ValidatePhoneNumber(phone); err == nil {
+			t.Errorf("Expected %q to be invalid", phone)
+		}
+	}
+}
+
+func TestMessagesService_FilterRecipients(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {})
+
+	lookups := 0
+	check := func(ctx context.Context, phone string) (string, error) {
+		lookups++
+		if phone == "+989120000000" {
+			return "number not in service", nil
+		}
+		return "", nil
+	}
+
+	items := []BulkMessageItem{
+		{To: "+989123456789", Message: "Hi"},
+		{To: "bad", Message: "Hi"},
+		{To: "+989120000000", Message: "Hi"},
+		{To: "+989120000000", Message: "Hi again"},
+	}
+	sendable, rejected, err := client.Messages.FilterRecipients(context.Background(), items, check)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(sendable) != 1 || sendable[0].To != "+989123456789" {
+		t.Errorf("Expected one sendable recipient, got %+v", sendable)
+	}
+	if len(rejected) != 3 {
+		t.Fatalf("Expected 3 rejected, got %+v", rejected)
+	}
+	if rejected[0].Index != 1 || rejected[0].Code != RejectInvalidNumber {
+		t.Errorf("Expected index 1 rejected as invalid, got %+v", rejected[0])
+	}
+	if rejected[2].Index != 3 || rejected[2].Code != RejectLookup || rejected[2].Message != "number not in service" {
+		t.Errorf("Expected index 3 rejected by lookup, got %+v", rejected[2])
+	}
+	if lookups != 2 {
+		t.Errorf("Expected 2 lookups, got %d", lookups)
+	}
+
+	lookupErr := errors.New("lookup unavailable")
+	_, _, err = client.Messages.FilterRecipients(context.Background(), items, func(ctx context.Context, phone string) (string, error) {
+		return "", lookupErr
+	})
+	if !errors.Is(err, lookupErr) {
+		t.Errorf("Expected lookup error, got %v", err)
+	}
+}
+
+func TestMessagesService_SendBulkMessages_PreFilter(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req SendBulkMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Messages) != 1 || req.Messages[0].To != "+989123456789" {
+			t.Errorf("Expected only the valid recipient to be sent, got %+v", req.Messages)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"total": 1, "success": 1, "status": "sent"}`))
+	}
+
+	client := setupTestClient(handler)
+	WithRecipientPreFilter()(client)
+
+	response, err := client.Messages.SendBulkMessage(context.Background(), []BulkMessageItem{
+		{To: "0912", Message: "Hi"},
+		{To: "+989123456789", Message: "Hi"},
+	}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Rejected) != 1 || response.Rejected[0].Recipient != "0912" || response.Rejected[0].Index != 0 {
+		t.Errorf("Expected 0912 rejected at index 0, got %+v", response.Rejected)
+	}
+
+	response, err = client.Messages.SendBulkMessage(context.Background(), []BulkMessageItem{{To: "0912", Message: "Hi"}}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Total != 0 || len(response.Rejected) != 1 {
+		t.Errorf("Expected nothing sent and one rejection, got %+v", response)
+	}
+}
@@ -0,0 +1,128 @@
+package signalads
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Redactor hides phone numbers in what the SDK logs or reports: error
+// messages, slow request endpoints and alerts. Implementations must be
+// safe for concurrent use.
+type Redactor interface {
+	RedactPhone(phone string) string
+}
+
+// RedactorFunc adapts a function to Redactor.
+type RedactorFunc func(phone string) string
+
+// RedactPhone calls f(phone).
+func (f RedactorFunc) RedactPhone(phone string) string {
+	return f(phone)
+}
+
+// MaskRedactor masks the middle digits of numbers, as in +98912***6789. It
+// keeps enough of a number to recognize its operator, but different numbers
+// may look the same.
+var MaskRedactor Redactor = RedactorFunc(MaskPhone)
+
+// MaskPhone returns phone with all but its first few and last four
+// characters replaced by "***".
+func MaskPhone(phone string) string {
+	if len(phone) < 8 {
+		return "***"
+	}
+	return phone[:min(6, len(phone)-7)] + "***" + phone[len(phone)-4:]
+}
+
+// NewHashRedactor returns a Redactor replacing numbers with a keyed hash,
+// as in phone#3f2a9c1b7d4e. The same number gives the same hash in every
+// format it is written in, so that logs and metrics can still be joined
+// on it; keep key secret so that hashes cannot be reversed by hashing
+// every possible number.
+func NewHashRedactor(key []byte) Redactor {
+	return RedactorFunc(func(phone string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(canonicalPhone(phone)))
+		return "phone#" + hex.EncodeToString(mac.Sum(nil))[:12]
+	})
+}
+
+// canonicalPhone reduces the ways of writing a number that
+// ValidatePhoneNumber accepts to its digits with the country code.
+func canonicalPhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, phone)
+	switch {
+	case strings.HasPrefix(digits, "00"):
+		return digits[2:]
+	case strings.HasPrefix(digits, "09"):
+		return "98" + digits[1:]
+	}
+	return digits
+}
+
+type redactorHolder struct {
+	r Redactor
+}
+
+var redactor atomic.Pointer[redactorHolder]
+
+// SetRedactor sets the Redactor the SDK applies to phone numbers in error
+// messages, slow request reports and log alerts. By default numbers are
+// left as is; nil restores that.
+func SetRedactor(r Redactor) {
+	redactor.Store(&redactorHolder{r: r})
+}
+
+// RedactPhone returns phone as the Redactor set with SetRedactor shows
+// it.
+func RedactPhone(phone string) string {
+	if h := redactor.Load(); h != nil && h.r != nil {
+		return h.r.RedactPhone(phone)
+	}
+	return phone
+}
+
+// phoneCandidate matches digit runs that may be phone numbers; only those
+// ValidatePhoneNumber accepts are redacted, which leaves IDs and Unix
+// timestamps alone.
+var phoneCandidate = regexp.MustCompile(`\+?\b\d{10,15}\b`)
+
+// RedactText returns s with every phone number in it redacted by the
+// Redactor set with SetRedactor.
+func RedactText(s string) string {
+	if h := redactor.Load(); h == nil || h.r == nil {
+		return s
+	}
+	return phoneCandidate.ReplaceAllStringFunc(s, func(candidate string) string {
+		if ValidatePhoneNumber(candidate) != nil {
+			return candidate
+		}
+		return RedactPhone(candidate)
+	})
+}
+
+// RedactAttr redacts phone numbers in string attributes. Use it as the
+// ReplaceAttr function of a slog handler to keep numbers out of logs:
+//
+//	slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{ReplaceAttr: signalads.RedactAttr})
+func RedactAttr(_ []string, a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(RedactText(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			a.Value = slog.StringValue(RedactText(err.Error()))
+		}
+	}
+	return a
+}
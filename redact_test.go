@@ -0,0 +1,75 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMaskPhone(t *testing.T) {
+	tests := map[string]string{
+		"+989123456789": "+98912***6789",
+		"09123456789":   "0912***6789",
+		"12345":         "***",
+	}
+	for phone, want := range tests {
+		if got := MaskPhone(phone); got != want {
+			t.Errorf("Expected %s for %s, got %s", want, phone, got)
+		}
+	}
+}
+
+func TestHashRedactor(t *testing.T) {
+	r := NewHashRedactor([]byte("secret"))
+
+	hashed := r.RedactPhone("+989123456789")
+	if !strings.HasPrefix(hashed, "phone#") || strings.Contains(hashed, "9123456789") {
+		t.Errorf("Expected a hash, got %s", hashed)
+	}
+	for _, other := range []string{"09123456789", "00989123456789", "+98 912 345 6789"} {
+		if got := r.RedactPhone(other); got != hashed {
+			t.Errorf("Expected %s to hash like +989123456789, got %s and %s", other, got, hashed)
+		}
+	}
+	if NewHashRedactor([]byte("other")).RedactPhone("+989123456789") == hashed {
+		t.Error("Expected different keys to give different hashes")
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	SetRedactor(MaskRedactor)
+	defer SetRedactor(nil)
+
+	got := RedactText("sent msg 1700000000000 to +989123456789 and 09121111111")
+	if want := "sent msg 1700000000000 to +98912***6789 and 0912***1111"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	err := ValidatePhoneNumber("+9891234")
+	if err == nil || strings.Contains(err.Error(), "+9891234") {
+		t.Errorf("Expected the number to be redacted in the error, got %v", err)
+	}
+}
+
+func TestRedactText_Disabled(t *testing.T) {
+	if got := RedactText("to +989123456789"); got != "to +989123456789" {
+		t.Errorf("Expected text unchanged without a redactor, got %q", got)
+	}
+}
+
+func TestRedactAttr(t *testing.T) {
+	SetRedactor(MaskRedactor)
+	defer SetRedactor(nil)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: RedactAttr}))
+	logger.InfoContext(context.Background(), "sending to +989123456789",
+		"to", "+989123456789", "err", errors.New("rejected 09123456789"))
+
+	if out := buf.String(); strings.Contains(out, "3456789") {
+		t.Errorf("Expected numbers to be redacted, got %s", out)
+	}
+}
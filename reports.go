@@ -0,0 +1,56 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+)
+
+// Groupings of a cost report.
+const (
+	CostGroupByTag       = "tag"
+	CostGroupByClientRef = "client_ref"
+)
+
+// ReportsService provides account-wide reports.
+type ReportsService struct {
+	client *Client
+}
+
+// CostByTag reports the messages sent and their cost per Tag set on the
+// send requests. A zero dateRange bound leaves that side of the range open.
+func (s *ReportsService) CostByTag(ctx context.Context, dateRange DateRange) (*CostReport, error) {
+	return s.costReport(ctx, CostGroupByTag, dateRange)
+}
+
+// CostByClientRef reports the messages sent and their cost per ClientRef
+// set on the send requests. A zero dateRange bound leaves that side of the
+// range open.
+func (s *ReportsService) CostByClientRef(ctx context.Context, dateRange DateRange) (*CostReport, error) {
+	return s.costReport(ctx, CostGroupByClientRef, dateRange)
+}
+
+func (s *ReportsService) costReport(ctx context.Context, groupBy string, dateRange DateRange) (*CostReport, error) {
+	queryParams := dateRange.query()
+	queryParams["group_by"] = groupBy
+
+	var report CostReport
+	if err := s.client.Get(ctx, "/reports/cost", &report, queryParams); err != nil {
+		return nil, fmt.Errorf("failed to get cost report: %w", err)
+	}
+	if report.GroupBy == "" {
+		report.GroupBy = groupBy
+	}
+
+	return &report, nil
+}
+
+// Group returns the group of the report with the given key. Messages sent
+// without a tag or client reference are in the group with an empty key.
+func (r *CostReport) Group(key string) (CostGroup, bool) {
+	for _, g := range r.Groups {
+		if g.Key == key {
+			return g, true
+		}
+	}
+	return CostGroup{}, false
+}
@@ -0,0 +1,95 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCostByTag(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/reports/cost" {
+			t.Errorf("Expected path /reports/cost, got %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("group_by") != "tag" {
+			t.Errorf("Expected group_by tag, got %s", q.Get("group_by"))
+		}
+		if q.Get("from") != "2024-03-01" || q.Get("to") != "2024-03-31" {
+			t.Errorf("Expected March 2024, got %s to %s", q.Get("from"), q.Get("to"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"groups": [{"key": "checkout", "messages": 120, "segments": 130, "cost": 65}, {"key": "", "messages": 10, "cost": 5}], "total_cost": 70, "currency": "IRR"}`))
+	})
+
+	report, err := client.Reports.CostByTag(context.Background(), DateRange{
+		From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.GroupBy != CostGroupByTag {
+		t.Errorf("Expected group by %s, got %s", CostGroupByTag, report.GroupBy)
+	}
+	if report.TotalCost != 70 {
+		t.Errorf("Expected total cost 70, got %v", report.TotalCost)
+	}
+	group, ok := report.Group("checkout")
+	if !ok || group.Messages != 120 || group.Cost != 65 {
+		t.Errorf("Expected checkout group with 120 messages costing 65, got %+v", group)
+	}
+	if _, ok := report.Group("missing"); ok {
+		t.Error("Expected no group for missing key")
+	}
+}
+
+func TestCostByClientRef(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("group_by") != "client_ref" {
+			t.Errorf("Expected group_by client_ref, got %s", q.Get("group_by"))
+		}
+		if q.Has("from") || q.Has("to") {
+			t.Errorf("Expected open date range, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"group_by": "client_ref", "groups": [{"key": "acme", "messages": 3, "cost": 1.5}], "total_cost": 1.5}`))
+	})
+
+	report, err := client.Reports.CostByClientRef(context.Background(), DateRange{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Groups) != 1 || report.Groups[0].Key != "acme" {
+		t.Errorf("Expected one group for acme, got %+v", report.Groups)
+	}
+}
+
+func TestSendMessage_AttributionLabels(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		if body["tag"] != "checkout" || body["client_ref"] != "acme" {
+			t.Errorf("Expected tag checkout and client_ref acme, got %v and %v", body["tag"], body["client_ref"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "m1", "status": "sent"}`))
+	})
+
+	_, err := client.Messages.SendSingleMessage(context.Background(), &SendMessageRequest{
+		To:        "+989123456789",
+		Message:   "Your order has shipped",
+		Tag:       "checkout",
+		ClientRef: "acme",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
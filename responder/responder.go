@@ -0,0 +1,246 @@
+// Package responder replies automatically to inbound SMS that match keyword
+// rules, for contests, surveys and "send CODE to 1000xx" style campaigns.
+//
+// A Responder consumes inbound message events from a
+// signalads.WebhookHandler, matches the message text against its rules in
+// the order they were added and sends the reply of the first matching rule
+// back to the sender through the client. Text is normalized before
+// matching, so Arabic and Persian letter variants, Persian and Arabic-Indic
+// digits, diacritics and letter case do not affect the result.
+package responder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+// Match is the data a reply template or Handler receives.
+type Match struct {
+	// Event that triggered the rule
+	Event *signalads.InboundMessageEvent
+
+	// Sender's phone number
+	From string
+
+	// Normalized message text
+	Text string
+
+	// Keyword of the matching rule; empty for regular expression rules
+	Keyword string
+
+	// Submatches of a regular expression rule, starting with the whole match
+	Groups []string
+}
+
+// Handler computes a reply for a matched message. An empty reply sends
+// nothing.
+type Handler func(ctx context.Context, m *Match) (string, error)
+
+// Rule is a keyword rule. Exactly one of Keyword and Pattern must be set,
+// and exactly one of Reply and Handler.
+type Rule struct {
+	// Keyword that the whole normalized message must equal
+	Keyword string
+
+	// Regular expression matched against the normalized message, in which
+	// Latin letters are upper case
+	Pattern *regexp.Regexp
+
+	// Reply text, executed as a text/template with a *Match
+	Reply string
+
+	// Handler for custom logic, used instead of Reply
+	Handler Handler
+}
+
+type compiledRule struct {
+	keyword string
+	pattern *regexp.Regexp
+	handler Handler
+}
+
+// Option configures a Responder.
+type Option func(*Responder)
+
+// WithFallback sets the reply sent when no rule matches. By default
+// unmatched messages get no reply.
+func WithFallback(reply string) Option {
+	return func(r *Responder) {
+		r.fallback = reply
+	}
+}
+
+// WithSender sets the sender line of replies. By default replies are sent
+// from the line the inbound message arrived on.
+func WithSender(from string) Option {
+	return func(r *Responder) {
+		r.from = from
+	}
+}
+
+// WithBeforeReply registers a hook that runs before a reply is sent and may
+// veto it by returning false, for example to enforce one entry per contest
+// participant.
+func WithBeforeReply(hook func(ctx context.Context, m *Match, reply string) bool) Option {
+	return func(r *Responder) {
+		r.beforeReply = hook
+	}
+}
+
+// Responder matches inbound messages against keyword rules and replies.
+type Responder struct {
+	client      *signalads.Client
+	rules       []compiledRule
+	fallback    string
+	from        string
+	beforeReply func(ctx context.Context, m *Match, reply string) bool
+}
+
+// New creates a Responder that replies through client.
+func New(client *signalads.Client, opts ...Option) *Responder {
+	r := &Responder{client: client}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// AddRule appends rule to the rule list. Rules are not safe to add while
+// messages are being handled.
+func (r *Responder) AddRule(rule Rule) error {
+	if (rule.Keyword == "") == (rule.Pattern == nil) {
+		return errors.New("rule needs exactly one of keyword and pattern")
+	}
+	if (rule.Reply == "") == (rule.Handler == nil) {
+		return errors.New("rule needs exactly one of reply and handler")
+	}
+
+	handler := rule.Handler
+	if handler == nil {
+		tmpl, err := template.New("reply").Parse(rule.Reply)
+		if err != nil {
+			return fmt.Errorf("failed to parse reply template: %w", err)
+		}
+		handler = templateHandler(tmpl)
+	}
+
+	r.rules = append(r.rules, compiledRule{
+		keyword: Normalize(rule.Keyword),
+		pattern: rule.Pattern,
+		handler: handler,
+	})
+	return nil
+}
+
+// Exact adds a rule replying with reply to messages equal to keyword.
+func (r *Responder) Exact(keyword, reply string) error {
+	return r.AddRule(Rule{Keyword: keyword, Reply: reply})
+}
+
+// Regexp adds a rule replying with reply to messages matching pattern.
+func (r *Responder) Regexp(pattern, reply string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to compile pattern: %w", err)
+	}
+	return r.AddRule(Rule{Pattern: re, Reply: reply})
+}
+
+// Register subscribes the responder to inbound messages on h.
+func (r *Responder) Register(h *signalads.WebhookHandler) {
+	h.OnInboundMessage(r.HandleInbound)
+}
+
+// HandleInbound replies to msg according to the first matching rule. It
+// returns an error if the reply could not be computed or sent.
+func (r *Responder) HandleInbound(ctx context.Context, msg *signalads.InboundMessageEvent) error {
+	m := &Match{Event: msg, From: msg.From, Text: Normalize(msg.Message)}
+
+	reply, matched, err := r.reply(ctx, m)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		reply = r.fallback
+	}
+	if reply == "" {
+		return nil
+	}
+	if r.beforeReply != nil && !r.beforeReply(ctx, m, reply) {
+		return nil
+	}
+
+	from := r.from
+	if from == "" {
+		from = msg.To
+	}
+	_, err = r.client.Messages.SendSingleMessage(ctx, &signalads.SendMessageRequest{
+		To:      msg.From,
+		Message: reply,
+		From:    from,
+	})
+	return err
+}
+
+func (r *Responder) reply(ctx context.Context, m *Match) (string, bool, error) {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.pattern != nil {
+			groups := rule.pattern.FindStringSubmatch(m.Text)
+			if groups == nil {
+				continue
+			}
+			m.Groups = groups
+		} else if m.Text != rule.keyword {
+			continue
+		}
+
+		m.Keyword = rule.keyword
+		reply, err := rule.handler(ctx, m)
+		return reply, true, err
+	}
+	return "", false, nil
+}
+
+func templateHandler(tmpl *template.Template) Handler {
+	return func(_ context.Context, m *Match) (string, error) {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, m); err != nil {
+			return "", fmt.Errorf("failed to render reply: %w", err)
+		}
+		return buf.String(), nil
+	}
+}
+
+// persianReplacer maps Arabic letter variants to their Persian forms and
+// Persian and Arabic-Indic digits to ASCII digits.
+var persianReplacer = strings.NewReplacer(
+	"ي", "ی", "ى", "ی", "ك", "ک", "ة", "ه", "أ", "ا", "إ", "ا", "ٱ", "ا",
+	"۰", "0", "۱", "1", "۲", "2", "۳", "3", "۴", "4",
+	"۵", "5", "۶", "6", "۷", "7", "۸", "8", "۹", "9",
+	"٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4",
+	"٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9",
+	"\u200c", " ",
+)
+
+// Normalize prepares text for keyword matching: it unifies Arabic and
+// Persian letter variants, converts digits to ASCII, drops diacritics and
+// tatweel, upper-cases Latin letters and collapses whitespace.
+func Normalize(text string) string {
+	text = persianReplacer.Replace(text)
+	text = strings.Map(func(r rune) rune {
+		if r == '\u0640' || unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
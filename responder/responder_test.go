@@ -0,0 +1,137 @@
+package responder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/erfandiakoo/go-signalads"
+)
+
+func setupResponder(t *testing.T, opts ...Option) (*Responder, *[]signalads.SendMessageRequest) {
+	t.Helper()
+
+	var sent []signalads.SendMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req signalads.SendMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		sent = append(sent, req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(signalads.SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}))
+	t.Cleanup(server.Close)
+
+	client := signalads.NewClient("test-key", "test-secret", signalads.WithBaseURL(server.URL))
+	return New(client, opts...), &sent
+}
+
+func inbound(text string) *signalads.InboundMessageEvent {
+	return &signalads.InboundMessageEvent{From: "+989123456789", To: "100020", Message: text}
+}
+
+func TestResponder_Exact(t *testing.T) {
+	r, sent := setupResponder(t)
+	if err := r.Exact("عضویت", "Welcome {{.From}}"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Arabic yeh and surrounding spaces normalize to the keyword.
+	if err := r.HandleInbound(context.Background(), inbound(" عضويت ")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(*sent) != 1 {
+		t.Fatalf("Expected 1 reply, got %d", len(*sent))
+	}
+	reply := (*sent)[0]
+	if reply.To != "+989123456789" || reply.From != "100020" {
+		t.Errorf("Expected reply to sender from the inbound line, got to=%s from=%s", reply.To, reply.From)
+	}
+	if reply.Message != "Welcome +989123456789" {
+		t.Errorf("Expected rendered reply, got '%s'", reply.Message)
+	}
+}
+
+func TestResponder_Regexp(t *testing.T) {
+	r, sent := setupResponder(t)
+	if err := r.Regexp(`^CODE (\d+)$`, "Code {{index .Groups 1}} registered"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.HandleInbound(context.Background(), inbound("code ۱۲۳۴")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(*sent) != 1 || (*sent)[0].Message != "Code 1234 registered" {
+		t.Errorf("Expected 'Code 1234 registered', got %+v", *sent)
+	}
+}
+
+func TestResponder_HandlerAndHooks(t *testing.T) {
+	seen := map[string]bool{}
+	r, sent := setupResponder(t,
+		WithFallback("Unknown command"),
+		WithBeforeReply(func(ctx context.Context, m *Match, reply string) bool {
+			if seen[m.From] {
+				return false
+			}
+			seen[m.From] = true
+			return true
+		}),
+	)
+	err := r.AddRule(Rule{
+		Pattern: regexp.MustCompile(`^VOTE ([A-C])$`),
+		Handler: func(ctx context.Context, m *Match) (string, error) {
+			return "Vote for " + m.Groups[1] + " counted", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r.HandleInbound(context.Background(), inbound("vote b"))
+	r.HandleInbound(context.Background(), inbound("vote c"))
+
+	if len(*sent) != 1 || (*sent)[0].Message != "Vote for B counted" {
+		t.Errorf("Expected a single counted vote, got %+v", *sent)
+	}
+
+	delete(seen, "+989123456789")
+	r.HandleInbound(context.Background(), inbound("hello"))
+	if len(*sent) != 2 || (*sent)[1].Message != "Unknown command" {
+		t.Errorf("Expected fallback reply, got %+v", *sent)
+	}
+}
+
+func TestResponder_InvalidRules(t *testing.T) {
+	r := New(nil)
+	if err := r.AddRule(Rule{Reply: "x"}); err == nil {
+		t.Error("Expected error for a rule without keyword or pattern")
+	}
+	if err := r.AddRule(Rule{Keyword: "X"}); err == nil {
+		t.Error("Expected error for a rule without reply or handler")
+	}
+	if err := r.Exact("X", "{{.Missing"); err == nil {
+		t.Error("Expected error for an invalid template")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"  Join\tNow ":  "JOIN NOW",
+		"كد ۴۲":         "کد 42",
+		"مـــــرسی":     "مرسی",
+		"می\u200cخواهم": "می خواهم",
+		"سَلام":         "سلام",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
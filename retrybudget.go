@@ -0,0 +1,218 @@
+package signalads
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is returned by components that give up retrying
+// because the client's retry budget denied the retry.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// retryBudgetBuckets is the number of buckets the budget window is split
+// into; counts expire one bucket at a time.
+const retryBudgetBuckets = 10
+
+// RetryBudgetOption configures a RetryBudget.
+type RetryBudgetOption func(*RetryBudget)
+
+// WithErrorRateGuard denies every retry while more than maxErrorRate of the
+// requests in the window failed, once the window holds at least
+// minRequests requests. During a provider incident retries then stop
+// entirely instead of adding load.
+func WithErrorRateGuard(maxErrorRate float64, minRequests int) RetryBudgetOption {
+	return func(b *RetryBudget) {
+		b.maxErrorRate = maxErrorRate
+		b.guardMinRequests = minRequests
+		b.guard = true
+	}
+}
+
+// WithRetryDecisionHook calls fn after every retry the budget allows or
+// denies, with the decision and the counts over the window it was made in,
+// so that budget metrics can be pushed as they change rather than polled
+// with Stats. fn is called without the budget's lock held and must be safe
+// for concurrent use.
+func WithRetryDecisionHook(fn func(allowed bool, stats RetryBudgetStats)) RetryBudgetOption {
+	return func(b *RetryBudget) {
+		b.onDecision = fn
+	}
+}
+
+// RetryBudgetStats are the counts of a RetryBudget over its current window.
+type RetryBudgetStats struct {
+	// Requests made, retries included
+	Requests int
+
+	// Requests that failed with a transport error, 429 or 5xx status
+	Errors int
+
+	// Retries allowed and denied by the budget
+	Retries int
+	Denied  int
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (s RetryBudgetStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+type retryBudgetBucket struct {
+	start                             time.Time
+	requests, errors, retries, denied int
+}
+
+// RetryBudget caps retries to a fraction of the requests made over a
+// sliding window, so that retrying during a provider incident does not
+// multiply the load on the provider. It limits the client's hedged
+// requests and the retries of the Outbox and of other components that
+// consult it. A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	ratio      float64
+	minRetries int
+	window     time.Duration
+	now        func() time.Time
+
+	guard            bool
+	maxErrorRate     float64
+	guardMinRequests int
+
+	onDecision func(allowed bool, stats RetryBudgetStats)
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]retryBudgetBucket
+}
+
+// NewRetryBudget creates a budget allowing retries up to ratio of the
+// requests in the last window, plus minRetries so that clients with little
+// traffic can still retry. For example, NewRetryBudget(0.2, 10, time.Minute)
+// allows 10 retries a minute plus one for every five requests.
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration, opts ...RetryBudgetOption) *RetryBudget {
+	if window <= 0 {
+		window = time.Minute
+	}
+	// Buckets split the window evenly, so round it up to a whole number of
+	// nanoseconds per bucket.
+	if rem := window % retryBudgetBuckets; rem != 0 {
+		window += retryBudgetBuckets - rem
+	}
+	b := &RetryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		window:     window,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithRetryBudget limits the client's retries with b. Every request the
+// client makes is counted toward the budget. Share one budget between
+// clients that talk to the same provider.
+func WithRetryBudget(b *RetryBudget) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = b
+	}
+}
+
+// RetryBudget returns the budget set with WithRetryBudget, or nil.
+func (c *Client) RetryBudget() *RetryBudget {
+	return c.retryBudget
+}
+
+// allowRetry reports whether the client's retry budget, if any, allows
+// another retry, and counts it if so.
+func (c *Client) allowRetry() bool {
+	return c.retryBudget == nil || c.retryBudget.Retry()
+}
+
+// Retry reports whether a retry is allowed now. An allowed retry is counted
+// against the budget, so call it only right before retrying.
+func (b *RetryBudget) Retry() bool {
+	b.mu.Lock()
+	current := b.rotateLocked()
+	stats := b.statsLocked()
+	allowed := float64(stats.Retries) < float64(b.minRetries)+b.ratio*float64(stats.Requests)
+	if allowed && b.guard && stats.Requests >= b.guardMinRequests && stats.ErrorRate() > b.maxErrorRate {
+		allowed = false
+	}
+	if allowed {
+		current.retries++
+		stats.Retries++
+	} else {
+		current.denied++
+		stats.Denied++
+	}
+	b.mu.Unlock()
+
+	if b.onDecision != nil {
+		b.onDecision(allowed, stats)
+	}
+	return allowed
+}
+
+// Record counts a request and whether it failed.
+func (b *RetryBudget) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := b.rotateLocked()
+	current.requests++
+	if failed {
+		current.errors++
+	}
+}
+
+// Stats returns the counts over the current window.
+func (b *RetryBudget) Stats() RetryBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotateLocked()
+	return b.statsLocked()
+}
+
+// rotateLocked resets the buckets that have left the window and returns
+// the bucket for the current time.
+func (b *RetryBudget) rotateLocked() *retryBudgetBucket {
+	width := b.window / retryBudgetBuckets
+	now := b.now()
+	start := now.Truncate(width)
+	current := &b.buckets[(start.UnixNano()/int64(width))%retryBudgetBuckets]
+	if !current.start.Equal(start) {
+		*current = retryBudgetBucket{start: start}
+	}
+	for i := range b.buckets {
+		if now.Sub(b.buckets[i].start) >= b.window {
+			b.buckets[i] = retryBudgetBucket{}
+		}
+	}
+	return current
+}
+
+func (b *RetryBudget) statsLocked() RetryBudgetStats {
+	var stats RetryBudgetStats
+	for i := range b.buckets {
+		stats.Requests += b.buckets[i].requests
+		stats.Errors += b.buckets[i].errors
+		stats.Retries += b.buckets[i].retries
+		stats.Denied += b.buckets[i].denied
+	}
+	return stats
+}
+
+// recordRequest counts a completed request in the client's retry budget.
+func (c *Client) recordRequest(resp *http.Response, err error) {
+	if c.retryBudget == nil {
+		return
+	}
+	failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	c.retryBudget.Record(failed)
+}
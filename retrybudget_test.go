@@ -0,0 +1,152 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget_Ratio(t *testing.T) {
+	budget := NewRetryBudget(0.2, 1, time.Minute)
+	now := time.Now()
+	budget.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		budget.Record(false)
+	}
+	// One minimum retry plus 20% of 10 requests.
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if budget.Retry() {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Expected 3 retries allowed, got %d", allowed)
+	}
+
+	stats := budget.Stats()
+	if stats.Requests != 10 || stats.Retries != 3 || stats.Denied != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Counts expire with the window.
+	now = now.Add(2 * time.Minute)
+	if stats := budget.Stats(); stats.Requests != 0 || stats.Retries != 0 {
+		t.Errorf("Expected empty window, got %+v", stats)
+	}
+	if !budget.Retry() {
+		t.Error("Expected minimum retry to be allowed in a new window")
+	}
+}
+
+func TestRetryBudget_TinyWindow(t *testing.T) {
+	budget := NewRetryBudget(0.2, 1, time.Nanosecond)
+	budget.Record(false)
+	if !budget.Retry() {
+		t.Error("Expected minimum retry to be allowed")
+	}
+}
+
+func TestRetryBudget_WindowRoundedUp(t *testing.T) {
+	budget := NewRetryBudget(0.2, 1, 15*time.Nanosecond)
+	if budget.window != 20*time.Nanosecond {
+		t.Errorf("Expected the window rounded up to 20ns, got %v", budget.window)
+	}
+}
+
+func TestRetryBudget_DecisionHook(t *testing.T) {
+	var decisions []bool
+	var last RetryBudgetStats
+	budget := NewRetryBudget(0, 1, time.Minute, WithRetryDecisionHook(func(allowed bool, stats RetryBudgetStats) {
+		decisions = append(decisions, allowed)
+		last = stats
+	}))
+
+	budget.Record(true)
+	budget.Retry()
+	budget.Retry()
+
+	if len(decisions) != 2 || !decisions[0] || decisions[1] {
+		t.Errorf("Expected one allowed and one denied retry, got %v", decisions)
+	}
+	if last.Requests != 1 || last.Retries != 1 || last.Denied != 1 {
+		t.Errorf("Expected the counts after the decision, got %+v", last)
+	}
+}
+
+func TestRetryBudget_ErrorRateGuard(t *testing.T) {
+	budget := NewRetryBudget(1, 100, time.Minute, WithErrorRateGuard(0.5, 4))
+
+	budget.Record(true)
+	budget.Record(true)
+	if !budget.Retry() {
+		t.Error("Expected retry to be allowed below the guard's minimum requests")
+	}
+
+	budget.Record(true)
+	budget.Record(false)
+	if budget.Retry() {
+		t.Error("Expected retry to be denied at a 75% error rate")
+	}
+	if rate := budget.Stats().ErrorRate(); rate != 0.75 {
+		t.Errorf("Expected error rate 0.75, got %v", rate)
+	}
+}
+
+func TestRetryBudget_RecordsClientRequests(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"id": "user-1"}`))
+	}
+	budget := NewRetryBudget(0.2, 0, time.Minute)
+	client := setupTestClient(handler)
+	WithRetryBudget(budget)(client)
+
+	client.Messages.GetUserInfo(context.Background())
+	status = http.StatusOK
+	client.Messages.GetUserInfo(context.Background())
+
+	stats := budget.Stats()
+	if stats.Requests != 2 || stats.Errors != 1 {
+		t.Errorf("Expected 2 requests and 1 error, got %+v", stats)
+	}
+}
+
+func TestOutbox_RetryBudgetDefersRetries(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message": "unavailable"}`))
+	}
+	client := setupTestClient(handler)
+	WithRetryBudget(NewRetryBudget(0, 0, time.Minute))(client)
+
+	store := NewMemoryOutboxStore()
+	outbox := NewOutbox(client, store, WithOutboxBackoff(func(int) time.Duration { return 0 }))
+	ctx := context.Background()
+	id, err := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Hi"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = outbox.Flush(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected only the first attempt to be sent, got %d requests", calls)
+	}
+	msg, err := outbox.Status(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Attempts != 1 || msg.Status != OutboxStatusPending {
+		t.Errorf("Expected 1 attempt and pending status, got %d and %s", msg.Attempts, msg.Status)
+	}
+}
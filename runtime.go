@@ -0,0 +1,79 @@
+package signalads
+
+// Most of a Client's configuration is fixed by the options passed to
+// NewClient. The settings below may also change while the client is in use,
+// for example to rotate credentials or fail over to another API host, and
+// are guarded by Client.configMu.
+
+// WithDefaultFrom sets the sender number used by sends whose request does
+// not set From.
+func WithDefaultFrom(from string) ClientOption {
+	return func(c *Client) {
+		c.defaultFrom = from
+	}
+}
+
+// BaseURL returns the API base URL requests are currently sent to.
+func (c *Client) BaseURL() string {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+	return c.baseURL
+}
+
+// SetBaseURL changes the API base URL, for example to fail over to another
+// host. Requests already in flight keep their URL. It is safe to call while
+// the client is in use.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	c.baseURL = baseURL
+}
+
+// SetCredentials replaces the credentials with a fixed API key and secret.
+// It is safe to call while the client is in use; providers that rotate
+// secrets on their own can be installed with SetCredentialsProvider.
+func (c *Client) SetCredentials(apiKey, apiSecret string) {
+	c.SetCredentialsProvider(StaticCredentials{APIKey: apiKey, APISecret: apiSecret})
+}
+
+// SetCredentialsProvider replaces the client's credentials provider. A nil
+// provider is ignored. It is safe to call while the client is in use.
+func (c *Client) SetCredentialsProvider(provider CredentialsProvider) {
+	if provider == nil {
+		return
+	}
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	c.credentials = provider
+}
+
+// DefaultFrom returns the sender number used by sends whose request does
+// not set From.
+func (c *Client) DefaultFrom() string {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+	return c.defaultFrom
+}
+
+// SetDefaultFrom changes the default sender number. It is safe to call
+// while the client is in use.
+func (c *Client) SetDefaultFrom(from string) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	c.defaultFrom = from
+}
+
+// currentCredentials returns the credentials provider in use.
+func (c *Client) currentCredentials() CredentialsProvider {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+	return c.credentials
+}
+
+// fromOrDefault returns from, or the default sender if from is empty.
+func (c *Client) fromOrDefault(from string) string {
+	if from != "" {
+		return from
+	}
+	return c.DefaultFrom()
+}
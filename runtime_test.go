@@ -0,0 +1,118 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestDefaultFrom(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		froms []string
+	)
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			From string `json:"from"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		mu.Lock()
+		froms = append(froms, body.From)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "m1", "status": "sent"}`))
+	})
+	WithDefaultFrom("1000")(client)
+	ctx := context.Background()
+
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "hi"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Messages.SendSingleMessage(ctx, &SendMessageRequest{To: "+989123456789", Message: "hi", From: "2000"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client.SetDefaultFrom("3000")
+	if _, err := client.Messages.SendTemplate(ctx, "+989123456789", "tpl-1", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"1000", "2000", "3000"}
+	if len(froms) != len(want) {
+		t.Fatalf("Expected %d requests, got %d", len(want), len(froms))
+	}
+	for i := range want {
+		if froms[i] != want[i] {
+			t.Errorf("Request %d: expected from %s, got %s", i, want[i], froms[i])
+		}
+	}
+}
+
+func TestSetBaseURLAndCredentials(t *testing.T) {
+	var keys []string
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, name+":"+r.Header.Get("X-API-Key"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "u1"}`))
+		}
+	}
+	primary := httptest.NewServer(handler("primary"))
+	defer primary.Close()
+	secondary := httptest.NewServer(handler("secondary"))
+	defer secondary.Close()
+
+	client := NewClient("key-1", "secret-1", WithBaseURL(primary.URL))
+	ctx := context.Background()
+	if _, err := client.Messages.GetUserInfo(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client.SetBaseURL(secondary.URL)
+	client.SetCredentials("key-2", "secret-2")
+	if client.BaseURL() != secondary.URL {
+		t.Errorf("Expected base URL %s, got %s", secondary.URL, client.BaseURL())
+	}
+	if _, err := client.Messages.GetUserInfo(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"primary:key-1", "secondary:key-2"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, keys)
+	}
+}
+
+func TestRuntimeConfig_ConcurrentUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "m1", "status": "sent"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("key", "secret", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Messages.SendMessage(ctx, "+989123456789", "hi"); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			client.SetCredentials("key", "rotated")
+			client.SetDefaultFrom("1000")
+			client.SetBaseURL(server.URL)
+		}()
+	}
+	wg.Wait()
+}
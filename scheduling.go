@@ -0,0 +1,62 @@
+package signalads
+
+import "time"
+
+// WithSchedulingLocation sets the time zone schedule times are sent to the
+// API in. It defaults to Tehran, the zone the API schedules in; change it
+// only for a gateway configured with another zone.
+func WithSchedulingLocation(loc *time.Location) ClientOption {
+	return func(c *Client) {
+		c.schedulingLoc = loc
+	}
+}
+
+// SchedulingLocation returns the time zone schedule times are sent in.
+func (c *Client) SchedulingLocation() *time.Location {
+	if c.schedulingLoc == nil {
+		return Tehran
+	}
+	return c.schedulingLoc
+}
+
+// ScheduleTime returns the instant at which the wall clock in the
+// scheduling location reads the given date and time, for callers who mean
+// "9:00 in Tehran" rather than 9:00 in their own zone. A wall time skipped
+// by a daylight saving change resolves to the same wall time on the clock
+// in effect before the change, that is, after the gap; a wall time that
+// occurs twice resolves to its first occurrence.
+func (c *Client) ScheduleTime(year int, month time.Month, day, hour, min int) time.Time {
+	return wallClock(year, month, day, hour, min, c.SchedulingLocation())
+}
+
+// scheduleTime converts t to the scheduling location, so that the API
+// receives the instant t with the offset it expects. The instant itself is
+// never changed.
+func (c *Client) scheduleTime(t time.Time) time.Time {
+	return t.In(c.SchedulingLocation())
+}
+
+// wallClock resolves a wall time in loc without relying on time.Date's
+// unspecified choice around daylight saving changes.
+func wallClock(year int, month time.Month, day, hour, min int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	approx := time.Date(year, month, day, hour, min, 0, 0, loc)
+	_, before := approx.Add(-12 * time.Hour).Zone()
+	_, after := approx.Add(12 * time.Hour).Zone()
+
+	var earliest time.Time
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if t.Hour() != hour || t.Minute() != min || t.Day() != wall.Day() {
+			continue
+		}
+		if earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+	}
+	if !earliest.IsZero() {
+		return earliest
+	}
+	// The wall time falls in a gap
+	return wall.Add(-time.Duration(before) * time.Second).In(loc)
+}
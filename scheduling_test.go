@@ -0,0 +1,68 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRescheduleMessage_SchedulingLocation(t *testing.T) {
+	newTime := time.Date(2026, 3, 21, 5, 30, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		if body["scheduled_at"] != "2026-03-21T09:00:00+03:30" {
+			t.Errorf("Expected scheduled_at in Tehran time, got '%s'", body["scheduled_at"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "msg-1"}`))
+	}
+
+	client := setupTestClient(handler)
+	if _, err := client.Messages.RescheduleMessage(context.Background(), "msg-1", newTime); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestClient_ScheduleTime(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {})
+
+	at := client.ScheduleTime(2026, time.March, 21, 9, 0)
+	if want := time.Date(2026, 3, 21, 5, 30, 0, 0, time.UTC); !at.Equal(want) {
+		t.Errorf("Expected 9:00 Tehran to be %v, got %v", want, at.UTC())
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %v", err)
+	}
+	WithSchedulingLocation(ny)(client)
+
+	tests := []struct {
+		name string
+		day  int
+		hour int
+		want string
+	}{
+		{"ordinary", 7, 9, "2026-03-07T09:00:00-05:00"},
+		{"after spring forward", 8, 10, "2026-03-08T10:00:00-04:00"},
+		{"skipped by spring forward", 8, 2, "2026-03-08T03:00:00-04:00"},
+	}
+	for _, tt := range tests {
+		got := client.ScheduleTime(2026, time.March, tt.day, tt.hour, 0).Format(time.RFC3339)
+		if got != tt.want {
+			t.Errorf("%s: Expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	repeated := client.ScheduleTime(2026, time.November, 1, 1, 30).Format(time.RFC3339)
+	if !strings.HasSuffix(repeated, "-04:00") {
+		t.Errorf("Expected the first 1:30 of the fall back day, got %s", repeated)
+	}
+}
@@ -0,0 +1,83 @@
+package signalads
+
+// SMS segment sizes for the two encodings used by carriers.
+const (
+	GSM7SingleSegmentLength = 160
+	GSM7MultiSegmentLength  = 153
+	UCS2SingleSegmentLength = 70
+	UCS2MultiSegmentLength  = 67
+)
+
+// gsm7Basic is the GSM 03.38 basic character set.
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extended characters are sent as an escape sequence and take two
+// septets each.
+const gsm7Extended = "^{}\\[~]|€\f"
+
+var (
+	gsm7BasicSet    = runeSet(gsm7Basic)
+	gsm7ExtendedSet = runeSet(gsm7Extended)
+)
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool, len(chars))
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
+
+// IsGSM7 reports whether text can be sent with the GSM 7-bit alphabet.
+func IsGSM7(text string) bool {
+	for _, r := range text {
+		if !gsm7BasicSet[r] && !gsm7ExtendedSet[r] {
+			return false
+		}
+	}
+	return true
+}
+
+// CountSegments returns how many SMS segments text occupies, choosing GSM-7
+// when every character allows it and UCS-2 otherwise. Empty text counts as
+// one segment.
+func CountSegments(text string) int {
+	if IsGSM7(text) {
+		return segmentsFor(gsm7Length(text), GSM7SingleSegmentLength, GSM7MultiSegmentLength)
+	}
+	return segmentsFor(ucs2Length(text), UCS2SingleSegmentLength, UCS2MultiSegmentLength)
+}
+
+func gsm7Length(text string) int {
+	n := 0
+	for _, r := range text {
+		if gsm7ExtendedSet[r] {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// ucs2Length counts UTF-16 code units; characters outside the Basic
+// Multilingual Plane, such as most emoji, take two.
+func ucs2Length(text string) int {
+	n := 0
+	for _, r := range text {
+		if r > 0xFFFF {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+func segmentsFor(length, single, multi int) int {
+	if length <= single {
+		return 1
+	}
+	return (length + multi - 1) / multi
+}
@@ -0,0 +1,41 @@
+package signalads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{name: "empty", text: "", expected: 1},
+		{name: "short gsm7", text: "Hello", expected: 1},
+		{name: "gsm7 single limit", text: strings.Repeat("a", 160), expected: 1},
+		{name: "gsm7 two segments", text: strings.Repeat("a", 161), expected: 2},
+		{name: "gsm7 three segments", text: strings.Repeat("a", 307), expected: 3},
+		{name: "extended chars count double", text: strings.Repeat("€", 81), expected: 2},
+		{name: "persian single", text: strings.Repeat("س", 70), expected: 1},
+		{name: "persian two segments", text: strings.Repeat("س", 71), expected: 2},
+		{name: "emoji uses surrogate pairs", text: strings.Repeat("😀", 36), expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountSegments(tt.text); got != tt.expected {
+				t.Errorf("Expected %d segments, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsGSM7(t *testing.T) {
+	if !IsGSM7("Hello {world} €5") {
+		t.Error("Expected GSM-7 text to be detected")
+	}
+	if IsGSM7("سلام") {
+		t.Error("Expected Persian text not to be GSM-7")
+	}
+}
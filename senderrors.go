@@ -0,0 +1,23 @@
+package signalads
+
+import "context"
+
+// WithErrorHandler registers fn to be called when a message sent in the
+// background fails for good: a send of an AsyncSender that returns an
+// error, or an Outbox message that is marked failed. It gives applications
+// one place to alert on, persist or compensate for messages that no caller
+// is waiting on. fn runs on the sending goroutine and should return
+// quickly.
+func WithErrorHandler(fn func(ctx context.Context, req *SendMessageRequest, err error)) ClientOption {
+	return func(c *Client) {
+		c.errorHandler = fn
+	}
+}
+
+// reportSendFailure passes a permanently failed background send to the
+// error handler, if one is set.
+func (c *Client) reportSendFailure(ctx context.Context, req *SendMessageRequest, err error) {
+	if c.errorHandler != nil {
+		c.errorHandler(ctx, req, err)
+	}
+}
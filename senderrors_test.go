@@ -0,0 +1,105 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type recordedSendFailure struct {
+	req *SendMessageRequest
+	err error
+}
+
+func recordSendFailures(client *Client) func() []recordedSendFailure {
+	var mu sync.Mutex
+	var failures []recordedSendFailure
+	WithErrorHandler(func(_ context.Context, req *SendMessageRequest, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, recordedSendFailure{req: req, err: err})
+	})(client)
+	return func() []recordedSendFailure {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedSendFailure(nil), failures...)
+	}
+}
+
+func TestErrorHandler_AsyncSender(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(APIError{Message: "Invalid phone number"})
+	})
+	failures := recordSendFailures(client)
+
+	sender := client.Messages.Async(1)
+	req := &SendMessageRequest{To: "+989123456789", Message: "Test"}
+	_, err := sender.Send(context.Background(), req).Result()
+	sender.Close()
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	got := failures()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 reported failure, got %d", len(got))
+	}
+	if got[0].req.To != req.To {
+		t.Errorf("Expected request to %s, got %s", req.To, got[0].req.To)
+	}
+	var apiErr *APIError
+	if !errors.As(got[0].err, &apiErr) {
+		t.Errorf("Expected *APIError, got %v", got[0].err)
+	}
+}
+
+func TestErrorHandler_AsyncSenderSuccess(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-123", Status: "sent"})
+	})
+	failures := recordSendFailures(client)
+
+	sender := client.Messages.Async(1)
+	sender.Send(context.Background(), &SendMessageRequest{To: "+989123456789", Message: "Test"})
+	sender.Close()
+
+	if got := failures(); len(got) != 0 {
+		t.Errorf("Expected no reported failures, got %d", len(got))
+	}
+}
+
+func TestErrorHandler_OutboxFailsAfterRetries(t *testing.T) {
+	var calls int32
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	failures := recordSendFailures(client)
+
+	outbox := NewOutbox(client, NewMemoryOutboxStore(), WithOutboxBackoff(noBackoff), WithOutboxMaxAttempts(3))
+	ctx := context.Background()
+	if _, err := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		outbox.Flush(ctx)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 API calls, got %d", got)
+	}
+	got := failures()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 reported failure after the last attempt, got %d", len(got))
+	}
+	if got[0].req.Message != "Test" {
+		t.Errorf("Expected message 'Test', got '%s'", got[0].req.Message)
+	}
+}
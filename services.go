@@ -0,0 +1,139 @@
+package signalads
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// The *API interfaces are the method sets of the services, and the types of
+// the Client's service fields. Replace a field with a wrapper around the
+// original to decorate a service, for example to add caching or tracing,
+// or with a fake in tests:
+//
+//	client.Templates = myCachingTemplates{TemplatesAPI: client.Templates}
+//
+// Components the client builds itself, such as the Outbox and the
+// AccountWatcher, call the services through these fields, so they see the
+// wrappers too. Helpers returned by a service, such as an AsyncSender, are
+// bound to the concrete service.
+
+// MessagesAPI is the interface of MessagesService.
+type MessagesAPI interface {
+	SendSingleMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error)
+	SendMessage(ctx context.Context, to, message string) (*SendMessageResponse, error)
+	SendMessageWithDocument(ctx context.Context, to, message, documentLink, caption string) (*SendMessageResponse, error)
+	SendBulkMessages(ctx context.Context, req *SendBulkMessageRequest) (*SendBulkMessageResponse, error)
+	SendBulkMessage(ctx context.Context, messages []BulkMessageItem, from string) (*SendBulkMessageResponse, error)
+	SendTemplateMessage(ctx context.Context, req *SendTemplateMessageRequest) (*SendMessageResponse, error)
+	SendTemplate(ctx context.Context, to, templateID string, params map[string]string) (*SendMessageResponse, error)
+	SendVoiceMessage(ctx context.Context, req *SendVoiceMessageRequest) (*SendMessageResponse, error)
+	SendVoice(ctx context.Context, to, message, voiceType, language string) (*SendMessageResponse, error)
+	SendBulkVoiceMessages(ctx context.Context, req *SendBulkVoiceRequest) (*SendBulkVoiceResponse, error)
+	SendBulkVoice(ctx context.Context, recipients []string, message, voiceType, language string) (*SendBulkVoiceResponse, error)
+	SendOTPMessage(ctx context.Context, req *SendOTPRequest) (*OTPSendResult, error)
+	SendOTP(ctx context.Context, phone, code, templateID string) (*OTPSendResult, error)
+	SendPatternMessage(ctx context.Context, req *SendPatternRequest) (*SendPatternResponse, error)
+	SendPattern(ctx context.Context, to, patternCode string, values map[string]string) (*SendPatternResponse, error)
+
+	ListMessages(ctx context.Context, params *PaginationParams) (*ListMessagesResponse, error)
+	StreamMessages(ctx context.Context, params *PaginationParams, fn func(*Message) error) error
+	MessagesToday(ctx context.Context) ([]Message, error)
+	MessagesBetween(ctx context.Context, from, to time.Time) ([]Message, error)
+	FailedSince(ctx context.Context, t time.Time) ([]Message, error)
+	ListScheduledMessages(ctx context.Context, params *PaginationParams) (*ListScheduledMessagesResponse, error)
+	RescheduleMessage(ctx context.Context, messageID string, newTime time.Time) (*ScheduledMessage, error)
+	GetMessageStatus(ctx context.Context, messageID string) (*MessageStatus, error)
+	GetVoiceCampaign(ctx context.Context, campaignID string) (*VoiceCampaign, error)
+	GetUserInfo(ctx context.Context) (*UserInfo, error)
+
+	CheckDocumentLink(ctx context.Context, link string) error
+	FilterRecipients(ctx context.Context, items []BulkMessageItem, checks ...RecipientCheck) ([]BulkMessageItem, []BulkFailure, error)
+	EstimateAndReserve(ctx context.Context, req *SendBulkMessageRequest) (*CostEstimate, error)
+	RunExperiment(ctx context.Context, exp *Experiment, recipients []string) (*ExperimentRun, error)
+	ExperimentStats(ctx context.Context, run *ExperimentRun) ([]VariantStats, error)
+
+	Async(workers int, opts ...AsyncOption) *AsyncSender
+	Aggregator(opts ...AggregatorOption) *Aggregator
+}
+
+// TemplatesAPI is the interface of TemplatesService.
+type TemplatesAPI interface {
+	ListTemplates(ctx context.Context, params *PaginationParams) (*ListTemplatesResponse, error)
+	GetUsageStats(ctx context.Context, templateID string, dateRange DateRange) (*TemplateUsageStats, error)
+	SyncTo(ctx context.Context, store TemplateStore) (*TemplateSyncResult, error)
+	Resolver(opts ...TemplateResolverOption) *TemplateResolver
+}
+
+// InboxAPI is the interface of InboxService.
+type InboxAPI interface {
+	ListInboundMessages(ctx context.Context, params *PaginationParams) (*ListInboundMessagesResponse, error)
+	DownloadAttachment(ctx context.Context, attachmentURL string, w io.Writer) error
+}
+
+// ShortlinksAPI is the interface of ShortlinksService.
+type ShortlinksAPI interface {
+	GetClicks(ctx context.Context, linkID string, params *PaginationParams) (*ListLinkClicksResponse, error)
+}
+
+// CallsAPI is the interface of CallsService.
+type CallsAPI interface {
+	ListCalls(ctx context.Context, params *PaginationParams) (*ListCallsResponse, error)
+	GetCall(ctx context.Context, callID string) (*Call, error)
+	GetCallRecording(ctx context.Context, callID string, w io.Writer) error
+	RequestTranscription(ctx context.Context, callID, language string) (*Transcription, error)
+	GetTranscription(ctx context.Context, transcriptionID string) (*Transcription, error)
+	WaitForTranscription(ctx context.Context, transcriptionID string, interval time.Duration) (*Transcription, error)
+}
+
+// FilesAPI is the interface of FilesService.
+type FilesAPI interface {
+	UploadFile(ctx context.Context, r io.Reader, name string) (*File, error)
+	ListFiles(ctx context.Context, params *PaginationParams) (*ListFilesResponse, error)
+	DeleteFile(ctx context.Context, fileID string) error
+}
+
+// ReportsAPI is the interface of ReportsService.
+type ReportsAPI interface {
+	CostByTag(ctx context.Context, dateRange DateRange) (*CostReport, error)
+	CostByClientRef(ctx context.Context, dateRange DateRange) (*CostReport, error)
+}
+
+// QuotaAPI is the interface of QuotaService.
+type QuotaAPI interface {
+	GetDailyQuota(ctx context.Context) (*DailyQuota, error)
+	RemainingToday(ctx context.Context) (int, error)
+	Forecast(ctx context.Context, planned int) (*QuotaForecast, error)
+}
+
+// LinesAPI is the interface of LinesService.
+type LinesAPI interface {
+	ListLines(ctx context.Context) (*ListLinesResponse, error)
+	ExpiringWithin(ctx context.Context, d time.Duration) ([]Line, error)
+}
+
+// BillingAPI is the interface of BillingService.
+type BillingAPI interface {
+	Snapshot(ctx context.Context) (*BalancePoint, error)
+	RunSnapshots(ctx context.Context, interval time.Duration, onError func(ctx context.Context, err error)) error
+	BalanceHistory(ctx context.Context, dateRange DateRange, granularity time.Duration) ([]BalancePoint, error)
+}
+
+// WebhooksAPI is the interface of WebhooksService.
+type WebhooksAPI interface {
+	TestEndpoint(ctx context.Context, endpoint string) (*WebhookTestResult, error)
+}
+
+var (
+	_ MessagesAPI   = (*MessagesService)(nil)
+	_ TemplatesAPI  = (*TemplatesService)(nil)
+	_ InboxAPI      = (*InboxService)(nil)
+	_ ShortlinksAPI = (*ShortlinksService)(nil)
+	_ CallsAPI      = (*CallsService)(nil)
+	_ FilesAPI      = (*FilesService)(nil)
+	_ ReportsAPI    = (*ReportsService)(nil)
+	_ QuotaAPI      = (*QuotaService)(nil)
+	_ LinesAPI      = (*LinesService)(nil)
+	_ BillingAPI    = (*BillingService)(nil)
+	_ WebhooksAPI   = (*WebhooksService)(nil)
+)
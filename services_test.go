@@ -0,0 +1,42 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+type countingMessages struct {
+	MessagesAPI
+	sends int
+}
+
+func (m *countingMessages) SendSingleMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
+	m.sends++
+	return m.MessagesAPI.SendSingleMessage(ctx, req)
+}
+
+func TestClient_DecoratedService(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	client := setupTestClient(handler)
+	counting := &countingMessages{MessagesAPI: client.Messages}
+	client.Messages = counting
+
+	outbox := NewOutbox(client, NewMemoryOutboxStore())
+	ctx := context.Background()
+	if _, err := outbox.Enqueue(ctx, &SendMessageRequest{To: "+989123456789", Message: "Test"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := outbox.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if counting.sends != 1 {
+		t.Errorf("Expected the outbox to send through the decorated service, got %d sends", counting.sends)
+	}
+}
@@ -0,0 +1,231 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSessionIdleTimeout = 10 * time.Minute
+	DefaultSessionBufferSize  = 16
+)
+
+var (
+	// ErrSessionExists is returned by StartSession when the phone number
+	// already has an open session.
+	ErrSessionExists = errors.New("session already open for this phone number")
+
+	// ErrSessionClosed is returned when sending on a session that has been
+	// closed or has expired.
+	ErrSessionClosed = errors.New("session is closed")
+
+	// ErrSessionBufferFull is returned for an inbound reply that arrives
+	// while the session's reply buffer is full. Returned from a webhook
+	// callback, it makes the provider deliver the reply again later.
+	ErrSessionBufferFull = errors.New("session reply buffer is full")
+)
+
+// SessionOption configures Sessions.
+type SessionOption func(*Sessions)
+
+// WithSessionIdleTimeout sets how long a session stays open without
+// messages in either direction. It defaults to DefaultSessionIdleTimeout.
+func WithSessionIdleTimeout(d time.Duration) SessionOption {
+	return func(m *Sessions) {
+		if d > 0 {
+			m.idleTimeout = d
+		}
+	}
+}
+
+// WithSessionSender sets the line that session messages are sent from.
+func WithSessionSender(from string) SessionOption {
+	return func(m *Sessions) {
+		m.from = from
+	}
+}
+
+// WithSessionBufferSize sets how many unread replies a session holds.
+func WithSessionBufferSize(size int) SessionOption {
+	return func(m *Sessions) {
+		if size > 0 {
+			m.bufferSize = size
+		}
+	}
+}
+
+// Sessions correlates inbound SMS with two-way conversations. Each session
+// is scoped to one phone number: messages are sent to it with Send and
+// replies from it arrive on the session's Replies channel. Sessions are fed
+// inbound messages with HandleInbound, either from a WebhookHandler (see
+// Register) or from polling the inbox.
+type Sessions struct {
+	client      *Client
+	idleTimeout time.Duration
+	bufferSize  int
+	from        string
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewSessions creates an empty session registry sending through client.
+func NewSessions(client *Client, opts ...SessionOption) *Sessions {
+	m := &Sessions{
+		client:      client,
+		idleTimeout: DefaultSessionIdleTimeout,
+		bufferSize:  DefaultSessionBufferSize,
+		sessions:    make(map[string]*Session),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// StartSession opens a session with phone.
+func (m *Sessions) StartSession(phone string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[phone]; ok {
+		return nil, ErrSessionExists
+	}
+
+	s := &Session{
+		Phone:    phone,
+		sessions: m,
+		replies:  make(chan *InboundMessageEvent, m.bufferSize),
+		done:     make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.timer = time.AfterFunc(m.idleTimeout, s.Close)
+	s.mu.Unlock()
+	m.sessions[phone] = s
+	return s, nil
+}
+
+// Register routes inbound messages received by h to open sessions.
+func (m *Sessions) Register(h *WebhookHandler) {
+	h.OnInboundMessage(m.HandleInbound)
+}
+
+// HandleInbound delivers msg to the open session with its sender. Messages
+// from numbers without a session are ignored.
+func (m *Sessions) HandleInbound(_ context.Context, msg *InboundMessageEvent) error {
+	m.mu.Lock()
+	s := m.sessions[msg.From]
+	m.mu.Unlock()
+
+	if s == nil {
+		return nil
+	}
+	return s.deliver(msg)
+}
+
+// Session is a conversation with a single phone number.
+type Session struct {
+	Phone string
+
+	sessions *Sessions
+	replies  chan *InboundMessageEvent
+	done     chan struct{}
+	timer    *time.Timer
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Send sends text to the session's phone number and restarts the idle
+// timer.
+func (s *Session) Send(ctx context.Context, text string) (*SendMessageResponse, error) {
+	if !s.touch() {
+		return nil, ErrSessionClosed
+	}
+	return s.sessions.client.Messages.SendSingleMessage(ctx, &SendMessageRequest{
+		To:      s.Phone,
+		Message: text,
+		From:    s.sessions.from,
+	})
+}
+
+// Replies returns the channel of messages received from the phone number.
+// It is closed when the session ends.
+func (s *Session) Replies() <-chan *InboundMessageEvent {
+	return s.replies
+}
+
+// Done returns a channel that is closed when the session ends.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close ends the session. Replies already buffered remain readable.
+func (s *Session) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.timer.Stop()
+	close(s.replies)
+	close(s.done)
+	s.mu.Unlock()
+
+	m := s.sessions
+	m.mu.Lock()
+	if m.sessions[s.Phone] == s {
+		delete(m.sessions, s.Phone)
+	}
+	m.mu.Unlock()
+}
+
+// touch restarts the idle timer, reporting false if the session is closed.
+func (s *Session) touch() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.timer.Reset(s.sessions.idleTimeout)
+	return true
+}
+
+func (s *Session) deliver(msg *InboundMessageEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	select {
+	case s.replies <- msg:
+		s.timer.Reset(s.sessions.idleTimeout)
+		return nil
+	default:
+		return ErrSessionBufferFull
+	}
+}
+
+// Ask sends question and waits for the next reply, for simple
+// question-and-answer flows. It returns ErrSessionClosed if the session
+// ends before a reply arrives.
+func (s *Session) Ask(ctx context.Context, question string) (*InboundMessageEvent, error) {
+	if _, err := s.Send(ctx, question); err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply, ok := <-s.replies:
+		if !ok {
+			return nil, ErrSessionClosed
+		}
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
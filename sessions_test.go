@@ -0,0 +1,111 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupSessions(t *testing.T, opts ...SessionOption) (*Sessions, chan SendMessageRequest) {
+	t.Helper()
+
+	sent := make(chan SendMessageRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sent <- req
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL))
+	return NewSessions(client, opts...), sent
+}
+
+func TestSessions_Ask(t *testing.T) {
+	sessions, sent := setupSessions(t, WithSessionSender("100020"))
+	webhooks := newTestWebhookHandler()
+	sessions.Register(webhooks)
+
+	session, err := sessions.StartSession("+989123456789")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	go func() {
+		req := <-sent
+		if req.To != "+989123456789" || req.From != "100020" {
+			t.Errorf("Unexpected question request: %+v", req)
+		}
+
+		other := `{"id": "evt-1", "type": "message.inbound", "data": {"from": "+989000000000", "message": "noise"}}`
+		webhooks.ServeHTTP(httptest.NewRecorder(), signedWebhookRequest("/", strings.NewReader(other)))
+
+		answer := `{"id": "evt-2", "type": "message.inbound", "data": {"from": "+989123456789", "message": "Tehran"}}`
+		webhooks.ServeHTTP(httptest.NewRecorder(), signedWebhookRequest("/", strings.NewReader(answer)))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	reply, err := session.Ask(ctx, "Which city do you live in?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reply.Message != "Tehran" {
+		t.Errorf("Expected reply 'Tehran', got '%s'", reply.Message)
+	}
+}
+
+func TestSessions_IdleExpiry(t *testing.T) {
+	sessions, _ := setupSessions(t, WithSessionIdleTimeout(20*time.Millisecond))
+
+	session, err := sessions.StartSession("+989123456789")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := sessions.StartSession("+989123456789"); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("Expected ErrSessionExists, got %v", err)
+	}
+
+	select {
+	case <-session.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected session to expire")
+	}
+
+	if _, ok := <-session.Replies(); ok {
+		t.Error("Expected replies channel to be closed")
+	}
+	if _, err := session.Send(context.Background(), "Hi"); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("Expected ErrSessionClosed, got %v", err)
+	}
+	if _, err := sessions.StartSession("+989123456789"); err != nil {
+		t.Errorf("Expected a new session after expiry, got %v", err)
+	}
+}
+
+func TestSessions_BufferFull(t *testing.T) {
+	sessions, _ := setupSessions(t, WithSessionBufferSize(1))
+
+	session, err := sessions.StartSession("+989123456789")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	msg := &InboundMessageEvent{From: "+989123456789", Message: "1"}
+	if err := sessions.HandleInbound(context.Background(), msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := sessions.HandleInbound(context.Background(), msg); !errors.Is(err, ErrSessionBufferFull) {
+		t.Errorf("Expected ErrSessionBufferFull, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+)
+
+// ShortlinksService provides methods for tracked short links in messages.
+type ShortlinksService struct {
+	client *Client
+}
+
+// GetClicks retrieves the recorded clicks of a short link.
+func (s *ShortlinksService) GetClicks(ctx context.Context, linkID string, params *PaginationParams) (*ListLinkClicksResponse, error) {
+	var v validator
+	v.required("link_id", linkID, "link ID is required")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	var response ListLinkClicksResponse
+	if err := s.client.Get(ctx, "/shortlinks/"+pathSegment(linkID)+"/clicks", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to get link clicks: %w", err)
+	}
+
+	return &response, nil
+}
+
+// eachClick calls fn for every click of a short link, fetching all pages
+// from api.
+func eachClick(ctx context.Context, api ShortlinksAPI, linkID string, fn func(*LinkClickEvent)) error {
+	params := &PaginationParams{Page: 1, PerPage: 100}
+	for {
+		response, err := api.GetClicks(ctx, linkID, params)
+		if err != nil {
+			return err
+		}
+		for i := range response.Clicks {
+			fn(&response.Clicks[i])
+		}
+		if len(response.Clicks) == 0 || !response.Pagination.HasNext {
+			return nil
+		}
+		params.Page++
+	}
+}
@@ -0,0 +1,63 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShortlinksService_GetClicks(t *testing.T) {
+	clickedAt := time.Date(2026, 3, 20, 9, 15, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/shortlinks/lnk-1/clicks" {
+			t.Errorf("Expected path '/shortlinks/lnk-1/clicks', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page 100, got '%s'", r.URL.Query().Get("per_page"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListLinkClicksResponse{
+			Clicks: []LinkClickEvent{{LinkID: "lnk-1", Recipient: "+989123456789", URL: "https://shop.example/sale", ClickedAt: clickedAt}},
+			Total:  1,
+		})
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Shortlinks.GetClicks(context.Background(), "lnk-1", &PaginationParams{PerPage: 100})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Clicks) != 1 || !response.Clicks[0].ClickedAt.Equal(clickedAt) {
+		t.Errorf("Unexpected clicks: %+v", response.Clicks)
+	}
+
+	if _, err := client.Shortlinks.GetClicks(context.Background(), "", nil); !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestWebhookHandler_LinkClick(t *testing.T) {
+	h := newTestWebhookHandler()
+
+	var got *LinkClickEvent
+	h.OnLinkClick(func(ctx context.Context, click *LinkClickEvent) error {
+		got = click
+		return nil
+	})
+
+	body := `{"id": "evt-1", "type": "link.click", "data": {"link_id": "lnk-1", "message_id": "msg-1", "recipient": "+989123456789", "url": "https://shop.example/sale", "clicked_at": "2026-03-20T09:15:00Z"}}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signedWebhookRequest("/webhook", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got == nil || got.LinkID != "lnk-1" || got.MessageID != "msg-1" {
+		t.Errorf("Unexpected link click: %+v", got)
+	}
+}
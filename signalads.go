@@ -1,35 +1,147 @@
 package signalads
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
 
 // Client represents a SignalAds API client.
 // It provides methods to interact with the SignalAds API services.
+//
+// A Client is safe for concurrent use and is meant to be shared. Its options
+// are applied once by NewClient and must not be applied again afterwards;
+// only the base URL, credentials and default sender can change at runtime,
+// through SetBaseURL, SetCredentials and SetDefaultFrom.
 type Client struct {
-	baseURL    string
+	// configMu guards the settings that can change at runtime.
+	configMu    sync.RWMutex
+	baseURL     string
+	credentials CredentialsProvider
+	defaultFrom string
+
 	httpClient *http.Client
-	apiKey     string
-	apiSecret  string
-	Messages   *MessagesService
+	Messages   MessagesAPI
+	Templates  TemplatesAPI
+	Inbox      InboxAPI
+	Shortlinks ShortlinksAPI
+	Calls      CallsAPI
+	Files      FilesAPI
+	Reports    ReportsAPI
+	Quota      QuotaAPI
+	Lines      LinesAPI
+	Billing    BillingAPI
+	Webhooks   WebhooksAPI
+
+	// ownsHTTPClient and ownsTransport report whether httpClient and its
+	// transport were created by the client, and may be changed by options,
+	// rather than supplied by the caller and possibly shared.
+	ownsHTTPClient bool
+	ownsTransport  bool
+
+	cache       *responseCache
+	statusCache *statusCache
+
+	compressRequests bool
+	compressMinSize  int
+
+	certPins map[string]map[string]bool
+
+	segmentPrice float64
+
+	longMessagePolicy LongMessagePolicy
+	maxSegments       int
+
+	footer        string
+	footerWarning func(ctx context.Context, warning FooterWarning)
+
+	documentCheck *documentCheck
+
+	calendar      *Calendar
+	governor      *Governor
+	priorityQueue *PriorityQueue
+
+	frequencyCap *frequencyCap
+
+	preFilter       bool
+	recipientChecks []RecipientCheck
+
+	locale string
+
+	webhookSecret string
+
+	patternEndpoint string
+
+	maxResponseSize int64
+	maxRequestSize  int64
+
+	rawResponses        bool
+	strictDecoding      bool
+	unknownFieldHandler func(ctx context.Context, warning UnknownFieldWarning)
+
+	hedgeDelay  time.Duration
+	deadlines   *Deadlines
+	retryBudget *RetryBudget
+
+	latency       *LatencyHistogram
+	slowThreshold time.Duration
+	slowRequest   func(ctx context.Context, slow SlowRequest)
+
+	checkpointer Checkpointer
+
+	errorHandler func(ctx context.Context, req *SendMessageRequest, err error)
+
+	templateSchemas TemplateStore
+
+	profile string
+
+	schedulingLoc *time.Location
+
+	balanceStore BalanceStore
+
+	usageRecorder UsageRecorder
+	tracker       *StatusTracker
+
+	rateMu    sync.RWMutex
+	rateLimit RateLimit
 }
 
 // NewClient creates a new SignalAds API client with the provided credentials.
-// apiKey and apiSecret are required for authentication.
+// apiKey and apiSecret are required for authentication unless a
+// CredentialsProvider is supplied with WithCredentialsProvider.
 // Additional configuration can be provided using ClientOption functions.
 func NewClient(apiKey, apiSecret string, opts ...ClientOption) *Client {
 	client := &Client{
 		baseURL: DefaultBaseURL,
 		httpClient: &http.Client{
-			Timeout: DefaultTimeout,
+			Timeout:   DefaultTimeout,
+			Transport: newDefaultTransport(),
 		},
-		apiKey:    apiKey,
-		apiSecret: apiSecret,
+		ownsHTTPClient:  true,
+		ownsTransport:   true,
+		credentials:     StaticCredentials{APIKey: apiKey, APISecret: apiSecret},
+		maxResponseSize: DefaultMaxResponseSize,
+		maxRequestSize:  DefaultMaxRequestSize,
+		patternEndpoint: DefaultPatternEndpoint,
 	}
 
 	for _, opt := range opts {
 		opt(client)
 	}
+	client.installCertificatePins()
 
 	client.Messages = &MessagesService{client: client}
+	client.Templates = &TemplatesService{client: client}
+	client.Inbox = &InboxService{client: client}
+	client.Shortlinks = &ShortlinksService{client: client}
+	client.Calls = &CallsService{client: client}
+	client.Files = &FilesService{client: client}
+	client.Reports = &ReportsService{client: client}
+	client.Quota = &QuotaService{client: client}
+	client.Lines = &LinesService{client: client}
+	client.Billing = &BillingService{client: client}
+	client.Webhooks = &WebhooksService{client: client}
 
 	return client
 }
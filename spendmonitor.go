@@ -0,0 +1,192 @@
+package signalads
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults of a SpendMonitor.
+const (
+	DefaultSpendHistory    = 12
+	DefaultSpendMinHistory = 3
+)
+
+// SpendAnomaly describes a window whose spend exceeded the configured
+// multiple of the trailing average.
+type SpendAnomaly struct {
+	// Start of the window
+	WindowStart time.Time
+
+	// Spend so far in the window
+	Spend float64
+
+	// Average spend of the trailing windows, quiet windows included
+	TrailingAverage float64
+
+	// Threshold the spend exceeded
+	Threshold float64
+}
+
+// SpendMonitorOption configures a SpendMonitor.
+type SpendMonitorOption func(*SpendMonitor)
+
+// WithSpendHistory sets how many trailing windows the average covers. It
+// defaults to DefaultSpendHistory.
+func WithSpendHistory(windows int) SpendMonitorOption {
+	return func(m *SpendMonitor) {
+		if windows > 0 {
+			m.history = windows
+		}
+	}
+}
+
+// WithSpendMinHistory sets how many windows must have passed before the
+// monitor raises anomalies, so that the first sends of a process are not
+// compared against nothing. It defaults to DefaultSpendMinHistory.
+func WithSpendMinHistory(windows int) SpendMonitorOption {
+	return func(m *SpendMonitor) {
+		if windows >= 0 {
+			m.minHistory = windows
+		}
+	}
+}
+
+// WithSpendFloor sets a spend below which a window is never anomalous,
+// however quiet the trailing windows were. Without a floor, a window after
+// trailing windows without any spend is never anomalous either.
+func WithSpendFloor(amount float64) SpendMonitorOption {
+	return func(m *SpendMonitor) {
+		m.floor = amount
+	}
+}
+
+// SpendMonitor watches spend per time window and calls a function when a
+// window's spend exceeds a multiple of the average of the trailing
+// windows, so that a bug that sends in a loop is noticed within one
+// window. It is a UsageRecorder; register it with WithUsageRecorder, using
+// MultiUsageRecorder to keep another recorder. A SpendMonitor is safe for
+// concurrent use.
+type SpendMonitor struct {
+	window     time.Duration
+	multiple   float64
+	onAnomaly  func(ctx context.Context, anomaly SpendAnomaly)
+	history    int
+	minHistory int
+	floor      float64
+	now        func() time.Time
+
+	mu       sync.Mutex
+	start    time.Time
+	current  int64     // index of the current window since start
+	spend    float64   // spend in the current window
+	trailing []float64 // spend of the previous windows, oldest first
+	alerted  bool      // whether the current window was reported
+}
+
+// NewSpendMonitor creates a monitor calling onAnomaly, at most once per
+// window, when the spend in a window exceeds multiple times the trailing
+// average. onAnomaly runs on the sending goroutine and should return
+// quickly.
+func NewSpendMonitor(window time.Duration, multiple float64, onAnomaly func(ctx context.Context, anomaly SpendAnomaly), opts ...SpendMonitorOption) *SpendMonitor {
+	if window <= 0 {
+		window = time.Hour
+	}
+	m := &SpendMonitor{
+		window:     window,
+		multiple:   multiple,
+		onAnomaly:  onAnomaly,
+		history:    DefaultSpendHistory,
+		minHistory: DefaultSpendMinHistory,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// RecordUsage implements UsageRecorder.
+func (m *SpendMonitor) RecordUsage(ctx context.Context, usage Usage) {
+	if usage.Cost <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	m.rotateLocked()
+	m.spend += usage.Cost
+	anomaly, ok := m.checkLocked()
+	m.mu.Unlock()
+
+	if ok && m.onAnomaly != nil {
+		m.onAnomaly(ctx, anomaly)
+	}
+}
+
+// Spend returns the spend in the current window and the trailing average.
+func (m *SpendMonitor) Spend() (current, average float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rotateLocked()
+	return m.spend, m.averageLocked()
+}
+
+// rotateLocked moves to the window containing the current time, pushing
+// the windows that have ended, quiet ones included, into the history.
+func (m *SpendMonitor) rotateLocked() {
+	now := m.now()
+	if m.start.IsZero() {
+		m.start = now
+		return
+	}
+	index := int64(now.Sub(m.start) / m.window)
+	if index <= m.current {
+		return
+	}
+
+	m.trailing = append(m.trailing, m.spend)
+	for i := m.current + 1; i < index && i-m.current <= int64(m.history); i++ {
+		m.trailing = append(m.trailing, 0)
+	}
+	if len(m.trailing) > m.history {
+		m.trailing = m.trailing[len(m.trailing)-m.history:]
+	}
+	m.current = index
+	m.spend = 0
+	m.alerted = false
+}
+
+func (m *SpendMonitor) averageLocked() float64 {
+	if len(m.trailing) == 0 {
+		return 0
+	}
+	var total float64
+	for _, spend := range m.trailing {
+		total += spend
+	}
+	return total / float64(len(m.trailing))
+}
+
+// checkLocked returns the anomaly to report for the current window, if any.
+func (m *SpendMonitor) checkLocked() (SpendAnomaly, bool) {
+	if m.alerted || m.current < int64(m.minHistory) {
+		return SpendAnomaly{}, false
+	}
+	average := m.averageLocked()
+	if average <= 0 && m.floor <= 0 {
+		return SpendAnomaly{}, false
+	}
+	threshold := max(m.multiple*average, m.floor)
+	if m.spend <= threshold {
+		return SpendAnomaly{}, false
+	}
+
+	m.alerted = true
+	return SpendAnomaly{
+		WindowStart:     m.start.Add(time.Duration(m.current) * m.window),
+		Spend:           m.spend,
+		TrailingAverage: average,
+		Threshold:       threshold,
+	}, true
+}
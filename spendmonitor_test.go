@@ -0,0 +1,86 @@
+package signalads
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSpendMonitor_Anomaly(t *testing.T) {
+	var anomalies []SpendAnomaly
+	m := NewSpendMonitor(time.Minute, 3, func(ctx context.Context, anomaly SpendAnomaly) {
+		anomalies = append(anomalies, anomaly)
+	}, WithSpendMinHistory(2))
+
+	now := time.Date(2026, 3, 21, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Three ordinary windows of 100 each
+	for i := 0; i < 3; i++ {
+		m.RecordUsage(ctx, Usage{Cost: 100})
+		now = now.Add(time.Minute)
+	}
+	if len(anomalies) != 0 {
+		t.Fatalf("Expected no anomalies for steady spend, got %+v", anomalies)
+	}
+
+	// A send loop
+	for i := 0; i < 10; i++ {
+		m.RecordUsage(ctx, Usage{Cost: 100})
+	}
+	if len(anomalies) != 1 {
+		t.Fatalf("Expected 1 anomaly per window, got %d", len(anomalies))
+	}
+	a := anomalies[0]
+	if a.TrailingAverage != 100 || a.Threshold != 300 || a.Spend != 400 {
+		t.Errorf("Expected spend 400 over threshold 300, got %+v", a)
+	}
+	if want := now.Truncate(time.Minute); !a.WindowStart.Equal(want) {
+		t.Errorf("Expected window start %v, got %v", want, a.WindowStart)
+	}
+
+	current, average := m.Spend()
+	if current != 1000 || average != 100 {
+		t.Errorf("Expected 1000 this window against 100, got %v and %v", current, average)
+	}
+}
+
+func TestSpendMonitor_QuietWindowsLowerAverage(t *testing.T) {
+	var fired bool
+	m := NewSpendMonitor(time.Minute, 2, func(ctx context.Context, anomaly SpendAnomaly) {
+		fired = true
+	}, WithSpendHistory(4), WithSpendMinHistory(0))
+
+	now := time.Date(2026, 3, 21, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.RecordUsage(ctx, Usage{Cost: 400})
+	now = now.Add(4 * time.Minute)
+	m.RecordUsage(ctx, Usage{Cost: 250})
+
+	if _, average := m.Spend(); average != 100 {
+		t.Errorf("Expected quiet windows to count toward the average, got %v", average)
+	}
+	if !fired {
+		t.Error("Expected 250 after an average of 100 to be an anomaly")
+	}
+}
+
+func TestSpendMonitor_Floor(t *testing.T) {
+	var fired bool
+	m := NewSpendMonitor(time.Minute, 2, func(ctx context.Context, anomaly SpendAnomaly) {
+		fired = true
+	}, WithSpendMinHistory(0), WithSpendFloor(1000))
+	ctx := context.Background()
+
+	m.RecordUsage(ctx, Usage{Cost: 500})
+	if fired {
+		t.Error("Expected spend under the floor not to be an anomaly")
+	}
+	m.RecordUsage(ctx, Usage{Cost: 600})
+	if !fired {
+		t.Error("Expected spend over the floor to be an anomaly")
+	}
+}
@@ -0,0 +1,170 @@
+package signalads
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// MessageStatusCode is the canonical status of a message. The API spells
+// statuses differently across endpoints and gateways ("Delivered",
+// "DELIVRD", 10); NormalizeStatus maps them all to these values.
+type MessageStatusCode string
+
+// Canonical message statuses.
+const (
+	StatusPending     MessageStatusCode = "pending"
+	StatusQueued      MessageStatusCode = "queued"
+	StatusScheduled   MessageStatusCode = "scheduled"
+	StatusSent        MessageStatusCode = "sent"
+	StatusDelivered   MessageStatusCode = "delivered"
+	StatusUndelivered MessageStatusCode = "undelivered"
+	StatusFailed      MessageStatusCode = "failed"
+	StatusRejected    MessageStatusCode = "rejected"
+	StatusExpired     MessageStatusCode = "expired"
+	StatusCanceled    MessageStatusCode = "canceled"
+	StatusUnknown     MessageStatusCode = "unknown"
+)
+
+// statusSpellings maps lower-cased status spellings, including SMPP
+// delivery receipt states and the gateway's numeric codes, to canonical
+// statuses.
+var statusSpellings = map[string]MessageStatusCode{
+	"pending":       StatusPending,
+	"new":           StatusPending,
+	"processing":    StatusPending,
+	"queued":        StatusQueued,
+	"queue":         StatusQueued,
+	"1":             StatusQueued,
+	"scheduled":     StatusScheduled,
+	"2":             StatusScheduled,
+	"sent":          StatusSent,
+	"submitted":     StatusSent,
+	"accepted":      StatusSent,
+	"acceptd":       StatusSent,
+	"enroute":       StatusSent,
+	"4":             StatusSent,
+	"5":             StatusSent,
+	"delivered":     StatusDelivered,
+	"delivrd":       StatusDelivered,
+	"10":            StatusDelivered,
+	"undelivered":   StatusUndelivered,
+	"undeliv":       StatusUndelivered,
+	"undeliverable": StatusUndelivered,
+	"11":            StatusUndelivered,
+	"failed":        StatusFailed,
+	"error":         StatusFailed,
+	"6":             StatusFailed,
+	"rejected":      StatusRejected,
+	"rejectd":       StatusRejected,
+	"blocked":       StatusRejected,
+	"14":            StatusRejected,
+	"expired":       StatusExpired,
+	"canceled":      StatusCanceled,
+	"cancelled":     StatusCanceled,
+	"deleted":       StatusCanceled,
+	"13":            StatusCanceled,
+}
+
+// NormalizeStatus returns the canonical status for a status spelling, or
+// StatusUnknown if it is not recognized. An empty status stays empty.
+func NormalizeStatus(raw string) MessageStatusCode {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if key == "" {
+		return ""
+	}
+	if status, ok := statusSpellings[key]; ok {
+		return status
+	}
+	return StatusUnknown
+}
+
+// Terminal reports whether a message with status s can no longer change
+// state.
+func (s MessageStatusCode) Terminal() bool {
+	switch s {
+	case StatusDelivered, StatusUndelivered, StatusFailed, StatusRejected, StatusExpired, StatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeStatus reads a status given as a JSON string or number and returns
+// its text.
+func decodeStatus(data json.RawMessage) string {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return ""
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		raw = string(data)
+	}
+	return raw
+}
+
+// StatusCode returns the canonical form of Status.
+func (m *MessageStatus) StatusCode() MessageStatusCode {
+	return NormalizeStatus(m.Status)
+}
+
+// UnmarshalJSON accepts numeric statuses and derives Reason when the API
+// does not report one.
+func (m *MessageStatus) UnmarshalJSON(data []byte) error {
+	type plain MessageStatus
+	aux := struct {
+		*plain
+		Status json.RawMessage `json:"status"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.Status = decodeStatus(aux.Status)
+	if m.Reason == "" {
+		m.Reason = failureReason(m.ErrorCode, m.Error)
+	}
+	return nil
+}
+
+// StatusCode returns the canonical form of Status.
+func (m *Message) StatusCode() MessageStatusCode {
+	return NormalizeStatus(m.Status)
+}
+
+// UnmarshalJSON accepts numeric statuses.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type plain Message
+	aux := struct {
+		*plain
+		Status json.RawMessage `json:"status"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.Status = decodeStatus(aux.Status)
+	return nil
+}
+
+// StatusCode returns the canonical form of Status.
+func (e *DeliveryReportEvent) StatusCode() MessageStatusCode {
+	return NormalizeStatus(e.Status)
+}
+
+// UnmarshalJSON accepts numeric statuses and derives Reason when the API
+// does not report one.
+func (e *DeliveryReportEvent) UnmarshalJSON(data []byte) error {
+	type plain DeliveryReportEvent
+	aux := struct {
+		*plain
+		Status json.RawMessage `json:"status"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.Status = decodeStatus(aux.Status)
+	if e.Reason == "" {
+		e.Reason = failureReason(e.ErrorCode, e.Error)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want MessageStatusCode
+	}{
+		{"delivered", StatusDelivered},
+		{"Delivered", StatusDelivered},
+		{"DELIVRD", StatusDelivered},
+		{"10", StatusDelivered},
+		{" UNDELIV ", StatusUndelivered},
+		{"ACCEPTD", StatusSent},
+		{"REJECTD", StatusRejected},
+		{"cancelled", StatusCanceled},
+		{"error", StatusFailed},
+		{"1", StatusQueued},
+		{"", ""},
+		{"bogus", StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeStatus(tt.raw); got != tt.want {
+			t.Errorf("NormalizeStatus(%q): expected %q, got %q", tt.raw, tt.want, got)
+		}
+	}
+}
+
+func TestMessageStatusCode_Terminal(t *testing.T) {
+	for _, s := range []MessageStatusCode{StatusDelivered, StatusUndelivered, StatusFailed, StatusRejected, StatusExpired, StatusCanceled} {
+		if !s.Terminal() {
+			t.Errorf("Expected %q to be terminal", s)
+		}
+	}
+	for _, s := range []MessageStatusCode{StatusPending, StatusQueued, StatusScheduled, StatusSent, StatusUnknown} {
+		if s.Terminal() {
+			t.Errorf("Expected %q not to be terminal", s)
+		}
+	}
+}
+
+func TestMessageStatus_UnmarshalStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    MessageStatusCode
+		wantRaw string
+	}{
+		{"canonical", `{"id": "m1", "status": "delivered"}`, StatusDelivered, "delivered"},
+		{"smpp", `{"id": "m1", "status": "DELIVRD"}`, StatusDelivered, "DELIVRD"},
+		{"numeric", `{"id": "m1", "status": 11}`, StatusUndelivered, "11"},
+		{"missing", `{"id": "m1"}`, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status MessageStatus
+			if err := json.Unmarshal([]byte(tt.body), &status); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if status.ID != "m1" {
+				t.Errorf("Expected message ID m1, got %s", status.ID)
+			}
+			if status.StatusCode() != tt.want {
+				t.Errorf("Expected status %q, got %q", tt.want, status.StatusCode())
+			}
+			if status.Status != tt.wantRaw {
+				t.Errorf("Expected raw status %q, got %q", tt.wantRaw, status.Status)
+			}
+		})
+	}
+}
+
+func TestMessageStatus_RoundTrip(t *testing.T) {
+	in := MessageStatus{ID: "m1", Status: "REJECTD"}
+	body, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out MessageStatus
+	if err = json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.StatusCode() != StatusRejected || out.Status != "REJECTD" {
+		t.Errorf("Expected rejected/REJECTD, got %s/%s", out.StatusCode(), out.Status)
+	}
+}
+
+func TestListMessages_NormalizesStatus(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"messages": [{"id": "a", "status": "Delivered"}, {"id": "b", "status": 6}]}`))
+	})
+
+	resp, err := client.Messages.ListMessages(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resp.Messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(resp.Messages))
+	}
+	if resp.Messages[0].StatusCode() != StatusDelivered || resp.Messages[0].Status != "Delivered" {
+		t.Errorf("Expected delivered/Delivered, got %s/%s", resp.Messages[0].StatusCode(), resp.Messages[0].Status)
+	}
+	if resp.Messages[1].StatusCode() != StatusFailed || resp.Messages[1].Status != "6" {
+		t.Errorf("Expected failed/6, got %s/%s", resp.Messages[1].StatusCode(), resp.Messages[1].Status)
+	}
+}
+
+func TestDeliveryReportEvent_NormalizesStatus(t *testing.T) {
+	event := &WebhookEvent{Type: EventDeliveryReport, Data: json.RawMessage(`{"message_id": "m1", "status": "UNDELIV"}`)}
+	report, err := event.DeliveryReport()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.StatusCode() != StatusUndelivered || report.Status != "UNDELIV" {
+		t.Errorf("Expected undelivered/UNDELIV, got %s/%s", report.StatusCode(), report.Status)
+	}
+}
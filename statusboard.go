@@ -0,0 +1,163 @@
+package signalads
+
+import (
+	"container/list"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RecentStatusStore is an in-memory TrackerStore that keeps only recent
+// messages, so that status dashboards can be served from memory without
+// growing without bound. Messages are evicted, least recently updated
+// first, once the store holds more than its capacity, and dropped once
+// they have not been updated for longer than its maximum age.
+//
+// Use it with a StatusTracker registered for delivery report webhooks, and
+// run the tracker's poller to fill the gaps that missed webhooks leave:
+//
+//	store := signalads.NewRecentStatusStore(10000, 24*time.Hour)
+//	tracker := signalads.NewStatusTracker(store)
+//	tracker.Register(webhooks)
+//	go tracker.Run(ctx, client, time.Minute, 10*time.Minute)
+type RecentStatusStore struct {
+	capacity int
+	maxAge   time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	order    *list.List // of *TrackedMessage, most recently updated first
+	messages map[string]*list.Element
+}
+
+// NewRecentStatusStore creates a store holding at most capacity messages,
+// each for at most maxAge after its last update. A capacity or maxAge of
+// zero or less disables that limit.
+func NewRecentStatusStore(capacity int, maxAge time.Duration) *RecentStatusStore {
+	return &RecentStatusStore{
+		capacity: capacity,
+		maxAge:   maxAge,
+		now:      time.Now,
+		order:    list.New(),
+		messages: make(map[string]*list.Element),
+	}
+}
+
+// Save implements TrackerStore.
+func (s *RecentStatusStore) Save(_ context.Context, msg *TrackedMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *msg
+	if e, ok := s.messages[msg.MessageID]; ok {
+		e.Value = &stored
+		s.order.MoveToFront(e)
+	} else {
+		s.messages[msg.MessageID] = s.order.PushFront(&stored)
+	}
+	s.evictLocked()
+	return nil
+}
+
+// Get implements TrackerStore.
+func (s *RecentStatusStore) Get(_ context.Context, messageID string) (*TrackedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictLocked()
+	e, ok := s.messages[messageID]
+	if !ok {
+		return nil, ErrTrackedMessageNotFound
+	}
+	msg := *e.Value.(*TrackedMessage)
+	return &msg, nil
+}
+
+// Pending implements TrackerStore.
+func (s *RecentStatusStore) Pending(_ context.Context, sentBefore time.Time, limit int) ([]*TrackedMessage, error) {
+	pending := s.collect(func(msg *TrackedMessage) bool {
+		return !msg.Terminal() && msg.SentAt.Before(sentBefore)
+	})
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].SentAt.Before(pending[j].SentAt)
+	})
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+// Recent returns the messages with any of statuses, or all messages if
+// none are given, most recently updated first. A limit above zero caps the
+// number of messages returned.
+func (s *RecentStatusStore) Recent(limit int, statuses ...MessageStatusCode) []*TrackedMessage {
+	want := make(map[MessageStatusCode]bool, len(statuses))
+	for _, status := range statuses {
+		want[status] = true
+	}
+
+	recent := s.collect(func(msg *TrackedMessage) bool {
+		return len(want) == 0 || want[NormalizeStatus(msg.Status)]
+	})
+	if limit > 0 && len(recent) > limit {
+		recent = recent[:limit]
+	}
+	return recent
+}
+
+// Counts returns the number of stored messages in each status.
+func (s *RecentStatusStore) Counts() map[MessageStatusCode]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictLocked()
+	counts := make(map[MessageStatusCode]int)
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		counts[NormalizeStatus(e.Value.(*TrackedMessage).Status)]++
+	}
+	return counts
+}
+
+// Len returns the number of stored messages.
+func (s *RecentStatusStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictLocked()
+	return s.order.Len()
+}
+
+// collect returns copies of the stored messages that match keep, most
+// recently updated first.
+func (s *RecentStatusStore) collect(keep func(*TrackedMessage) bool) []*TrackedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictLocked()
+	var matched []*TrackedMessage
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		if msg := e.Value.(*TrackedMessage); keep(msg) {
+			m := *msg
+			matched = append(matched, &m)
+		}
+	}
+	return matched
+}
+
+// evictLocked drops the messages over capacity or past the maximum age.
+func (s *RecentStatusStore) evictLocked() {
+	var cutoff time.Time
+	if s.maxAge > 0 {
+		cutoff = s.now().Add(-s.maxAge)
+	}
+	for e := s.order.Back(); e != nil; e = s.order.Back() {
+		msg := e.Value.(*TrackedMessage)
+		overCapacity := s.capacity > 0 && s.order.Len() > s.capacity
+		if !overCapacity && (cutoff.IsZero() || !msg.UpdatedAt.Before(cutoff)) {
+			return
+		}
+		s.order.Remove(e)
+		delete(s.messages, msg.MessageID)
+	}
+}
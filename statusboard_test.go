@@ -0,0 +1,81 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecentStatusStore_EvictsOverCapacity(t *testing.T) {
+	store := NewRecentStatusStore(2, 0)
+	ctx := context.Background()
+	for _, id := range []string{"msg_1", "msg_2", "msg_3"} {
+		store.Save(ctx, &TrackedMessage{MessageID: id, Status: "sent", UpdatedAt: time.Now()})
+	}
+
+	if store.Len() != 2 {
+		t.Errorf("Expected 2 messages, got %d", store.Len())
+	}
+	if _, err := store.Get(ctx, "msg_1"); !errors.Is(err, ErrTrackedMessageNotFound) {
+		t.Errorf("Expected msg_1 to be evicted, got %v", err)
+	}
+
+	// Updating msg_2 makes msg_3 the least recently updated.
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_2", Status: "delivered", UpdatedAt: time.Now()})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_4", Status: "sent", UpdatedAt: time.Now()})
+	if _, err := store.Get(ctx, "msg_3"); !errors.Is(err, ErrTrackedMessageNotFound) {
+		t.Errorf("Expected msg_3 to be evicted, got %v", err)
+	}
+	if _, err := store.Get(ctx, "msg_2"); err != nil {
+		t.Errorf("Expected msg_2 to be kept, got %v", err)
+	}
+}
+
+func TestRecentStatusStore_EvictsByAge(t *testing.T) {
+	now := time.Now()
+	store := NewRecentStatusStore(0, time.Hour)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_old", Status: "delivered", UpdatedAt: now.Add(-2 * time.Hour)})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_new", Status: "sent", UpdatedAt: now})
+
+	if _, err := store.Get(ctx, "msg_old"); !errors.Is(err, ErrTrackedMessageNotFound) {
+		t.Errorf("Expected msg_old to be evicted, got %v", err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Expected 1 message, got %d", store.Len())
+	}
+}
+
+func TestRecentStatusStore_Queries(t *testing.T) {
+	store := NewRecentStatusStore(100, time.Hour)
+	ctx := context.Background()
+	now := time.Now()
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_1", Status: "DELIVRD", SentAt: now.Add(-time.Hour), UpdatedAt: now})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_2", Status: "sent", SentAt: now.Add(-time.Hour), UpdatedAt: now})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_3", Status: "failed", SentAt: now, UpdatedAt: now})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_4", Status: "sent", SentAt: now, UpdatedAt: now})
+
+	recent := store.Recent(0)
+	if len(recent) != 4 || recent[0].MessageID != "msg_4" {
+		t.Errorf("Expected 4 messages, most recent first, got %+v", recent)
+	}
+	if recent := store.Recent(1, StatusSent); len(recent) != 1 || recent[0].MessageID != "msg_4" {
+		t.Errorf("Expected msg_4, got %+v", recent)
+	}
+
+	counts := store.Counts()
+	if counts[StatusDelivered] != 1 || counts[StatusSent] != 2 || counts[StatusFailed] != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+
+	pending, err := store.Pending(ctx, now.Add(-time.Minute), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pending) != 1 || pending[0].MessageID != "msg_2" {
+		t.Errorf("Expected msg_2 pending, got %+v", pending)
+	}
+}
@@ -0,0 +1,103 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// WithStatusCache caches GetMessageStatus results for messages in a terminal
+// state, whose status no longer changes. A cached status is served as is for
+// ttl; for a further stale period it is still served but refreshed in the
+// background. Statuses of messages still in flight are never cached.
+func WithStatusCache(store CacheStore, ttl, stale time.Duration) ClientOption {
+	return func(c *Client) {
+		c.statusCache = &statusCache{
+			store:      store,
+			ttl:        ttl,
+			stale:      stale,
+			refreshing: make(map[string]bool),
+		}
+	}
+}
+
+type cacheBypassContextKey struct{}
+
+// WithCacheBypass returns a context whose requests skip the response and
+// status caches and always reach the API. Fresh results still update the
+// caches.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassContextKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassContextKey{}).(bool)
+	return bypass
+}
+
+type statusCache struct {
+	store CacheStore
+	ttl   time.Duration
+	stale time.Duration
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+type statusCacheEntry struct {
+	Status    MessageStatus `json:"status"`
+	FetchedAt time.Time     `json:"fetched_at"`
+}
+
+func (sc *statusCache) key(messageID string) string {
+	return "status:" + messageID
+}
+
+// get returns the cached status of messageID and whether it is past its ttl.
+func (sc *statusCache) get(messageID string) (status *MessageStatus, stale, ok bool) {
+	body, ok := sc.store.Get(sc.key(messageID))
+	if !ok {
+		return nil, false, false
+	}
+	var entry statusCacheEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, false, false
+	}
+	return &entry.Status, time.Since(entry.FetchedAt) > sc.ttl, true
+}
+
+func (sc *statusCache) set(messageID string, status *MessageStatus) {
+	if !status.StatusCode().Terminal() {
+		return
+	}
+	body, err := json.Marshal(statusCacheEntry{Status: *status, FetchedAt: time.Now()})
+	if err != nil {
+		return
+	}
+	sc.store.Set(sc.key(messageID), body, sc.ttl+sc.stale)
+}
+
+// startRefresh reports whether the caller should refresh messageID, making
+// sure only one refresh per message runs at a time.
+func (sc *statusCache) startRefresh(messageID string) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.refreshing[messageID] {
+		return false
+	}
+	sc.refreshing[messageID] = true
+	return true
+}
+
+func (sc *statusCache) endRefresh(messageID string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.refreshing, messageID)
+}
+
+// isTerminalStatus reports whether a message with status can no longer
+// change state.
+func isTerminalStatus(status string) bool {
+	return NormalizeStatus(status).Terminal()
+}
@@ -0,0 +1,100 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMessagesService_GetMessageStatus_Cache(t *testing.T) {
+	var requests atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/messages/msg_pending/status" {
+			w.Write([]byte(`{"id": "msg_pending", "status": "pending"}`))
+			return
+		}
+		w.Write([]byte(`{"id": "msg_1", "status": "delivered"}`))
+	}
+
+	client := setupTestClient(handler)
+	WithStatusCache(NewMemoryCache(), time.Minute, time.Minute)(client)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		status, err := client.Messages.GetMessageStatus(ctx, "msg_1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if status.Status != "delivered" {
+			t.Errorf("Expected status delivered, got %s", status.Status)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request for a terminal status, got %d", n)
+	}
+
+	if _, err := client.Messages.GetMessageStatus(WithCacheBypass(ctx), "msg_1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected bypass to reach the API, got %d requests", n)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Messages.GetMessageStatus(ctx, "msg_pending"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("Expected pending status not to be cached, got %d requests", n)
+	}
+}
+
+func TestMessagesService_GetMessageStatus_StaleWhileRevalidate(t *testing.T) {
+	refreshed := make(chan struct{}, 1)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "delivered", "cost": 2}`))
+		select {
+		case refreshed <- struct{}{}:
+		default:
+		}
+	}
+
+	client := setupTestClient(handler)
+	store := NewMemoryCache()
+	WithStatusCache(store, time.Minute, time.Hour)(client)
+
+	// Seed an entry fetched past its ttl but within the stale period.
+	store.Set("status:msg_1", []byte(`{"status":{"id":"msg_1","status":"delivered","cost":1},"fetched_at":"2000-01-01T00:00:00Z"}`), time.Hour)
+
+	status, err := client.Messages.GetMessageStatus(context.Background(), "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Cost != 1 {
+		t.Errorf("Expected the stale status to be served, got cost %v", status.Cost)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a background refresh")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		cached, stale, ok := client.statusCache.get("msg_1")
+		if ok && !stale && cached.Cost == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the refreshed status in the cache, got %+v", cached)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,133 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// streamList performs a GET request and decodes the JSON array found under
+// field in the response object one element at a time, calling fn for each.
+// A bare top-level array is also accepted. The response body is never held
+// in memory as a whole. Iteration stops at the first error returned by fn,
+// which is passed back unchanged.
+func streamList[T any](ctx context.Context, c *Client, endpoint string, queryParams map[string]string, field string, fn func(*T) error) error {
+	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, queryParams)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, err = c.readResponse(resp)
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	tok, err := dec.Token()
+	if err != nil {
+		return streamError(err)
+	}
+
+	switch tok {
+	case json.Delim('['):
+		return decodeArrayElements(dec, fn)
+	case json.Delim('{'):
+	default:
+		return &DecodeError{Err: fmt.Errorf("unexpected token %v", tok)}
+	}
+
+	envelope := make(map[string]json.RawMessage)
+	found, err := streamObject(dec, field, envelope, fn)
+	if err != nil || found {
+		return err
+	}
+	if apiErr := envelopeError(envelope); apiErr != nil {
+		normalizeErrorCode(apiErr)
+		c.localizeError(apiErr)
+		return apiErr
+	}
+	return nil
+}
+
+// streamObject scans the object whose opening brace dec has consumed for
+// the array under field, and reports whether it found it. The array may
+// also be nested in a "data" member, or be the "data" member itself, as in
+// response envelopes. When the array is not found, the closing brace is
+// left unread. Envelope members met on the way are collected in envelope,
+// if it is not nil; once they mark an error envelope, nothing more is
+// streamed, so that the caller can report the error.
+func streamObject[T any](dec *json.Decoder, field string, envelope map[string]json.RawMessage, fn func(*T) error) (bool, error) {
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return false, streamError(err)
+		}
+		key, _ := keyTok.(string)
+		if (key != field && key != "data") || isErrorEnvelope(envelope) {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return false, streamError(err)
+			}
+			if envelope != nil && envelopeKeys[key] {
+				envelope[key] = skip
+			}
+			continue
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return false, streamError(err)
+		}
+		switch {
+		case tok == json.Delim('['):
+			return true, decodeArrayElements(dec, fn)
+		case key == "data" && tok == json.Delim('{'):
+			found, nestedErr := streamObject(dec, field, nil, fn)
+			if found || nestedErr != nil {
+				return found, nestedErr
+			}
+			if _, closeErr := dec.Token(); closeErr != nil {
+				return false, streamError(closeErr)
+			}
+		case key == "data":
+			// A scalar data member; keep looking for field.
+		case tok == nil:
+			return true, nil
+		default:
+			return false, &DecodeError{Err: fmt.Errorf("field %q is not an array", field)}
+		}
+	}
+
+	return false, nil
+}
+
+func decodeArrayElements[T any](dec *json.Decoder, fn func(*T) error) error {
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return streamError(err)
+		}
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return streamError(err)
+	}
+	return nil
+}
+
+// streamError classifies an error from the streaming decoder: malformed JSON
+// is a decode error, anything else came from reading the connection.
+func streamError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &DecodeError{Err: err}
+	}
+	return &TransportError{Op: "failed to read response body", Err: err}
+}
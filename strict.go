@@ -0,0 +1,183 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnknownFieldWarning reports response fields the SDK does not model,
+// usually a sign that the API added fields since this SDK version.
+type UnknownFieldWarning struct {
+	// URL path of the request, such as "/api/v1/messages"
+	Endpoint string
+
+	// Go type the response was decoded into
+	Type string
+
+	// JSON paths of the unknown fields, such as "messages[0].channel"
+	Fields []string
+}
+
+// WithStrictDecoding makes the client check every decoded response for
+// fields it does not model and report them, once per response, to the
+// handler set with WithUnknownFieldHandler or, by default, as a warning on
+// the default slog logger. Decoding itself is unaffected.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// WithUnknownFieldHandler sets the handler of the warnings reported by
+// WithStrictDecoding, and enables strict decoding.
+func WithUnknownFieldHandler(fn func(ctx context.Context, warning UnknownFieldWarning)) ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+		c.unknownFieldHandler = fn
+	}
+}
+
+// checkUnknownFields reports the fields of body that v does not model.
+func (c *Client) checkUnknownFields(ctx context.Context, endpoint string, body []byte, v interface{}) {
+	if v == nil {
+		return
+	}
+	payload, apiErr := unwrapEnvelope(body)
+	if apiErr != nil {
+		return
+	}
+
+	t := reflect.TypeOf(v)
+	var fields []string
+	collectUnknownFields(payload, t, "", &fields)
+	if len(fields) == 0 {
+		return
+	}
+
+	warning := UnknownFieldWarning{Endpoint: endpoint, Type: strings.TrimPrefix(t.String(), "*"), Fields: fields}
+	if c.unknownFieldHandler != nil {
+		c.unknownFieldHandler(ctx, warning)
+		return
+	}
+	slog.WarnContext(ctx, "signalads: response has unknown fields",
+		"endpoint", warning.Endpoint, "type", warning.Type, "fields", warning.Fields)
+}
+
+// jsonAliaser is implemented by types whose UnmarshalJSON accepts names
+// besides those of their fields.
+type jsonAliaser interface {
+	jsonAliases() []string
+}
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	paginationType = reflect.TypeOf(Pagination{})
+	sdkPackage     = paginationType.PkgPath()
+)
+
+// collectUnknownFields appends to fields the paths of members of data that
+// have no counterpart in t. Types from other packages, such as time.Time,
+// and untyped values are not inspected.
+func collectUnknownFields(data []byte, t reflect.Type, path string, fields *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.PkgPath() != sdkPackage {
+			return
+		}
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return
+		}
+		known := knownJSONFields(t)
+		keys := make([]string, 0, len(members))
+		for key := range members {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldType, ok := known[strings.ToLower(key)]
+			switch {
+			case !ok:
+				*fields = append(*fields, joinPath(path, key))
+			case fieldType != nil:
+				collectUnknownFields(members[key], fieldType, joinPath(path, key), fields)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if t == rawMessageType {
+			return
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return
+		}
+		for i, item := range items {
+			collectUnknownFields(item, t.Elem(), path+"["+strconv.Itoa(i)+"]", fields)
+		}
+	case reflect.Map:
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return
+		}
+		for key, value := range members {
+			collectUnknownFields(value, t.Elem(), joinPath(path, key), fields)
+		}
+	}
+}
+
+// knownJSONFields maps the lower-cased JSON names t accepts to the type of
+// their field, or to nil for names whose value is not inspected.
+func knownJSONFields(t reflect.Type) map[string]reflect.Type {
+	known := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range knownJSONFields(f.Type) {
+				known[k] = v
+			}
+			continue
+		}
+		if f.Type == paginationType {
+			for k := range knownJSONFields(reflect.TypeOf(rawPagination{})) {
+				known[k] = nil
+			}
+			known["meta"], known["pagination"] = nil, nil
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		known[strings.ToLower(name)] = f.Type
+	}
+
+	if aliaser, ok := reflect.New(t).Interface().(jsonAliaser); ok {
+		for _, alias := range aliaser.jsonAliases() {
+			known[strings.ToLower(alias)] = nil
+		}
+	}
+	return known
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonAliases implements jsonAliaser.
+func (f *BulkFailure) jsonAliases() []string {
+	return []string{"to", "phone", "error_code", "error"}
+}
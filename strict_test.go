@@ -0,0 +1,67 @@
+package signalads
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStrictDecoding_UnknownFields(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"messages": [{"id": "m1", "channel": "sms", "sent_at": "2026-03-01T10:00:00Z"}],
+			"meta": {"page": 1},
+			"total": 1,
+			"region": "eu"
+		}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var warnings []UnknownFieldWarning
+	client := NewClient("key", "secret", WithBaseURL(server.URL), WithUnknownFieldHandler(func(ctx context.Context, warning UnknownFieldWarning) {
+		warnings = append(warnings, warning)
+	}))
+
+	response, err := client.Messages.ListMessages(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Messages) != 1 {
+		t.Errorf("Expected decoding to be unaffected, got %d messages", len(response.Messages))
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d", len(warnings))
+	}
+	w := warnings[0]
+	if w.Endpoint != "/messages" || w.Type != "signalads.ListMessagesResponse" {
+		t.Errorf("Unexpected warning source: %+v", w)
+	}
+	if want := []string{"messages[0].channel", "region"}; !reflect.DeepEqual(w.Fields, want) {
+		t.Errorf("Expected fields %v, got %v", want, w.Fields)
+	}
+}
+
+func TestStrictDecoding_KnownAliases(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"total": 1, "failed_items": [{"index": 0, "phone": "+989123456789", "error_code": "X"}], "data": {"anything": 1}}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("key", "secret", WithBaseURL(server.URL), WithUnknownFieldHandler(func(ctx context.Context, warning UnknownFieldWarning) {
+		t.Errorf("Expected no warning, got %+v", warning)
+	}))
+
+	_, err := client.Messages.SendBulkMessage(context.Background(), []BulkMessageItem{{To: "+989123456789", Message: "hi"}}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
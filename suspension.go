@@ -0,0 +1,61 @@
+package signalads
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrAccountSuspended matches, with errors.Is, any error the API returns
+// because the account is suspended or blocked.
+var ErrAccountSuspended = &APIError{
+	Code:       ErrCodeAccountSuspended,
+	Message:    "Account suspended",
+	StatusCode: http.StatusForbidden,
+}
+
+// suspendedCodes are the error codes the API uses for suspended or blocked
+// accounts, besides ErrCodeAccountSuspended.
+var suspendedCodes = map[string]bool{
+	"ACCOUNT_BLOCKED":  true,
+	"ACCOUNT_DISABLED": true,
+	"USER_SUSPENDED":   true,
+	"USER_BLOCKED":     true,
+}
+
+// suspendedWords identify suspension in the message of a 401 or 403
+// response that also mentions the account or user.
+var suspendedWords = []string{"suspend", "block", "deactivat", "disabled"}
+
+// IsAccountSuspended reports whether err is or wraps an API error saying
+// the account is suspended or blocked. Such errors are not retryable: every
+// request fails until the account is reinstated in the panel.
+func IsAccountSuspended(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == ErrCodeAccountSuspended
+}
+
+// normalizeErrorCode rewrites the code of errors that report a suspended
+// account in one of the API's other ways to ErrCodeAccountSuspended.
+func normalizeErrorCode(apiErr *APIError) {
+	if apiErr.Code == ErrCodeAccountSuspended {
+		return
+	}
+	if suspendedCodes[strings.ToUpper(apiErr.Code)] {
+		apiErr.Code = ErrCodeAccountSuspended
+		return
+	}
+	if apiErr.StatusCode != http.StatusForbidden && apiErr.StatusCode != http.StatusUnauthorized {
+		return
+	}
+	text := strings.ToLower(apiErr.Message + " " + apiErr.ErrorMsg)
+	if !strings.Contains(text, "account") && !strings.Contains(text, "user") {
+		return
+	}
+	for _, word := range suspendedWords {
+		if strings.Contains(text, word) {
+			apiErr.Code = ErrCodeAccountSuspended
+			return
+		}
+	}
+}
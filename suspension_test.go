@@ -0,0 +1,45 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestIsAccountSuspended(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		suspended bool
+	}{
+		{name: "suspended code", status: http.StatusForbidden, body: `{"code": "ACCOUNT_SUSPENDED", "message": "Suspended"}`, suspended: true},
+		{name: "blocked code", status: http.StatusForbidden, body: `{"code": "account_blocked", "message": "Blocked"}`, suspended: true},
+		{name: "generic 403 message", status: http.StatusForbidden, body: `{"message": "Your account has been suspended"}`, suspended: true},
+		{name: "unstructured 403", status: http.StatusForbidden, body: `user is deactivated`, suspended: true},
+		{name: "plain forbidden", status: http.StatusForbidden, body: `{"message": "Permission denied"}`},
+		{name: "blocked recipient", status: http.StatusForbidden, body: `{"message": "Recipient number is blocked"}`},
+		{name: "bad request", status: http.StatusBadRequest, body: `{"message": "Account name suspended in field"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Messages.GetUserInfo(context.Background())
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if got := IsAccountSuspended(err); got != tt.suspended {
+				t.Errorf("Expected IsAccountSuspended %v, got %v for %v", tt.suspended, got, err)
+			}
+			if got := errors.Is(err, ErrAccountSuspended); got != tt.suspended {
+				t.Errorf("Expected errors.Is(err, ErrAccountSuspended) %v, got %v", tt.suspended, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTemplateResolverMinRefresh is how often a TemplateResolver may
+// list the templates again to resolve an unknown name.
+const DefaultTemplateResolverMinRefresh = time.Minute
+
+// TemplateResolverOption configures a TemplateResolver.
+type TemplateResolverOption func(*TemplateResolver)
+
+// WithTemplateResolverMinRefresh sets how long a TemplateResolver waits
+// after listing the templates before a miss may list them again, so that a
+// misspelt name does not list the templates on every send. It defaults to
+// DefaultTemplateResolverMinRefresh.
+func WithTemplateResolverMinRefresh(d time.Duration) TemplateResolverOption {
+	return func(r *TemplateResolver) {
+		r.minRefresh = d
+	}
+}
+
+// TemplateResolver maps template names, such as "otp-login", to the IDs the
+// account gave them, so that code does not hard-code IDs that differ
+// between accounts. The names are cached and listed again when a name is
+// missing from the cache. A TemplateResolver is safe for concurrent use.
+type TemplateResolver struct {
+	templates  *TemplatesService
+	minRefresh time.Duration
+	now        func() time.Time
+
+	refreshMu sync.Mutex // serializes refreshes
+
+	mu          sync.RWMutex
+	ids         map[string]string // template name to ID
+	refreshedAt time.Time
+}
+
+// Resolver creates a TemplateResolver for the account's templates. The
+// templates are first listed on the first Resolve.
+func (s *TemplatesService) Resolver(opts ...TemplateResolverOption) *TemplateResolver {
+	r := &TemplateResolver{
+		templates:  s,
+		minRefresh: DefaultTemplateResolverMinRefresh,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Resolve returns the ID of the template named name. If name is not cached
+// the templates are listed again, unless they were listed less than the
+// minimum refresh interval ago. The error wraps ErrTemplateNotFound if no
+// template has the name.
+func (r *TemplateResolver) Resolve(ctx context.Context, name string) (string, error) {
+	if id, ok := r.lookup(name); ok {
+		return id, nil
+	}
+
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	// Another caller may have refreshed while this one waited.
+	if id, ok := r.lookup(name); ok {
+		return id, nil
+	}
+	r.mu.RLock()
+	recent := !r.refreshedAt.IsZero() && r.now().Sub(r.refreshedAt) < r.minRefresh
+	r.mu.RUnlock()
+	if !recent {
+		if err := r.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+		if id, ok := r.lookup(name); ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no template named %q", ErrTemplateNotFound, name)
+}
+
+// Refresh lists the templates and replaces the cached names.
+func (r *TemplateResolver) Refresh(ctx context.Context) error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	return r.refreshLocked(ctx)
+}
+
+func (r *TemplateResolver) refreshLocked(ctx context.Context) error {
+	templates, err := listAllTemplates(ctx, r.templates)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]string, len(templates))
+	for i := range templates {
+		if templates[i].Name != "" {
+			ids[templates[i].Name] = templates[i].ID
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = ids
+	r.refreshedAt = r.now()
+	return nil
+}
+
+func (r *TemplateResolver) lookup(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.ids[name]
+	return id, ok
+}
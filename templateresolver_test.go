@@ -0,0 +1,80 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTemplateResolver_Resolve(t *testing.T) {
+	var mu sync.Mutex
+	var lists int32
+	templates := []Template{{ID: "1001", Name: "otp-login"}}
+
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lists, 1)
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListTemplatesResponse{Templates: templates, Page: 1, PerPage: 100, Total: len(templates)})
+	})
+	resolver := client.Templates.Resolver(WithTemplateResolverMinRefresh(time.Hour))
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	id, err := resolver.Resolve(ctx, "otp-login")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "1001" {
+		t.Errorf("Expected ID 1001, got %s", id)
+	}
+	if _, err = resolver.Resolve(ctx, "otp-login"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&lists); got != 1 {
+		t.Errorf("Expected cached name to list templates once, got %d", got)
+	}
+
+	// A template added in the panel is found once the interval has passed.
+	mu.Lock()
+	templates = append(templates, Template{ID: "1002", Name: "welcome"})
+	mu.Unlock()
+
+	_, err = resolver.Resolve(ctx, "welcome")
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound within the refresh interval, got %v", err)
+	}
+	if got := atomic.LoadInt32(&lists); got != 1 {
+		t.Errorf("Expected no listing within the refresh interval, got %d", got)
+	}
+
+	now = now.Add(time.Hour)
+	id, err = resolver.Resolve(ctx, "welcome")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "1002" {
+		t.Errorf("Expected ID 1002, got %s", id)
+	}
+	if got := atomic.LoadInt32(&lists); got != 2 {
+		t.Errorf("Expected the miss to list templates again, got %d", got)
+	}
+}
+
+func TestTemplateResolver_ListError(t *testing.T) {
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := client.Templates.Resolver().Resolve(context.Background(), "otp-login")
+	if err == nil || errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected listing error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TemplatesService provides methods for managing message templates.
+type TemplatesService struct {
+	client *Client
+}
+
+// ListTemplates retrieves the account's message templates.
+func (s *TemplatesService) ListTemplates(ctx context.Context, params *PaginationParams) (*ListTemplatesResponse, error) {
+	var response ListTemplatesResponse
+	if err := s.client.Get(ctx, "/templates", &response, paginationQuery(params)); err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetUsageStats retrieves send counts, delivery rate and cost for a
+// template. A zero dateRange bound leaves that side of the range open.
+func (s *TemplatesService) GetUsageStats(ctx context.Context, templateID string, dateRange DateRange) (*TemplateUsageStats, error) {
+	var v validator
+	v.required("template_id", templateID, "template ID is required")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	var stats TemplateUsageStats
+	if err := s.client.Get(ctx, "/templates/"+pathSegment(templateID)+"/stats", &stats, dateRange.query()); err != nil {
+		return nil, fmt.Errorf("failed to get template usage stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// query encodes the range as from and to dates.
+func (r DateRange) query() map[string]string {
+	queryParams := make(map[string]string, 2)
+	if !r.From.IsZero() {
+		queryParams["from"] = r.From.Format(time.DateOnly)
+	}
+	if !r.To.IsZero() {
+		queryParams["to"] = r.To.Format(time.DateOnly)
+	}
+	return queryParams
+}
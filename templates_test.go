@@ -0,0 +1,59 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTemplatesService_GetUsageStats(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/templates/otp-login/stats" {
+			t.Errorf("Expected path '/templates/otp-login/stats', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("from") != "2026-01-01" {
+			t.Errorf("Expected from '2026-01-01', got '%s'", r.URL.Query().Get("from"))
+		}
+		if r.URL.Query().Has("to") {
+			t.Errorf("Expected no 'to' for an open range, got '%s'", r.URL.Query().Get("to"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TemplateUsageStats{
+			TemplateID:   "otp-login",
+			Sent:         1000,
+			Delivered:    940,
+			Failed:       60,
+			DeliveryRate: 0.94,
+			Cost:         120000,
+		})
+	}
+
+	client := setupTestClient(handler)
+	stats, err := client.Templates.GetUsageStats(context.Background(), "otp-login", DateRange{
+		From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Sent != 1000 || stats.DeliveryRate != 0.94 {
+		t.Errorf("Expected 1000 sent at 0.94, got %d at %.2f", stats.Sent, stats.DeliveryRate)
+	}
+}
+
+func TestTemplatesService_GetUsageStats_Validation(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request without a template ID")
+	}
+
+	client := setupTestClient(handler)
+	_, err := client.Templates.GetUsageStats(context.Background(), "", DateRange{})
+	if !IsValidationError(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Template parameter types reported in TemplateParam.Type.
+const (
+	TemplateParamString = "string"
+	TemplateParamNumber = "number"
+
+	// Only the digits 0 to 9, keeping leading zeros, as in OTP codes
+	TemplateParamDigits = "digits"
+)
+
+// WithTemplateSchemas makes SendTemplateMessage, and the OTP and experiment
+// sends built on it, check template parameters against the schemas in
+// store before sending, so that an over-long or non-numeric value fails
+// with a *ValidationError instead of being rejected by the API. Keep store
+// current with TemplatesService.SyncTo. Templates missing from store are
+// sent unchecked.
+func WithTemplateSchemas(store TemplateStore) ClientOption {
+	return func(c *Client) {
+		c.templateSchemas = store
+	}
+}
+
+// checkTemplateParams validates the parameters of req against the schema of
+// its template, if the client has one.
+func (c *Client) checkTemplateParams(ctx context.Context, req *SendTemplateMessageRequest) error {
+	if c.templateSchemas == nil {
+		return nil
+	}
+	stored, err := c.templateSchemas.Get(ctx, req.TemplateID)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get template %s: %w", req.TemplateID, err)
+	}
+	return stored.Template.Validate(req.TemplateParams)
+}
@@ -0,0 +1,90 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithTemplateSchemas(t *testing.T) {
+	var calls int32
+	client := setupTestClient(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-123", Status: "sent"})
+	})
+	store := NewMemoryTemplateStore()
+	store.Save(context.Background(), &StoredTemplate{Template: Template{
+		ID:     "tpl-otp",
+		Body:   "Your code is {code}",
+		Params: []TemplateParam{{Name: "code", Type: TemplateParamDigits, Required: true, MaxLength: 8}},
+	}})
+	WithTemplateSchemas(store)(client)
+	ctx := context.Background()
+
+	_, err := client.Messages.SendOTP(ctx, "+989123456789", "123456789", "tpl-otp")
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if verr.Errors[0].Field != "template_params.code" || verr.Errors[0].Rule != RuleMaxLength {
+		t.Errorf("Expected max_length error on template_params.code, got %+v", verr.Errors[0])
+	}
+
+	if _, err = client.Messages.SendOTP(ctx, "+989123456789", "12a4", "tpl-otp"); !IsValidationError(err) {
+		t.Errorf("Expected validation error for non-digit code, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no API calls for invalid parameters, got %d", got)
+	}
+
+	if _, err = client.Messages.SendOTP(ctx, "+989123456789", "012345", "tpl-otp"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err = client.Messages.SendTemplate(ctx, "+989123456789", "tpl-unknown", map[string]string{"any": "value"}); err != nil {
+		t.Errorf("Expected template missing from the store to be sent unchecked, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 API calls, got %d", got)
+	}
+}
+
+func TestTemplate_ValidateConstraints(t *testing.T) {
+	tpl := &Template{Params: []TemplateParam{
+		{Name: "code", Type: TemplateParamDigits, MinLength: 4},
+		{Name: "ref", Pattern: "[A-Z]{3}-[0-9]+"},
+	}}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		rule   string
+	}{
+		{name: "valid", params: map[string]string{"code": "0042", "ref": "ORD-17"}},
+		{name: "optional omitted", params: map[string]string{}},
+		{name: "too short", params: map[string]string{"code": "042"}, rule: RuleMinLength},
+		{name: "not digits", params: map[string]string{"code": "4.25"}, rule: RuleEncoding},
+		{name: "pattern mismatch", params: map[string]string{"ref": "ORD-17x"}, rule: RulePattern},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tpl.Validate(tt.params)
+			if tt.rule == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected validation error, got %v", err)
+			}
+			if verr.Errors[0].Rule != tt.rule {
+				t.Errorf("Expected rule %s, got %s", tt.rule, verr.Errors[0].Rule)
+			}
+		})
+	}
+}
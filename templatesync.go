@@ -0,0 +1,306 @@
+package signalads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrTemplateNotFound is returned by TemplateStore.Get for unknown templates.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// StoredTemplate is a template as mirrored into a TemplateStore.
+type StoredTemplate struct {
+	Template Template `json:"template"`
+
+	// Digest of the template's content, used to detect edits
+	Hash string `json:"hash"`
+
+	// When the template was last written to the store
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// TemplateStore holds a local copy of the template catalogue.
+// Implementations must be safe for concurrent use.
+type TemplateStore interface {
+	// List returns all stored templates.
+	List(ctx context.Context) ([]StoredTemplate, error)
+
+	// Get returns the template with id, or ErrTemplateNotFound.
+	Get(ctx context.Context, id string) (*StoredTemplate, error)
+
+	// Save inserts or replaces a template.
+	Save(ctx context.Context, tpl *StoredTemplate) error
+
+	// Delete removes a template. Deleting an unknown template is not an
+	// error.
+	Delete(ctx context.Context, id string) error
+}
+
+// TemplateSyncResult reports what SyncTo changed in the store.
+type TemplateSyncResult struct {
+	// IDs of templates new to the store
+	Added []string
+
+	// IDs of templates edited in the panel since the last sync
+	Updated []string
+
+	// IDs of templates no longer in the panel, removed from the store
+	Removed []string
+
+	// Number of templates that did not change
+	Unchanged int
+}
+
+// Changed reports whether the sync modified the store.
+func (r *TemplateSyncResult) Changed() bool {
+	return len(r.Added) > 0 || len(r.Updated) > 0 || len(r.Removed) > 0
+}
+
+// SyncTo mirrors the account's templates, with their parameter schemas,
+// into store. Templates whose content is unchanged are not rewritten, and
+// templates deleted in the panel are removed from store. If listing fails,
+// store is left untouched. If writing to store fails, the error is a
+// *PartialResult listing the IDs of the templates synced and not synced.
+func (s *TemplatesService) SyncTo(ctx context.Context, store TemplateStore) (*TemplateSyncResult, error) {
+	remote, err := listAllTemplates(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored templates: %w", err)
+	}
+	hashes := make(map[string]string, len(local))
+	for i := range local {
+		hashes[local[i].Template.ID] = local[i].Hash
+	}
+
+	result := &TemplateSyncResult{}
+	now := time.Now()
+	seen := make(map[string]bool, len(remote))
+	for i := range remote {
+		seen[remote[i].ID] = true
+	}
+	var stale []string
+	for id := range hashes {
+		if !seen[id] {
+			stale = append(stale, id)
+		}
+	}
+	sort.Strings(stale)
+
+	// synced lists the templates reconciled so far, for the partial result.
+	var synced []string
+	partial := func(failed []string, err error) *PartialResult {
+		return &PartialResult{Completed: synced, Failed: append(failed, stale...), Err: err}
+	}
+	for i := range remote {
+		tpl := &remote[i]
+		hash, hashErr := tpl.hash()
+		if hashErr != nil {
+			return result, partial(templateIDs(remote[i:]), hashErr)
+		}
+		old, exists := hashes[tpl.ID]
+		if exists && old == hash {
+			result.Unchanged++
+			synced = append(synced, tpl.ID)
+			continue
+		}
+
+		if err := store.Save(ctx, &StoredTemplate{Template: *tpl, Hash: hash, SyncedAt: now}); err != nil {
+			return result, partial(templateIDs(remote[i:]), fmt.Errorf("failed to save template %s: %w", tpl.ID, err))
+		}
+		if exists {
+			result.Updated = append(result.Updated, tpl.ID)
+		} else {
+			result.Added = append(result.Added, tpl.ID)
+		}
+		synced = append(synced, tpl.ID)
+	}
+
+	for len(stale) > 0 {
+		id := stale[0]
+		if err := store.Delete(ctx, id); err != nil {
+			return result, partial(nil, fmt.Errorf("failed to delete template %s: %w", id, err))
+		}
+		stale = stale[1:]
+		result.Removed = append(result.Removed, id)
+		synced = append(synced, id)
+	}
+
+	return result, nil
+}
+
+func templateIDs(templates []Template) []string {
+	ids := make([]string, len(templates))
+	for i := range templates {
+		ids[i] = templates[i].ID
+	}
+	return ids
+}
+
+// listAllTemplates fetches every page of templates from api.
+func listAllTemplates(ctx context.Context, api TemplatesAPI) ([]Template, error) {
+	params := &PaginationParams{Page: 1, PerPage: 100}
+	var templates []Template
+	for {
+		response, err := api.ListTemplates(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, response.Templates...)
+		if len(response.Templates) == 0 || !response.Pagination.HasNext {
+			return templates, nil
+		}
+		params.Page++
+	}
+}
+
+// hash returns a digest of the template's content. UpdatedAt is left out so
+// that a touch without an edit does not count as a change.
+func (t *Template) hash() (string, error) {
+	content := *t
+	content.UpdatedAt = time.Time{}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode template %s: %w", t.ID, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Validate checks params against the template's parameter schema: required
+// parameters must be present and non-empty, values must fit MinLength and
+// MaxLength, match the parameter's Type and Pattern, and unknown parameters
+// are rejected. A Pattern that does not compile is ignored.
+func (t *Template) Validate(params map[string]string) error {
+	var v validator
+	known := make(map[string]bool, len(t.Params))
+	for _, p := range t.Params {
+		known[p.Name] = true
+		field := "template_params." + p.Name
+		value, ok := params[p.Name]
+		if p.Required && (!ok || value == "") {
+			v.add(field, RuleRequired, "parameter "+strconv.Quote(p.Name)+" is required")
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		length := utf8.RuneCountInString(value)
+		if p.MinLength > 0 && length < p.MinLength {
+			v.add(field, RuleMinLength, fmt.Sprintf("parameter %q is shorter than %d characters", p.Name, p.MinLength))
+		}
+		if p.MaxLength > 0 && length > p.MaxLength {
+			v.add(field, RuleMaxLength, fmt.Sprintf("parameter %q is longer than %d characters", p.Name, p.MaxLength))
+		}
+		switch p.Type {
+		case TemplateParamNumber:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				v.add(field, RuleEncoding, fmt.Sprintf("parameter %q must be a number", p.Name))
+			}
+		case TemplateParamDigits:
+			if strings.Trim(value, "0123456789") != "" {
+				v.add(field, RuleEncoding, fmt.Sprintf("parameter %q must contain only digits", p.Name))
+			}
+		}
+		if p.Pattern != "" {
+			if re, err := regexp.Compile("^(?:" + p.Pattern + ")$"); err == nil && !re.MatchString(value) {
+				v.add(field, RulePattern, fmt.Sprintf("parameter %q does not match %s", p.Name, p.Pattern))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.add("template_params."+name, RuleUnknown, "template has no parameter "+strconv.Quote(name))
+	}
+	return v.err()
+}
+
+// Render validates params and returns the template body with each
+// "{name}" placeholder replaced by its value.
+func (t *Template) Render(params map[string]string) (string, error) {
+	if err := t.Validate(params); err != nil {
+		return "", err
+	}
+	pairs := make([]string, 0, 2*len(t.Params))
+	for _, p := range t.Params {
+		pairs = append(pairs, "{"+p.Name+"}", params[p.Name])
+	}
+	return strings.NewReplacer(pairs...).Replace(t.Body), nil
+}
+
+// MemoryTemplateStore is a TemplateStore that keeps templates in memory.
+type MemoryTemplateStore struct {
+	mu        sync.Mutex
+	templates map[string]StoredTemplate
+}
+
+// NewMemoryTemplateStore creates an empty MemoryTemplateStore.
+func NewMemoryTemplateStore() *MemoryTemplateStore {
+	return &MemoryTemplateStore{templates: make(map[string]StoredTemplate)}
+}
+
+// List implements TemplateStore. Templates are sorted by ID.
+func (s *MemoryTemplateStore) List(_ context.Context) ([]StoredTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := make([]StoredTemplate, 0, len(s.templates))
+	for _, tpl := range s.templates {
+		templates = append(templates, tpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Template.ID < templates[j].Template.ID
+	})
+	return templates, nil
+}
+
+// Get implements TemplateStore.
+func (s *MemoryTemplateStore) Get(_ context.Context, id string) (*StoredTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tpl, ok := s.templates[id]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+	return &tpl, nil
+}
+
+// Save implements TemplateStore.
+func (s *MemoryTemplateStore) Save(_ context.Context, tpl *StoredTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates[tpl.Template.ID] = *tpl
+	return nil
+}
+
+// Delete implements TemplateStore.
+func (s *MemoryTemplateStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.templates, id)
+	return nil
+}
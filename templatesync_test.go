@@ -0,0 +1,127 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTemplatesService_SyncTo(t *testing.T) {
+	var mu sync.Mutex
+	templates := []Template{
+		{ID: "otp", Body: "Code: {code}", Params: []TemplateParam{{Name: "code", Type: "number", Required: true}}},
+		{ID: "welcome", Body: "Hi {name}", Params: []TemplateParam{{Name: "name", MaxLength: 10}}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates" {
+			t.Errorf("Expected path '/templates', got '%s'", r.URL.Path)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListTemplatesResponse{Templates: templates, Page: 1, PerPage: 100, Total: len(templates)})
+	}
+
+	client := setupTestClient(handler)
+	store := NewMemoryTemplateStore()
+	ctx := context.Background()
+
+	result, err := client.Templates.SyncTo(ctx, store)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Added, []string{"otp", "welcome"}) {
+		t.Errorf("Expected both templates added, got %v", result.Added)
+	}
+
+	result, err = client.Templates.SyncTo(ctx, store)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Changed() || result.Unchanged != 2 {
+		t.Errorf("Expected no changes on resync, got %+v", result)
+	}
+
+	mu.Lock()
+	templates = []Template{{ID: "otp", Body: "Your code: {code}", Params: templates[0].Params}}
+	mu.Unlock()
+
+	result, err = client.Templates.SyncTo(ctx, store)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Updated, []string{"otp"}) {
+		t.Errorf("Expected otp updated, got %v", result.Updated)
+	}
+	if !reflect.DeepEqual(result.Removed, []string{"welcome"}) {
+		t.Errorf("Expected welcome removed, got %v", result.Removed)
+	}
+
+	stored, err := store.Get(ctx, "otp")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored.Template.Body != "Your code: {code}" {
+		t.Errorf("Expected the edited body, got '%s'", stored.Template.Body)
+	}
+	if _, err := store.Get(ctx, "welcome"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func TestTemplatesService_SyncTo_ListError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	client := setupTestClient(handler)
+	store := NewMemoryTemplateStore()
+	store.Save(context.Background(), &StoredTemplate{Template: Template{ID: "otp"}})
+
+	if _, err := client.Templates.SyncTo(context.Background(), store); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if _, err := store.Get(context.Background(), "otp"); err != nil {
+		t.Errorf("Expected the store to be left untouched, got %v", err)
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tpl := &Template{
+		Body: "Hi {name}, your code is {code}",
+		Params: []TemplateParam{
+			{Name: "name", MaxLength: 5},
+			{Name: "code", Type: "number", Required: true},
+		},
+	}
+
+	text, err := tpl.Render(map[string]string{"name": "Sara", "code": "1234"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if text != "Hi Sara, your code is 1234" {
+		t.Errorf("Expected rendered text, got '%s'", text)
+	}
+
+	_, err = tpl.Render(map[string]string{"name": "Alexander", "code": "x", "extra": "1"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	rules := make([]string, 0, len(verr.Errors))
+	for _, e := range verr.Errors {
+		rules = append(rules, e.Rule)
+	}
+	if !reflect.DeepEqual(rules, []string{RuleMaxLength, RuleEncoding, RuleUnknown}) {
+		t.Errorf("Expected max_length, encoding and unknown errors, got %v", rules)
+	}
+
+	if err := tpl.Validate(map[string]string{}); !IsValidationError(err) {
+		t.Errorf("Expected missing required parameter to fail, got %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TrackedMessage is the delivery state of a sent message recorded by a
+// StatusTracker.
+type TrackedMessage struct {
+	MessageID string    `json:"message_id"`
+	To        string    `json:"to"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Terminal reports whether the message has reached a final status.
+func (m *TrackedMessage) Terminal() bool {
+	return isTerminalStatus(m.Status)
+}
+
+// TrackerStore persists tracked messages. Implementations must be safe for
+// concurrent use.
+type TrackerStore interface {
+	// Save inserts msg or replaces the stored message with the same ID.
+	Save(ctx context.Context, msg *TrackedMessage) error
+
+	// Get returns the message with the given ID, or
+	// ErrTrackedMessageNotFound.
+	Get(ctx context.Context, messageID string) (*TrackedMessage, error)
+
+	// Pending returns messages in a non-terminal status sent before the
+	// given time, oldest first. A limit above zero caps the number of
+	// messages returned.
+	Pending(ctx context.Context, sentBefore time.Time, limit int) ([]*TrackedMessage, error)
+}
+
+// ErrTrackedMessageNotFound is returned by TrackerStore.Get for unknown IDs.
+var ErrTrackedMessageNotFound = errors.New("tracked message not found")
+
+// TrackerOption configures a StatusTracker.
+type TrackerOption func(*StatusTracker)
+
+// WithTrackerErrorHandler sets the function called when a send could not be
+// recorded, or when Run fails to poll. Such failures never fail the send
+// itself. By default they are dropped.
+func WithTrackerErrorHandler(fn func(ctx context.Context, err error)) TrackerOption {
+	return func(t *StatusTracker) {
+		t.onError = fn
+	}
+}
+
+// StatusTracker records every message sent by a client configured with
+// WithStatusTracker and keeps its status up to date from delivery report
+// webhooks and from polling.
+type StatusTracker struct {
+	store    TrackerStore
+	onError  func(ctx context.Context, err error)
+	holdback time.Duration
+	hooks    []func(ctx context.Context, t Transition)
+
+	heldMu sync.Mutex
+	held   map[string]*heldReport
+
+	// Serialize the updates of each message within this process
+	locks [trackerLocks]sync.Mutex
+}
+
+// NewStatusTracker creates a tracker persisting to store.
+func NewStatusTracker(store TrackerStore, opts ...TrackerOption) *StatusTracker {
+	t := &StatusTracker{store: store}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// WithStatusTracker records the ID of every message the client sends in
+// tracker.
+func WithStatusTracker(tracker *StatusTracker) ClientOption {
+	return func(c *Client) {
+		c.tracker = tracker
+	}
+}
+
+// Track records a sent message, then applies the delivery report held for
+// it, if any. A message already recorded, for example by a delivery report
+// that arrived first, keeps a later status and gains the recipient if it
+// had none.
+func (t *StatusTracker) Track(ctx context.Context, messageID, to, status string) error {
+	if status == "" {
+		status = "sent"
+	}
+	if err := t.update(ctx, &TrackedMessage{MessageID: messageID, To: to, Status: status, SentAt: time.Now()}); err != nil {
+		return err
+	}
+	if held := t.takeHeld(messageID); held != nil {
+		return t.update(ctx, held)
+	}
+	return nil
+}
+
+// Get returns the recorded state of a message.
+func (t *StatusTracker) Get(ctx context.Context, messageID string) (*TrackedMessage, error) {
+	return t.store.Get(ctx, messageID)
+}
+
+// PendingOlderThan returns messages sent more than d ago that have not
+// reached a final status, oldest first.
+func (t *StatusTracker) PendingOlderThan(ctx context.Context, d time.Duration) ([]*TrackedMessage, error) {
+	pending, err := t.store.Pending(ctx, time.Now().Add(-d), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending messages: %w", err)
+	}
+	return pending, nil
+}
+
+// Update records a status reported for a message. Statuses the message
+// lifecycle does not allow next, as reported by CanTransition, are ignored,
+// so late or reordered reports are harmless.
+func (t *StatusTracker) Update(ctx context.Context, messageID, status, errMsg string) error {
+	return t.update(ctx, &TrackedMessage{MessageID: messageID, Status: status, Error: errMsg})
+}
+
+// update merges a reported state into the recorded one. Reports that change
+// nothing are not saved.
+func (t *StatusTracker) update(ctx context.Context, reported *TrackedMessage) error {
+	transition, changed, err := t.merge(ctx, reported)
+	if err != nil {
+		return err
+	}
+	if changed {
+		t.transitioned(ctx, transition)
+	}
+	return nil
+}
+
+// merge does the work of update under the lock of the message, so that
+// concurrent reports for one message do not overwrite each other. It
+// returns the transition to announce, if any.
+func (t *StatusTracker) merge(ctx context.Context, reported *TrackedMessage) (Transition, bool, error) {
+	mu := &t.locks[lockIndex(reported.MessageID)]
+	mu.Lock()
+	defer mu.Unlock()
+
+	msg, err := t.store.Get(ctx, reported.MessageID)
+	switch {
+	case errors.Is(err, ErrTrackedMessageNotFound):
+		msg = &TrackedMessage{MessageID: reported.MessageID}
+	case err != nil:
+		return Transition{}, false, fmt.Errorf("failed to load tracked message %s: %w", reported.MessageID, err)
+	case msg.Status == reported.Status && msg.Error == reported.Error && (reported.To == "" || msg.To != ""):
+		return Transition{}, false, nil
+	}
+
+	from, to := NormalizeStatus(msg.Status), NormalizeStatus(reported.Status)
+	changed := from != to
+	if changed && !from.CanTransition(to) {
+		// The recorded status stands, but a recipient the record lacks is
+		// still worth keeping.
+		if msg.To != "" || reported.To == "" {
+			return Transition{}, false, nil
+		}
+		changed = false
+	} else {
+		msg.Status = reported.Status
+		msg.Error = reported.Error
+	}
+	if msg.To == "" {
+		msg.To = reported.To
+	}
+	msg.UpdatedAt = time.Now()
+	if msg.SentAt.IsZero() {
+		msg.SentAt = reported.SentAt
+	}
+	if msg.SentAt.IsZero() {
+		msg.SentAt = msg.UpdatedAt
+	}
+
+	if err := t.store.Save(ctx, msg); err != nil {
+		return Transition{}, false, fmt.Errorf("failed to update tracked message %s: %w", reported.MessageID, err)
+	}
+	return Transition{MessageID: msg.MessageID, From: from, To: to, At: msg.UpdatedAt}, changed, nil
+}
+
+// trackerLocks is the number of locks a StatusTracker spreads message IDs
+// over.
+const trackerLocks = 64
+
+func lockIndex(messageID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(messageID))
+	return int(h.Sum32() % trackerLocks)
+}
+
+// Register subscribes the tracker to delivery reports on h. With
+// WithReportHoldback, reports for messages not tracked yet are held.
+func (t *StatusTracker) Register(h *WebhookHandler) {
+	h.OnDeliveryReport(func(ctx context.Context, report *DeliveryReportEvent) error {
+		return t.report(ctx, &TrackedMessage{MessageID: report.MessageID, Status: report.Status, Error: report.Error})
+	})
+}
+
+// Poll queries the status of every message sent more than olderThan ago
+// that is still pending, and returns how many messages were refreshed. If
+// it stops early, the error is a *PartialResult listing the IDs of the
+// messages refreshed and not refreshed.
+func (t *StatusTracker) Poll(ctx context.Context, client *Client, olderThan time.Duration) (int, error) {
+	pending, err := t.PendingOlderThan(ctx, olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, msg := range pending {
+		if err = ctx.Err(); err == nil {
+			err = t.refresh(ctx, client, msg.MessageID)
+		}
+		if err != nil {
+			partial := &PartialResult{Err: err}
+			for _, m := range pending[:i] {
+				partial.Completed = append(partial.Completed, m.MessageID)
+			}
+			for _, m := range pending[i:] {
+				partial.Failed = append(partial.Failed, m.MessageID)
+			}
+			return i, partial
+		}
+	}
+	return len(pending), nil
+}
+
+// refresh queries the status of one message and records it.
+func (t *StatusTracker) refresh(ctx context.Context, client *Client, messageID string) error {
+	status, err := client.Messages.GetMessageStatus(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	return t.Update(ctx, messageID, status.Status, status.Error)
+}
+
+// Backfill lists the messages sent since the given time and records their
+// statuses, catching messages whose delivery reports were missed or that
+// were sent by other processes. It returns how many messages were recorded
+// and, if it stopped early, a *PartialResult listing the IDs of the
+// messages recorded before it did. ctx is checked before every page.
+func (t *StatusTracker) Backfill(ctx context.Context, client *Client, since time.Time) (int, error) {
+	params := &PaginationParams{Page: 1, PerPage: 100, Filter: F().Between(since, time.Time{})}
+	var recorded []string
+	for {
+		if err := ctx.Err(); err != nil {
+			return len(recorded), &PartialResult{Completed: recorded, Err: err}
+		}
+		response, err := client.Messages.ListMessages(ctx, params)
+		if err != nil {
+			return len(recorded), &PartialResult{Completed: recorded, Err: fmt.Errorf("failed to backfill message statuses: %w", err)}
+		}
+		for i := range response.Messages {
+			m := &response.Messages[i]
+			if m.ID == "" {
+				continue
+			}
+			sentAt := m.SentAt
+			if sentAt.IsZero() {
+				sentAt = m.CreatedAt
+			}
+			err = t.update(ctx, &TrackedMessage{
+				MessageID: m.ID,
+				To:        m.To,
+				Status:    m.Status,
+				Error:     m.Error,
+				SentAt:    sentAt,
+			})
+			if err != nil {
+				return len(recorded), &PartialResult{Completed: recorded, Failed: []string{m.ID}, Err: err}
+			}
+			recorded = append(recorded, m.ID)
+		}
+		if len(response.Messages) == 0 || !response.Pagination.HasNext {
+			return len(recorded), nil
+		}
+		params.Page++
+	}
+}
+
+// Run polls the statuses of messages pending for longer than olderThan and
+// backfills the messages sent since the previous run, every interval until
+// ctx is done. It is the fallback for delivery reports that never arrive.
+// Errors are passed to the tracker's error handler and do not stop Run.
+func (t *StatusTracker) Run(ctx context.Context, client *Client, interval, olderThan time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Overlap runs a little so that messages listed late are not missed.
+	since := time.Now().Add(-interval)
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		started := time.Now()
+		if _, err := t.Poll(ctx, client, olderThan); err != nil && ctx.Err() == nil {
+			t.reportError(ctx, err)
+		}
+		if _, err := t.Backfill(ctx, client, since); err == nil {
+			since = started.Add(-interval / 2)
+		} else if ctx.Err() == nil {
+			t.reportError(ctx, err)
+		}
+	}
+}
+
+func (t *StatusTracker) reportError(ctx context.Context, err error) {
+	if t.onError != nil {
+		t.onError(ctx, err)
+	}
+}
+
+// trackSent records a message sent by c, if c has a tracker.
+func (c *Client) trackSent(ctx context.Context, messageID, to, status string) {
+	if c.tracker == nil || messageID == "" {
+		return
+	}
+	if err := c.tracker.Track(ctx, messageID, to, status); err != nil {
+		c.tracker.reportError(ctx, err)
+	}
+}
+
+// trackBulk records the messages of a bulk send.
+func (c *Client) trackBulk(ctx context.Context, req *SendBulkMessageRequest, resp *SendBulkMessageResponse) {
+	if c.tracker == nil {
+		return
+	}
+	for _, sent := range resp.sentMessages(req) {
+		c.trackSent(ctx, sent.ID, sent.To, sent.Status)
+	}
+}
+
+// MemoryTrackerStore is a TrackerStore that keeps messages in memory. It
+// does not survive restarts and is mainly useful for tests.
+type MemoryTrackerStore struct {
+	mu       sync.Mutex
+	messages map[string]TrackedMessage
+}
+
+// NewMemoryTrackerStore creates an empty MemoryTrackerStore.
+func NewMemoryTrackerStore() *MemoryTrackerStore {
+	return &MemoryTrackerStore{messages: make(map[string]TrackedMessage)}
+}
+
+// Save implements TrackerStore.
+func (s *MemoryTrackerStore) Save(_ context.Context, msg *TrackedMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[msg.MessageID] = *msg
+	return nil
+}
+
+// Get implements TrackerStore.
+func (s *MemoryTrackerStore) Get(_ context.Context, messageID string) (*TrackedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, ok := s.messages[messageID]
+	if !ok {
+		return nil, ErrTrackedMessageNotFound
+	}
+	return &msg, nil
+}
+
+// Pending implements TrackerStore.
+func (s *MemoryTrackerStore) Pending(_ context.Context, sentBefore time.Time, limit int) ([]*TrackedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []*TrackedMessage
+	for _, msg := range s.messages {
+		if !msg.Terminal() && msg.SentAt.Before(sentBefore) {
+			m := msg
+			pending = append(pending, &m)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].SentAt.Before(pending[j].SentAt)
+	})
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
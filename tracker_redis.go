@@ -0,0 +1,137 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RedisClient is the subset of Redis commands RedisTrackerStore needs. It
+// keeps the SDK free of a Redis dependency; adapting a client such as
+// go-redis takes a few lines per method.
+type RedisClient interface {
+	// Get returns the string value of key, and false if the key does not
+	// exist.
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Set stores value at key without expiry.
+	Set(ctx context.Context, key, value string) error
+
+	// ZAdd adds member to the sorted set at key with score.
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+
+	// ZRem removes member from the sorted set at key.
+	ZRem(ctx context.Context, key, member string) error
+
+	// ZRangeByScore returns members of the sorted set at key with a score of
+	// at most maxScore, lowest first. A limit above zero caps the result.
+	ZRangeByScore(ctx context.Context, key string, maxScore float64, limit int) ([]string, error)
+}
+
+// RedisTrackerStore is a TrackerStore backed by Redis. Each message is a
+// JSON string under prefix+"msg:"+id, and pending messages are indexed in a
+// sorted set under prefix+"pending" scored by send time.
+type RedisTrackerStore struct {
+	client    RedisClient
+	prefix    string
+	encrypter Encrypter
+}
+
+// RedisTrackerOption configures a RedisTrackerStore.
+type RedisTrackerOption func(*RedisTrackerStore)
+
+// WithRedisEncrypter encrypts the stored messages, recipients included,
+// with e. Messages written without encryption are still read.
+func WithRedisEncrypter(e Encrypter) RedisTrackerOption {
+	return func(s *RedisTrackerStore) {
+		s.encrypter = e
+	}
+}
+
+// NewRedisTrackerStore creates a store using client with keys starting with
+// prefix, such as "signalads:".
+func NewRedisTrackerStore(client RedisClient, prefix string, opts ...RedisTrackerOption) *RedisTrackerStore {
+	s := &RedisTrackerStore{client: client, prefix: prefix}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *RedisTrackerStore) messageKey(messageID string) string {
+	return s.prefix + "msg:" + messageID
+}
+
+func (s *RedisTrackerStore) pendingKey() string {
+	return s.prefix + "pending"
+}
+
+// Save implements TrackerStore.
+func (s *RedisTrackerStore) Save(ctx context.Context, msg *TrackedMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode tracked message: %w", err)
+	}
+	value, err := encryptString(s.encrypter, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt tracked message: %w", err)
+	}
+	if err := s.client.Set(ctx, s.messageKey(msg.MessageID), value); err != nil {
+		return fmt.Errorf("failed to save tracked message: %w", err)
+	}
+
+	if msg.Terminal() {
+		err = s.client.ZRem(ctx, s.pendingKey(), msg.MessageID)
+	} else {
+		err = s.client.ZAdd(ctx, s.pendingKey(), float64(msg.SentAt.UnixMilli()), msg.MessageID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to index tracked message: %w", err)
+	}
+	return nil
+}
+
+// Get implements TrackerStore.
+func (s *RedisTrackerStore) Get(ctx context.Context, messageID string) (*TrackedMessage, error) {
+	data, ok, err := s.client.Get(ctx, s.messageKey(messageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracked message: %w", err)
+	}
+	if !ok {
+		return nil, ErrTrackedMessageNotFound
+	}
+
+	data, err = decryptString(s.encrypter, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt tracked message: %w", err)
+	}
+
+	var msg TrackedMessage
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode tracked message: %w", err)
+	}
+	return &msg, nil
+}
+
+// Pending implements TrackerStore.
+func (s *RedisTrackerStore) Pending(ctx context.Context, sentBefore time.Time, limit int) ([]*TrackedMessage, error) {
+	ids, err := s.client.ZRangeByScore(ctx, s.pendingKey(), float64(sentBefore.UnixMilli()-1), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending messages: %w", err)
+	}
+
+	pending := make([]*TrackedMessage, 0, len(ids))
+	for _, id := range ids {
+		msg, getErr := s.Get(ctx, id)
+		if errors.Is(getErr, ErrTrackedMessageNotFound) {
+			continue
+		}
+		if getErr != nil {
+			return nil, getErr
+		}
+		pending = append(pending, msg)
+	}
+	return pending, nil
+}
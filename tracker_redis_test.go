@@ -0,0 +1,104 @@
+package signalads
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeRedis struct {
+	mu      sync.Mutex
+	strings map[string]string
+	zsets   map[string]map[string]float64
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{strings: make(map[string]string), zsets: make(map[string]map[string]float64)}
+}
+
+func (f *fakeRedis) Get(_ context.Context, key string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.strings[key]
+	return v, ok, nil
+}
+
+func (f *fakeRedis) Set(_ context.Context, key, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.strings[key] = value
+	return nil
+}
+
+func (f *fakeRedis) ZAdd(_ context.Context, key string, score float64, member string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.zsets[key] == nil {
+		f.zsets[key] = make(map[string]float64)
+	}
+	f.zsets[key][member] = score
+	return nil
+}
+
+func (f *fakeRedis) ZRem(_ context.Context, key, member string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.zsets[key], member)
+	return nil
+}
+
+func (f *fakeRedis) ZRangeByScore(_ context.Context, key string, maxScore float64, limit int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var members []string
+	for m, score := range f.zsets[key] {
+		if score <= maxScore {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return f.zsets[key][members[i]] < f.zsets[key][members[j]]
+	})
+	if limit > 0 && len(members) > limit {
+		members = members[:limit]
+	}
+	return members, nil
+}
+
+func TestRedisTrackerStore(t *testing.T) {
+	redis := newFakeRedis()
+	store := NewRedisTrackerStore(redis, "test:")
+	ctx := context.Background()
+
+	old := time.Now().Add(-time.Hour)
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_2", Status: "sent", SentAt: old.Add(time.Minute)})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_1", Status: "sent", SentAt: old})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_3", Status: "sent", SentAt: time.Now()})
+
+	pending, err := store.Pending(ctx, time.Now().Add(-time.Minute), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pending) != 2 || pending[0].MessageID != "msg_1" || pending[1].MessageID != "msg_2" {
+		t.Fatalf("Expected msg_1 and msg_2 pending, got %+v", pending)
+	}
+
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_1", Status: "delivered", SentAt: old})
+	pending, _ = store.Pending(ctx, time.Now(), 1)
+	if len(pending) != 1 || pending[0].MessageID != "msg_2" {
+		t.Errorf("Expected msg_2 pending, got %+v", pending)
+	}
+
+	msg, err := store.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" {
+		t.Errorf("Expected status delivered, got %s", msg.Status)
+	}
+	if _, ok := redis.strings["test:msg:msg_1"]; !ok {
+		t.Error("Expected key with prefix test:msg:")
+	}
+}
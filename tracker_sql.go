@@ -0,0 +1,241 @@
+package signalads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLTrackerStore is a TrackerStore backed by a database/sql table. It uses
+// only portable SQL and stores times as Unix milliseconds, so it works with
+// PostgreSQL, MySQL and SQLite drivers alike.
+type SQLTrackerStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+	encrypter   Encrypter
+}
+
+// SQLTrackerOption configures an SQLTrackerStore.
+type SQLTrackerOption func(*SQLTrackerStore)
+
+// WithSQLTable sets the table name. It defaults to "signalads_messages".
+func WithSQLTable(table string) SQLTrackerOption {
+	return func(s *SQLTrackerStore) {
+		s.table = table
+	}
+}
+
+// WithSQLDollarPlaceholders makes queries use $1-style placeholders, as
+// PostgreSQL drivers require, instead of "?".
+func WithSQLDollarPlaceholders() SQLTrackerOption {
+	return func(s *SQLTrackerStore) {
+		s.placeholder = dollarPlaceholder
+	}
+}
+
+// WithSQLEncrypter encrypts the recipient column with e. Encrypted numbers
+// take up to 128 characters; CreateTable sizes the column accordingly, but
+// an existing table must be altered by hand. Rows written without
+// encryption are still read.
+func WithSQLEncrypter(e Encrypter) SQLTrackerOption {
+	return func(s *SQLTrackerStore) {
+		s.encrypter = e
+	}
+}
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// NewSQLTrackerStore creates a store using db. Call CreateTable once to
+// create the table if it does not exist.
+func NewSQLTrackerStore(db *sql.DB, opts ...SQLTrackerOption) (*SQLTrackerStore, error) {
+	s := &SQLTrackerStore{
+		db:          db,
+		table:       "signalads_messages",
+		placeholder: questionPlaceholder,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if !sqlIdentifier.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	return s, nil
+}
+
+// CreateTable creates the store's table if it does not exist.
+func (s *SQLTrackerStore) CreateTable(ctx context.Context) error {
+	recipient := "VARCHAR(32)"
+	if s.encrypter != nil {
+		recipient = "VARCHAR(128)"
+	}
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	message_id VARCHAR(128) NOT NULL PRIMARY KEY,
+	recipient `+recipient+` NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	error TEXT NOT NULL,
+	terminal INTEGER NOT NULL,
+	sent_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create tracker table: %w", err)
+	}
+	return nil
+}
+
+// query replaces the ? placeholders of q with the store's placeholder style.
+func (s *SQLTrackerStore) query(q string) string {
+	return rebindSQL(q, s.placeholder)
+}
+
+// rebindSQL replaces the ? placeholders of q with placeholder(n) for the
+// n-th placeholder, starting at 1.
+func rebindSQL(q string, placeholder func(n int) string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString(placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func questionPlaceholder(int) string {
+	return "?"
+}
+
+func dollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Save implements TrackerStore. It updates the message's row, and inserts
+// one if there is none. When a concurrent Save inserts the row between the
+// two, the insert fails on the primary key and the row is updated instead.
+func (s *SQLTrackerStore) Save(ctx context.Context, msg *TrackedMessage) error {
+	to, err := encryptString(s.encrypter, msg.To)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt recipient: %w", err)
+	}
+
+	updated, err := s.update(ctx, msg, to)
+	if err != nil {
+		return fmt.Errorf("failed to save tracked message: %w", err)
+	}
+	if updated {
+		return nil
+	}
+
+	_, insertErr := s.db.ExecContext(ctx, s.query(`INSERT INTO `+s.table+
+		` (message_id, recipient, status, error, terminal, sent_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		msg.MessageID, to, msg.Status, msg.Error, sqlBool(msg.Terminal()), msg.SentAt.UnixMilli(), msg.UpdatedAt.UnixMilli())
+	if insertErr == nil {
+		return nil
+	}
+
+	// Drivers do not agree on how a duplicate key is reported, so retry
+	// the update and only give up if the row is still missing. MySQL
+	// reports no affected rows for an update that changes nothing, so a
+	// row that already holds msg counts as saved.
+	if updated, err = s.update(ctx, msg, to); err == nil && !updated {
+		var exists int
+		err = s.db.QueryRowContext(ctx, s.query(`SELECT 1 FROM `+s.table+` WHERE message_id = ?`), msg.MessageID).Scan(&exists)
+		updated = err == nil
+	}
+	if !updated {
+		return fmt.Errorf("failed to save tracked message: %w", insertErr)
+	}
+	return nil
+}
+
+// update overwrites the row of msg, reporting whether there was one.
+func (s *SQLTrackerStore) update(ctx context.Context, msg *TrackedMessage, to string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, s.query(`UPDATE `+s.table+
+		` SET recipient = ?, status = ?, error = ?, terminal = ?, sent_at = ?, updated_at = ? WHERE message_id = ?`),
+		to, msg.Status, msg.Error, sqlBool(msg.Terminal()), msg.SentAt.UnixMilli(), msg.UpdatedAt.UnixMilli(), msg.MessageID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Get implements TrackerStore.
+func (s *SQLTrackerStore) Get(ctx context.Context, messageID string) (*TrackedMessage, error) {
+	row := s.db.QueryRowContext(ctx, s.query(`SELECT message_id, recipient, status, error, sent_at, updated_at FROM `+
+		s.table+` WHERE message_id = ?`), messageID)
+	msg, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTrackedMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracked message: %w", err)
+	}
+	return msg, nil
+}
+
+// Pending implements TrackerStore.
+func (s *SQLTrackerStore) Pending(ctx context.Context, sentBefore time.Time, limit int) ([]*TrackedMessage, error) {
+	q := `SELECT message_id, recipient, status, error, sent_at, updated_at FROM ` + s.table +
+		` WHERE terminal = 0 AND sent_at < ? ORDER BY sent_at`
+	args := []interface{}{sentBefore.UnixMilli()}
+	if limit > 0 {
+		q += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.query(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending messages: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []*TrackedMessage
+	for rows.Next() {
+		msg, scanErr := s.scan(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("failed to read pending message: %w", scanErr)
+		}
+		pending = append(pending, msg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pending messages: %w", err)
+	}
+	return pending, nil
+}
+
+func (s *SQLTrackerStore) scan(row interface{ Scan(...interface{}) error }) (*TrackedMessage, error) {
+	var msg TrackedMessage
+	var sentAt, updatedAt int64
+	if err := row.Scan(&msg.MessageID, &msg.To, &msg.Status, &msg.Error, &sentAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	to, err := decryptString(s.encrypter, msg.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt recipient of message %s: %w", msg.MessageID, err)
+	}
+	msg.To = to
+	msg.SentAt = time.UnixMilli(sentAt)
+	msg.UpdatedAt = time.UnixMilli(updatedAt)
+	return &msg, nil
+}
+
+func sqlBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
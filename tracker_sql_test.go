@@ -0,0 +1,242 @@
+package signalads
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSQLDriver understands just the statements SQLTrackerStore issues and
+// records every query it receives.
+type fakeSQLDriver struct {
+	mu      sync.Mutex
+	rows    map[string][]driver.Value
+	queries []string
+
+	// beforeInsert, if set, runs before each INSERT, with mu held
+	beforeInsert func(d *fakeSQLDriver)
+}
+
+func (d *fakeSQLDriver) Open(string) (driver.Conn, error) {
+	return &fakeSQLConn{d: d}, nil
+}
+
+type fakeSQLConn struct {
+	d *fakeSQLDriver
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) { return fakeSQLTx{}, nil }
+
+type fakeSQLTx struct{}
+
+func (fakeSQLTx) Commit() error   { return nil }
+func (fakeSQLTx) Rollback() error { return nil }
+
+type fakeSQLStmt struct {
+	d     *fakeSQLDriver
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error  { return nil }
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT"):
+		if d.beforeInsert != nil {
+			d.beforeInsert(d)
+		}
+		// message_id, recipient, status, error, terminal, sent_at, updated_at
+		if _, ok := d.rows[args[0].(string)]; ok {
+			return nil, errors.New("duplicate key value violates unique constraint")
+		}
+		d.rows[args[0].(string)] = args
+	case strings.HasPrefix(s.query, "UPDATE"):
+		id := args[6].(string)
+		if _, ok := d.rows[id]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		d.rows[id] = append([]driver.Value{id}, args[:6]...)
+	default:
+		return nil, errors.New("unexpected exec: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+
+	var result [][]driver.Value
+	switch {
+	case strings.HasPrefix(s.query, "SELECT 1"):
+		rows := &fakeSQLRows{columns: []string{"1"}}
+		if _, ok := d.rows[args[0].(string)]; ok {
+			rows.rows = append(rows.rows, []driver.Value{int64(1)})
+		}
+		return rows, nil
+	case strings.Contains(s.query, "WHERE message_id"):
+		if row, ok := d.rows[args[0].(string)]; ok {
+			result = append(result, selectColumns(row))
+		}
+	case strings.Contains(s.query, "WHERE terminal = 0"):
+		for _, row := range d.rows {
+			if row[4].(int64) == 0 && row[5].(int64) < args[0].(int64) {
+				result = append(result, selectColumns(row))
+			}
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i][4].(int64) < result[j][4].(int64) })
+		if len(args) > 1 && int(args[1].(int64)) < len(result) {
+			result = result[:args[1].(int64)]
+		}
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	return &fakeSQLRows{
+		columns: []string{"message_id", "recipient", "status", "error", "sent_at", "updated_at"},
+		rows:    result,
+	}, nil
+}
+
+// selectColumns drops the terminal column from a stored row.
+func selectColumns(row []driver.Value) []driver.Value {
+	return []driver.Value{row[0], row[1], row[2], row[3], row[5], row[6]}
+}
+
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string { return r.columns }
+
+func (r *fakeSQLRows) Close() error { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQLTrackerStore(t *testing.T) {
+	fake := &fakeSQLDriver{rows: make(map[string][]driver.Value)}
+	sql.Register("signalads-fake", fake)
+	db, err := sql.Open("signalads-fake", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewSQLTrackerStore(db, WithSQLDollarPlaceholders())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	if err := store.CreateTable(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_1", To: "+989123456789", Status: "sent", SentAt: old, UpdatedAt: old})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_2", Status: "sent", SentAt: old.Add(time.Minute), UpdatedAt: old})
+
+	pending, err := store.Pending(ctx, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pending) != 2 || pending[0].MessageID != "msg_1" {
+		t.Fatalf("Expected msg_1 and msg_2 pending, got %+v", pending)
+	}
+	if !pending[0].SentAt.Equal(old) {
+		t.Errorf("Expected sent_at %v, got %v", old, pending[0].SentAt)
+	}
+
+	if err := store.Save(ctx, &TrackedMessage{MessageID: "msg_1", To: "+989123456789", Status: "delivered", SentAt: old, UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	msg, err := store.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" || msg.To != "+989123456789" {
+		t.Errorf("Expected delivered message to +989123456789, got %+v", msg)
+	}
+
+	pending, _ = store.Pending(ctx, time.Now(), 1)
+	if len(pending) != 1 || pending[0].MessageID != "msg_2" {
+		t.Errorf("Expected msg_2 pending, got %+v", pending)
+	}
+
+	if _, err := store.Get(ctx, "msg_unknown"); !errors.Is(err, ErrTrackedMessageNotFound) {
+		t.Errorf("Expected ErrTrackedMessageNotFound, got %v", err)
+	}
+
+	for _, q := range fake.queries {
+		if strings.Contains(q, "?") {
+			t.Errorf("Expected dollar placeholders, got %q", q)
+		}
+	}
+
+	if _, err := NewSQLTrackerStore(db, WithSQLTable("messages; DROP TABLE x")); err == nil {
+		t.Error("Expected an invalid table name to be rejected")
+	}
+}
+
+func TestSQLTrackerStore_SaveConflict(t *testing.T) {
+	fake := &fakeSQLDriver{rows: make(map[string][]driver.Value)}
+	sql.Register("signalads-fake-conflict", fake)
+	db, err := sql.Open("signalads-fake-conflict", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewSQLTrackerStore(db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Another Save inserts the row between this Save's update and insert
+	now := time.Now().Truncate(time.Millisecond)
+	fake.beforeInsert = func(d *fakeSQLDriver) {
+		d.rows["msg_1"] = []driver.Value{"msg_1", "+989123456789", "sent", "", int64(0), now.UnixMilli(), now.UnixMilli()}
+		d.beforeInsert = nil
+	}
+
+	ctx := context.Background()
+	err = store.Save(ctx, &TrackedMessage{MessageID: "msg_1", To: "+989123456789", Status: "delivered", SentAt: now, UpdatedAt: now})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	msg, err := store.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" {
+		t.Errorf("Expected status delivered, got %s", msg.Status)
+	}
+}
@@ -0,0 +1,214 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatusTracker_RecordsSends(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/send-message/bulk" {
+			w.Write([]byte(`{"total": 2, "success": 2, "message_ids": ["msg_2", "msg_3"]}`))
+			return
+		}
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	store := NewMemoryTrackerStore()
+	tracker := NewStatusTracker(store)
+	client := setupTestClient(handler)
+	WithStatusTracker(tracker)(client)
+	ctx := context.Background()
+
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", "Hi"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Messages.SendBulkMessage(ctx, []BulkMessageItem{
+		{To: "+989120000002", Message: "Hi"},
+		{To: "+989120000003", Message: "Hi"},
+	}, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.To != "+989123456789" || msg.Status != "sent" {
+		t.Errorf("Expected sent message to +989123456789, got %+v", msg)
+	}
+
+	msg, err = tracker.Get(ctx, "msg_3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.To != "+989120000003" {
+		t.Errorf("Expected recipient +989120000003, got %s", msg.To)
+	}
+
+	if _, err := tracker.Get(ctx, "msg_unknown"); !errors.Is(err, ErrTrackedMessageNotFound) {
+		t.Errorf("Expected ErrTrackedMessageNotFound, got %v", err)
+	}
+}
+
+func TestStatusTracker_WebhookUpdates(t *testing.T) {
+	tracker := NewStatusTracker(NewMemoryTrackerStore())
+	ctx := context.Background()
+	if err := tracker.Track(ctx, "msg_1", "+989123456789", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	h := newTestWebhookHandler()
+	tracker.Register(h)
+
+	post := func(status string) {
+		body := `{"id": "evt", "type": "message.status", "data": {"message_id": "msg_1", "status": "` + status + `"}}`
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedWebhookRequest("/webhook", bytes.NewBufferString(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+	}
+
+	post("delivered")
+	post("sent") // late report
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" {
+		t.Errorf("Expected status delivered, got %s", msg.Status)
+	}
+	if msg.To != "+989123456789" {
+		t.Errorf("Expected recipient to be kept, got %q", msg.To)
+	}
+}
+
+func TestStatusTracker_PendingOlderThanAndPoll(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_old", "status": "delivered"}`))
+	}
+
+	store := NewMemoryTrackerStore()
+	tracker := NewStatusTracker(store)
+	ctx := context.Background()
+
+	old := time.Now().Add(-time.Hour)
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_old", Status: "sent", SentAt: old, UpdatedAt: old})
+	store.Save(ctx, &TrackedMessage{MessageID: "msg_done", Status: "failed", SentAt: old, UpdatedAt: old})
+	tracker.Track(ctx, "msg_new", "+989123456789", "")
+
+	pending, err := tracker.PendingOlderThan(ctx, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pending) != 1 || pending[0].MessageID != "msg_old" {
+		t.Fatalf("Expected only msg_old pending, got %+v", pending)
+	}
+
+	n, err := tracker.Poll(ctx, setupTestClient(handler), 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 message polled, got %d", n)
+	}
+
+	pending, _ = tracker.PendingOlderThan(ctx, 10*time.Minute)
+	if len(pending) != 0 {
+		t.Errorf("Expected nothing pending after polling, got %+v", pending)
+	}
+}
+
+func TestStatusTracker_ErrorHandler(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "msg_1", "status": "sent"}`))
+	}
+
+	var reported error
+	tracker := NewStatusTracker(failingTrackerStore{}, WithTrackerErrorHandler(func(ctx context.Context, err error) {
+		reported = err
+	}))
+	client := setupTestClient(handler)
+	WithStatusTracker(tracker)(client)
+
+	if _, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi"); err != nil {
+		t.Fatalf("Expected the send to succeed, got %v", err)
+	}
+	if reported == nil {
+		t.Error("Expected the tracking failure to be reported")
+	}
+}
+
+type failingTrackerStore struct{}
+
+func (failingTrackerStore) Save(context.Context, *TrackedMessage) error {
+	return errors.New("store down")
+}
+
+func (failingTrackerStore) Get(context.Context, string) (*TrackedMessage, error) {
+	return nil, errors.New("store down")
+}
+
+func (failingTrackerStore) Pending(context.Context, time.Time, int) ([]*TrackedMessage, error) {
+	return nil, errors.New("store down")
+}
+
+func TestStatusTracker_Backfill(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" {
+			t.Errorf("Expected path /messages, got %s", r.URL.Path)
+			return
+		}
+		if r.URL.Query().Get("since") == "" {
+			t.Error("Expected since filter")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`{"messages": [{"id": "msg_1", "to": "+989120000001", "status": "delivered"}], "page": 1, "per_page": 1, "total": 2}`))
+		case "2":
+			w.Write([]byte(`{"messages": [{"id": "msg_2", "to": "+989120000002", "status": "sent"}], "page": 2, "per_page": 1, "total": 2}`))
+		default:
+			t.Errorf("Unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}
+
+	store := NewMemoryTrackerStore()
+	tracker := NewStatusTracker(store)
+	client := setupTestClient(handler)
+	ctx := context.Background()
+	tracker.Track(ctx, "msg_1", "+989120000001", "sent")
+
+	n, err := tracker.Backfill(ctx, client, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 messages, got %d", n)
+	}
+
+	msg, err := tracker.Get(ctx, "msg_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Status != "delivered" {
+		t.Errorf("Expected status delivered, got %s", msg.Status)
+	}
+	msg, err = tracker.Get(ctx, "msg_2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.To != "+989120000002" || msg.Status != "sent" {
+		t.Errorf("Unexpected backfilled message: %+v", msg)
+	}
+}
@@ -0,0 +1,144 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// EventTranscription is the webhook event type sent when a transcription
+// completes or fails.
+const EventTranscription = "transcription.completed"
+
+// Transcription statuses reported in Transcription.Status.
+const (
+	TranscriptionPending   = "pending"
+	TranscriptionCompleted = "completed"
+	TranscriptionFailed    = "failed"
+)
+
+// Transcription is the text of a call recording.
+type Transcription struct {
+	RawResponse
+
+	ID     string `json:"id"`
+	CallID string `json:"call_id"`
+
+	// Language of the recording, e.g. "fa" or "en"
+	Language string `json:"language,omitempty"`
+
+	// TranscriptionPending, TranscriptionCompleted or TranscriptionFailed
+	Status string `json:"status"`
+
+	Text  string `json:"text,omitempty"`
+	Error string `json:"error,omitempty"`
+
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// Done reports whether the transcription has completed or failed.
+func (t *Transcription) Done() bool {
+	return t.Status == TranscriptionCompleted || t.Status == TranscriptionFailed
+}
+
+// GetCallRecording writes the audio recorded during a call, such as a
+// voicemail or IVR response, to w. Recordings are only available where the
+// provider records calls.
+func (s *CallsService) GetCallRecording(ctx context.Context, callID string, w io.Writer) error {
+	if callID == "" {
+		return &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "call ID is required"}}}
+	}
+
+	resp, err := s.client.doRequest(ctx, http.MethodGet, "/calls/"+pathSegment(callID)+"/recording", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get call recording: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, err = s.client.readResponse(resp)
+		return fmt.Errorf("failed to get call recording: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return &TransportError{Op: "failed to read call recording", Err: err}
+	}
+	return nil
+}
+
+// RequestTranscription starts transcribing the recording of a call in
+// language. Transcription runs asynchronously: wait for it with
+// WaitForTranscription or receive it with WebhookHandler.OnTranscription.
+func (s *CallsService) RequestTranscription(ctx context.Context, callID, language string) (*Transcription, error) {
+	if callID == "" {
+		return nil, &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "call ID is required"}}}
+	}
+
+	body := map[string]string{"language": language}
+	var transcription Transcription
+	if err := s.client.Post(ctx, "/calls/"+pathSegment(callID)+"/transcriptions", body, &transcription); err != nil {
+		return nil, fmt.Errorf("failed to request transcription: %w", err)
+	}
+
+	return &transcription, nil
+}
+
+// GetTranscription retrieves a transcription by its ID.
+func (s *CallsService) GetTranscription(ctx context.Context, transcriptionID string) (*Transcription, error) {
+	if transcriptionID == "" {
+		return nil, &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "transcription ID is required"}}}
+	}
+
+	var transcription Transcription
+	if err := s.client.Get(ctx, "/transcriptions/"+pathSegment(transcriptionID), &transcription, nil); err != nil {
+		return nil, fmt.Errorf("failed to get transcription: %w", err)
+	}
+
+	return &transcription, nil
+}
+
+// WaitForTranscription polls a transcription every interval until it is
+// done or ctx ends. A failed transcription is returned without error; check
+// its Status.
+func (s *CallsService) WaitForTranscription(ctx context.Context, transcriptionID string, interval time.Duration) (*Transcription, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		transcription, err := s.GetTranscription(ctx, transcriptionID)
+		if err != nil {
+			return nil, err
+		}
+		if transcription.Done() {
+			return transcription, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Transcription decodes the payload of an EventTranscription event.
+func (e *WebhookEvent) Transcription() (*Transcription, error) {
+	var transcription Transcription
+	if err := e.decodeData(EventTranscription, &transcription); err != nil {
+		return nil, err
+	}
+	return &transcription, nil
+}
+
+// OnTranscription registers fn for completed and failed transcriptions.
+func (h *WebhookHandler) OnTranscription(fn func(ctx context.Context, transcription *Transcription) error) {
+	h.On(EventTranscription, func(ctx context.Context, event *WebhookEvent) error {
+		transcription, err := event.Transcription()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, transcription)
+	})
+}
@@ -0,0 +1,112 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallsService_GetCallRecording(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calls/call-1/recording" {
+			t.Errorf("Expected path '/calls/call-1/recording', got '%s'", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("mp3-bytes"))
+	}
+
+	client := setupTestClient(handler)
+	var buf bytes.Buffer
+	if err := client.Calls.GetCallRecording(context.Background(), "call-1", &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "mp3-bytes" {
+		t.Errorf("Expected recording body, got '%s'", buf.String())
+	}
+}
+
+func TestCallsService_GetCallRecording_NotFound(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	client := setupTestClient(handler)
+	var buf bytes.Buffer
+	if err := client.Calls.GetCallRecording(context.Background(), "call-1", &buf); err == nil {
+		t.Error("Expected error, got nil")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got '%s'", buf.String())
+	}
+}
+
+func TestCallsService_Transcription(t *testing.T) {
+	var polls atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/calls/call-1/transcriptions":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["language"] != "fa" {
+				t.Errorf("Expected language 'fa', got '%s'", body["language"])
+			}
+			json.NewEncoder(w).Encode(Transcription{ID: "tr-1", CallID: "call-1", Status: TranscriptionPending})
+		case r.Method == http.MethodGet && r.URL.Path == "/transcriptions/tr-1":
+			status := TranscriptionPending
+			if polls.Add(1) >= 2 {
+				status = TranscriptionCompleted
+			}
+			json.NewEncoder(w).Encode(Transcription{ID: "tr-1", Status: status, Text: "yes, call me back"})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+
+	client := setupTestClient(handler)
+	transcription, err := client.Calls.RequestTranscription(context.Background(), "call-1", "fa")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transcription.Done() {
+		t.Error("Expected a new transcription to be pending")
+	}
+
+	done, err := client.Calls.WaitForTranscription(context.Background(), transcription.ID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if done.Status != TranscriptionCompleted || done.Text != "yes, call me back" {
+		t.Errorf("Unexpected transcription: %+v", done)
+	}
+	if polls.Load() != 2 {
+		t.Errorf("Expected 2 polls, got %d", polls.Load())
+	}
+}
+
+func TestWebhookHandler_OnTranscription(t *testing.T) {
+	h := newTestWebhookHandler()
+	var got *Transcription
+	h.OnTranscription(func(ctx context.Context, transcription *Transcription) error {
+		got = transcription
+		return nil
+	})
+
+	body := `{"id": "evt-3", "type": "transcription.completed", "data": {"id": "tr-1", "call_id": "call-1", "status": "completed", "text": "hello"}}`
+	req := signedWebhookRequest("/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got == nil || got.CallID != "call-1" || got.Text != "hello" {
+		t.Errorf("Unexpected transcription: %+v", got)
+	}
+}
@@ -0,0 +1,304 @@
+package signalads
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestDefaultTransport(t *testing.T) {
+	client := NewClient("test-key", "test-secret")
+
+	tr, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if !tr.ForceAttemptHTTP2 {
+		t.Error("Expected default transport to attempt HTTP/2")
+	}
+	if tr.DisableKeepAlives {
+		t.Error("Expected keep-alives to be enabled")
+	}
+	if tr == http.DefaultTransport {
+		t.Error("Expected a private transport, got http.DefaultTransport")
+	}
+}
+
+func TestWithForceHTTP2(t *testing.T) {
+	var proto int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.ProtoMajor
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+	}}
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithHTTPClient(httpClient),
+		WithForceHTTP2(),
+	)
+
+	if err := client.Get(context.Background(), "/ping", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proto != 2 {
+		t.Errorf("Expected HTTP/2, got HTTP/%d", proto)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithForceHTTP2_CustomRoundTripper(t *testing.T) {
+	rt := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	client := NewClient("test-key", "test-secret", WithHTTPClient(&http.Client{Transport: rt}), WithForceHTTP2())
+
+	if _, ok := client.httpClient.Transport.(roundTripperFunc); !ok {
+		t.Errorf("Expected custom RoundTripper to be left untouched, got %T", client.httpClient.Transport)
+	}
+}
+
+func TestTransportOptions_SharedClientUntouched(t *testing.T) {
+	sharedTransport := &http.Transport{}
+	shared := &http.Client{Transport: sharedTransport}
+
+	client := NewClient("test-key", "test-secret",
+		WithHTTPClient(shared),
+		WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), //nolint:gosec // checks that the setting stays private
+		WithCertificatePins("panel.signalads.com", "pin"),
+		WithRedirectPolicy(NoRedirects),
+		WithTimeout(time.Second),
+	)
+
+	if shared.Transport != sharedTransport || shared.CheckRedirect != nil || shared.Timeout != 0 {
+		t.Errorf("Expected the supplied client to be left untouched, got %+v", shared)
+	}
+	if cfg := sharedTransport.TLSClientConfig; cfg != nil && cfg.InsecureSkipVerify {
+		t.Error("Expected the supplied transport's TLS configuration to be left untouched")
+	}
+
+	own, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok || own == sharedTransport {
+		t.Fatalf("Expected the client to send through a clone of the transport, got %T", client.httpClient.Transport)
+	}
+	if own.TLSClientConfig == nil || !own.TLSClientConfig.InsecureSkipVerify {
+		t.Error("Expected the TLS configuration to apply to the clone")
+	}
+	if client.httpClient.Timeout != time.Second {
+		t.Errorf("Expected timeout 1s on the copy, got %s", client.httpClient.Timeout)
+	}
+}
+
+func newTLSTestServer(t *testing.T) (*httptest.Server, *x509.CertPool) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	return server, pool
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server, pool := newTLSTestServer(t)
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL))
+	if err := client.Get(context.Background(), "/ping", nil, nil); err == nil {
+		t.Error("Expected error for untrusted certificate, got nil")
+	}
+
+	client = NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+	)
+	if err := client.Get(context.Background(), "/ping", nil, nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWithCertificatePins(t *testing.T) {
+	server, pool := newTLSTestServer(t)
+	tlsConfig := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	// The test certificate is valid for example.com, so route that name to
+	// the test server to exercise pinning against a DNS host.
+	newPinnedClient := func(opts ...ClientOption) *Client {
+		opts = append([]ClientOption{WithBaseURL("https://example.com"), WithTLSConfig(tlsConfig)}, opts...)
+		client := NewClient("test-key", "test-secret", opts...)
+		client.transport().DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server.Listener.Addr().String())
+		}
+		return client
+	}
+	ctx := context.Background()
+
+	client := newPinnedClient(WithCertificatePins("example.com", CertificatePin(server.Certificate())))
+	if err := client.Get(ctx, "/ping", nil, nil); err != nil {
+		t.Errorf("Unexpected error with matching pin: %v", err)
+	}
+
+	client = newPinnedClient(WithCertificatePins("example.com", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="))
+	if err := client.Get(ctx, "/ping", nil, nil); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("Expected ErrCertificatePinMismatch, got %v", err)
+	}
+
+	client = newPinnedClient(WithCertificatePins("panel.signalads.com", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="))
+	if err := client.Get(ctx, "/ping", nil, nil); err != nil {
+		t.Errorf("Expected pins for another host to be ignored, got %v", err)
+	}
+}
+
+func TestVerifyPins_VerifiedChainsOnly(t *testing.T) {
+	server, _ := newTLSTestServer(t)
+	leaf := server.Certificate()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pinned, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pins := map[string]bool{CertificatePin(pinned): true}
+
+	// The pinned certificate is appended to a chain that does not lead to it
+	cs := tls.ConnectionState{
+		ServerName:       "example.com",
+		PeerCertificates: []*x509.Certificate{leaf, pinned},
+		VerifiedChains:   [][]*x509.Certificate{{leaf}},
+	}
+	if err := verifyPins(cs, pins); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("Expected ErrCertificatePinMismatch for an unverified pinned certificate, got %v", err)
+	}
+
+	cs.VerifiedChains = nil
+	if err := verifyPins(cs, map[string]bool{CertificatePin(leaf): true}); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("Expected ErrCertificatePinMismatch without verified chains, got %v", err)
+	}
+
+	cs.VerifiedChains = [][]*x509.Certificate{{leaf}, {pinned}}
+	if err := verifyPins(cs, pins); err != nil {
+		t.Errorf("Expected a pin in a verified chain to match, got %v", err)
+	}
+}
+
+func TestRedirectPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, "http://example.invalid/new", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	client := NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithRedirectPolicy(NoRedirects))
+	if err := client.Get(ctx, "/old", nil, nil); GetStatusCode(err) != http.StatusFound {
+		t.Errorf("Expected redirect response to be returned as error, got %v", err)
+	}
+
+	client = NewClient("test-key", "test-secret", WithBaseURL(server.URL), WithRedirectPolicy(SameHostRedirects(3)))
+	if err := client.Get(ctx, "/old", nil, nil); err != nil {
+		t.Errorf("Expected same-host redirect to be followed, got %v", err)
+	}
+	if err := client.Get(ctx, "/away", nil, nil); err == nil {
+		t.Error("Expected foreign-host redirect to be refused, got nil")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL("http://api.signalads.invalid/api/v1"),
+		WithProxy(proxyURL),
+	)
+
+	if err := client.Get(context.Background(), "/user/info", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proxied != "http://api.signalads.invalid/api/v1/user/info" {
+		t.Errorf("Expected request to go through proxy, got '%s'", proxied)
+	}
+
+	client = NewClient("test-key", "test-secret", WithProxy(proxyURL), WithProxy(nil))
+	if client.transport().Proxy != nil {
+		t.Error("Expected WithProxy(nil) to disable proxying")
+	}
+}
+
+func TestWithProxy_SharedClientUntouched(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.signalads.invalid:3128")
+	req := httptest.NewRequest(http.MethodGet, "http://api.signalads.invalid/", nil)
+
+	client := NewClient("test-key", "test-secret", WithHTTPClient(http.DefaultClient), WithProxy(proxyURL))
+	if http.DefaultClient.Transport != nil {
+		t.Errorf("Expected http.DefaultClient to be left untouched, got transport %T", http.DefaultClient.Transport)
+	}
+	if got, _ := client.transport().Proxy(req); got == nil || got.String() != proxyURL.String() {
+		t.Errorf("Expected the client to use the proxy, got %v", got)
+	}
+
+	shared := &http.Client{Transport: http.DefaultTransport}
+	NewClient("test-key", "test-secret", WithHTTPClient(shared), WithProxy(proxyURL))
+	if shared.Transport != http.DefaultTransport {
+		t.Error("Expected the supplied client's transport to be left in place")
+	}
+	if got, _ := http.DefaultTransport.(*http.Transport).Proxy(req); got != nil && got.String() == proxyURL.String() {
+		t.Error("Expected http.DefaultTransport's proxy to be left untouched")
+	}
+}
+
+func TestWithProxyFromEnvironment(t *testing.T) {
+	// http.ProxyFromEnvironment caches the environment on first use, so only
+	// check that a proxy function is installed.
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	client := NewClient("test-key", "test-secret", WithHTTPClient(httpClient), WithProxyFromEnvironment())
+
+	if client.transport().Proxy == nil {
+		t.Error("Expected proxy function to be set")
+	}
+	if NewClient("test-key", "test-secret").transport().Proxy == nil {
+		t.Error("Expected default transport to use the environment proxy")
+	}
+}
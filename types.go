@@ -10,6 +10,9 @@ import (
 type PaginationParams struct {
 	Page    int `json:"page,omitempty"`
 	PerPage int `json:"per_page,omitempty"`
+
+	// Conditions the listed items must meet (optional)
+	Filter *Filter `json:"-"`
 }
 
 // PaginatedResponse represents a paginated API response
@@ -40,12 +43,24 @@ type SendMessageRequest struct {
 	// Sender ID or phone number (optional)
 	From string `json:"from,omitempty"`
 
+	// Character encoding (optional, defaults to EncodingAuto)
+	Encoding Encoding `json:"encoding,omitempty"`
+
+	// Category of the message (optional); see Category
+	Category Category `json:"category,omitempty"`
+
+	// Cost attribution labels for ReportsService (optional)
+	Tag       string `json:"tag,omitempty"`
+	ClientRef string `json:"client_ref,omitempty"`
+
 	// Additional parameters that may be supported by the API
 	Params map[string]interface{} `json:"params,omitempty"`
 }
 
 // SendMessageResponse represents the response from sending a message
 type SendMessageResponse struct {
+	RawResponse
+
 	// Message ID if successful
 	ID string `json:"id,omitempty"`
 
@@ -66,6 +81,10 @@ type SendMessageResponse struct {
 
 	// Additional response data
 	Data map[string]interface{} `json:"data,omitempty"`
+
+	// Responses of the separate messages a long text was split into, set
+	// only by LongMessageSplit
+	Parts []SendMessageResponse `json:"-"`
 }
 
 // BulkMessageItem represents a single message in a bulk send request
@@ -83,12 +102,24 @@ type SendBulkMessageRequest struct {
 	// Sender ID or phone number (optional)
 	From string `json:"from,omitempty"`
 
+	// Character encoding of all messages (optional, defaults to EncodingAuto)
+	Encoding Encoding `json:"encoding,omitempty"`
+
+	// Category of the message (optional); see Category
+	Category Category `json:"category,omitempty"`
+
+	// Cost attribution labels for ReportsService (optional)
+	Tag       string `json:"tag,omitempty"`
+	ClientRef string `json:"client_ref,omitempty"`
+
 	// Additional parameters
 	Params map[string]interface{} `json:"params,omitempty"`
 }
 
 // SendBulkMessageResponse represents the response from bulk sending
 type SendBulkMessageResponse struct {
+	RawResponse
+
 	// Total number of messages sent
 	Total int `json:"total"`
 
@@ -104,6 +135,13 @@ type SendBulkMessageResponse struct {
 	// Detailed results for each message
 	Results []SendMessageResponse `json:"results,omitempty"`
 
+	// Messages that were not accepted
+	FailedItems []BulkFailure `json:"failed_items,omitempty"`
+
+	// Recipients dropped by the pre-filter before sending, indexed by their
+	// position in the original request
+	Rejected []BulkFailure `json:"-"`
+
 	// Status
 	Status string `json:"status"`
 
@@ -111,6 +149,21 @@ type SendBulkMessageResponse struct {
 	Data map[string]interface{} `json:"data,omitempty"`
 }
 
+// BulkFailure describes a message in a bulk request that was not accepted
+type BulkFailure struct {
+	// Position of the message in the request
+	Index int `json:"index"`
+
+	// Recipient phone number
+	Recipient string `json:"recipient"`
+
+	// Error code reported for this message
+	Code string `json:"code,omitempty"`
+
+	// Error message reported for this message
+	Message string `json:"message,omitempty"`
+}
+
 // SendTemplateMessageRequest represents a request to send a template message
 type SendTemplateMessageRequest struct {
 	// Recipient phone number (required)
@@ -125,10 +178,53 @@ type SendTemplateMessageRequest struct {
 	// Sender ID or phone number (optional)
 	From string `json:"from,omitempty"`
 
+	// Category of the message (optional); see Category
+	Category Category `json:"category,omitempty"`
+
+	// Cost attribution labels for ReportsService (optional)
+	Tag       string `json:"tag,omitempty"`
+	ClientRef string `json:"client_ref,omitempty"`
+
 	// Additional parameters
 	Params map[string]interface{} `json:"params,omitempty"`
 }
 
+// SendPatternRequest represents a request to the pattern (verify lookup)
+// endpoint
+type SendPatternRequest struct {
+	// Recipient phone number (required)
+	To string `json:"recipient"`
+
+	// Code of a pattern approved in the panel (required)
+	PatternCode string `json:"pattern_code"`
+
+	// Values of the pattern's variables, by variable name
+	Values map[string]string `json:"values,omitempty"`
+
+	// Sender line (optional)
+	From string `json:"originator,omitempty"`
+
+	// Category of the message (optional); defaults to CategoryOTP
+	Category Category `json:"category,omitempty"`
+}
+
+// SendPatternResponse represents the response from the pattern endpoint
+type SendPatternResponse struct {
+	RawResponse
+
+	// Message ID if successful
+	MessageID string `json:"message_id"`
+
+	// Status of the message
+	Status string `json:"status"`
+
+	// Recipient phone number
+	To string `json:"recipient,omitempty"`
+
+	// Cost of the message (if available)
+	Cost float64 `json:"cost,omitempty"`
+}
+
 // SendVoiceMessageRequest represents a request to send a voice/audio message
 type SendVoiceMessageRequest struct {
 	// Recipient phone number (required)
@@ -149,10 +245,170 @@ type SendVoiceMessageRequest struct {
 	// Sender ID or phone number (optional)
 	From string `json:"from,omitempty"`
 
+	// Category of the message (optional); see Category
+	Category Category `json:"category,omitempty"`
+
+	// Cost attribution labels for ReportsService (optional)
+	Tag       string `json:"tag,omitempty"`
+	ClientRef string `json:"client_ref,omitempty"`
+
 	// Additional parameters
 	Params map[string]interface{} `json:"params,omitempty"`
 }
 
+// SendBulkVoiceRequest represents a request to send one voice message to
+// many recipients
+type SendBulkVoiceRequest struct {
+	// Recipient phone numbers (required)
+	Recipients []string `json:"recipients"`
+
+	// Voice message text - will be converted to speech
+	Message string `json:"message,omitempty"`
+
+	// Audio file URL (optional, if provided, this will be used instead of text-to-speech)
+	AudioURL string `json:"audio_url,omitempty"`
+
+	// Voice type (optional, e.g., "male", "female")
+	VoiceType string `json:"voice_type,omitempty"`
+
+	// Language (optional, e.g., "fa", "en")
+	Language string `json:"language,omitempty"`
+
+	// Sender ID or phone number (optional)
+	From string `json:"from,omitempty"`
+
+	// Campaign grouping the calls (optional); generated when empty
+	CampaignID string `json:"campaign_id,omitempty"`
+
+	// Category of the message (optional); see Category
+	Category Category `json:"category,omitempty"`
+
+	// Cost attribution labels for ReportsService (optional)
+	Tag       string `json:"tag,omitempty"`
+	ClientRef string `json:"client_ref,omitempty"`
+
+	// Additional parameters
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Maximum recipients per API call; zero uses DefaultVoiceChunkSize
+	ChunkSize int `json:"-"`
+}
+
+// SendBulkVoiceResponse represents the outcome of a bulk voice send
+type SendBulkVoiceResponse struct {
+	CampaignID string
+
+	// Calls accepted by the API
+	Messages []SendMessageResponse
+
+	// Recipients whose call was not accepted, indexed by their position in
+	// the request
+	FailedItems []BulkFailure
+
+	// Recipients dropped by the pre-filter or frequency cap before sending
+	Rejected []BulkFailure
+
+	// Recipients skipped because the checkpoint shows them already sent
+	Skipped int
+}
+
+// SendOTPRequest represents a request to send a one-time password
+type SendOTPRequest struct {
+	// Recipient phone number (required)
+	To string
+
+	// The one-time password (required)
+	Code string
+
+	// Template ID (required)
+	TemplateID string
+
+	// Template parameter the code is passed as; defaults to
+	// DefaultOTPCodeParam
+	CodeParam string
+
+	// How long the code is valid; defaults to DefaultOTPTTL
+	TTL time.Duration
+
+	// Template parameter the validity is passed as, in whole minutes
+	// (optional)
+	TTLParam string
+
+	// Additional template parameters (optional)
+	Params map[string]string
+
+	// Sender ID or phone number (optional)
+	From string
+}
+
+// OTPSendResult represents the outcome of sending a one-time password
+type OTPSendResult struct {
+	MessageID string
+	Status    string
+	Cost      float64
+
+	// When the code stops being valid
+	ExpiresAt time.Time
+}
+
+// VoiceCampaign represents the progress of a bulk voice send
+type VoiceCampaign struct {
+	RawResponse
+
+	ID string `json:"id"`
+
+	// e.g. "running", "completed"
+	Status string `json:"status"`
+
+	Total    int `json:"total"`
+	Answered int `json:"answered"`
+	NoAnswer int `json:"no_answer"`
+	Busy     int `json:"busy"`
+	Failed   int `json:"failed"`
+	Pending  int `json:"pending"`
+
+	Cost        float64   `json:"cost,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// File represents a hosted document or audio file
+type File struct {
+	RawResponse
+
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Public URL to use as DocumentLink or AudioURL
+	URL string `json:"url"`
+
+	ContentType string `json:"content_type,omitempty"`
+
+	// Size in bytes
+	Size int64 `json:"size,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// ListFilesResponse represents the response from listing hosted files
+type ListFilesResponse struct {
+	RawResponse
+
+	Files []File `json:"files"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
+}
+
 // Message represents a message in the list
 type Message struct {
 	ID          string    `json:"id"`
@@ -165,31 +421,109 @@ type Message struct {
 	DeliveredAt time.Time `json:"delivered_at,omitempty"`
 	ReadAt      time.Time `json:"read_at,omitempty"`
 	Error       string    `json:"error,omitempty"`
+	Tag         string    `json:"tag,omitempty"`
+	ClientRef   string    `json:"client_ref,omitempty"`
 	CreatedAt   time.Time `json:"created_at,omitempty"`
 }
 
 // ListMessagesResponse represents the response from listing messages
 type ListMessagesResponse struct {
+	RawResponse
+
 	Messages []Message `json:"messages"`
-	Page     int       `json:"page"`
-	PerPage  int       `json:"per_page"`
-	Total    int       `json:"total"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
+}
+
+// Answer statuses reported in Call.AnswerStatus.
+const (
+	CallAnswered = "answered"
+	CallNoAnswer = "no_answer"
+	CallBusy     = "busy"
+	CallFailed   = "failed"
+)
+
+// Call represents the detail record of a voice call
+type Call struct {
+	RawResponse
+
+	ID string `json:"id"`
+
+	// ID of the voice message that placed the call
+	MessageID string `json:"message_id,omitempty"`
+
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+
+	// Whether the call was answered, e.g. CallAnswered, CallNoAnswer
+	AnswerStatus string `json:"answer_status"`
+
+	// Call duration in seconds, counted from answer
+	Duration int `json:"duration"`
+
+	// Keys pressed by the recipient during the call
+	DTMF string `json:"dtmf,omitempty"`
+
+	Cost       float64   `json:"cost,omitempty"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	AnsweredAt time.Time `json:"answered_at,omitempty"`
+	EndedAt    time.Time `json:"ended_at,omitempty"`
+}
+
+// ListCallsResponse represents the response from listing calls
+type ListCallsResponse struct {
+	RawResponse
+
+	Calls []Call `json:"calls"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
 }
 
 // MessageStatus represents the status of a message
 type MessageStatus struct {
-	ID          string    `json:"id"`
-	Status      string    `json:"status"` // e.g., "sent", "delivered", "failed", "pending"
+	RawResponse
+
+	ID string `json:"id"`
+
+	// Status as the API spelled it, e.g. "DELIVRD"; see StatusCode
+	Status string `json:"status"`
+
 	To          string    `json:"to"`
 	SentAt      time.Time `json:"sent_at,omitempty"`
 	DeliveredAt time.Time `json:"delivered_at,omitempty"`
 	ReadAt      time.Time `json:"read_at,omitempty"`
 	Error       string    `json:"error,omitempty"`
+	ErrorCode   string    `json:"error_code,omitempty"`
 	Cost        float64   `json:"cost,omitempty"`
+
+	// Why the message was not delivered, derived from ErrorCode or Error
+	Reason FailureReason `json:"reason,omitempty"`
 }
 
 // UserInfo represents user account information
 type UserInfo struct {
+	RawResponse
+
 	ID          string    `json:"id"`
 	Username    string    `json:"username,omitempty"`
 	Email       string    `json:"email,omitempty"`
@@ -201,3 +535,237 @@ type UserInfo struct {
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
 	Permissions []string  `json:"permissions,omitempty"`
 }
+
+// Line represents a sender line of the account
+type Line struct {
+	Number string `json:"number"`
+
+	// Line type, LineDedicated or LineShared
+	Type string `json:"type,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	// When the line expires unless renewed; zero for lines that do not
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+
+	AutoRenew bool `json:"auto_renew,omitempty"`
+}
+
+// ListLinesResponse represents the response from listing lines
+type ListLinesResponse struct {
+	RawResponse
+
+	Lines []Line `json:"lines"`
+}
+
+// ScheduledMessage represents a message queued for later delivery
+type ScheduledMessage struct {
+	RawResponse
+
+	ID          string    `json:"id"`
+	To          string    `json:"to"`
+	From        string    `json:"from,omitempty"`
+	Message     string    `json:"message"`
+	Status      string    `json:"status,omitempty"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+
+// ListScheduledMessagesResponse represents the response from listing
+// scheduled messages
+type ListScheduledMessagesResponse struct {
+	RawResponse
+
+	Messages []ScheduledMessage `json:"messages"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
+}
+
+// RescheduleMessageRequest represents a request to move a scheduled message
+type RescheduleMessageRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// DateRange represents an inclusive range of days
+type DateRange struct {
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+}
+
+// DailyQuota represents the account's daily send quota
+type DailyQuota struct {
+	RawResponse
+
+	// Messages allowed per day; zero if the account has no daily quota
+	Limit int `json:"limit"`
+
+	// Messages sent today
+	Used int `json:"used"`
+
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at,omitempty"`
+}
+
+// QuotaForecast represents whether a planned send fits the daily quota
+type QuotaForecast struct {
+	Planned int
+
+	// Messages left today, or UnlimitedQuota
+	Remaining int
+
+	Fits bool
+
+	// Messages that would be cut off; zero when the send fits
+	Shortfall int
+
+	// When the quota resets (zero if not reported)
+	ResetsAt time.Time
+}
+
+// CostReport represents messages and cost grouped by an attribution label
+type CostReport struct {
+	RawResponse
+
+	// CostGroupByTag or CostGroupByClientRef
+	GroupBy string `json:"group_by"`
+
+	Groups    []CostGroup `json:"groups"`
+	TotalCost float64     `json:"total_cost"`
+	Currency  string      `json:"currency,omitempty"`
+}
+
+// CostGroup represents the messages of one tag or client reference
+type CostGroup struct {
+	Key      string  `json:"key"`
+	Messages int     `json:"messages"`
+	Segments int     `json:"segments,omitempty"`
+	Cost     float64 `json:"cost"`
+}
+
+// Template represents a message template defined in the panel
+type Template struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Template text with parameter placeholders
+	Body string `json:"body"`
+
+	// Parameters the template accepts
+	Params []TemplateParam `json:"params,omitempty"`
+
+	// Review status, e.g. "approved", "pending", "rejected"
+	Status string `json:"status,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// TemplateParam describes one parameter of a template
+type TemplateParam struct {
+	Name string `json:"name"`
+
+	// Value type, one of the TemplateParam* constants
+	Type string `json:"type,omitempty"`
+
+	Required bool `json:"required,omitempty"`
+
+	// Minimum and maximum value length; zero means unlimited
+	MinLength int `json:"min_length,omitempty"`
+	MaxLength int `json:"max_length,omitempty"`
+
+	// Regular expression the whole value must match (optional)
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// ListTemplatesResponse represents the response from listing templates
+type ListTemplatesResponse struct {
+	RawResponse
+
+	Templates []Template `json:"templates"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
+}
+
+// TemplateUsageStats represents usage statistics of a message template
+type TemplateUsageStats struct {
+	RawResponse
+
+	TemplateID string `json:"template_id"`
+
+	// Number of messages sent with the template
+	Sent int `json:"sent"`
+
+	// Number of messages confirmed delivered
+	Delivered int `json:"delivered"`
+
+	// Number of messages that failed
+	Failed int `json:"failed"`
+
+	// Delivered divided by Sent, between 0 and 1
+	DeliveryRate float64 `json:"delivery_rate"`
+
+	// Total cost of the messages
+	Cost float64 `json:"cost"`
+
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+}
+
+// ListInboundMessagesResponse represents the response from listing received
+// messages
+type ListInboundMessagesResponse struct {
+	RawResponse
+
+	Messages []InboundMessageEvent `json:"messages"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
+}
+
+// ListLinkClicksResponse represents the response from listing short link
+// clicks
+type ListLinkClicksResponse struct {
+	RawResponse
+
+	Clicks []LinkClickEvent `json:"clicks"`
+
+	// Position of the page in the full list
+	Pagination Pagination `json:"-"`
+
+	// Deprecated: Use Pagination.Page.
+	Page int `json:"page"`
+
+	// Deprecated: Use Pagination.PerPage.
+	PerPage int `json:"per_page"`
+
+	// Deprecated: Use Pagination.Total.
+	Total int `json:"total"`
+}
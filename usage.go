@@ -0,0 +1,112 @@
+package signalads
+
+import "context"
+
+// Channels reported in Usage.Channel.
+const (
+	UsageChannelSMS      = "sms"
+	UsageChannelTemplate = "template"
+	UsageChannelPattern  = "pattern"
+	UsageChannelVoice    = "voice"
+)
+
+// Usage describes the messages accepted by one successful send call.
+type Usage struct {
+	// Tenant set on the request context with WithTenant; empty if none
+	Tenant string
+
+	// Kind of send, such as UsageChannelSMS
+	Channel string
+
+	// Number of messages the API accepted
+	Messages int
+
+	// Number of SMS segments in the accepted messages; zero for template,
+	// pattern and voice messages, whose length is only known to the API
+	Segments int
+
+	// Cost reported by the API, or Segments multiplied by the price set
+	// with WithSegmentPrice when the API reports none
+	Cost float64
+}
+
+// UsageRecorder receives usage after every successful send, for example to
+// charge messages back to internal tenants. RecordUsage is called
+// synchronously on the sending goroutine and should return quickly.
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, usage Usage)
+}
+
+// UsageRecorderFunc adapts a function to UsageRecorder.
+type UsageRecorderFunc func(ctx context.Context, usage Usage)
+
+// RecordUsage calls f(ctx, usage).
+func (f UsageRecorderFunc) RecordUsage(ctx context.Context, usage Usage) {
+	f(ctx, usage)
+}
+
+// MultiUsageRecorder returns a recorder passing usage to each of recorders
+// in turn.
+func MultiUsageRecorder(recorders ...UsageRecorder) UsageRecorder {
+	return UsageRecorderFunc(func(ctx context.Context, usage Usage) {
+		for _, recorder := range recorders {
+			recorder.RecordUsage(ctx, usage)
+		}
+	})
+}
+
+// WithUsageRecorder registers recorder to be called after each successful
+// send.
+func WithUsageRecorder(recorder UsageRecorder) ClientOption {
+	return func(c *Client) {
+		c.usageRecorder = recorder
+	}
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context that attributes sends made with it to tenant
+// in recorded usage.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set with WithTenant, or an empty
+// string.
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenant
+}
+
+func (c *Client) recordUsage(ctx context.Context, usage Usage) {
+	if c.usageRecorder == nil {
+		return
+	}
+	usage.Tenant = TenantFromContext(ctx)
+	if usage.Cost <= 0 {
+		usage.Cost = float64(usage.Segments) * c.segmentPrice
+	}
+	c.usageRecorder.RecordUsage(ctx, usage)
+}
+
+// bulkUsage counts the messages of req that are not listed as failed in
+// response.
+func bulkUsage(req *SendBulkMessageRequest, response *SendBulkMessageResponse) Usage {
+	failed := make(map[int]bool, len(response.FailedItems))
+	for i := range response.FailedItems {
+		failed[response.FailedItems[i].Index] = true
+	}
+
+	usage := Usage{Channel: UsageChannelSMS}
+	for i := range req.Messages {
+		if failed[i] {
+			continue
+		}
+		usage.Messages++
+		usage.Segments += CountSegmentsWithEncoding(req.Messages[i].Message, req.Encoding)
+	}
+	for i := range response.Results {
+		usage.Cost += response.Results[i].Cost
+	}
+	return usage
+}
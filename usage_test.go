@@ -0,0 +1,129 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUsageRecorder_Single(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendMessageResponse{ID: "msg-1", Status: "sent"})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var recorded []Usage
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithSegmentPrice(100),
+		WithUsageRecorder(UsageRecorderFunc(func(ctx context.Context, usage Usage) {
+			recorded = append(recorded, usage)
+		})),
+	)
+
+	ctx := WithTenant(context.Background(), "shop-42")
+	if _, err := client.Messages.SendMessage(ctx, "+989123456789", strings.Repeat("x", 200)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("Expected 1 usage record, got %d", len(recorded))
+	}
+	usage := recorded[0]
+	if usage.Tenant != "shop-42" {
+		t.Errorf("Expected tenant 'shop-42', got '%s'", usage.Tenant)
+	}
+	if usage.Channel != UsageChannelSMS || usage.Messages != 1 || usage.Segments != 2 {
+		t.Errorf("Expected 1 SMS of 2 segments, got %+v", usage)
+	}
+	if usage.Cost != 200 {
+		t.Errorf("Expected cost 200, got %.2f", usage.Cost)
+	}
+}
+
+func TestUsageRecorder_BulkSkipsFailures(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SendBulkMessageResponse{
+			Total:   3,
+			Success: 2,
+			Failed:  1,
+			Results: []SendMessageResponse{
+				{Status: "sent", Cost: 120},
+				{Status: "failed"},
+				{Status: "sent", Cost: 120},
+			},
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var recorded []Usage
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithUsageRecorder(UsageRecorderFunc(func(ctx context.Context, usage Usage) {
+			recorded = append(recorded, usage)
+		})),
+	)
+
+	_, err := client.Messages.SendBulkMessages(context.Background(), &SendBulkMessageRequest{
+		Messages: []BulkMessageItem{
+			{To: "+989123456781", Message: "Hi"},
+			{To: "+989123456782", Message: "Hi"},
+			{To: "+989123456783", Message: "Hi"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("Expected 1 usage record, got %d", len(recorded))
+	}
+	if recorded[0].Messages != 2 || recorded[0].Segments != 2 {
+		t.Errorf("Expected 2 accepted messages, got %+v", recorded[0])
+	}
+	if recorded[0].Cost != 240 {
+		t.Errorf("Expected reported cost 240, got %.2f", recorded[0].Cost)
+	}
+}
+
+func TestUsageRecorder_NotCalledOnError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient("test-key", "test-secret",
+		WithBaseURL(server.URL),
+		WithUsageRecorder(UsageRecorderFunc(func(ctx context.Context, usage Usage) {
+			t.Error("Expected no usage for a failed send")
+		})),
+	)
+
+	if _, err := client.Messages.SendMessage(context.Background(), "+989123456789", "Hi"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestMultiUsageRecorder(t *testing.T) {
+	var first, second int
+	recorder := MultiUsageRecorder(
+		UsageRecorderFunc(func(ctx context.Context, usage Usage) { first += usage.Messages }),
+		UsageRecorderFunc(func(ctx context.Context, usage Usage) { second += usage.Messages }),
+	)
+
+	recorder.RecordUsage(context.Background(), Usage{Messages: 2})
+	if first != 2 || second != 2 {
+		t.Errorf("Expected both recorders to see 2 messages, got %d and %d", first, second)
+	}
+}
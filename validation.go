@@ -0,0 +1,192 @@
+package signalads
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validation rules reported in FieldError.Rule.
+const (
+	RuleRequired        = "required"
+	RuleRequiredWithout = "required_without"
+	RuleMinItems        = "min_items"
+	RuleEncoding        = "encoding"
+	RuleMaxSegments     = "max_segments"
+	RuleUnique          = "unique"
+	RuleMinLength       = "min_length"
+	RuleMaxLength       = "max_length"
+	RulePattern         = "pattern"
+	RuleUnknown         = "unknown"
+)
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	// JSON path of the field, such as "to" or "messages[2].message". Empty
+	// when the request as a whole is invalid.
+	Field string `json:"field"`
+
+	// Rule that the field violated, such as RuleRequired
+	Rule string `json:"rule"`
+
+	// Human-readable description of the problem
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a request fails client-side validation
+// before it is sent. It lists every problem found, not just the first.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for i := range e.Errors {
+		fe := &e.Errors[i]
+		if fe.Field == "" {
+			messages = append(messages, fe.Message)
+		} else {
+			messages = append(messages, fe.Field+": "+fe.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fields returns the paths of all invalid fields.
+func (e *ValidationError) Fields() []string {
+	fields := make([]string, 0, len(e.Errors))
+	for i := range e.Errors {
+		fields = append(fields, e.Errors[i].Field)
+	}
+	return fields
+}
+
+// IsValidationError reports whether err is or wraps a *ValidationError.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// validator collects field errors for a single request.
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field, rule, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+func (v *validator) required(field, value, message string) {
+	if value == "" {
+		v.add(field, RuleRequired, message)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
+
+func nilRequestError() error {
+	return &ValidationError{Errors: []FieldError{{Rule: RuleRequired, Message: "request cannot be nil"}}}
+}
+
+func indexedField(list string, i int, field string) string {
+	return fmt.Sprintf("%s[%d].%s", list, i, field)
+}
+
+func validateSendMessageRequest(req *SendMessageRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	v.required("to", req.To, "recipient phone number is required")
+	v.required("message", req.Message, "message text is required")
+	v.encoding("message", req.Message, req.Encoding)
+	return v.err()
+}
+
+func validateSendBulkMessageRequest(req *SendBulkMessageRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	if len(req.Messages) == 0 {
+		v.add("messages", RuleMinItems, "at least one message is required")
+	}
+	for i := range req.Messages {
+		v.required(indexedField("messages", i, "to"), req.Messages[i].To, "recipient phone number is required")
+		v.required(indexedField("messages", i, "message"), req.Messages[i].Message, "message text is required")
+		v.encoding(indexedField("messages", i, "message"), req.Messages[i].Message, req.Encoding)
+	}
+	return v.err()
+}
+
+func validateSendTemplateMessageRequest(req *SendTemplateMessageRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	v.required("to", req.To, "recipient phone number is required")
+	v.required("template_id", req.TemplateID, "template ID is required")
+	return v.err()
+}
+
+func validateSendPatternRequest(req *SendPatternRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	v.required("recipient", req.To, "recipient phone number is required")
+	v.required("pattern_code", req.PatternCode, "pattern code is required")
+	return v.err()
+}
+
+func validateSendOTPRequest(req *SendOTPRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	v.required("to", req.To, "recipient phone number is required")
+	v.required("code", req.Code, "code is required")
+	v.required("template_id", req.TemplateID, "template ID is required")
+	return v.err()
+}
+
+func validateSendVoiceMessageRequest(req *SendVoiceMessageRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	v.required("to", req.To, "recipient phone number is required")
+	if req.Message == "" && req.AudioURL == "" {
+		v.add("message", RuleRequiredWithout, "either message text or audio URL is required")
+	}
+	return v.err()
+}
+
+func validateSendBulkVoiceRequest(req *SendBulkVoiceRequest) error {
+	if req == nil {
+		return nilRequestError()
+	}
+
+	var v validator
+	if len(req.Recipients) == 0 {
+		v.add("recipients", RuleMinItems, "at least one recipient is required")
+	}
+	for i, to := range req.Recipients {
+		v.required(fmt.Sprintf("recipients[%d]", i), to, "recipient phone number is required")
+	}
+	if req.Message == "" && req.AudioURL == "" {
+		v.add("message", RuleRequiredWithout, "either message text or audio URL is required")
+	}
+	return v.err()
+}
@@ -0,0 +1,88 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestValidateSendBulkMessageRequest_FieldPaths(t *testing.T) {
+	req := &SendBulkMessageRequest{
+		Messages: []BulkMessageItem{
+			{To: "+989123456789", Message: "Hello"},
+			{To: "", Message: "Hello"},
+			{To: "+989123456780", Message: ""},
+		},
+	}
+
+	err := validateSendBulkMessageRequest(req)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+
+	expected := []string{"messages[1].to", "messages[2].message"}
+	if !reflect.DeepEqual(ve.Fields(), expected) {
+		t.Errorf("Expected fields %v, got %v", expected, ve.Fields())
+	}
+	for _, fe := range ve.Errors {
+		if fe.Rule != RuleRequired {
+			t.Errorf("Expected rule %s, got %s", RuleRequired, fe.Rule)
+		}
+	}
+}
+
+func TestValidateSendVoiceMessageRequest(t *testing.T) {
+	err := validateSendVoiceMessageRequest(&SendVoiceMessageRequest{})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+	if len(ve.Errors) != 2 {
+		t.Fatalf("Expected 2 field errors, got %d", len(ve.Errors))
+	}
+	if ve.Errors[1].Rule != RuleRequiredWithout {
+		t.Errorf("Expected rule %s, got %s", RuleRequiredWithout, ve.Errors[1].Rule)
+	}
+
+	if err := validateSendVoiceMessageRequest(&SendVoiceMessageRequest{To: "+989123456789", AudioURL: "https://example.com/a.mp3"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Errors: []FieldError{
+		{Field: "to", Rule: RuleRequired, Message: "recipient phone number is required"},
+		{Field: "template_id", Rule: RuleRequired, Message: "template ID is required"},
+	}}
+
+	expected := "to: recipient phone number is required; template_id: template ID is required"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+	if !IsValidationError(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("Expected IsValidationError to see through wrapping")
+	}
+}
+
+func TestMessagesService_ValidationBeforeSend(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for an invalid message")
+	}
+
+	client := setupTestClient(handler)
+
+	_, err := client.Messages.SendTemplateMessage(context.Background(), &SendTemplateMessageRequest{})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	expected := []string{"to", "template_id"}
+	if !reflect.DeepEqual(ve.Fields(), expected) {
+		t.Errorf("Expected fields %v, got %v", expected, ve.Fields())
+	}
+}
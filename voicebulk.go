@@ -0,0 +1,208 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultVoiceChunkSize is the number of recipients sent per API call by
+// SendBulkVoiceMessages.
+const DefaultVoiceChunkSize = 100
+
+// SendBulkVoiceMessages sends one voice message to many recipients. The
+// recipients are split into chunks of req.ChunkSize, sent one API call at
+// a time under a shared campaign ID that GetVoiceCampaign reports on. The
+// pre-filter, frequency cap and quotas apply as for SendBulkMessages.
+//
+// A chunk that fails does not stop the others: its recipients are reported
+// in FailedItems, and the returned *PartialResult joins the chunk errors
+// and lists the recipients reached, alongside a response covering
+// everything that was sent. ctx is checked before every chunk, so a
+// canceled send stops at the next chunk boundary.
+//
+// With WithCheckpointer, every chunk the API accepted is recorded under
+// req.CampaignID, and sending the same campaign again skips its recipients,
+// so an interrupted send can be resumed by repeating the call.
+func (s *MessagesService) SendBulkVoiceMessages(ctx context.Context, req *SendBulkVoiceRequest) (*SendBulkVoiceResponse, error) {
+	if err := validateSendBulkVoiceRequest(req); err != nil {
+		return nil, err
+	}
+	ctx, category := resolveCategory(ctx, req.Category)
+	if err := s.client.checkCalendar(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	campaignID := req.CampaignID
+	if campaignID == "" {
+		id, err := newID()
+		if err != nil {
+			return nil, err
+		}
+		campaignID = id
+	}
+
+	checkpoint, err := s.client.loadCheckpoint(ctx, req.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	// pending maps positions in items to positions in req.Recipients.
+	items := make([]BulkMessageItem, 0, len(req.Recipients))
+	pending := make([]int, 0, len(req.Recipients))
+	for i, to := range req.Recipients {
+		if checkpoint != nil && checkpoint.Contains(i) {
+			continue
+		}
+		items = append(items, BulkMessageItem{To: to, Message: req.Message})
+		pending = append(pending, i)
+	}
+	filtered, rejected, release, err := s.filterBulkRecipients(ctx, &SendBulkMessageRequest{Messages: items})
+	if err != nil {
+		return nil, err
+	}
+	kept := keptIndexes(len(items), rejected)
+	index := make([]int, len(kept))
+	for i, k := range kept {
+		index[i] = pending[k]
+	}
+	for i := range rejected {
+		rejected[i].Index = pending[rejected[i].Index]
+	}
+
+	response := &SendBulkVoiceResponse{
+		CampaignID: campaignID,
+		Rejected:   rejected,
+		Skipped:    len(req.Recipients) - len(items),
+	}
+	size := req.ChunkSize
+	if size <= 0 {
+		size = DefaultVoiceChunkSize
+	}
+
+	var errs []error
+	for start := 0; start < len(filtered.Messages); start += size {
+		if ctx.Err() != nil {
+			for i := start; i < len(filtered.Messages); i++ {
+				response.FailedItems = append(response.FailedItems, BulkFailure{Index: index[i], Recipient: filtered.Messages[i].To, Message: ctx.Err().Error()})
+				release(filtered.Messages[i].To)
+			}
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		end := min(start+size, len(filtered.Messages))
+		chunk := *req
+		chunk.CampaignID = campaignID
+		chunk.Category = category
+		chunk.From = s.client.fromOrDefault(req.From)
+		chunk.Recipients = make([]string, 0, end-start)
+		for _, item := range filtered.Messages[start:end] {
+			chunk.Recipients = append(chunk.Recipients, item.To)
+		}
+
+		if chunkErr := s.sendVoiceChunk(ctx, &chunk, index[start:end], response, release); chunkErr != nil {
+			errs = append(errs, chunkErr)
+			for i, to := range chunk.Recipients {
+				response.FailedItems = append(response.FailedItems, BulkFailure{Index: index[start+i], Recipient: to, Message: chunkErr.Error()})
+			}
+		} else if checkpoint != nil {
+			// The chunk went out; record it even if ctx was canceled meanwhile.
+			checkpoint.add(index[start:end])
+			if saveErr := s.client.checkpointer.Save(context.WithoutCancel(ctx), req.CampaignID, checkpoint); saveErr != nil {
+				errs = append(errs, fmt.Errorf("failed to save checkpoint: %w", saveErr))
+			}
+		}
+	}
+
+	sort.SliceStable(response.FailedItems, func(i, j int) bool {
+		return response.FailedItems[i].Index < response.FailedItems[j].Index
+	})
+	if len(errs) == 0 {
+		return response, nil
+	}
+	return response, sentPartially(response.Messages, response.FailedItems, errors.Join(errs...))
+}
+
+// sendVoiceChunk sends one chunk of a bulk voice send and adds its results
+// to response. index maps positions in the chunk to positions in the
+// original request. release gives back the frequency slots of the chunk
+// when the governor rejects it.
+func (s *MessagesService) sendVoiceChunk(ctx context.Context, chunk *SendBulkVoiceRequest, index []int, response *SendBulkVoiceResponse, release func(phones ...string)) error {
+	if err := s.client.reserve(ctx, chunk.Recipients...); err != nil {
+		release(chunk.Recipients...)
+		return err
+	}
+
+	var result SendBulkMessageResponse
+	if err := s.client.Post(ctx, "/send-message/voice/bulk", chunk, &result); err != nil {
+		return fmt.Errorf("failed to send bulk voice messages: %w", err)
+	}
+
+	// Reuse the SMS bulk helpers, which only look at recipients.
+	bulkReq := &SendBulkMessageRequest{Messages: make([]BulkMessageItem, len(chunk.Recipients))}
+	for i, to := range chunk.Recipients {
+		bulkReq.Messages[i].To = to
+	}
+	result.fillFailedItems(bulkReq)
+	for _, failure := range result.FailedItems {
+		if failure.Index >= 0 && failure.Index < len(index) {
+			failure.Index = index[failure.Index]
+		}
+		response.FailedItems = append(response.FailedItems, failure)
+	}
+
+	sent := result.sentMessages(bulkReq)
+	response.Messages = append(response.Messages, sent...)
+	s.client.trackBulk(ctx, bulkReq, &result)
+
+	usage := Usage{Channel: UsageChannelVoice, Messages: len(sent)}
+	for i := range result.Results {
+		usage.Cost += result.Results[i].Cost
+	}
+	s.client.recordUsage(ctx, usage)
+	return nil
+}
+
+// keptIndexes returns, in order, the indexes below n that are not in
+// rejected.
+func keptIndexes(n int, rejected []BulkFailure) []int {
+	dropped := make(map[int]bool, len(rejected))
+	for _, r := range rejected {
+		dropped[r.Index] = true
+	}
+	index := make([]int, 0, n-len(rejected))
+	for i := 0; i < n; i++ {
+		if !dropped[i] {
+			index = append(index, i)
+		}
+	}
+	return index
+}
+
+// SendBulkVoice is a convenience method for sending a voice message to
+// many recipients.
+func (s *MessagesService) SendBulkVoice(ctx context.Context, recipients []string, message, voiceType, language string) (*SendBulkVoiceResponse, error) {
+	return s.SendBulkVoiceMessages(ctx, &SendBulkVoiceRequest{
+		Recipients: recipients,
+		Message:    message,
+		VoiceType:  voiceType,
+		Language:   language,
+	})
+}
+
+// GetVoiceCampaign retrieves the progress of a bulk voice send.
+func (s *MessagesService) GetVoiceCampaign(ctx context.Context, campaignID string) (*VoiceCampaign, error) {
+	if campaignID == "" {
+		return nil, &ValidationError{Errors: []FieldError{{Field: "id", Rule: RuleRequired, Message: "campaign ID is required"}}}
+	}
+
+	var campaign VoiceCampaign
+	if err := s.client.Get(ctx, "/voice-campaigns/"+pathSegment(campaignID), &campaign, nil); err != nil {
+		return nil, fmt.Errorf("failed to get voice campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
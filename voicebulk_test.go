@@ -0,0 +1,108 @@
+package signalads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSendBulkVoiceMessages_Chunks(t *testing.T) {
+	var calls atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send-message/voice/bulk" {
+			t.Errorf("Expected path '/send-message/voice/bulk', got '%s'", r.URL.Path)
+		}
+		var req SendBulkVoiceRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.CampaignID != "camp-1" {
+			t.Errorf("Expected campaign 'camp-1', got '%s'", req.CampaignID)
+		}
+		if len(req.Recipients) > 2 {
+			t.Errorf("Expected at most 2 recipients per chunk, got %d", len(req.Recipients))
+		}
+
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if n == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "boom"}`))
+			return
+		}
+
+		var resp SendBulkMessageResponse
+		for _, to := range req.Recipients {
+			status := "queued"
+			if to == "+989120000002" {
+				status = "failed"
+			}
+			resp.Results = append(resp.Results, SendMessageResponse{ID: "call-" + to, To: to, Status: status})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+
+	client := setupTestClient(handler)
+	response, err := client.Messages.SendBulkVoiceMessages(context.Background(), &SendBulkVoiceRequest{
+		Recipients: []string{"+989120000001", "+989120000002", "+989120000003", "+989120000004", "+989120000005"},
+		AudioURL:   "https://example.com/a.mp3",
+		CampaignID: "camp-1",
+		ChunkSize:  2,
+	})
+	if err == nil {
+		t.Error("Expected the failed chunk to be reported")
+	}
+	if response == nil {
+		t.Fatal("Expected a response covering the successful chunks")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 chunks, got %d", calls.Load())
+	}
+	if len(response.Messages) != 2 {
+		t.Errorf("Expected 2 accepted calls, got %d", len(response.Messages))
+	}
+
+	var indexes []int
+	for _, f := range response.FailedItems {
+		indexes = append(indexes, f.Index)
+	}
+	if len(indexes) != 3 || indexes[0] != 1 || indexes[1] != 2 || indexes[2] != 3 {
+		t.Errorf("Expected failed indexes [1 2 3], got %v", indexes)
+	}
+}
+
+func TestSendBulkVoiceMessages_Validation(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for an invalid send")
+	}
+
+	client := setupTestClient(handler)
+	_, err := client.Messages.SendBulkVoiceMessages(context.Background(), &SendBulkVoiceRequest{Recipients: []string{"+989120000001", ""}})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if len(verr.Errors) != 2 {
+		t.Errorf("Expected 2 field errors, got %v", verr.Errors)
+	}
+}
+
+func TestGetVoiceCampaign(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/voice-campaigns/camp-1" {
+			t.Errorf("Expected path '/voice-campaigns/camp-1', got '%s'", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "camp-1", "status": "running", "total": 5, "answered": 2, "pending": 3}`))
+	}
+
+	client := setupTestClient(handler)
+	campaign, err := client.Messages.GetVoiceCampaign(context.Background(), "camp-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if campaign.Total != 5 || campaign.Answered != 2 || campaign.Pending != 3 {
+		t.Errorf("Unexpected campaign: %+v", campaign)
+	}
+}
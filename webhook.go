@@ -0,0 +1,312 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Webhook event types.
+const (
+	EventInboundMessage = "message.inbound"
+	EventDeliveryReport = "message.status"
+	EventLinkClick      = "link.click"
+)
+
+// maxWebhookBodySize bounds the size of a webhook request body.
+const maxWebhookBodySize = 1 << 20
+
+// WebhookEvent is the envelope of every webhook notification. Data holds
+// the type-specific payload; use the typed accessors such as
+// InboundMessage to decode it.
+type WebhookEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+
+	// done receives the outcome reported by a consumer of the event
+	// channel through WebhookHandler.Done.
+	done chan error
+}
+
+// InboundMessageEvent is an SMS received on one of the account's lines.
+type InboundMessageEvent struct {
+	ID string `json:"id"`
+
+	// Phone number of the sender
+	From string `json:"from"`
+
+	// Line number the message was sent to
+	To string `json:"to"`
+
+	Message    string    `json:"message"`
+	ReceivedAt time.Time `json:"received_at,omitempty"`
+
+	// Media attached to an MMS; fetch them with InboxService.DownloadAttachment
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a media file received with an inbound message.
+type Attachment struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type,omitempty"`
+
+	// Size in bytes
+	Size int64 `json:"size,omitempty"`
+
+	Filename string `json:"filename,omitempty"`
+}
+
+// DeliveryReportEvent reports a status change of a sent message.
+type DeliveryReportEvent struct {
+	MessageID string    `json:"message_id"`
+	To        string    `json:"to,omitempty"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode string    `json:"error_code,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+
+	// Why the message was not delivered, derived from ErrorCode or Error
+	Reason FailureReason `json:"reason,omitempty"`
+}
+
+// LinkClickEvent reports a click on a short link sent in a message.
+type LinkClickEvent struct {
+	LinkID    string `json:"link_id"`
+	MessageID string `json:"message_id,omitempty"`
+
+	// Phone number the link was sent to
+	Recipient string `json:"recipient,omitempty"`
+
+	// Destination URL of the link
+	URL string `json:"url"`
+
+	ClickedAt time.Time `json:"clicked_at"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+}
+
+// InboundMessage decodes the payload of an EventInboundMessage event.
+func (e *WebhookEvent) InboundMessage() (*InboundMessageEvent, error) {
+	var msg InboundMessageEvent
+	if err := e.decodeData(EventInboundMessage, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// DeliveryReport decodes the payload of an EventDeliveryReport event.
+func (e *WebhookEvent) DeliveryReport() (*DeliveryReportEvent, error) {
+	var report DeliveryReportEvent
+	if err := e.decodeData(EventDeliveryReport, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// LinkClick decodes the payload of an EventLinkClick event.
+func (e *WebhookEvent) LinkClick() (*LinkClickEvent, error) {
+	var click LinkClickEvent
+	if err := e.decodeData(EventLinkClick, &click); err != nil {
+		return nil, err
+	}
+	return &click, nil
+}
+
+func (e *WebhookEvent) decodeData(eventType string, v interface{}) error {
+	if e.Type != eventType {
+		return fmt.Errorf("event %s has type %q, not %q", e.ID, e.Type, eventType)
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return &DecodeError{Body: e.Data, Err: err}
+	}
+	return nil
+}
+
+// ParseWebhookEvent decodes a webhook event envelope from r.
+func ParseWebhookEvent(r io.Reader) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.NewDecoder(r).Decode(&event); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	if event.Type == "" {
+		return nil, &DecodeError{Err: errors.New("event type is missing")}
+	}
+	return &event, nil
+}
+
+// WebhookFunc processes one webhook event. Returning an error makes the
+// handler respond with a server error so the provider delivers the event
+// again later.
+type WebhookFunc func(ctx context.Context, event *WebhookEvent) error
+
+// WebhookHandler is an http.Handler that receives webhook notifications and
+// dispatches them to the callbacks registered for their event type. Events
+// without callbacks are acknowledged and dropped. Requests are only
+// accepted with a valid signature; see WithSigningSecret.
+type WebhookHandler struct {
+	mu       sync.RWMutex
+	handlers map[string][]WebhookFunc
+
+	deadLetters DeadLetterStore
+	events      chan<- *WebhookEvent
+	overflow    WebhookOverflow
+
+	dedup    DedupStore
+	dedupTTL time.Duration
+
+	secrets   []string
+	tolerance time.Duration
+	insecure  bool
+}
+
+// WebhookOption configures a WebhookHandler.
+type WebhookOption func(*WebhookHandler)
+
+// NewWebhookHandler returns a handler with no callbacks registered.
+func NewWebhookHandler(opts ...WebhookOption) *WebhookHandler {
+	h := &WebhookHandler{handlers: make(map[string][]WebhookFunc)}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// On registers fn for events of eventType. Callbacks run in registration
+// order until one fails.
+func (h *WebhookHandler) On(eventType string, fn WebhookFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+}
+
+// OnInboundMessage registers fn for inbound SMS.
+func (h *WebhookHandler) OnInboundMessage(fn func(ctx context.Context, msg *InboundMessageEvent) error) {
+	h.On(EventInboundMessage, func(ctx context.Context, event *WebhookEvent) error {
+		msg, err := event.InboundMessage()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, msg)
+	})
+}
+
+// OnDeliveryReport registers fn for delivery status reports.
+func (h *WebhookHandler) OnDeliveryReport(fn func(ctx context.Context, report *DeliveryReportEvent) error) {
+	h.On(EventDeliveryReport, func(ctx context.Context, event *WebhookEvent) error {
+		report, err := event.DeliveryReport()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, report)
+	})
+}
+
+// OnLinkClick registers fn for short link clicks.
+func (h *WebhookHandler) OnLinkClick(fn func(ctx context.Context, click *LinkClickEvent) error) {
+	h.On(EventLinkClick, func(ctx context.Context, event *WebhookEvent) error {
+		click, err := event.LinkClick()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, click)
+	})
+}
+
+// Dispatch runs the callbacks registered for event's type.
+func (h *WebhookHandler) Dispatch(ctx context.Context, event *WebhookEvent) error {
+	h.mu.RLock()
+	handlers := h.handlers[event.Type]
+	h.mu.RUnlock()
+
+	for _, fn := range handlers {
+		if err := fn(ctx, event); err != nil {
+			return fmt.Errorf("failed to handle %s event %s: %w", event.Type, event.ID, err)
+		}
+	}
+	return nil
+}
+
+// ServeHTTP verifies the request's signature, then parses the event in the
+// request body and dispatches it.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if err = h.verify(r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event, err := ParseWebhookEvent(bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handle(r.Context(), event); err != nil {
+		if errors.Is(err, ErrWebhookQueueFull) {
+			http.Error(w, "event queue full", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "event processing failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handle queues or dispatches event unless it is a duplicate, releasing it
+// from the dedup store when it cannot be processed.
+func (h *WebhookHandler) handle(ctx context.Context, event *WebhookEvent) error {
+	if event.Type == EventWebhookTest {
+		return h.Dispatch(ctx, event)
+	}
+
+	ok, err := h.claim(ctx, event)
+	if err != nil || !ok {
+		return err
+	}
+	if err = h.process(ctx, event); err != nil {
+		if releaseErr := h.release(context.WithoutCancel(ctx), event); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+	}
+	return err
+}
+
+// process queues or dispatches event, falling back to the dead-letter
+// store when it cannot be processed.
+func (h *WebhookHandler) process(ctx context.Context, event *WebhookEvent) error {
+	var err error
+	if h.events != nil {
+		if err = h.enqueue(ctx, event, h.overflow); err != nil {
+			if h.overflow != OverflowDeadLetter {
+				return err
+			}
+		} else if err = h.awaitDone(ctx, event); err == nil || ctx.Err() != nil {
+			return err
+		}
+	} else if err = h.Dispatch(ctx, event); err == nil {
+		return nil
+	}
+
+	if h.deadLetters == nil {
+		return err
+	}
+	return h.Fail(ctx, event, err)
+}
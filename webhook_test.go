@@ -0,0 +1,88 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const inboundEventJSON = `{
+	"id": "evt-1",
+	"type": "message.inbound",
+	"created_at": "2026-03-20T09:00:00Z",
+	"data": {"id": "in-1", "from": "+989123456789", "to": "100020", "message": "JOIN"}
+}`
+
+func TestWebhookHandler_InboundMessage(t *testing.T) {
+	h := newTestWebhookHandler()
+
+	var got *InboundMessageEvent
+	h.OnInboundMessage(func(ctx context.Context, msg *InboundMessageEvent) error {
+		got = msg
+		return nil
+	})
+
+	req := signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got == nil {
+		t.Fatal("Expected inbound message callback to run")
+	}
+	if got.From != "+989123456789" || got.To != "100020" || got.Message != "JOIN" {
+		t.Errorf("Unexpected inbound message: %+v", got)
+	}
+}
+
+func TestWebhookHandler_CallbackError(t *testing.T) {
+	h := newTestWebhookHandler()
+	h.OnInboundMessage(func(ctx context.Context, msg *InboundMessageEvent) error {
+		return errors.New("database unavailable")
+	})
+
+	req := signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 so the event is redelivered, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandler_BadRequests(t *testing.T) {
+	h := newTestWebhookHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, signedWebhookRequest("/webhook", strings.NewReader(`{"id": "evt-1"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an event without type, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, signedWebhookRequest("/webhook", strings.NewReader(`{"id": "evt-2", "type": "unknown"}`)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected unhandled events to be acknowledged, got %d", rec.Code)
+	}
+}
+
+func TestWebhookEvent_WrongType(t *testing.T) {
+	event, err := ParseWebhookEvent(strings.NewReader(inboundEventJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := event.DeliveryReport(); err == nil {
+		t.Error("Expected error decoding an inbound message as a delivery report")
+	}
+}
@@ -0,0 +1,172 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// EventWebhookTest is the type of the test events sent by
+// WebhooksService.TestEndpoint and CheckWebhookEndpoint.
+const EventWebhookTest = "webhook.test"
+
+// ErrWebhookEndpoint is wrapped by the *WebhookEndpointError returned when a
+// webhook endpoint fails TestEndpoint or CheckWebhookEndpoint.
+var ErrWebhookEndpoint = errors.New("webhook endpoint check failed")
+
+// WebhookEndpointError describes why a webhook endpoint did not accept the
+// test event.
+type WebhookEndpointError struct {
+	URL    string
+	Reason string
+
+	// HTTP status of the response, if the endpoint answered
+	StatusCode int
+}
+
+func (e *WebhookEndpointError) Error() string {
+	return fmt.Sprintf("%v %s: %s", ErrWebhookEndpoint, e.URL, e.Reason)
+}
+
+func (e *WebhookEndpointError) Unwrap() error {
+	return ErrWebhookEndpoint
+}
+
+// ErrWebhookSecretRequired is returned by WebhooksService.TestEndpoint when
+// the client has no signing secret to sign the test event with.
+var ErrWebhookSecretRequired = errors.New("webhook signing secret is required; set one with WithWebhookSecret")
+
+// WithWebhookSecret sets the secret webhook events are signed with, the one
+// the receiving WebhookHandler is configured with through
+// WithSigningSecret. WebhooksService.TestEndpoint signs its test event with
+// it.
+func WithWebhookSecret(secret string) ClientOption {
+	return func(c *Client) {
+		c.webhookSecret = secret
+	}
+}
+
+// WebhookTestResult is the outcome of a test event sent by
+// WebhooksService.TestEndpoint.
+type WebhookTestResult struct {
+	URL string
+
+	// HTTP status of the response, or 0 if the endpoint was not reachable
+	StatusCode int
+
+	// Whether the endpoint accepted the signature of the test event. An
+	// endpoint that answers 401 or 403 rejected it.
+	SignatureVerified bool
+}
+
+// WebhooksService checks the endpoints webhook events are delivered to.
+type WebhooksService struct {
+	client *Client
+}
+
+// TestEndpoint posts an EventWebhookTest event to endpoint, signed with the
+// secret set by WithWebhookSecret the way the provider signs webhook
+// events, and reports whether the endpoint was reachable and verified the
+// signature. Unless the endpoint accepts the event with a 2xx status, the
+// error is a *WebhookEndpointError that tells why; the result is returned
+// along with it whenever the endpoint answered. Run it from outside the
+// network the endpoint is served on to debug delivery reports that do not
+// arrive.
+func (s *WebhooksService) TestEndpoint(ctx context.Context, endpoint string) (*WebhookTestResult, error) {
+	if s.client.webhookSecret == "" {
+		return nil, ErrWebhookSecretRequired
+	}
+	return postWebhookTest(ctx, endpoint, s.client.webhookSecret)
+}
+
+// defaultWebhookCheckTimeout bounds a webhook endpoint check.
+const defaultWebhookCheckTimeout = 10 * time.Second
+
+// CheckWebhookEndpoint posts an unsigned EventWebhookTest event to endpoint
+// and returns a *WebhookEndpointError unless the endpoint accepts it with a
+// 2xx status. The error tells whether the endpoint is unreachable,
+// redirects, rejects POST requests or fails to process events. A
+// WebhookHandler that verifies signatures rejects unsigned events; test it
+// with WebhooksService.TestEndpoint instead. A WebhookHandler acknowledges
+// test events without queueing them.
+func CheckWebhookEndpoint(ctx context.Context, endpoint string) error {
+	_, err := postWebhookTest(ctx, endpoint, "")
+	return err
+}
+
+// postWebhookTest posts a test event to endpoint, signed with secret unless
+// it is empty.
+func postWebhookTest(ctx context.Context, endpoint, secret string) (*WebhookTestResult, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &WebhookEndpointError{URL: endpoint, Reason: "not an absolute http(s) URL"}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	body, err := json.Marshal(&WebhookEvent{
+		ID:        "test_" + id,
+		Type:      EventWebhookTest,
+		CreatedAt: now,
+		Data:      json.RawMessage("{}"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode test event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(SignatureHeader, SignWebhook(secret, now, body))
+	}
+
+	httpClient := &http.Client{
+		Timeout: defaultWebhookCheckTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &WebhookEndpointError{URL: endpoint, Reason: "not reachable: " + err.Error()}
+	}
+	resp.Body.Close()
+
+	status := resp.StatusCode
+	result := &WebhookTestResult{URL: endpoint, StatusCode: status}
+	switch {
+	case status >= 200 && status < 300:
+		result.SignatureVerified = secret != ""
+		return result, nil
+	case status >= 300 && status < 400:
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "redirects to " + resp.Header.Get("Location") + "; webhook events are not redirected", StatusCode: status}
+	case (status == http.StatusUnauthorized || status == http.StatusForbidden) && secret != "":
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "rejected the signature; check that the endpoint uses the same signing secret and that its clock is correct", StatusCode: status}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "requires credentials or a signature", StatusCode: status}
+	case status == http.StatusNotFound:
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "not found", StatusCode: status}
+	case status == http.StatusMethodNotAllowed:
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "does not accept POST requests", StatusCode: status}
+	case status >= 500:
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "failed to process the event, status " + strconv.Itoa(status), StatusCode: status}
+	default:
+		return result, &WebhookEndpointError{URL: endpoint, Reason: "rejected the event, status " + strconv.Itoa(status), StatusCode: status}
+	}
+}
@@ -0,0 +1,120 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckWebhookEndpoint(t *testing.T) {
+	events := make(chan *WebhookEvent, 1)
+	h := NewWebhookHandler(WithEventChannel(events, OverflowReject), WithoutSignatureVerification())
+	tested := false
+	h.On(EventWebhookTest, func(ctx context.Context, event *WebhookEvent) error {
+		tested = true
+		return nil
+	})
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	if err := CheckWebhookEndpoint(context.Background(), server.URL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !tested {
+		t.Error("Expected the test event to be dispatched")
+	}
+	if len(events) != 0 {
+		t.Error("Expected the test event not to be queued")
+	}
+}
+
+func TestWebhooksService_TestEndpoint(t *testing.T) {
+	h := newTestWebhookHandler()
+	tested := false
+	h.On(EventWebhookTest, func(ctx context.Context, event *WebhookEvent) error {
+		tested = true
+		return nil
+	})
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+	ctx := context.Background()
+
+	client := NewClient("test-key", "test-secret", WithWebhookSecret(testWebhookSecret))
+	result, err := client.Webhooks.TestEndpoint(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.SignatureVerified || result.StatusCode != http.StatusOK {
+		t.Errorf("Expected a verified signature, got %+v", result)
+	}
+	if !tested {
+		t.Error("Expected the test event to be dispatched")
+	}
+
+	client = NewClient("test-key", "test-secret", WithWebhookSecret("whsec_other"))
+	result, err = client.Webhooks.TestEndpoint(ctx, server.URL)
+	var endpointErr *WebhookEndpointError
+	if !errors.As(err, &endpointErr) || endpointErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected the signature to be rejected, got %v", err)
+	}
+	if result == nil || result.SignatureVerified {
+		t.Errorf("Expected an unverified signature in the result, got %+v", result)
+	}
+
+	client = NewClient("test-key", "test-secret")
+	if _, err := client.Webhooks.TestEndpoint(ctx, server.URL); !errors.Is(err, ErrWebhookSecretRequired) {
+		t.Errorf("Expected ErrWebhookSecretRequired, got %v", err)
+	}
+	if err := CheckWebhookEndpoint(ctx, server.URL); !errors.As(err, &endpointErr) || endpointErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected an unsigned event to be rejected, got %v", err)
+	}
+}
+
+func TestCheckWebhookEndpoint_Failures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/webhook", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/moved", http.StatusMovedPermanently},
+		{"/get-only", http.StatusMethodNotAllowed},
+		{"/broken", http.StatusInternalServerError},
+		{"/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		err := CheckWebhookEndpoint(context.Background(), server.URL+tt.path)
+		var endpointErr *WebhookEndpointError
+		if !errors.As(err, &endpointErr) {
+			t.Errorf("%s: Expected *WebhookEndpointError, got %v", tt.path, err)
+			continue
+		}
+		if endpointErr.StatusCode != tt.status {
+			t.Errorf("%s: Expected status %d, got %d", tt.path, tt.status, endpointErr.StatusCode)
+		}
+		if !errors.Is(err, ErrWebhookEndpoint) {
+			t.Errorf("%s: Expected error to wrap ErrWebhookEndpoint", tt.path)
+		}
+	}
+
+	err := CheckWebhookEndpoint(context.Background(), "/webhook")
+	var endpointErr *WebhookEndpointError
+	if !errors.As(err, &endpointErr) || endpointErr.StatusCode != 0 {
+		t.Errorf("Expected a relative URL to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package signalads
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultDedupTTL is how long WithDeduplication remembers an event by
+// default; providers stop redelivering events well before that.
+const DefaultDedupTTL = 24 * time.Hour
+
+// DedupStore remembers the IDs of webhook events being or already
+// processed. Implementations must be safe for concurrent use; share one
+// store, such as a Redis or SQL table, between the instances receiving
+// webhooks so that a redelivery to another instance is suppressed too.
+type DedupStore interface {
+	// Claim records eventID for ttl and reports true, unless eventID is
+	// already recorded, in which case it reports false.
+	Claim(ctx context.Context, eventID string, ttl time.Duration) (bool, error)
+
+	// Release forgets eventID, so that it can be claimed again.
+	Release(ctx context.Context, eventID string) error
+}
+
+// WithDeduplication makes the handler suppress events it has already
+// received, by event ID, so that callbacks see each event once even though
+// the provider redelivers events. Duplicates are acknowledged without
+// running callbacks. An event whose processing fails is forgotten again, so
+// that the provider's redelivery is processed; one kept by the dead-letter
+// store stays recorded, as Replay processes it. IDs are remembered for ttl,
+// or DefaultDedupTTL if ttl is zero or less.
+func WithDeduplication(store DedupStore, ttl time.Duration) WebhookOption {
+	return func(h *WebhookHandler) {
+		if ttl <= 0 {
+			ttl = DefaultDedupTTL
+		}
+		h.dedup = store
+		h.dedupTTL = ttl
+	}
+}
+
+// claim reports whether event should be processed, recording it in the
+// dedup store if so. Events without an ID are always processed.
+func (h *WebhookHandler) claim(ctx context.Context, event *WebhookEvent) (bool, error) {
+	if h.dedup == nil || event.ID == "" {
+		return true, nil
+	}
+	ok, err := h.dedup.Claim(ctx, event.ID, h.dedupTTL)
+	if err != nil {
+		return false, fmt.Errorf("failed to check event %s for duplicates: %w", event.ID, err)
+	}
+	return ok, nil
+}
+
+// release forgets event in the dedup store after its processing failed.
+func (h *WebhookHandler) release(ctx context.Context, event *WebhookEvent) error {
+	if h.dedup == nil || event.ID == "" {
+		return nil
+	}
+	if err := h.dedup.Release(ctx, event.ID); err != nil {
+		return fmt.Errorf("failed to release event %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// MemoryDedupStore is an in-process DedupStore. It only suppresses
+// duplicates delivered to the same process.
+type MemoryDedupStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	expires map[string]time.Time
+	ops     int
+}
+
+// NewMemoryDedupStore creates an empty MemoryDedupStore.
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{now: time.Now, expires: make(map[string]time.Time)}
+}
+
+// Claim implements DedupStore.
+func (m *MemoryDedupStore) Claim(_ context.Context, eventID string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.ops++
+	if m.ops%1024 == 0 {
+		m.sweep(now)
+	}
+
+	if expires, ok := m.expires[eventID]; ok && now.Before(expires) {
+		return false, nil
+	}
+	m.expires[eventID] = now.Add(ttl)
+	return true, nil
+}
+
+// Release implements DedupStore.
+func (m *MemoryDedupStore) Release(_ context.Context, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.expires, eventID)
+	return nil
+}
+
+// sweep forgets the expired event IDs.
+func (m *MemoryDedupStore) sweep(now time.Time) {
+	for id, expires := range m.expires {
+		if !now.Before(expires) {
+			delete(m.expires, id)
+		}
+	}
+}
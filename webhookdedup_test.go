@@ -0,0 +1,58 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWebhookHandler_Deduplication(t *testing.T) {
+	h := newTestWebhookHandler(WithDeduplication(NewMemoryDedupStore(), 0))
+	calls := 0
+	fail := true
+	h.OnInboundMessage(func(ctx context.Context, msg *InboundMessageEvent) error {
+		calls++
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+	if code := postInboundEvent(ctx, h); code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", code)
+	}
+
+	fail = false
+	if code := postInboundEvent(ctx, h); code != http.StatusOK {
+		t.Fatalf("Expected the redelivery of a failed event to be processed, got status %d", code)
+	}
+	if code := postInboundEvent(ctx, h); code != http.StatusOK {
+		t.Fatalf("Expected a duplicate to be acknowledged, got status %d", code)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 callback runs, got %d", calls)
+	}
+}
+
+func TestMemoryDedupStore_Expiry(t *testing.T) {
+	store := NewMemoryDedupStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if ok, _ := store.Claim(ctx, "evt-1", time.Hour); !ok {
+		t.Fatal("Expected the first claim to succeed")
+	}
+	if ok, _ := store.Claim(ctx, "evt-1", time.Hour); ok {
+		t.Error("Expected a second claim to fail")
+	}
+
+	now = now.Add(time.Hour)
+	if ok, _ := store.Claim(ctx, "evt-1", time.Hour); !ok {
+		t.Error("Expected a claim to succeed after expiry")
+	}
+}
@@ -0,0 +1,89 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrWebhookQueueFull is returned when an event cannot be queued because the
+// event channel is full.
+var ErrWebhookQueueFull = errors.New("webhook event queue is full")
+
+// WebhookOverflow selects what a handler in channel mode does with an event
+// when the channel is full.
+type WebhookOverflow int
+
+const (
+	// OverflowBlock waits for room in the channel until the request is
+	// canceled, then answers 503 so the provider delivers the event again.
+	OverflowBlock WebhookOverflow = iota
+
+	// OverflowReject answers 503 right away.
+	OverflowReject
+
+	// OverflowDeadLetter saves the event to the dead-letter store and
+	// acknowledges it. Without a store it behaves like OverflowReject.
+	OverflowDeadLetter
+)
+
+// WithEventChannel makes the handler push parsed events onto ch instead of
+// running callbacks, so they can be processed by the application's own
+// workers. The buffer size of ch sets how many events can wait; overflow
+// decides what happens when it is full. Consumers must report the outcome
+// of every event they receive with Done: the webhook request is only
+// acknowledged once the event has been processed, so that an event lost by
+// a crashing consumer is delivered again by the provider.
+func WithEventChannel(ch chan<- *WebhookEvent, overflow WebhookOverflow) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.events = ch
+		h.overflow = overflow
+	}
+}
+
+// Done reports that a consumer of the event channel has processed event, or
+// failed to with err. The handler answers the webhook request with the
+// outcome; a failed event is kept in the dead-letter store if there is one
+// and delivered again by the provider otherwise. Calls after the first are
+// ignored.
+func (h *WebhookHandler) Done(event *WebhookEvent, err error) {
+	if event.done == nil {
+		return
+	}
+	select {
+	case event.done <- err:
+	default:
+	}
+}
+
+// awaitDone waits for the consumer to report the outcome of event, which
+// enqueue has put on the channel.
+func (h *WebhookHandler) awaitDone(ctx context.Context, event *WebhookEvent) error {
+	select {
+	case err := <-event.done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("event %s not processed: %w", event.ID, ctx.Err())
+	}
+}
+
+// enqueue pushes event onto the event channel, waiting for room only if
+// overflow is OverflowBlock.
+func (h *WebhookHandler) enqueue(ctx context.Context, event *WebhookEvent, overflow WebhookOverflow) error {
+	event.done = make(chan error, 1)
+	select {
+	case h.events <- event:
+		return nil
+	default:
+	}
+	if overflow != OverflowBlock {
+		return ErrWebhookQueueFull
+	}
+
+	select {
+	case h.events <- event:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrWebhookQueueFull, ctx.Err())
+	}
+}
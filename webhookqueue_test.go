@@ -0,0 +1,119 @@
+package signalads
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postInboundEvent(ctx context.Context, h http.Handler) int {
+	req := signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookHandler_EventChannel(t *testing.T) {
+	events := make(chan *WebhookEvent, 1)
+	h := newTestWebhookHandler(WithEventChannel(events, OverflowReject))
+	h.On(EventInboundMessage, func(ctx context.Context, event *WebhookEvent) error {
+		t.Error("Expected callbacks not to run in channel mode")
+		return nil
+	})
+
+	done := make(chan int)
+	go func() {
+		done <- postInboundEvent(context.Background(), h)
+	}()
+	event := <-events
+	if event.ID != "evt-1" || event.Type != EventInboundMessage {
+		t.Errorf("Unexpected queued event: %+v", event)
+	}
+
+	select {
+	case code := <-done:
+		t.Fatalf("Expected the request to wait for the consumer, got status %d", code)
+	case <-time.After(20 * time.Millisecond):
+	}
+	h.Done(event, nil)
+	if code := <-done; code != http.StatusOK {
+		t.Fatalf("Expected status 200 once the event was processed, got %d", code)
+	}
+
+	go func() {
+		done <- postInboundEvent(context.Background(), h)
+	}()
+	h.Done(<-events, errors.New("processing failed"))
+	if code := <-done; code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 for a failed event, got %d", code)
+	}
+}
+
+func TestWebhookHandler_EventChannelFull(t *testing.T) {
+	events := make(chan *WebhookEvent, 1)
+	h := newTestWebhookHandler(WithEventChannel(events, OverflowReject))
+	events <- &WebhookEvent{ID: "waiting"}
+
+	if code := postInboundEvent(context.Background(), h); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 on a full channel, got %d", code)
+	}
+}
+
+func TestWebhookHandler_EventChannelUnconfirmed(t *testing.T) {
+	events := make(chan *WebhookEvent, 1)
+	h := newTestWebhookHandler(WithEventChannel(events, OverflowReject))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if code := postInboundEvent(ctx, h); code == http.StatusOK {
+		t.Error("Expected an event nobody processed not to be acknowledged")
+	}
+}
+
+func TestWebhookHandler_EventChannelBlock(t *testing.T) {
+	events := make(chan *WebhookEvent, 1)
+	h := newTestWebhookHandler(WithEventChannel(events, OverflowBlock))
+	events <- &WebhookEvent{ID: "waiting"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if code := postInboundEvent(ctx, h); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 after the request was canceled, got %d", code)
+	}
+
+	done := make(chan int)
+	go func() {
+		done <- postInboundEvent(context.Background(), h)
+	}()
+	<-events
+	h.Done(<-events, nil)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("Expected the blocked request to succeed once there was room, got %d", code)
+	}
+}
+
+func TestWebhookHandler_EventChannelDeadLetter(t *testing.T) {
+	events := make(chan *WebhookEvent)
+	store := NewMemoryDeadLetterStore()
+	h := newTestWebhookHandler(WithEventChannel(events, OverflowDeadLetter), WithDeadLetterStore(store))
+
+	if code := postInboundEvent(context.Background(), h); code != http.StatusOK {
+		t.Fatalf("Expected overflowing event to be dead-lettered, got status %d", code)
+	}
+	letters, _ := store.List(context.Background(), 0)
+	if len(letters) != 1 {
+		t.Fatalf("Expected one dead letter, got %d", len(letters))
+	}
+
+	go func() {
+		h.Done(<-events, nil)
+	}()
+	n, err := h.Replay(context.Background())
+	if err != nil || n != 1 {
+		t.Errorf("Expected the letter to be replayed onto the channel, got %d, %v", n, err)
+	}
+}
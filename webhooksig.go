@@ -0,0 +1,118 @@
+package signalads
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers carrying the signature of a webhook request.
+const (
+	// SignatureHeader holds the hex HMAC-SHA256, keyed with the signing
+	// secret, of the timestamp, a dot, and the request body.
+	SignatureHeader = "X-SignalAds-Signature"
+
+	// TimestampHeader holds the Unix time, in seconds, the request was
+	// signed at.
+	TimestampHeader = "X-SignalAds-Timestamp"
+)
+
+// DefaultSignatureTolerance is how far a webhook signature's timestamp may be
+// from the current time by default.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// ErrInvalidSignature is returned by VerifyWebhookSignature for requests
+// that are unsigned, signed with another secret, or signed too long ago.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WithSigningSecret makes the handler verify the signature of every request
+// against secret, and answer 401 to requests that are unsigned or signed
+// with another secret, before any callback runs. Pass the previous secrets
+// after the current one while rotating secrets. A handler serving HTTP
+// requests needs this option, or WithoutSignatureVerification; otherwise it
+// answers 401 to every request.
+func WithSigningSecret(secret string, previous ...string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.secrets = append([]string{secret}, previous...)
+	}
+}
+
+// WithSignatureTolerance sets how far a signature's timestamp may be from
+// the current time, in either direction, bounding how long a captured
+// request can be replayed. It defaults to DefaultSignatureTolerance.
+func WithSignatureTolerance(d time.Duration) WebhookOption {
+	return func(h *WebhookHandler) {
+		if d > 0 {
+			h.tolerance = d
+		}
+	}
+}
+
+// WithoutSignatureVerification makes the handler accept unsigned requests.
+// Use it only when the handler is not reachable from the internet, or sits
+// behind a proxy that authenticates the provider itself: anyone who can
+// post to an unverified handler can forge inbound messages and delivery
+// reports.
+func WithoutSignatureVerification() WebhookOption {
+	return func(h *WebhookHandler) {
+		h.insecure = true
+	}
+}
+
+// SignWebhook returns the signature of body signed with secret at
+// timestamp, as sent in SignatureHeader.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	return hex.EncodeToString(webhookMAC(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// VerifyWebhookSignature checks the signature headers of a webhook request
+// with body against secrets, accepting timestamps within tolerance of now.
+// It returns ErrInvalidSignature if no secret matches.
+func VerifyWebhookSignature(header http.Header, body []byte, tolerance time.Duration, secrets ...string) error {
+	timestamp := header.Get(TimestampHeader)
+	signature, err := hex.DecodeString(header.Get(SignatureHeader))
+	if timestamp == "" || err != nil || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	for _, secret := range secrets {
+		if secret != "" && hmac.Equal(signature, webhookMAC(secret, timestamp, body)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func webhookMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// verify checks the signature of a request received by the handler.
+func (h *WebhookHandler) verify(header http.Header, body []byte) error {
+	if h.insecure {
+		return nil
+	}
+	if len(h.secrets) == 0 {
+		return ErrInvalidSignature
+	}
+	tolerance := h.tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	return VerifyWebhookSignature(header, body, tolerance, h.secrets...)
+}
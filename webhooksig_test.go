@@ -0,0 +1,111 @@
+package signalads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// newTestWebhookHandler returns a handler that verifies signatures made
+// with testWebhookSecret.
+func newTestWebhookHandler(opts ...WebhookOption) *WebhookHandler {
+	return NewWebhookHandler(append([]WebhookOption{WithSigningSecret(testWebhookSecret)}, opts...)...)
+}
+
+// signedWebhookRequest returns a webhook request for body signed with
+// testWebhookSecret.
+func signedWebhookRequest(target string, body io.Reader) *http.Request {
+	return signedWebhookRequestAt(target, body, testWebhookSecret, time.Now())
+}
+
+func signedWebhookRequestAt(target string, body io.Reader, secret string, at time.Time) *http.Request {
+	data, _ := io.ReadAll(body)
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(data))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+	req.Header.Set(SignatureHeader, SignWebhook(secret, at, data))
+	return req
+}
+
+func TestWebhookHandler_Signature(t *testing.T) {
+	h := NewWebhookHandler(WithSigningSecret("whsec_new", testWebhookSecret))
+	calls := 0
+	h.On(EventInboundMessage, func(ctx context.Context, event *WebhookEvent) error {
+		calls++
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"unsigned", httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(inboundEventJSON)), http.StatusUnauthorized},
+		{"wrong secret", signedWebhookRequestAt("/webhook", strings.NewReader(inboundEventJSON), "whsec_other", time.Now()), http.StatusUnauthorized},
+		{"stale", signedWebhookRequestAt("/webhook", strings.NewReader(inboundEventJSON), "whsec_new", time.Now().Add(-time.Hour)), http.StatusUnauthorized},
+		{"current secret", signedWebhookRequestAt("/webhook", strings.NewReader(inboundEventJSON), "whsec_new", time.Now()), http.StatusOK},
+		{"previous secret", signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON)), http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, tt.req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: Expected status %d, got %d", tt.name, tt.want, rec.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected callbacks to run for the 2 signed requests only, got %d", calls)
+	}
+}
+
+func TestWebhookHandler_SignatureTamperedBody(t *testing.T) {
+	h := newTestWebhookHandler()
+	req := signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON))
+	req.Body = io.NopCloser(strings.NewReader(strings.Replace(inboundEventJSON, "JOIN", "STOP", 1)))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a tampered body, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandler_NoSecret(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewWebhookHandler().ServeHTTP(rec, signedWebhookRequest("/webhook", strings.NewReader(inboundEventJSON)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a handler without a secret to reject requests, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	NewWebhookHandler(WithoutSignatureVerification()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(inboundEventJSON)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected an unverified handler to accept unsigned requests, got %d", rec.Code)
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(inboundEventJSON)
+	now := time.Now()
+	header := http.Header{}
+	header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	header.Set(SignatureHeader, SignWebhook("secret", now, body))
+
+	if err := VerifyWebhookSignature(header, body, time.Minute, "secret"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := VerifyWebhookSignature(header, body, time.Minute, "other"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+	if err := VerifyWebhookSignature(header, body, time.Minute, ""); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected an empty secret to match nothing, got %v", err)
+	}
+}